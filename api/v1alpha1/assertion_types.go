@@ -0,0 +1,69 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// AssertionOperator compares the observed value of a metric with the
+// expected value
+// +kubebuilder:validation:Enum=">";">=";"<";"<=";"=="
+type AssertionOperator string
+
+const (
+	// GreaterThan requires the metric to be greater than the value
+	GreaterThan AssertionOperator = ">"
+	// GreaterThanOrEqual requires the metric to be at least the value
+	GreaterThanOrEqual AssertionOperator = ">="
+	// LessThan requires the metric to be less than the value
+	LessThan AssertionOperator = "<"
+	// LessThanOrEqual requires the metric to be at most the value
+	LessThanOrEqual AssertionOperator = "<="
+	// Equal requires the metric to be equal to the value
+	Equal AssertionOperator = "=="
+)
+
+// Assertion is an expectation on a metric of the results of the benchmark
+// (e.g. read.iops >= 20000)
+type Assertion struct {
+	// Metric is the name of the metric (e.g. read.iops, receiver.bps, tps, p99)
+	Metric string `json:"metric"`
+
+	// Operator compares the observed value with the expected value
+	Operator AssertionOperator `json:"operator"`
+
+	// Value is the expected value as a decimal number (e.g. 20000, 9e9).
+	// Durations (e.g. 5ms) are converted to the time unit of the metric.
+	Value string `json:"value"`
+}
+
+// AssertionResult is the outcome of an assertion
+type AssertionResult struct {
+	Assertion `json:",inline"`
+
+	// Observed is the value of the metric in the results
+	// +optional
+	Observed string `json:"observed,omitempty"`
+
+	// Unit of the observed value
+	// +optional
+	Unit string `json:"unit,omitempty"`
+
+	// Passed is true if the assertion holds
+	Passed bool `json:"passed"`
+
+	// Message explains why the assertion could not be evaluated
+	// +optional
+	Message string `json:"message,omitempty"`
+}
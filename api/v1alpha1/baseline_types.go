@@ -0,0 +1,141 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// MetricDirection tells whether the higher or the lower values of a
+// metric are better
+// +kubebuilder:validation:Enum=HigherIsBetter;LowerIsBetter
+type MetricDirection string
+
+const (
+	// HigherIsBetter is the direction of throughput-like metrics (e.g. iops, tps)
+	HigherIsBetter MetricDirection = "HigherIsBetter"
+	// LowerIsBetter is the direction of latency-like metrics
+	LowerIsBetter MetricDirection = "LowerIsBetter"
+)
+
+// MetricTolerance overrides the comparison settings of a metric
+type MetricTolerance struct {
+	// Name of the metric (e.g. read.iops)
+	Name string `json:"name"`
+
+	// TolerancePercent is the largest accepted regression of the metric,
+	// relative to the baseline in percent
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TolerancePercent *int32 `json:"tolerancePercent,omitempty"`
+
+	// Direction tells whether the higher or the lower values of the metric
+	// are better. By default the metrics measured in time units (ns, us,
+	// ms, s) or in percent are considered better when lower, all the
+	// others when higher.
+	// +optional
+	Direction MetricDirection `json:"direction,omitempty"`
+
+	// Ignore excludes the metric from the comparison
+	// +optional
+	Ignore bool `json:"ignore,omitempty"`
+}
+
+// BaselineSpec configures the comparison of the results of the benchmark
+// with a baseline. The baseline is the most recent BenchmarkResult in the
+// namespace of the benchmark labelled with kubestone.xridge.io/baseline=<key>.
+type BaselineSpec struct {
+	// Key identifies the baseline (e.g. fio-gp2-randread)
+	// +kubebuilder:validation:Pattern=^[a-zA-Z0-9]([-_.a-zA-Z0-9]*[a-zA-Z0-9])?$
+	// +kubebuilder:validation:MaxLength=63
+	Key string `json:"key"`
+
+	// TolerancePercent is the largest accepted regression of the metrics,
+	// relative to the baseline in percent. Defaults to 5.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TolerancePercent *int32 `json:"tolerancePercent,omitempty"`
+
+	// Metrics overrides the comparison settings of individual metrics
+	// +optional
+	Metrics []MetricTolerance `json:"metrics,omitempty"`
+
+	// FailOnRegression moves the benchmark to Failed phase when any of
+	// its metrics has regressed. Regressions are only reported by the
+	// Regressed condition and a Warning event by default.
+	// +optional
+	FailOnRegression bool `json:"failOnRegression,omitempty"`
+
+	// Promote makes the record of this run the new baseline of the key,
+	// if the run has succeeded
+	// +optional
+	Promote bool `json:"promote,omitempty"`
+}
+
+// MetricComparison is the comparison of a metric with its baseline value
+type MetricComparison struct {
+	// Name of the metric
+	Name string `json:"name"`
+
+	// Unit of the metric
+	// +optional
+	Unit string `json:"unit,omitempty"`
+
+	// Baseline is the value of the metric in the baseline
+	Baseline string `json:"baseline"`
+
+	// Value is the value of the metric in this run
+	Value string `json:"value"`
+
+	// DeltaPercent is the change of the value relative to the baseline in
+	// percent. Empty if the baseline value is zero.
+	// +optional
+	DeltaPercent string `json:"deltaPercent,omitempty"`
+
+	// TolerancePercent is the largest accepted regression of the metric
+	TolerancePercent int32 `json:"tolerancePercent"`
+
+	// Direction tells whether the higher or the lower values of the metric are better
+	Direction MetricDirection `json:"direction"`
+
+	// Regressed is true if the metric has regressed beyond the tolerance
+	// +optional
+	Regressed bool `json:"regressed,omitempty"`
+}
+
+// BaselineComparison is the result of the comparison of the benchmark
+// results with the baseline
+type BaselineComparison struct {
+	// Key of the baseline
+	Key string `json:"key"`
+
+	// BaselineResult is the name of the BenchmarkResult used as baseline.
+	// Empty if no baseline was found for the key.
+	// +optional
+	BaselineResult string `json:"baselineResult,omitempty"`
+
+	// Metrics contains the comparison of the metrics present both in the
+	// results and in the baseline
+	// +optional
+	Metrics []MetricComparison `json:"metrics,omitempty"`
+}
+
+// IsRegressed returns true if any of the compared metrics has regressed
+func (c *BaselineComparison) IsRegressed() bool {
+	for _, metric := range c.Metrics {
+		if metric.Regressed {
+			return true
+		}
+	}
+	return false
+}
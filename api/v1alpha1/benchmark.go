@@ -0,0 +1,36 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Benchmark is implemented by all the benchmark custom resources.
+// It provides uniform access to the common parts of the benchmarks.
+// +kubebuilder:object:generate=false
+type Benchmark interface {
+	metav1.Object
+	runtime.Object
+
+	// GetBenchmarkStatus returns a pointer to the status of the benchmark
+	GetBenchmarkStatus() *BenchmarkStatus
+
+	// GetCommonSpec returns a pointer to the settings shared by the benchmarks
+	GetCommonSpec() *CommonSpec
+}
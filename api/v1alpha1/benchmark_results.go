@@ -0,0 +1,145 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import "strconv"
+
+// BenchmarkMetric is a single value parsed from the output of the benchmark
+type BenchmarkMetric struct {
+	// Name of the metric (e.g. tps, read.iops, latency.p99)
+	Name string `json:"name"`
+
+	// Value of the metric in decimal notation. It is stored as string,
+	// as floating point numbers are not supported in CRDs.
+	Value string `json:"value"`
+
+	// Unit of the value (e.g. ops/s, bytes/s, us)
+	// +optional
+	Unit string `json:"unit,omitempty"`
+}
+
+// BenchmarkResults contains the machine-readable results of the
+// benchmark, parsed from the logs of the benchmark job(s)
+type BenchmarkResults struct {
+	// Metrics contains the summary values of the benchmark
+	// +optional
+	Metrics []BenchmarkMetric `json:"metrics,omitempty"`
+
+	// Statistics contains the statistics of the metrics over the measured
+	// runs of repeated benchmarks
+	// +optional
+	Statistics []MetricStatistics `json:"statistics,omitempty"`
+
+	// Fio contains the detailed results of fio benchmarks
+	// +optional
+	Fio *FioResults `json:"fio,omitempty"`
+
+	// Iperf3 contains the detailed results of iperf3 benchmarks
+	// +optional
+	Iperf3 *Iperf3Results `json:"iperf3,omitempty"`
+
+	// StorageClasses contains the results of the benchmark for each
+	// StorageClass of a StorageClass comparison, in the order of the
+	// StorageClassNames. The metrics are also present in Metrics, prefixed
+	// with the name of the StorageClass (e.g. fast.read.iops). For repeated
+	// benchmarks these are the results of the last run.
+	// +optional
+	StorageClasses []StorageClassResults `json:"storageClasses,omitempty"`
+}
+
+// StorageClassResults are the results of the benchmark run against the
+// PVC of one StorageClass
+type StorageClassResults struct {
+	// StorageClass is the name of the StorageClass of the benchmarked PVC
+	StorageClass string `json:"storageClass"`
+
+	// Metrics contains the summary values of the benchmark
+	// +optional
+	Metrics []BenchmarkMetric `json:"metrics,omitempty"`
+
+	// Fio contains the detailed results of fio benchmarks
+	// +optional
+	Fio *FioResults `json:"fio,omitempty"`
+}
+
+// MetricStatistics summarizes the values of a metric over the measured
+// runs of a repeated benchmark. The values are stored as strings in
+// decimal notation, as floating point numbers are not supported in CRDs.
+type MetricStatistics struct {
+	// Name of the metric
+	Name string `json:"name"`
+
+	// Unit of the metric
+	// +optional
+	Unit string `json:"unit,omitempty"`
+
+	// Samples is the number of runs which reported the metric
+	Samples int32 `json:"samples"`
+
+	// Mean is the arithmetic mean of the values
+	Mean string `json:"mean"`
+
+	// Median is the median of the values
+	Median string `json:"median"`
+
+	// StdDev is the sample standard deviation of the values
+	StdDev string `json:"stdDev"`
+
+	// Min is the smallest value
+	Min string `json:"min"`
+
+	// Max is the largest value
+	Max string `json:"max"`
+
+	// ConfidenceLow is the lower bound of the 95% confidence interval
+	// of the mean, based on Student's t-distribution
+	// +optional
+	ConfidenceLow string `json:"confidenceLow,omitempty"`
+
+	// ConfidenceHigh is the upper bound of the 95% confidence interval
+	// of the mean
+	// +optional
+	ConfidenceHigh string `json:"confidenceHigh,omitempty"`
+}
+
+// Float64 returns the value of the metric as a floating point number
+func (m *BenchmarkMetric) Float64() (float64, error) {
+	return strconv.ParseFloat(m.Value, 64)
+}
+
+// AddMetric appends a metric to the results. If a metric with the same
+// name is already present, its value and unit are overwritten.
+func (r *BenchmarkResults) AddMetric(name string, value float64, unit string) {
+	formatted := strconv.FormatFloat(value, 'f', -1, 64)
+	if metric := r.GetMetric(name); metric != nil {
+		metric.Value = formatted
+		metric.Unit = unit
+		return
+	}
+	r.Metrics = append(r.Metrics, BenchmarkMetric{Name: name, Value: formatted, Unit: unit})
+}
+
+// GetMetric returns the metric with the given name, or nil if the
+// metric is not present
+func (r *BenchmarkResults) GetMetric(name string) *BenchmarkMetric {
+	for i := range r.Metrics {
+		if r.Metrics[i].Name == name {
+			return &r.Metrics[i]
+		}
+	}
+	return nil
+}
@@ -16,10 +16,322 @@ limitations under the License.
 
 package v1alpha1
 
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BenchmarkPhase is a label for the lifecycle phase of the benchmark
+// +kubebuilder:validation:Enum=Pending;Validating;DeployingServer;Running;Succeeded;Failed;Cancelled
+type BenchmarkPhase string
+
+const (
+	// BenchmarkPending means that the benchmark has been accepted by the
+	// controller, but the execution has not been started yet
+	BenchmarkPending BenchmarkPhase = "Pending"
+	// BenchmarkValidating means that the benchmark spec is under validation
+	BenchmarkValidating BenchmarkPhase = "Validating"
+	// BenchmarkDeployingServer means that the server side of the benchmark
+	// (deployment, service, statefulset) is being deployed
+	BenchmarkDeployingServer BenchmarkPhase = "DeployingServer"
+	// BenchmarkRunning means that the benchmark job(s) are executing
+	BenchmarkRunning BenchmarkPhase = "Running"
+	// BenchmarkSucceeded means that the benchmark has completed successfully
+	BenchmarkSucceeded BenchmarkPhase = "Succeeded"
+	// BenchmarkFailed means that the benchmark has terminated with failure
+	BenchmarkFailed BenchmarkPhase = "Failed"
+	// BenchmarkCancelled means that the benchmark has been cancelled
+	BenchmarkCancelled BenchmarkPhase = "Cancelled"
+)
+
+// BenchmarkConditionType is the type of a benchmark condition
+type BenchmarkConditionType string
+
+const (
+	// ConditionValidated is true when the benchmark spec passed validation
+	ConditionValidated BenchmarkConditionType = "Validated"
+	// ConditionServerReady is true when the server side of the benchmark
+	// is ready to accept the benchmark clients
+	ConditionServerReady BenchmarkConditionType = "ServerReady"
+	// ConditionRunning is true while the benchmark job(s) are executing
+	ConditionRunning BenchmarkConditionType = "Running"
+	// ConditionSucceeded is true when the benchmark has completed
+	// successfully, and false when it has failed or has been cancelled
+	ConditionSucceeded BenchmarkConditionType = "Succeeded"
+	// ConditionFailed is true when the benchmark has failed
+	ConditionFailed BenchmarkConditionType = "Failed"
+	// ConditionCancelled is true when the benchmark has been cancelled
+	ConditionCancelled BenchmarkConditionType = "Cancelled"
+	// ConditionCleanedUp is true when the objects created for the finished
+	// benchmark have been deleted according to its cleanup policy
+	ConditionCleanedUp BenchmarkConditionType = "CleanedUp"
+	// ConditionRegressed is true when any of the metrics of the benchmark
+	// has regressed compared to its baseline beyond the tolerance
+	ConditionRegressed BenchmarkConditionType = "Regressed"
+	// ConditionRecorded is true when the BenchmarkResult of the finished
+	// benchmark has been created, and false when it has been rejected
+	ConditionRecorded BenchmarkConditionType = "Recorded"
+)
+
+// BenchmarkCondition contains the details of one aspect of the
+// benchmark's current state. It follows the layout of the upstream
+// metav1.Condition, so that generic tools (e.g. kubectl wait) can use it.
+type BenchmarkCondition struct {
+	// Type of the condition
+	Type BenchmarkConditionType `json:"type"`
+
+	// Status of the condition, one of True, False, Unknown
+	Status corev1.ConditionStatus `json:"status"`
+
+	// ObservedGeneration is the generation of the benchmark the condition
+	// was set upon
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastTransitionTime is the last time the condition transitioned
+	// from one status to another
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+
+	// Reason is a brief CamelCase reason for the condition's last transition
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human readable message with details about the transition
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ChildReference refers to an object created for the benchmark
+type ChildReference struct {
+	// APIVersion of the created object (e.g. batch/v1)
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// Kind of the created object (e.g. Job, Deployment, Service)
+	Kind string `json:"kind"`
+
+	// Name of the created object
+	Name string `json:"name"`
+}
+
 // BenchmarkStatus describes the current state of the benchmark
 type BenchmarkStatus struct {
-	// Running shows the state of execution
-	Running bool `json:"running"`
-	// Completed shows the state of completion
-	Completed bool `json:"completed"`
+	// Phase is the current lifecycle phase of the benchmark
+	// +optional
+	Phase BenchmarkPhase `json:"phase,omitempty"`
+
+	// Conditions contains the latest observations of the benchmark's state
+	// +optional
+	Conditions []BenchmarkCondition `json:"conditions,omitempty"`
+
+	// StartTime is the time when the controller started to process the benchmark
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time when the benchmark has finished
+	// (either succeeded, failed or cancelled)
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Duration is the time elapsed between StartTime and CompletionTime
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// PhaseTransitionTime is the time when the benchmark entered its current phase
+	// +optional
+	PhaseTransitionTime *metav1.Time `json:"phaseTransitionTime,omitempty"`
+
+	// ObservedGeneration is the generation of the benchmark spec which
+	// was picked up by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Reason is a brief CamelCase reason of the current phase
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message contains the details of the current phase, e.g. the
+	// exit code and termination message of a failed container
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Children are the objects created for the benchmark
+	// +optional
+	Children []ChildReference `json:"children,omitempty"`
+
+	// Results are the parsed results of the successfully completed benchmark.
+	// The metrics of repeated benchmarks are the mean values of the
+	// measured runs.
+	// +optional
+	Results *BenchmarkResults `json:"results,omitempty"`
+
+	// Iterations contains the results of the finished runs of a repeated
+	// benchmark, including the warmup runs
+	// +optional
+	Iterations []BenchmarkIteration `json:"iterations,omitempty"`
+
+	// Comparison is the comparison of the results with the baseline of
+	// the benchmark
+	// +optional
+	Comparison *BaselineComparison `json:"comparison,omitempty"`
+
+	// Assertions contains the outcome of the assertions of the benchmark
+	// +optional
+	Assertions []AssertionResult `json:"assertions,omitempty"`
+}
+
+// BenchmarkIteration describes a finished run of a repeated benchmark
+type BenchmarkIteration struct {
+	// Iteration is the number of the run, starting from 1
+	Iteration int32 `json:"iteration"`
+
+	// Warmup is true for the warmup runs, which are excluded from the statistics
+	// +optional
+	Warmup bool `json:"warmup,omitempty"`
+
+	// CompletionTime is the time when the run has finished
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Metrics are the summary values of the run
+	// +optional
+	Metrics []BenchmarkMetric `json:"metrics,omitempty"`
+}
+
+// IsFinished returns true if the benchmark has reached a terminal phase
+func (s *BenchmarkStatus) IsFinished() bool {
+	return s.Phase == BenchmarkSucceeded ||
+		s.Phase == BenchmarkFailed ||
+		s.Phase == BenchmarkCancelled
+}
+
+// IsInProgress returns true if the benchmark has been picked up by the
+// controller, but has not finished yet
+func (s *BenchmarkStatus) IsInProgress() bool {
+	return s.Phase != "" && !s.IsFinished()
+}
+
+// SetPhase moves the benchmark to the given phase and maintains the
+// start and completion times together with the phase related conditions.
+func (s *BenchmarkStatus) SetPhase(phase BenchmarkPhase, reason, message string) {
+	now := metav1.Now()
+	if s.Phase != phase || s.PhaseTransitionTime == nil {
+		s.PhaseTransitionTime = &now
+	}
+	s.Phase = phase
+	s.Reason = reason
+	s.Message = message
+
+	switch phase {
+	case BenchmarkValidating, BenchmarkDeployingServer, BenchmarkRunning:
+		if s.StartTime == nil {
+			s.StartTime = &now
+		}
+		if phase == BenchmarkRunning {
+			s.SetCondition(ConditionRunning, corev1.ConditionTrue, reason, message)
+		}
+	case BenchmarkSucceeded, BenchmarkFailed, BenchmarkCancelled:
+		if s.StartTime == nil {
+			s.StartTime = &now
+		}
+		if s.CompletionTime == nil {
+			s.CompletionTime = &now
+		}
+		s.Duration = &metav1.Duration{Duration: s.CompletionTime.Sub(s.StartTime.Time)}
+
+		if s.GetCondition(ConditionRunning) != nil {
+			s.SetCondition(ConditionRunning, corev1.ConditionFalse, reason, message)
+		}
+		if phase == BenchmarkSucceeded {
+			s.SetCondition(ConditionSucceeded, corev1.ConditionTrue, reason, message)
+		} else {
+			s.SetCondition(ConditionSucceeded, corev1.ConditionFalse, reason, message)
+		}
+		if phase == BenchmarkFailed {
+			s.SetCondition(ConditionFailed, corev1.ConditionTrue, reason, message)
+		}
+		if phase == BenchmarkCancelled {
+			s.SetCondition(ConditionCancelled, corev1.ConditionTrue, reason, message)
+		}
+	}
+}
+
+// GetCondition returns the condition with the given type, or nil
+// if the condition is not present
+func (s *BenchmarkStatus) GetCondition(conditionType BenchmarkConditionType) *BenchmarkCondition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == conditionType {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// IsConditionTrue returns true if the condition with the given
+// type is present and its status is True
+func (s *BenchmarkStatus) IsConditionTrue(conditionType BenchmarkConditionType) bool {
+	condition := s.GetCondition(conditionType)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+// SetCondition adds or updates the condition with the given type.
+// The LastTransitionTime is only updated when the status changes.
+func (s *BenchmarkStatus) SetCondition(conditionType BenchmarkConditionType,
+	status corev1.ConditionStatus, reason, message string) {
+	condition := s.GetCondition(conditionType)
+	if condition == nil {
+		s.Conditions = append(s.Conditions, BenchmarkCondition{Type: conditionType})
+		condition = &s.Conditions[len(s.Conditions)-1]
+	}
+	if condition.Status != status {
+		condition.LastTransitionTime = metav1.Now()
+	}
+	condition.Status = status
+	condition.ObservedGeneration = s.ObservedGeneration
+	condition.Reason = reason
+	condition.Message = message
+}
+
+// AddChild registers the given object in the Children list, unless
+// it is already present
+func (s *BenchmarkStatus) AddChild(apiVersion, kind, name string) {
+	for _, child := range s.Children {
+		if child.Kind == kind && child.Name == name {
+			return
+		}
+	}
+	s.Children = append(s.Children, ChildReference{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Name:       name,
+	})
+}
+
+// ChildBenchmarkStatus describes the state of a benchmark created by a
+// composite benchmark: a step of a suite, a run of a sweep, a node of a
+// fan-out or a pair of an iperf3 matrix
+type ChildBenchmarkStatus struct {
+	// BenchmarkName is the name of the created benchmark.
+	// Empty until the benchmark is started.
+	// +optional
+	BenchmarkName string `json:"benchmarkName,omitempty"`
+
+	// Phase is the phase of the benchmark
+	// +optional
+	Phase BenchmarkPhase `json:"phase,omitempty"`
+
+	// Reason is a brief CamelCase reason of the phase
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message contains the details of the phase
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// IsFinished returns true if the benchmark has reached a terminal phase
+func (s *ChildBenchmarkStatus) IsFinished() bool {
+	return s.Phase == BenchmarkSucceeded ||
+		s.Phase == BenchmarkFailed ||
+		s.Phase == BenchmarkCancelled
 }
@@ -0,0 +1,123 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BenchmarkFanoutSpec defines the benchmark and the nodes of the fan-out
+type BenchmarkFanoutSpec struct {
+	CommonSpec `json:",inline"`
+
+	// Template is the benchmark executed on each of the selected nodes
+	Template BenchmarkTemplate `json:"template"`
+
+	// NodeSelector selects the nodes by their labels. All nodes are
+	// selected if empty. Unschedulable and not ready nodes are skipped.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// NodeNamePath is the JSON path of the field of the spec which pins
+	// the benchmark pod to the node. Defaults to .podConfig.podScheduling.nodeName,
+	// or .clientConfiguration.podScheduling.nodeName for the client-server
+	// benchmarks (Iperf3, Qperf, Nighthawk).
+	// +optional
+	NodeNamePath string `json:"nodeNamePath,omitempty"`
+
+	// Parallelism is the maximum number of nodes benchmarked at the same
+	// time. Defaults to 1, which benchmarks the nodes one after the other.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Parallelism *int32 `json:"parallelism,omitempty"`
+
+	// OutlierTolerancePercent is the largest deviation of a metric of a
+	// node from the median of all nodes, in percent, before the node is
+	// reported as outlier. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	OutlierTolerancePercent *int32 `json:"outlierTolerancePercent,omitempty"`
+}
+
+// BenchmarkFanoutNode describes the benchmark of a node: a row in the
+// results table of the fan-out
+type BenchmarkFanoutNode struct {
+	// NodeName is the name of the benchmarked node
+	NodeName string `json:"nodeName"`
+
+	ChildBenchmarkStatus `json:",inline"`
+
+	// Metrics are the summary values of the benchmark of the node
+	// +optional
+	Metrics []BenchmarkMetric `json:"metrics,omitempty"`
+
+	// Outliers are the names of the metrics of the node deviating from
+	// the median of all nodes beyond the outlier tolerance
+	// +optional
+	Outliers []string `json:"outliers,omitempty"`
+}
+
+// BenchmarkFanoutStatus describes the state of the fan-out. Nodes is the
+// results table of the fan-out, with a row for each selected node.
+type BenchmarkFanoutStatus struct {
+	BenchmarkStatus `json:",inline"`
+
+	// Nodes contains the state and the metrics of the benchmarks of the
+	// selected nodes, ordered by node name
+	// +optional
+	Nodes []BenchmarkFanoutNode `json:"nodes,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Kind",type="string",JSONPath=".spec.template.kind"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// BenchmarkFanout runs a benchmark on every node matching a selector,
+// and reports the results of the nodes side by side
+type BenchmarkFanout struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BenchmarkFanoutSpec   `json:"spec,omitempty"`
+	Status BenchmarkFanoutStatus `json:"status,omitempty"`
+}
+
+// GetBenchmarkStatus returns the status of the fan-out
+func (cr *BenchmarkFanout) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status.BenchmarkStatus
+}
+
+// GetCommonSpec returns the common settings of the fan-out
+func (cr *BenchmarkFanout) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
+}
+
+// +kubebuilder:object:root=true
+
+// BenchmarkFanoutList contains a list of BenchmarkFanout
+type BenchmarkFanoutList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BenchmarkFanout `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BenchmarkFanout{}, &BenchmarkFanoutList{})
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// BenchmarkReference identifies the benchmark custom resource of a run
+type BenchmarkReference struct {
+	// APIVersion of the benchmark
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the benchmark (e.g. Fio, Iperf3)
+	Kind string `json:"kind"`
+
+	// Name of the benchmark
+	Name string `json:"name"`
+
+	// UID of the benchmark, which distinguishes the runs of
+	// benchmarks recreated with the same name
+	UID types.UID `json:"uid"`
+}
+
+// ContainerImage describes the image a benchmark container was run with
+type ContainerImage struct {
+	// Container is the name of the container in the benchmark pod
+	Container string `json:"container"`
+
+	// Image is the image reference from the pod spec
+	Image string `json:"image"`
+
+	// ImageID is the resolved image reference (including the digest)
+	// reported by the container runtime
+	// +optional
+	ImageID string `json:"imageID,omitempty"`
+}
+
+// BenchmarkResultSpec is the snapshot of a finished benchmark run
+type BenchmarkResultSpec struct {
+	// Benchmark refers to the custom resource of the run. The custom
+	// resource itself may have been deleted since the run.
+	Benchmark BenchmarkReference `json:"benchmark"`
+
+	// BenchmarkSpec is the spec of the benchmark custom resource
+	// at the time of the run
+	// +optional
+	BenchmarkSpec *runtime.RawExtension `json:"benchmarkSpec,omitempty"`
+
+	// Images are the images of the benchmark containers
+	// +optional
+	Images []ContainerImage `json:"images,omitempty"`
+
+	// NodeNames are the nodes the benchmark pods were scheduled to
+	// +optional
+	NodeNames []string `json:"nodeNames,omitempty"`
+
+	// Phase is the terminal phase of the benchmark (Succeeded or Failed)
+	Phase BenchmarkPhase `json:"phase"`
+
+	// Reason is a brief CamelCase reason of the terminal phase
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message contains the details of the terminal phase
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// StartTime is the time when the controller started to process the benchmark
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time when the benchmark has finished
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Duration is the time elapsed between StartTime and CompletionTime
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Results are the parsed results of the benchmark
+	// +optional
+	Results *BenchmarkResults `json:"results,omitempty"`
+
+	// Iterations contains the results of the runs of repeated benchmarks
+	// +optional
+	Iterations []BenchmarkIteration `json:"iterations,omitempty"`
+
+	// Comparison is the comparison of the results with the baseline
+	// +optional
+	Comparison *BaselineComparison `json:"comparison,omitempty"`
+
+	// Assertions contains the outcome of the assertions
+	// +optional
+	Assertions []AssertionResult `json:"assertions,omitempty"`
+
+	// LogExcerpt contains the last lines of the logs of the benchmark pods
+	// +optional
+	LogExcerpt string `json:"logExcerpt,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Kind",type="string",JSONPath=".spec.benchmark.kind"
+// +kubebuilder:printcolumn:name="Benchmark",type="string",JSONPath=".spec.benchmark.name"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".spec.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".spec.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// BenchmarkResult is the durable record of a finished benchmark run. It
+// is not owned by the benchmark, therefore it is kept when the benchmark
+// custom resource (and the objects created for it) are deleted.
+type BenchmarkResult struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec BenchmarkResultSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BenchmarkResultList contains a list of BenchmarkResult
+type BenchmarkResultList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BenchmarkResult `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BenchmarkResult{}, &BenchmarkResultList{})
+}
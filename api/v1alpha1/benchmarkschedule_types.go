@@ -0,0 +1,122 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConcurrencyPolicy describes how the runs of a schedule are handled
+// when the previous run is still in progress
+// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrent allows the runs to overlap
+	AllowConcurrent ConcurrencyPolicy = "Allow"
+	// ForbidConcurrent skips the runs which are due while the previous one
+	// is in progress
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+	// ReplaceConcurrent deletes the unfinished runs when a new one is due
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+// BenchmarkScheduleSpec describes when and how the benchmarks are run
+type BenchmarkScheduleSpec struct {
+	// Schedule is the time of the runs in Cron format, see
+	// https://en.wikipedia.org/wiki/Cron (e.g. "0 2 * * *" for nightly runs)
+	Schedule string `json:"schedule"`
+
+	// StartingDeadlineSeconds is the deadline for starting a run which
+	// missed its scheduled time. Missed runs are not started after the
+	// deadline. If not set, the missed runs are started without deadline.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+
+	// ConcurrencyPolicy describes how to treat the concurrent runs.
+	// Defaults to Allow.
+	// +optional
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// Suspend stops the scheduling of new runs. The runs in progress
+	// are not affected.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// Template is the benchmark created for each run
+	Template BenchmarkTemplate `json:"template"`
+
+	// SuccessfulRunsHistoryLimit is the number of succeeded runs to keep.
+	// Defaults to 3. The BenchmarkResults of the deleted runs are kept.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SuccessfulRunsHistoryLimit *int32 `json:"successfulRunsHistoryLimit,omitempty"`
+
+	// FailedRunsHistoryLimit is the number of failed (or cancelled) runs
+	// to keep. Defaults to 1. The BenchmarkResults of the deleted runs
+	// are kept.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FailedRunsHistoryLimit *int32 `json:"failedRunsHistoryLimit,omitempty"`
+}
+
+// BenchmarkScheduleStatus describes the runs of the schedule
+type BenchmarkScheduleStatus struct {
+	// Active are the runs in progress
+	// +optional
+	Active []ChildReference `json:"active,omitempty"`
+
+	// LastScheduleTime is the scheduled time of the last started run, or
+	// of the last run skipped as the previous run was still in progress
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// LastSuccessfulTime is the completion time of the last succeeded run
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule"
+// +kubebuilder:printcolumn:name="Kind",type="string",JSONPath=".spec.template.kind"
+// +kubebuilder:printcolumn:name="Suspend",type="boolean",JSONPath=".spec.suspend"
+// +kubebuilder:printcolumn:name="Last Schedule",type="date",JSONPath=".status.lastScheduleTime"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// BenchmarkSchedule runs a benchmark periodically, like a CronJob
+type BenchmarkSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BenchmarkScheduleSpec   `json:"spec,omitempty"`
+	Status BenchmarkScheduleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BenchmarkScheduleList contains a list of BenchmarkSchedule
+type BenchmarkScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BenchmarkSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BenchmarkSchedule{}, &BenchmarkScheduleList{})
+}
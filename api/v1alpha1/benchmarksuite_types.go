@@ -0,0 +1,142 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// BenchmarkSuiteStep is a benchmark executed as part of the suite. The
+// benchmark is defined either by an embedded spec or by referring to an
+// existing benchmark, whose spec is used as template.
+type BenchmarkSuiteStep struct {
+	// Name identifies the step within the suite. The benchmark of the
+	// step is created as <suite name>-<step name>.
+	// +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	Name string `json:"name"`
+
+	// Kind of the benchmark (e.g. Fio, Sysbench, Iperf3, Pgbench)
+	Kind string `json:"kind"`
+
+	// Spec is the spec of the benchmark, in the same format as in the
+	// custom resource of the given kind
+	// +optional
+	Spec *runtime.RawExtension `json:"spec,omitempty"`
+
+	// BenchmarkRef refers to a benchmark of the given kind in the namespace
+	// of the suite. Its spec is used as the spec of the step, the referred
+	// benchmark itself is not executed.
+	// +optional
+	BenchmarkRef *corev1.LocalObjectReference `json:"benchmarkRef,omitempty"`
+
+	// ContinueOnFailure lets the suite proceed with the next steps when
+	// the benchmark of this step fails. The suite is stopped by default.
+	// +optional
+	ContinueOnFailure bool `json:"continueOnFailure,omitempty"`
+}
+
+// BenchmarkSuiteSpec defines the benchmarks of the suite
+type BenchmarkSuiteSpec struct {
+	CommonSpec `json:",inline"`
+
+	// Steps are the benchmarks of the suite. They are started in the
+	// given order.
+	// +kubebuilder:validation:MinItems=1
+	Steps []BenchmarkSuiteStep `json:"steps"`
+
+	// Parallelism is the maximum number of steps executed at the same
+	// time. Defaults to 1, which runs the steps serially.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Parallelism *int32 `json:"parallelism,omitempty"`
+}
+
+// BenchmarkSuiteStepStatus describes the state of a step of the suite
+type BenchmarkSuiteStepStatus struct {
+	// Name of the step
+	Name string `json:"name"`
+
+	// Kind of the benchmark of the step
+	Kind string `json:"kind"`
+
+	ChildBenchmarkStatus `json:",inline"`
+
+	// StartTime is the time when the benchmark of the step has started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time when the benchmark of the step has finished
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Results are the parsed results of the benchmark of the step
+	// +optional
+	Results *BenchmarkResults `json:"results,omitempty"`
+}
+
+// BenchmarkSuiteStatus describes the state of the suite and its steps.
+// The metrics of the steps are aggregated into Results, prefixed with
+// the name of the step (e.g. fio.read.iops).
+type BenchmarkSuiteStatus struct {
+	BenchmarkStatus `json:",inline"`
+
+	// Steps contains the state of the steps, in the order of the spec.
+	// Steps which were not started because the suite was stopped are
+	// Cancelled.
+	// +optional
+	Steps []BenchmarkSuiteStepStatus `json:"steps,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// BenchmarkSuite runs an ordered set of benchmarks as one unit
+type BenchmarkSuite struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BenchmarkSuiteSpec   `json:"spec,omitempty"`
+	Status BenchmarkSuiteStatus `json:"status,omitempty"`
+}
+
+// GetBenchmarkStatus returns the status of the suite
+func (cr *BenchmarkSuite) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status.BenchmarkStatus
+}
+
+// GetCommonSpec returns the common settings of the suite
+func (cr *BenchmarkSuite) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
+}
+
+// +kubebuilder:object:root=true
+
+// BenchmarkSuiteList contains a list of BenchmarkSuite
+type BenchmarkSuiteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BenchmarkSuite `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BenchmarkSuite{}, &BenchmarkSuiteList{})
+}
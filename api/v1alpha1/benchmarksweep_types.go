@@ -0,0 +1,153 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SweepRange generates the integer values of a parameter from From to To
+// (inclusive), either by adding Step or by multiplying with Factor
+// (e.g. from 1 to 64 with factor 2 yields 1, 2, 4, ..., 64)
+type SweepRange struct {
+	// From is the first value of the range
+	From int64 `json:"from"`
+
+	// To is the last value of the range, it is only included if it is
+	// reached by the steps
+	To int64 `json:"to"`
+
+	// Step is added to the value to get the next value. Defaults to 1
+	// unless Factor is given.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Step int64 `json:"step,omitempty"`
+
+	// Factor multiplies the value to get the next value
+	// +kubebuilder:validation:Minimum=2
+	// +optional
+	Factor int64 `json:"factor,omitempty"`
+}
+
+// SweepParameter is a parameter of the sweep. Its values are written to
+// the field of the spec at Path, and are substituted into the $(name)
+// references in the string fields of the spec (e.g. an options string
+// such as "--threads=$(threads)").
+type SweepParameter struct {
+	// Name of the parameter, used as key in the results of the sweep
+	// +kubebuilder:validation:Pattern=^[a-zA-Z][-_.a-zA-Z0-9]*$
+	Name string `json:"name"`
+
+	// Path is the JSON path of the field of the spec to set to the values
+	// of the parameter (e.g. .numConnections or .podConfig.resources.limits.cpu).
+	// The values are converted to the type of the field in the base spec.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Values are the values of the parameter
+	// +optional
+	Values []string `json:"values,omitempty"`
+
+	// Range generates the values of the parameter
+	// +optional
+	Range *SweepRange `json:"range,omitempty"`
+}
+
+// BenchmarkSweepSpec defines the base spec and the parameters of the sweep
+type BenchmarkSweepSpec struct {
+	CommonSpec `json:",inline"`
+
+	// Template is the base benchmark of the sweep
+	Template BenchmarkTemplate `json:"template"`
+
+	// Parameters are varied over the runs of the sweep. A benchmark is
+	// created for each combination of their values, the values of the
+	// first parameter changing the slowest.
+	// +kubebuilder:validation:MinItems=1
+	Parameters []SweepParameter `json:"parameters"`
+
+	// Parallelism is the maximum number of benchmarks executed at the
+	// same time. Defaults to 1, which runs the benchmarks serially.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Parallelism *int32 `json:"parallelism,omitempty"`
+}
+
+// BenchmarkSweepRun describes a benchmark of the sweep: a row in the
+// results table of the sweep
+type BenchmarkSweepRun struct {
+	// Parameters are the values of the parameters of the run, keyed by name
+	Parameters map[string]string `json:"parameters"`
+
+	ChildBenchmarkStatus `json:",inline"`
+
+	// Metrics are the summary values of the benchmark of the run
+	// +optional
+	Metrics []BenchmarkMetric `json:"metrics,omitempty"`
+}
+
+// BenchmarkSweepStatus describes the state of the sweep. Runs is the
+// results table of the sweep, with a row for each combination of the
+// parameter values.
+type BenchmarkSweepStatus struct {
+	BenchmarkStatus `json:",inline"`
+
+	// Runs contains the state and the metrics of the runs, in the order
+	// of the expansion of the parameters
+	// +optional
+	Runs []BenchmarkSweepRun `json:"runs,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Kind",type="string",JSONPath=".spec.template.kind"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// BenchmarkSweep runs a benchmark over the combinations of a set of
+// parameter values
+type BenchmarkSweep struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BenchmarkSweepSpec   `json:"spec,omitempty"`
+	Status BenchmarkSweepStatus `json:"status,omitempty"`
+}
+
+// GetBenchmarkStatus returns the status of the sweep
+func (cr *BenchmarkSweep) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status.BenchmarkStatus
+}
+
+// GetCommonSpec returns the common settings of the sweep
+func (cr *BenchmarkSweep) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
+}
+
+// +kubebuilder:object:root=true
+
+// BenchmarkSweepList contains a list of BenchmarkSweep
+type BenchmarkSweepList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BenchmarkSweep `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BenchmarkSweep{}, &BenchmarkSweepList{})
+}
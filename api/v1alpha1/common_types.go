@@ -0,0 +1,129 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// CleanupPolicy defines whether the objects created for the benchmark
+// (jobs, deployments, services, volumes, etc.) are deleted when the
+// benchmark finishes
+// +kubebuilder:validation:Enum=Retain;DeleteOnSuccess;DeleteAlways
+type CleanupPolicy string
+
+const (
+	// CleanupRetain keeps the objects until the benchmark is deleted
+	CleanupRetain CleanupPolicy = "Retain"
+	// CleanupDeleteOnSuccess deletes the objects of the succeeded benchmarks,
+	// while keeping them for the investigation of the failed ones
+	CleanupDeleteOnSuccess CleanupPolicy = "DeleteOnSuccess"
+	// CleanupDeleteAlways deletes the objects when the benchmark finishes
+	CleanupDeleteAlways CleanupPolicy = "DeleteAlways"
+)
+
+// CommonSpec contains the settings shared by all the benchmarks.
+// It is embedded into the spec of the benchmarks.
+type CommonSpec struct {
+	// Timeout limits the duration of the benchmark, measured from the
+	// start of the benchmark. Exceeding the timeout stops the benchmark
+	// and moves it to Failed phase. The jobs of the benchmark receive the
+	// remaining time as their active deadline.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Cancel stops the benchmark: the objects created for the benchmark
+	// are deleted and the benchmark is moved to Cancelled phase.
+	// +optional
+	Cancel bool `json:"cancel,omitempty"`
+
+	// CleanupPolicy defines whether the objects created for the benchmark
+	// are deleted when the benchmark finishes. The results of the benchmark
+	// are collected before the deletion. Defaults to Retain.
+	// +optional
+	CleanupPolicy CleanupPolicy `json:"cleanupPolicy,omitempty"`
+
+	// TTLSecondsAfterFinished is the time after which the finished benchmark
+	// (including the objects created for it) is deleted. The BenchmarkResult
+	// of the run is kept. If not set, the benchmark is kept until deleted.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// Repetitions is the number of measured runs of the benchmark. The
+	// runs are executed one after the other with freshly created objects,
+	// and the statistics of their metrics are computed. Defaults to 1.
+	// Benchmark suites and sweeps are not repeated.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Repetitions *int32 `json:"repetitions,omitempty"`
+
+	// WarmupRuns is the number of runs executed before the measured
+	// repetitions. Their results are kept, but are excluded from the statistics.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	WarmupRuns int32 `json:"warmupRuns,omitempty"`
+
+	// Baseline compares the results of the benchmark with a baseline when
+	// the benchmark succeeds, and reports the regressions
+	// +optional
+	Baseline *BaselineSpec `json:"baseline,omitempty"`
+
+	// Assertions are the expectations on the results of the benchmark. The
+	// benchmark only succeeds if all of them hold.
+	// +optional
+	Assertions []Assertion `json:"assertions,omitempty"`
+}
+
+// Runs returns the total number of runs of the benchmark, including the
+// warmup runs
+func (s *CommonSpec) Runs() int {
+	runs := 1
+	if s.Repetitions != nil {
+		runs = int(*s.Repetitions)
+	}
+	return runs + int(s.WarmupRuns)
+}
+
+// ShouldCleanup returns true if the objects created for the benchmark
+// finished in the given phase have to be deleted
+func (s *CommonSpec) ShouldCleanup(phase BenchmarkPhase) bool {
+	switch s.CleanupPolicy {
+	case CleanupDeleteAlways:
+		return true
+	case CleanupDeleteOnSuccess:
+		return phase == BenchmarkSucceeded
+	default:
+		return false
+	}
+}
+
+// BenchmarkTemplate describes the benchmarks created by the kinds which
+// run other benchmarks (e.g. schedules and sweeps)
+type BenchmarkTemplate struct {
+	// Kind of the benchmark (e.g. Fio, Sysbench, Iperf3, BenchmarkSuite)
+	Kind string `json:"kind"`
+
+	// Labels are added to the created benchmarks
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Spec is the spec of the benchmark, in the same format as in the
+	// custom resource of the given kind
+	Spec *runtime.RawExtension `json:"spec"`
+}
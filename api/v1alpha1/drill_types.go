@@ -24,6 +24,8 @@ import (
 // The benchmarkFile, and options is passed to drill as follows:
 // drill [OPTIONS] --benchmark <benchmarkFile>
 type DrillSpec struct {
+	CommonSpec `json:",inline"`
+
 	// Image defines the drill docker image used for the benchmark
 	Image ImageSpec `json:"image"`
 
@@ -48,8 +50,9 @@ type DrillSpec struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="Running",type="boolean",JSONPath=".status.running"
-// +kubebuilder:printcolumn:name="Completed",type="boolean",JSONPath=".status.completed"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Drill is the Schema for the drills API
 type Drill struct {
@@ -60,6 +63,16 @@ type Drill struct {
 	Status BenchmarkStatus `json:"status,omitempty"`
 }
 
+// GetBenchmarkStatus returns the status of the benchmark
+func (cr *Drill) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status
+}
+
+// GetCommonSpec returns the common settings of the benchmark
+func (cr *Drill) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
+}
+
 // +kubebuilder:object:root=true
 
 // DrillList contains a list of Drill
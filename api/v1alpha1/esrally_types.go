@@ -17,11 +17,14 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // EsRallySpec defines the desired state of EsRally
 type EsRallySpec struct {
+	CommonSpec `json:",inline"`
+
 	// Image defines the docker image used for the benchmark
 	// +optional
 	Image ImageSpec `json:"image,omitempty"`
@@ -51,6 +54,7 @@ type EsRallySpec struct {
 	Challenge *string `json:"challenge,omitempty"`
 
 	// Nodes defines the number of esrally clients to use. Default is 1
+	// +kubebuilder:validation:Minimum=1
 	// +optional
 	Nodes *int32 `json:"nodes,omitempty"`
 
@@ -75,7 +79,16 @@ type EsRallySecurity struct {
 // BasicAuth contains basic HTTP authentication credentials.
 type BasicAuth struct {
 	Username string `json:"username"`
-	Password string `json:"password"`
+
+	// Password is the password of the user.
+	// Deprecated: the password ends up in the pod spec, use PasswordSecretRef instead.
+	// +optional
+	Password string `json:"password,omitempty"`
+
+	// PasswordSecretRef selects the key of a Secret holding the password.
+	// Takes precedence over Password.
+	// +optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
 }
 
 type EsRallyVolConfig struct {
@@ -83,28 +96,29 @@ type EsRallyVolConfig struct {
 	StorageClass string `json:"storageClass"`
 }
 
-type EsRallyStatus struct {
-	// Running shows the state of execution
-	Running bool `json:"running"`
-	// Completed shows the state of completion
-	Completed bool `json:"completed"`
-	// Deployed shows the state of the StatefulSet needed for testing
-	Deployed bool `json:"deployed"`
-}
-
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="Deployed",type="boolean",JSONPath=".status.deployed"
-// +kubebuilder:printcolumn:name="Running",type="boolean",JSONPath=".status.running"
-// +kubebuilder:printcolumn:name="Completed",type="boolean",JSONPath=".status.completed"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // EsRally is the Schema for the esrallies API
 type EsRally struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec   EsRallySpec   `json:"spec,omitempty"`
-	Status EsRallyStatus `json:"status,omitempty"`
+	Spec   EsRallySpec     `json:"spec,omitempty"`
+	Status BenchmarkStatus `json:"status,omitempty"`
+}
+
+// GetBenchmarkStatus returns the status of the benchmark
+func (cr *EsRally) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status
+}
+
+// GetCommonSpec returns the common settings of the benchmark
+func (cr *EsRally) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
 }
 
 // +kubebuilder:object:root=true
@@ -22,6 +22,8 @@ import (
 
 // FioSpec defines the desired state of Fio
 type FioSpec struct {
+	CommonSpec `json:",inline"`
+
 	// Image defines the fio docker image used for the benchmark
 	Image ImageSpec `json:"image"`
 
@@ -41,6 +43,13 @@ type FioSpec struct {
 	// +optional
 	CmdLineArgs string `json:"cmdLineArgs,omitempty"`
 
+	// OutputFormat is passed to fio as --output-format, unless it is
+	// already present in CmdLineArgs. The results are parsed into the
+	// status of the CR when json or json+ is used. Defaults to json+.
+	// +kubebuilder:validation:Enum=json+;json;normal;terse
+	// +optional
+	OutputFormat string `json:"outputFormat,omitempty"`
+
 	// PodConfig contains the configuration for the benchmark pod, including
 	// pod labels and scheduling policies (affinity, toleration, node selector...)
 	// +optional
@@ -49,20 +58,127 @@ type FioSpec struct {
 	// Volume contains the configuration for the volume that the fio job should
 	// run on.
 	Volume VolumeSpec `json:"volume"`
+
+	// Workers enables the distributed mode of fio: the job files are executed
+	// by fio servers on the given number of nodes, driven by a single fio
+	// client, which merges their results. Each worker runs on the volume
+	// above, with its own PVC when the PVC is generated.
+	// +optional
+	Workers *FioWorkersSpec `json:"workers,omitempty"`
+}
+
+// FioWorkersSpec describes the fio servers of a distributed fio benchmark
+type FioWorkersSpec struct {
+	// Count is the number of workers. Each worker is placed on a different
+	// node, selected from the schedulable nodes matching NodeSelector.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=64
+	Count int32 `json:"count"`
+
+	// NodeSelector selects the nodes of the workers by their labels.
+	// All schedulable nodes are eligible if empty.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// PodConfig contains the configuration for the worker pods. The node
+	// of the workers is selected by the controller, therefore the node
+	// name cannot be set.
+	// +optional
+	PodConfig PodConfigurationSpec `json:"podConfig,omitempty"`
+}
+
+// FioDirectionResult contains the results of one I/O direction of a fio job
+type FioDirectionResult struct {
+	// IOPS is the average number of I/O operations per second
+	IOPS string `json:"iops"`
+
+	// Bandwidth is the average bandwidth in bytes per second
+	Bandwidth int64 `json:"bandwidth"`
+
+	// ClatP50 is the median completion latency in nanoseconds
+	ClatP50 int64 `json:"clatP50"`
+
+	// ClatP95 is the 95th percentile of completion latency in nanoseconds
+	ClatP95 int64 `json:"clatP95"`
+
+	// ClatP99 is the 99th percentile of completion latency in nanoseconds
+	ClatP99 int64 `json:"clatP99"`
+
+	// ClatP999 is the 99.9th percentile of completion latency in nanoseconds
+	ClatP999 int64 `json:"clatP999"`
+}
+
+// FioJobResult contains the results of a fio job
+type FioJobResult struct {
+	// Name of the fio job
+	Name string `json:"name"`
+
+	// Hostname is the worker which executed the job in distributed mode
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+
+	// Read contains the results of the read operations
+	// +optional
+	Read *FioDirectionResult `json:"read,omitempty"`
+
+	// Write contains the results of the write operations
+	// +optional
+	Write *FioDirectionResult `json:"write,omitempty"`
+
+	// Trim contains the results of the trim operations
+	// +optional
+	Trim *FioDirectionResult `json:"trim,omitempty"`
+}
+
+// FioResults contains the parsed JSON output of fio
+type FioResults struct {
+	// Version of fio that executed the benchmark
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Jobs contains the results per fio job, and per worker in
+	// distributed mode
+	Jobs []FioJobResult `json:"jobs"`
+
+	// AllClients contains the results of all the workers merged by the
+	// fio client in distributed mode
+	// +optional
+	AllClients *FioJobResult `json:"allClients,omitempty"`
+}
+
+// FioStatus describes the state of the fio benchmark
+type FioStatus struct {
+	BenchmarkStatus `json:",inline"`
+
+	// WorkerNodes contains the nodes of the workers in distributed mode.
+	// The nodes are selected on first entry and are kept for all the runs.
+	// +optional
+	WorkerNodes []string `json:"workerNodes,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="Running",type="boolean",JSONPath=".status.running"
-// +kubebuilder:printcolumn:name="Completed",type="boolean",JSONPath=".status.completed"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Fio is the Schema for the fios API
 type Fio struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec   FioSpec         `json:"spec,omitempty"`
-	Status BenchmarkStatus `json:"status,omitempty"`
+	Spec   FioSpec   `json:"spec,omitempty"`
+	Status FioStatus `json:"status,omitempty"`
+}
+
+// GetBenchmarkStatus returns the status of the benchmark
+func (cr *Fio) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status.BenchmarkStatus
+}
+
+// GetCommonSpec returns the common settings of the benchmark
+func (cr *Fio) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
 }
 
 // +kubebuilder:object:root=true
@@ -22,6 +22,8 @@ import (
 
 // IopingSpec defines the ioping benchmark run
 type IopingSpec struct {
+	CommonSpec `json:",inline"`
+
 	// Image defines the ioping docker image used for the benchmark
 	Image ImageSpec `json:"image"`
 
@@ -41,8 +43,9 @@ type IopingSpec struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="Running",type="boolean",JSONPath=".status.running"
-// +kubebuilder:printcolumn:name="Completed",type="boolean",JSONPath=".status.completed"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Ioping is the Schema for the iopings API
 type Ioping struct {
@@ -53,6 +56,16 @@ type Ioping struct {
 	Status BenchmarkStatus `json:"status,omitempty"`
 }
 
+// GetBenchmarkStatus returns the status of the benchmark
+func (cr *Ioping) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status
+}
+
+// GetCommonSpec returns the common settings of the benchmark
+func (cr *Ioping) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
+}
+
 // +kubebuilder:object:root=true
 
 // IopingList contains a list of Ioping
@@ -34,12 +34,30 @@ type Iperf3ConfigurationSpec struct {
 	// hosts network namespace is used. Default to false.
 	// +optional
 	HostNetwork bool `json:"hostNetwork,omitempty"`
+
+	// Replicas is the number of iperf3 server pods behind the server
+	// service. Only used in the server configuration. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Parallelism is the number of iperf3 clients started at the same
+	// time. As an iperf3 server serves one test at a time, each client
+	// is served by a separate server instance listening on its own port
+	// in every server pod. Only used in the client configuration.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=64
+	// +optional
+	Parallelism *int32 `json:"parallelism,omitempty"`
 }
 
 // Iperf3Spec defines the Iperf3 Benchmark Stone which
 // consist of server deployment with service definition
 // and client pod.
 type Iperf3Spec struct {
+	CommonSpec `json:",inline"`
+
 	// Image defines the iperf3 docker image used for the benchmark
 	Image ImageSpec `json:"image"`
 
@@ -55,12 +73,92 @@ type Iperf3Spec struct {
 	// If enabled the '--udp' parameter is added to iperf command line args
 	// +optional
 	UDP bool `json:"udp,omitempty"`
+
+	// JSON output is requested from the iperf3 client and the results
+	// are parsed into the status of the CR.
+	// If enabled the '--json' parameter is added to iperf command line args
+	// +optional
+	JSON bool `json:"json,omitempty"`
+}
+
+// ServerReplicas returns the number of iperf3 server pods
+func (s *Iperf3Spec) ServerReplicas() int32 {
+	if s.ServerConfiguration.Replicas == nil {
+		return 1
+	}
+	return *s.ServerConfiguration.Replicas
+}
+
+// ClientParallelism returns the number of iperf3 clients started at the
+// same time, which is also the number of server instances in each server pod
+func (s *Iperf3Spec) ClientParallelism() int32 {
+	if s.ClientConfiguration.Parallelism == nil {
+		return 1
+	}
+	return *s.ClientConfiguration.Parallelism
+}
+
+// Iperf3StreamResult contains the results of a single iperf3 stream
+type Iperf3StreamResult struct {
+	// Socket is the identifier of the stream
+	Socket int64 `json:"socket"`
+
+	// SenderBitsPerSecond is the throughput measured by the sender
+	// +optional
+	SenderBitsPerSecond int64 `json:"senderBitsPerSecond,omitempty"`
+
+	// ReceiverBitsPerSecond is the throughput measured by the receiver
+	// +optional
+	ReceiverBitsPerSecond int64 `json:"receiverBitsPerSecond,omitempty"`
+
+	// Retransmits is the number of TCP retransmits of the stream
+	// +optional
+	Retransmits *int64 `json:"retransmits,omitempty"`
+
+	// JitterMs is the UDP jitter in milliseconds
+	// +optional
+	JitterMs string `json:"jitterMs,omitempty"`
+
+	// LostPercent is the percentage of the lost UDP packets
+	// +optional
+	LostPercent string `json:"lostPercent,omitempty"`
+}
+
+// Iperf3Results contains the parsed JSON output of iperf3
+type Iperf3Results struct {
+	// Protocol used for the test (TCP or UDP)
+	Protocol string `json:"protocol"`
+
+	// Streams contains the results of the individual streams
+	// +optional
+	Streams []Iperf3StreamResult `json:"streams,omitempty"`
+
+	// Sum contains the summary of all the streams. The throughput and the
+	// retransmits of parallel clients are totalled, while their jitter and
+	// packet loss are averaged.
+	Sum Iperf3StreamResult `json:"sum"`
+
+	// Clients contains the summary of each client of a benchmark with
+	// parallel clients
+	// +optional
+	Clients []Iperf3StreamResult `json:"clients,omitempty"`
+
+	// LocalCPUPercent is the total CPU utilization of the client,
+	// averaged over parallel clients
+	// +optional
+	LocalCPUPercent string `json:"localCPUPercent,omitempty"`
+
+	// RemoteCPUPercent is the total CPU utilization of the server,
+	// averaged over parallel clients
+	// +optional
+	RemoteCPUPercent string `json:"remoteCPUPercent,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="Running",type="boolean",JSONPath=".status.running"
-// +kubebuilder:printcolumn:name="Completed",type="boolean",JSONPath=".status.completed"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Iperf3 is the Schema for the iperf3s API
 type Iperf3 struct {
@@ -71,6 +169,16 @@ type Iperf3 struct {
 	Status BenchmarkStatus `json:"status,omitempty"`
 }
 
+// GetBenchmarkStatus returns the status of the benchmark
+func (cr *Iperf3) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status
+}
+
+// GetCommonSpec returns the common settings of the benchmark
+func (cr *Iperf3) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
+}
+
 // +kubebuilder:object:root=true
 
 // Iperf3List contains a list of Iperf3
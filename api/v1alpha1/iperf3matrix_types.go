@@ -0,0 +1,153 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Iperf3NodePair is a pair of nodes measured by the iperf3 matrix
+type Iperf3NodePair struct {
+	// Server is the name of the node running the iperf3 server
+	Server string `json:"server"`
+
+	// Client is the name of the node running the iperf3 client
+	Client string `json:"client"`
+}
+
+// Iperf3MatrixSpec defines the iperf3 benchmark and the node pairs of
+// the matrix
+type Iperf3MatrixSpec struct {
+	CommonSpec `json:",inline"`
+
+	// Template is the iperf3 benchmark executed for each node pair. The
+	// node names of its server and client are set to the nodes of the
+	// pair, and its JSON output is always enabled. The objects of each
+	// pair are deleted when it finishes, unless its cleanupPolicy is set.
+	Template Iperf3Spec `json:"template"`
+
+	// NodeSelector selects the nodes of the matrix by their labels. All
+	// nodes are selected if empty. Unschedulable and not ready nodes are
+	// skipped. Every selected node is measured against every other one
+	// in both directions.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Pairs are the node pairs to measure instead of all the pairs of
+	// the selected nodes
+	// +optional
+	Pairs []Iperf3NodePair `json:"pairs,omitempty"`
+
+	// SlowTolerancePercent is the largest shortfall of the throughput of
+	// a pair from the median throughput of all pairs, in percent, before
+	// the pair is reported as slow. Defaults to 20.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	SlowTolerancePercent *int32 `json:"slowTolerancePercent,omitempty"`
+}
+
+// Iperf3MatrixPair describes the measurement of a node pair
+type Iperf3MatrixPair struct {
+	Iperf3NodePair       `json:",inline"`
+	ChildBenchmarkStatus `json:",inline"`
+
+	// Throughput is the throughput of the pair in bits/s, as measured by
+	// the receiver
+	// +optional
+	Throughput string `json:"throughput,omitempty"`
+
+	// Slow is true if the throughput of the pair falls short of the
+	// median of all pairs beyond the tolerance
+	// +optional
+	Slow bool `json:"slow,omitempty"`
+
+	// Metrics are the summary values of the benchmark of the pair
+	// +optional
+	Metrics []BenchmarkMetric `json:"metrics,omitempty"`
+}
+
+// Iperf3MatrixRow is a row of the throughput matrix: the throughputs
+// measured by the clients towards the server node of the row
+type Iperf3MatrixRow struct {
+	// Server is the name of the node running the iperf3 server
+	Server string `json:"server"`
+
+	// Throughput contains the throughput in bits/s measured from each of
+	// the client nodes, in the order of the nodes of the matrix. The
+	// values of the pairs not measured (e.g. the node itself) are empty.
+	Throughput []string `json:"throughput"`
+}
+
+// Iperf3MatrixStatus describes the state of the iperf3 matrix
+type Iperf3MatrixStatus struct {
+	BenchmarkStatus `json:",inline"`
+
+	// Nodes are the nodes of the matrix ordered by name, which are the
+	// columns of the throughput matrix
+	// +optional
+	Nodes []string `json:"nodes,omitempty"`
+
+	// Pairs contains the state and the metrics of the measured node
+	// pairs, in the order of their measurement
+	// +optional
+	Pairs []Iperf3MatrixPair `json:"pairs,omitempty"`
+
+	// Matrix is the throughput matrix of the nodes, with a row for each
+	// server node
+	// +optional
+	Matrix []Iperf3MatrixRow `json:"matrix,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Iperf3Matrix measures the network throughput between pairs of nodes
+// with iperf3, one pair after the other
+type Iperf3Matrix struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   Iperf3MatrixSpec   `json:"spec,omitempty"`
+	Status Iperf3MatrixStatus `json:"status,omitempty"`
+}
+
+// GetBenchmarkStatus returns the status of the iperf3 matrix
+func (cr *Iperf3Matrix) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status.BenchmarkStatus
+}
+
+// GetCommonSpec returns the common settings of the iperf3 matrix
+func (cr *Iperf3Matrix) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
+}
+
+// +kubebuilder:object:root=true
+
+// Iperf3MatrixList contains a list of Iperf3Matrix
+type Iperf3MatrixList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Iperf3Matrix `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Iperf3Matrix{}, &Iperf3MatrixList{})
+}
@@ -18,7 +18,10 @@ package v1alpha1
 
 import (
 	"errors"
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // PullPolicy controls how the docker images are downloaded
@@ -82,16 +85,55 @@ type VolumeSpec struct {
 	// claimName must be set to 'GENERATED'
 	// +optional
 	PersistentVolumeClaimSpec *corev1.PersistentVolumeClaimSpec `json:"persistentVolumeClaimSpec,omitempty"`
+
+	// StorageClassNames turns the benchmark into a comparison of the listed
+	// StorageClasses: a PVC is generated for each StorageClass, and the
+	// identical benchmark is run against each of them, one after the other.
+	// The PVCs are created from the PersistentVolumeClaimSpec with its
+	// storageClassName replaced, or request DefaultComparisonVolumeSize with
+	// ReadWriteOnce access mode if the PersistentVolumeClaimSpec is omitted.
+	// The VolumeSource.PersistentVolumeClaim's claimName must be set to 'GENERATED'.
+	// +optional
+	StorageClassNames []string `json:"storageClassNames,omitempty"`
 }
 
 // GeneratedPVC is the pre-defined name to be used as ClaimName
 // when the PVC is created on the fly for the benchmark.
 const GeneratedPVC = "GENERATED"
 
+// DefaultComparisonVolumeSize is the size of the PVCs generated for the
+// StorageClass comparison when the PersistentVolumeClaimSpec is omitted
+const DefaultComparisonVolumeSize = "10Gi"
+
+// IsStorageClassComparison returns true if the benchmark is run against
+// the PVCs of multiple StorageClasses
+func (v *VolumeSpec) IsStorageClassComparison() bool {
+	return len(v.StorageClassNames) > 0
+}
+
+// StorageClassClaimSpec returns the spec of the PVC generated for the
+// given StorageClass in the StorageClass comparison
+func (v *VolumeSpec) StorageClassClaimSpec(storageClass string) corev1.PersistentVolumeClaimSpec {
+	var spec corev1.PersistentVolumeClaimSpec
+	if v.PersistentVolumeClaimSpec != nil {
+		v.PersistentVolumeClaimSpec.DeepCopyInto(&spec)
+	} else {
+		spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+		spec.Resources.Requests = corev1.ResourceList{
+			corev1.ResourceStorage: resource.MustParse(DefaultComparisonVolumeSize),
+		}
+	}
+	spec.StorageClassName = &storageClass
+	return spec
+}
+
 // Validate method validates that the provided VolumeSpec meets the
 // requirements:
 // If PersistentVolumeClaimSpec is provided, then the VolumeSource's
-// PersistentVolumClaim's ClaimName should be set to GeneratedPVC
+// PersistentVolumClaim's ClaimName should be set to GeneratedPVC.
+// If StorageClassNames are provided, the VolumeSource must be a
+// PersistentVolumeClaim with GeneratedPVC ClaimName, and the names
+// must be unique.
 func (v *VolumeSpec) Validate() (ok bool, err error) {
 	if v.PersistentVolumeClaimSpec != nil {
 		if v.VolumeSource.PersistentVolumeClaim != nil &&
@@ -100,6 +142,23 @@ func (v *VolumeSpec) Validate() (ok bool, err error) {
 				"VolumeSource.PersistentVolumeClaim.ClaimName must be set to " + GeneratedPVC)
 		}
 	}
+	if v.IsStorageClassComparison() {
+		if v.VolumeSource.PersistentVolumeClaim == nil ||
+			v.VolumeSource.PersistentVolumeClaim.ClaimName != GeneratedPVC {
+			return false, errors.New("If StorageClassNames are defined, " +
+				"VolumeSource.PersistentVolumeClaim.ClaimName must be set to " + GeneratedPVC)
+		}
+		seen := map[string]bool{}
+		for _, name := range v.StorageClassNames {
+			if name == "" {
+				return false, errors.New("StorageClassNames must not contain empty names")
+			}
+			if seen[name] {
+				return false, fmt.Errorf("StorageClass %q is listed multiple times in StorageClassNames", name)
+			}
+			seen[name] = true
+		}
+	}
 	return true, nil
 }
 
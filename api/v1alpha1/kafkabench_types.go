@@ -17,11 +17,14 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // KafkaBenchSpec defines the desired state of KafkaBench
 type KafkaBenchSpec struct {
+	CommonSpec `json:",inline"`
+
 	// Image defines the kafka docker image used for the benchmark
 	Image ImageSpec `json:"image"`
 
@@ -43,6 +46,30 @@ type KafkaClusterInfo struct {
 
 	// List of Kafka Broker instances we to connect to
 	Brokers []string `json:"brokers"`
+
+	// SASL contains the credentials used to authenticate with the brokers
+	// +optional
+	SASL *KafkaSASLSpec `json:"sasl,omitempty"`
+}
+
+// KafkaSASLSpec contains the SASL credentials for the Kafka Brokers
+type KafkaSASLSpec struct {
+	// Mechanism is the SASL mechanism to use (default: PLAIN)
+	// +kubebuilder:validation:Enum=PLAIN;SCRAM-SHA-256;SCRAM-SHA-512
+	// +optional
+	Mechanism string `json:"mechanism,omitempty"`
+
+	// SecurityProtocol is the protocol used to communicate with the brokers
+	// (default: SASL_PLAINTEXT)
+	// +kubebuilder:validation:Enum=SASL_PLAINTEXT;SASL_SSL
+	// +optional
+	SecurityProtocol string `json:"securityProtocol,omitempty"`
+
+	// UsernameSecretRef selects the key of a Secret holding the user name
+	UsernameSecretRef corev1.SecretKeySelector `json:"usernameSecretRef"`
+
+	// PasswordSecretRef selects the key of a Secret holding the password
+	PasswordSecretRef corev1.SecretKeySelector `json:"passwordSecretRef"`
 }
 
 // TestSpec defines the specifications for the kafka tests
@@ -71,8 +98,9 @@ type KafkaTestSpec struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="Running",type="boolean",JSONPath=".status.running"
-// +kubebuilder:printcolumn:name="Completed",type="boolean",JSONPath=".status.completed"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // KafkaBench is the Schema for the kafkabenches API
 type KafkaBench struct {
@@ -83,6 +111,16 @@ type KafkaBench struct {
 	Status BenchmarkStatus `json:"status,omitempty"`
 }
 
+// GetBenchmarkStatus returns the status of the benchmark
+func (cr *KafkaBench) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status
+}
+
+// GetCommonSpec returns the common settings of the benchmark
+func (cr *KafkaBench) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
+}
+
 // +kubebuilder:object:root=true
 
 // KafkaBenchList contains a list of KafkaBench
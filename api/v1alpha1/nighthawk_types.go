@@ -41,6 +41,8 @@ type NighthawkServerConfigurationSpec struct {
 // consist of server deployment with service definition
 // and client pod.
 type NighthawkSpec struct {
+	CommonSpec `json:",inline"`
+
 	// Image defines the nighthawk docker image used for the benchmark
 	Image ImageSpec `json:"image"`
 
@@ -53,8 +55,9 @@ type NighthawkSpec struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="Running",type="boolean",JSONPath=".status.running"
-// +kubebuilder:printcolumn:name="Completed",type="boolean",JSONPath=".status.completed"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Nighthawk is the Schema for the nighthawks API
 type Nighthawk struct {
@@ -65,6 +68,16 @@ type Nighthawk struct {
 	Status BenchmarkStatus `json:"status,omitempty"`
 }
 
+// GetBenchmarkStatus returns the status of the benchmark
+func (cr *Nighthawk) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status
+}
+
+// GetCommonSpec returns the common settings of the benchmark
+func (cr *Nighthawk) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
+}
+
 // +kubebuilder:object:root=true
 
 // NighthawkList contains a list of Nighthawk
@@ -22,6 +22,8 @@ import (
 
 // OcpLogtestSpec defines the desired state of OcpLogtest
 type OcpLogtestSpec struct {
+	CommonSpec `json:",inline"`
+
 	// Image defines the docker image used for the benchmark
 	Image ImageSpec `json:"image"`
 
@@ -45,8 +47,9 @@ type OcpLogtestSpec struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="Running",type="boolean",JSONPath=".status.running"
-// +kubebuilder:printcolumn:name="Completed",type="boolean",JSONPath=".status.completed"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // OcpLogtest is the Schema for the ocplogtests API
 type OcpLogtest struct {
@@ -57,6 +60,16 @@ type OcpLogtest struct {
 	Status BenchmarkStatus `json:"status,omitempty"`
 }
 
+// GetBenchmarkStatus returns the status of the benchmark
+func (cr *OcpLogtest) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status
+}
+
+// GetCommonSpec returns the common settings of the benchmark
+func (cr *OcpLogtest) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
+}
+
 // +kubebuilder:object:root=true
 
 // OcpLogtestList contains a list of OcpLogtest
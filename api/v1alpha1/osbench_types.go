@@ -7,6 +7,8 @@ import (
 // OsbenchSpec contains the configuration parameters
 // with scheduling options for the osbench benchmark.
 type OsbenchSpec struct {
+	CommonSpec `json:",inline"`
+
 	// Image defines the osbench docker image used for the benchmark
 	Image ImageSpec `json:"image"`
 
@@ -25,8 +27,9 @@ type OsbenchSpec struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="Running",type="boolean",JSONPath=".status.running"
-// +kubebuilder:printcolumn:name="Completed",type="boolean",JSONPath=".status.completed"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Osbench is the Schema for the osbenches API
 type Osbench struct {
@@ -37,6 +40,16 @@ type Osbench struct {
 	Status BenchmarkStatus `json:"status,omitempty"`
 }
 
+// GetBenchmarkStatus returns the status of the benchmark
+func (cr *Osbench) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status
+}
+
+// GetCommonSpec returns the common settings of the benchmark
+func (cr *Osbench) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
+}
+
 // +kubebuilder:object:root=true
 
 // OsbenchList contains a list of Osbench
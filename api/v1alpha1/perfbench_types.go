@@ -6,6 +6,8 @@ import (
 
 // PerfbenchSpec defines the desired state of Perfbench
 type PerfbenchSpec struct {
+	CommonSpec `json:",inline"`
+
 	// Image defines the perfbench docker image used for the benchmark
 	Image ImageSpec `json:"image"`
 
@@ -20,8 +22,9 @@ type PerfbenchSpec struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="Running",type="boolean",JSONPath=".status.running"
-// +kubebuilder:printcolumn:name="Completed",type="boolean",JSONPath=".status.completed"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Perfbench is the Schema for the perfbenches API
 type Perfbench struct {
@@ -32,6 +35,16 @@ type Perfbench struct {
 	Status BenchmarkStatus `json:"status,omitempty"`
 }
 
+// GetBenchmarkStatus returns the status of the benchmark
+func (cr *Perfbench) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status
+}
+
+// GetCommonSpec returns the common settings of the benchmark
+func (cr *Perfbench) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
+}
+
 // +kubebuilder:object:root=true
 
 // PerfbenchList contains a list of Perfbench
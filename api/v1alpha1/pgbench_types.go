@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -31,8 +32,15 @@ type PostgresSpec struct {
 	// User is the PostgreSQL user name to connect as
 	User string `json:"user"`
 
-	// Password is to be used if the server demands password authentication
-	Password string `json:"password"`
+	// Password is to be used if the server demands password authentication.
+	// Deprecated: the password ends up in the pod spec, use PasswordSecretRef instead.
+	// +optional
+	Password string `json:"password,omitempty"`
+
+	// PasswordSecretRef selects the key of a Secret holding the password.
+	// Takes precedence over Password.
+	// +optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
 
 	// Database is name of the database
 	Database string `json:"database"`
@@ -40,6 +48,8 @@ type PostgresSpec struct {
 
 // PgbenchSpec describes a pgbench benchmark job
 type PgbenchSpec struct {
+	CommonSpec `json:",inline"`
+
 	// Image defines the docker image used for the benchmark
 	Image ImageSpec `json:"image"`
 
@@ -63,8 +73,9 @@ type PgbenchSpec struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="Running",type="boolean",JSONPath=".status.running"
-// +kubebuilder:printcolumn:name="Completed",type="boolean",JSONPath=".status.completed"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Pgbench is the Schema for the pgbenches API
 type Pgbench struct {
@@ -75,6 +86,16 @@ type Pgbench struct {
 	Status BenchmarkStatus `json:"status,omitempty"`
 }
 
+// GetBenchmarkStatus returns the status of the benchmark
+func (cr *Pgbench) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status
+}
+
+// GetCommonSpec returns the common settings of the benchmark
+func (cr *Pgbench) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
+}
+
 // +kubebuilder:object:root=true
 
 // PgbenchList contains a list of Pgbench
@@ -0,0 +1,156 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PVCLatencySpec defines the PVC provisioning benchmark
+type PVCLatencySpec struct {
+	CommonSpec `json:",inline"`
+
+	// Count is the number of PVCs (and consumer pods) created at once
+	// in each run of the benchmark. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Count *int32 `json:"count,omitempty"`
+
+	// StorageClassName is the StorageClass of the PVCs. It overrides the
+	// storageClassName of the PersistentVolumeClaimSpec. The default
+	// StorageClass of the cluster is used if both are empty.
+	// +optional
+	StorageClassName string `json:"storageClassName,omitempty"`
+
+	// PersistentVolumeClaimSpec is the template of the created PVCs.
+	// ReadWriteOnce PVCs of 1Gi are requested if omitted.
+	// +optional
+	PersistentVolumeClaimSpec *corev1.PersistentVolumeClaimSpec `json:"persistentVolumeClaimSpec,omitempty"`
+
+	// Image defines the docker image of the pods consuming the PVCs. The
+	// image must provide sh and sleep (e.g. busybox).
+	Image ImageSpec `json:"image"`
+
+	// PodConfig contains the configuration of the consumer pods, including
+	// pod labels and scheduling policies (affinity, toleration, node selector...)
+	// +optional
+	PodConfig PodConfigurationSpec `json:"podConfig,omitempty"`
+}
+
+// DefaultPVCLatencyCount is the number of PVCs created in each run,
+// when the count is not specified
+const DefaultPVCLatencyCount = 10
+
+// VolumeCount returns the number of PVCs created in each run
+func (s *PVCLatencySpec) VolumeCount() int {
+	if s.Count == nil {
+		return DefaultPVCLatencyCount
+	}
+	return int(*s.Count)
+}
+
+// PVCLatencyVolume contains the timeline of a PVC and its consumer pod
+// in the current run, as observed by the controller
+type PVCLatencyVolume struct {
+	// Name of the PVC and of its consumer pod
+	Name string `json:"name"`
+
+	// VolumeName is the name of the PersistentVolume bound to the PVC
+	// +optional
+	VolumeName string `json:"volumeName,omitempty"`
+
+	// CreationTime is the time when the PVC and its pod were created
+	// +optional
+	CreationTime *metav1.MicroTime `json:"creationTime,omitempty"`
+
+	// BoundTime is the time when the PVC was observed to be bound
+	// +optional
+	BoundTime *metav1.MicroTime `json:"boundTime,omitempty"`
+
+	// RunningTime is the time when the pod was running with the volume
+	// attached and mounted
+	// +optional
+	RunningTime *metav1.MicroTime `json:"runningTime,omitempty"`
+
+	// PodDeletionTime is the time when the deletion of the pod was requested
+	// +optional
+	PodDeletionTime *metav1.MicroTime `json:"podDeletionTime,omitempty"`
+
+	// DetachedTime is the time when the pod was observed to be gone and
+	// the volume to be detached from the node
+	// +optional
+	DetachedTime *metav1.MicroTime `json:"detachedTime,omitempty"`
+
+	// DeletionTime is the time when the deletion of the PVC was requested
+	// +optional
+	DeletionTime *metav1.MicroTime `json:"deletionTime,omitempty"`
+
+	// DeletedTime is the time when the PVC and its dynamically provisioned
+	// PersistentVolume were observed to be gone
+	// +optional
+	DeletedTime *metav1.MicroTime `json:"deletedTime,omitempty"`
+}
+
+// PVCLatencyStatus describes the state of the PVC provisioning benchmark
+type PVCLatencyStatus struct {
+	BenchmarkStatus `json:",inline"`
+
+	// Volumes contains the timeline of the PVCs of the current run
+	// +optional
+	Volumes []PVCLatencyVolume `json:"volumes,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// PVCLatency measures the provisioning, attach, detach and delete
+// latency of PVCs
+type PVCLatency struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PVCLatencySpec   `json:"spec,omitempty"`
+	Status PVCLatencyStatus `json:"status,omitempty"`
+}
+
+// GetBenchmarkStatus returns the status of the benchmark
+func (cr *PVCLatency) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status.BenchmarkStatus
+}
+
+// GetCommonSpec returns the common settings of the benchmark
+func (cr *PVCLatency) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
+}
+
+// +kubebuilder:object:root=true
+
+// PVCLatencyList contains a list of PVCLatency
+type PVCLatencyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PVCLatency `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PVCLatency{}, &PVCLatencyList{})
+}
@@ -39,6 +39,8 @@ type QperfConfigurationSpec struct {
 // consist of server deployment with service definition
 // and client pod.
 type QperfSpec struct {
+	CommonSpec `json:",inline"`
+
 	// Image defines the qperf docker image used for the benchmark
 	Image ImageSpec `json:"image"`
 
@@ -60,8 +62,9 @@ type QperfSpec struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="Running",type="boolean",JSONPath=".status.running"
-// +kubebuilder:printcolumn:name="Completed",type="boolean",JSONPath=".status.completed"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Qperf is the Schema for the qperves API
 type Qperf struct {
@@ -72,6 +75,16 @@ type Qperf struct {
 	Status BenchmarkStatus `json:"status,omitempty"`
 }
 
+// GetBenchmarkStatus returns the status of the benchmark
+func (cr *Qperf) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status
+}
+
+// GetCommonSpec returns the common settings of the benchmark
+func (cr *Qperf) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
+}
+
 // +kubebuilder:object:root=true
 
 // QperfList contains a list of Qperf
@@ -17,11 +17,14 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // S3BenchSpec defines the desired state of S3Bench
 type S3BenchSpec struct {
+	CommonSpec `json:",inline"`
+
 	// Image defines the warp docker image used for the benchmark
 	// +optional
 	Image ImageSpec `json:"image,omitempty"`
@@ -74,12 +77,26 @@ type S3BenchOptions struct {
 	// +optional
 	Insecure bool `json:"insecure,omitempty"`
 
+	// AccessKey is the S3 access key.
+	// Deprecated: the key ends up in the pod spec, use AccessKeySecretRef instead.
 	// +optional
 	AccessKey string `json:"accessKey,omitempty"`
 
+	// AccessKeySecretRef selects the key of a Secret holding the S3 access key.
+	// Takes precedence over AccessKey.
+	// +optional
+	AccessKeySecretRef *corev1.SecretKeySelector `json:"accessKeySecretRef,omitempty"`
+
+	// SecretKey is the S3 secret key.
+	// Deprecated: the key ends up in the pod spec, use SecretKeySecretRef instead.
 	// +optional
 	SecretKey string `json:"secretKey,omitempty"`
 
+	// SecretKeySecretRef selects the key of a Secret holding the S3 secret key.
+	// Takes precedence over SecretKey.
+	// +optional
+	SecretKeySecretRef *corev1.SecretKeySelector `json:"secretKeySecretRef,omitempty"`
+
 	// Tls defines if to use TLS (HTTPS) for transport (default: false)
 	// +optional
 	Tls bool `json:"tls,omitempty"`
@@ -213,8 +230,9 @@ type S3AutoTermOptions struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="Running",type="boolean",JSONPath=".status.running"
-// +kubebuilder:printcolumn:name="Completed",type="boolean",JSONPath=".status.completed"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // S3Bench is the Schema for the s3benches API
 type S3Bench struct {
@@ -225,6 +243,16 @@ type S3Bench struct {
 	Status BenchmarkStatus `json:"status,omitempty"`
 }
 
+// GetBenchmarkStatus returns the status of the benchmark
+func (cr *S3Bench) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status
+}
+
+// GetCommonSpec returns the common settings of the benchmark
+func (cr *S3Bench) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
+}
+
 // +kubebuilder:object:root=true
 
 // S3BenchList contains a list of S3Bench
@@ -25,6 +25,8 @@ import (
 // The options, testName and command parameters are passed
 // to the sysbench benchmarking application.
 type SysbenchSpec struct {
+	CommonSpec `json:",inline"`
+
 	// Image defines the sysbench docker image used for the benchmark
 	Image ImageSpec `json:"image"`
 
@@ -50,8 +52,9 @@ type SysbenchSpec struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="Running",type="boolean",JSONPath=".status.running"
-// +kubebuilder:printcolumn:name="Completed",type="boolean",JSONPath=".status.completed"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Sysbench is the Schema for the sysbenches API
 type Sysbench struct {
@@ -62,6 +65,16 @@ type Sysbench struct {
 	Status BenchmarkStatus `json:"status,omitempty"`
 }
 
+// GetBenchmarkStatus returns the status of the benchmark
+func (cr *Sysbench) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status
+}
+
+// GetCommonSpec returns the common settings of the benchmark
+func (cr *Sysbench) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
+}
+
 // +kubebuilder:object:root=true
 
 // SysbenchList contains a list of Sysbench
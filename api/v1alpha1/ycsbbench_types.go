@@ -17,11 +17,14 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // YcsbBenchSpec defines the desired state of YcsbBench
 type YcsbBenchSpec struct {
+	CommonSpec `json:",inline"`
+
 	// Image defines the docker image used for the benchmark
 	Image ImageSpec `json:"image"`
 
@@ -31,6 +34,11 @@ type YcsbBenchSpec struct {
 	Options    YcsbBenchOptions  `json:"options,omitempty"`
 	Properties map[string]string `json:"properties"`
 
+	// SecretProperties are YCSB properties whose values are read from
+	// Secrets, such as database passwords. The keys are property names.
+	// +optional
+	SecretProperties map[string]corev1.SecretKeySelector `json:"secretProperties,omitempty"`
+
 	// PodConfig contains the configuration for the benchmark pod, including
 	// pod labels and scheduling policies (affinity, toleration, node selector...)
 	// +optional
@@ -44,8 +52,9 @@ type YcsbBenchOptions struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="Running",type="boolean",JSONPath=".status.running"
-// +kubebuilder:printcolumn:name="Completed",type="boolean",JSONPath=".status.completed"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".status.duration"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // YcsbBench is the Schema for the ycsbbenches API
 type YcsbBench struct {
@@ -56,6 +65,16 @@ type YcsbBench struct {
 	Status BenchmarkStatus `json:"status,omitempty"`
 }
 
+// GetBenchmarkStatus returns the status of the benchmark
+func (cr *YcsbBench) GetBenchmarkStatus() *BenchmarkStatus {
+	return &cr.Status
+}
+
+// GetCommonSpec returns the common settings of the benchmark
+func (cr *YcsbBench) GetCommonSpec() *CommonSpec {
+	return &cr.Spec.CommonSpec
+}
+
 // +kubebuilder:object:root=true
 
 // YcsbBenchList contains a list of YcsbBench
@@ -22,144 +22,146 @@ limitations under the License.
 package v1alpha1
 
 import (
-	"k8s.io/api/core/v1"
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BasicAuth) DeepCopyInto(out *BasicAuth) {
+func (in *Assertion) DeepCopyInto(out *Assertion) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BasicAuth.
-func (in *BasicAuth) DeepCopy() *BasicAuth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Assertion.
+func (in *Assertion) DeepCopy() *Assertion {
 	if in == nil {
 		return nil
 	}
-	out := new(BasicAuth)
+	out := new(Assertion)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BenchmarkStatus) DeepCopyInto(out *BenchmarkStatus) {
+func (in *AssertionResult) DeepCopyInto(out *AssertionResult) {
 	*out = *in
+	out.Assertion = in.Assertion
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkStatus.
-func (in *BenchmarkStatus) DeepCopy() *BenchmarkStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssertionResult.
+func (in *AssertionResult) DeepCopy() *AssertionResult {
 	if in == nil {
 		return nil
 	}
-	out := new(BenchmarkStatus)
+	out := new(AssertionResult)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Drill) DeepCopyInto(out *Drill) {
+func (in *BaselineComparison) DeepCopyInto(out *BaselineComparison) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]MetricComparison, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Drill.
-func (in *Drill) DeepCopy() *Drill {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaselineComparison.
+func (in *BaselineComparison) DeepCopy() *BaselineComparison {
 	if in == nil {
 		return nil
 	}
-	out := new(Drill)
+	out := new(BaselineComparison)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Drill) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DrillList) DeepCopyInto(out *DrillList) {
+func (in *BaselineSpec) DeepCopyInto(out *BaselineSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	out.ListMeta = in.ListMeta
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Drill, len(*in))
+	if in.TolerancePercent != nil {
+		in, out := &in.TolerancePercent, &out.TolerancePercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]MetricTolerance, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrillList.
-func (in *DrillList) DeepCopy() *DrillList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaselineSpec.
+func (in *BaselineSpec) DeepCopy() *BaselineSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DrillList)
+	out := new(BaselineSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DrillList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BasicAuth) DeepCopyInto(out *BasicAuth) {
+	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
 	}
-	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BasicAuth.
+func (in *BasicAuth) DeepCopy() *BasicAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(BasicAuth)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DrillSpec) DeepCopyInto(out *DrillSpec) {
+func (in *BenchmarkCondition) DeepCopyInto(out *BenchmarkCondition) {
 	*out = *in
-	out.Image = in.Image
-	if in.BenchmarksVolume != nil {
-		in, out := &in.BenchmarksVolume, &out.BenchmarksVolume
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	in.PodConfig.DeepCopyInto(&out.PodConfig)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrillSpec.
-func (in *DrillSpec) DeepCopy() *DrillSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkCondition.
+func (in *BenchmarkCondition) DeepCopy() *BenchmarkCondition {
 	if in == nil {
 		return nil
 	}
-	out := new(DrillSpec)
+	out := new(BenchmarkCondition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EsRally) DeepCopyInto(out *EsRally) {
+func (in *BenchmarkFanout) DeepCopyInto(out *BenchmarkFanout) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EsRally.
-func (in *EsRally) DeepCopy() *EsRally {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkFanout.
+func (in *BenchmarkFanout) DeepCopy() *BenchmarkFanout {
 	if in == nil {
 		return nil
 	}
-	out := new(EsRally)
+	out := new(BenchmarkFanout)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *EsRally) DeepCopyObject() runtime.Object {
+func (in *BenchmarkFanout) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -167,31 +169,31 @@ func (in *EsRally) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EsRallyList) DeepCopyInto(out *EsRallyList) {
+func (in *BenchmarkFanoutList) DeepCopyInto(out *BenchmarkFanoutList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]EsRally, len(*in))
+		*out = make([]BenchmarkFanout, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EsRallyList.
-func (in *EsRallyList) DeepCopy() *EsRallyList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkFanoutList.
+func (in *BenchmarkFanoutList) DeepCopy() *BenchmarkFanoutList {
 	if in == nil {
 		return nil
 	}
-	out := new(EsRallyList)
+	out := new(BenchmarkFanoutList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *EsRallyList) DeepCopyObject() runtime.Object {
+func (in *BenchmarkFanoutList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -199,125 +201,162 @@ func (in *EsRallyList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EsRallySecurity) DeepCopyInto(out *EsRallySecurity) {
+func (in *BenchmarkFanoutNode) DeepCopyInto(out *BenchmarkFanoutNode) {
 	*out = *in
-	if in.BasicAuth != nil {
-		in, out := &in.BasicAuth, &out.BasicAuth
-		*out = new(BasicAuth)
-		**out = **in
+	out.ChildBenchmarkStatus = in.ChildBenchmarkStatus
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]BenchmarkMetric, len(*in))
+		copy(*out, *in)
+	}
+	if in.Outliers != nil {
+		in, out := &in.Outliers, &out.Outliers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EsRallySecurity.
-func (in *EsRallySecurity) DeepCopy() *EsRallySecurity {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkFanoutNode.
+func (in *BenchmarkFanoutNode) DeepCopy() *BenchmarkFanoutNode {
 	if in == nil {
 		return nil
 	}
-	out := new(EsRallySecurity)
+	out := new(BenchmarkFanoutNode)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EsRallySpec) DeepCopyInto(out *EsRallySpec) {
+func (in *BenchmarkFanoutSpec) DeepCopyInto(out *BenchmarkFanoutSpec) {
 	*out = *in
-	out.Image = in.Image
-	in.PodConfig.DeepCopyInto(&out.PodConfig)
-	if in.TrackRepository != nil {
-		in, out := &in.TrackRepository, &out.TrackRepository
-		*out = new(string)
-		**out = **in
-	}
-	if in.TrackParams != nil {
-		in, out := &in.TrackParams, &out.TrackParams
-		*out = new(map[string]string)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make(map[string]string, len(*in))
-			for key, val := range *in {
-				(*out)[key] = val
-			}
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
+	in.Template.DeepCopyInto(&out.Template)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
-	if in.Challenge != nil {
-		in, out := &in.Challenge, &out.Challenge
-		*out = new(string)
+	if in.Parallelism != nil {
+		in, out := &in.Parallelism, &out.Parallelism
+		*out = new(int32)
 		**out = **in
 	}
-	if in.Nodes != nil {
-		in, out := &in.Nodes, &out.Nodes
+	if in.OutlierTolerancePercent != nil {
+		in, out := &in.OutlierTolerancePercent, &out.OutlierTolerancePercent
 		*out = new(int32)
 		**out = **in
 	}
-	out.Persistence = in.Persistence
-	if in.Security != nil {
-		in, out := &in.Security, &out.Security
-		*out = new(EsRallySecurity)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkFanoutSpec.
+func (in *BenchmarkFanoutSpec) DeepCopy() *BenchmarkFanoutSpec {
+	if in == nil {
+		return nil
 	}
+	out := new(BenchmarkFanoutSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EsRallySpec.
-func (in *EsRallySpec) DeepCopy() *EsRallySpec {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkFanoutStatus) DeepCopyInto(out *BenchmarkFanoutStatus) {
+	*out = *in
+	in.BenchmarkStatus.DeepCopyInto(&out.BenchmarkStatus)
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]BenchmarkFanoutNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkFanoutStatus.
+func (in *BenchmarkFanoutStatus) DeepCopy() *BenchmarkFanoutStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(EsRallySpec)
+	out := new(BenchmarkFanoutStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EsRallyStatus) DeepCopyInto(out *EsRallyStatus) {
+func (in *BenchmarkIteration) DeepCopyInto(out *BenchmarkIteration) {
 	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]BenchmarkMetric, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EsRallyStatus.
-func (in *EsRallyStatus) DeepCopy() *EsRallyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkIteration.
+func (in *BenchmarkIteration) DeepCopy() *BenchmarkIteration {
 	if in == nil {
 		return nil
 	}
-	out := new(EsRallyStatus)
+	out := new(BenchmarkIteration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EsRallyVolConfig) DeepCopyInto(out *EsRallyVolConfig) {
+func (in *BenchmarkMetric) DeepCopyInto(out *BenchmarkMetric) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EsRallyVolConfig.
-func (in *EsRallyVolConfig) DeepCopy() *EsRallyVolConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkMetric.
+func (in *BenchmarkMetric) DeepCopy() *BenchmarkMetric {
 	if in == nil {
 		return nil
 	}
-	out := new(EsRallyVolConfig)
+	out := new(BenchmarkMetric)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Fio) DeepCopyInto(out *Fio) {
+func (in *BenchmarkReference) DeepCopyInto(out *BenchmarkReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkReference.
+func (in *BenchmarkReference) DeepCopy() *BenchmarkReference {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkResult) DeepCopyInto(out *BenchmarkResult) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Fio.
-func (in *Fio) DeepCopy() *Fio {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkResult.
+func (in *BenchmarkResult) DeepCopy() *BenchmarkResult {
 	if in == nil {
 		return nil
 	}
-	out := new(Fio)
+	out := new(BenchmarkResult)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Fio) DeepCopyObject() runtime.Object {
+func (in *BenchmarkResult) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -325,31 +364,31 @@ func (in *Fio) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FioList) DeepCopyInto(out *FioList) {
+func (in *BenchmarkResultList) DeepCopyInto(out *BenchmarkResultList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Fio, len(*in))
+		*out = make([]BenchmarkResult, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FioList.
-func (in *FioList) DeepCopy() *FioList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkResultList.
+func (in *BenchmarkResultList) DeepCopy() *BenchmarkResultList {
 	if in == nil {
 		return nil
 	}
-	out := new(FioList)
+	out := new(BenchmarkResultList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FioList) DeepCopyObject() runtime.Object {
+func (in *BenchmarkResultList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -357,69 +396,134 @@ func (in *FioList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FioSpec) DeepCopyInto(out *FioSpec) {
+func (in *BenchmarkResultSpec) DeepCopyInto(out *BenchmarkResultSpec) {
 	*out = *in
-	out.Image = in.Image
-	if in.BuiltinJobFiles != nil {
-		in, out := &in.BuiltinJobFiles, &out.BuiltinJobFiles
-		*out = make([]string, len(*in))
+	out.Benchmark = in.Benchmark
+	if in.BenchmarkSpec != nil {
+		in, out := &in.BenchmarkSpec, &out.BenchmarkSpec
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]ContainerImage, len(*in))
 		copy(*out, *in)
 	}
-	if in.CustomJobFiles != nil {
-		in, out := &in.CustomJobFiles, &out.CustomJobFiles
+	if in.NodeNames != nil {
+		in, out := &in.NodeNames, &out.NodeNames
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	in.PodConfig.DeepCopyInto(&out.PodConfig)
-	in.Volume.DeepCopyInto(&out.Volume)
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = new(BenchmarkResults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Iterations != nil {
+		in, out := &in.Iterations, &out.Iterations
+		*out = make([]BenchmarkIteration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Comparison != nil {
+		in, out := &in.Comparison, &out.Comparison
+		*out = new(BaselineComparison)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Assertions != nil {
+		in, out := &in.Assertions, &out.Assertions
+		*out = make([]AssertionResult, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FioSpec.
-func (in *FioSpec) DeepCopy() *FioSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkResultSpec.
+func (in *BenchmarkResultSpec) DeepCopy() *BenchmarkResultSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(FioSpec)
+	out := new(BenchmarkResultSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageSpec) DeepCopyInto(out *ImageSpec) {
+func (in *BenchmarkResults) DeepCopyInto(out *BenchmarkResults) {
 	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]BenchmarkMetric, len(*in))
+		copy(*out, *in)
+	}
+	if in.Statistics != nil {
+		in, out := &in.Statistics, &out.Statistics
+		*out = make([]MetricStatistics, len(*in))
+		copy(*out, *in)
+	}
+	if in.Fio != nil {
+		in, out := &in.Fio, &out.Fio
+		*out = new(FioResults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Iperf3 != nil {
+		in, out := &in.Iperf3, &out.Iperf3
+		*out = new(Iperf3Results)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageClasses != nil {
+		in, out := &in.StorageClasses, &out.StorageClasses
+		*out = make([]StorageClassResults, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSpec.
-func (in *ImageSpec) DeepCopy() *ImageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkResults.
+func (in *BenchmarkResults) DeepCopy() *BenchmarkResults {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageSpec)
+	out := new(BenchmarkResults)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Ioping) DeepCopyInto(out *Ioping) {
+func (in *BenchmarkSchedule) DeepCopyInto(out *BenchmarkSchedule) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ioping.
-func (in *Ioping) DeepCopy() *Ioping {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkSchedule.
+func (in *BenchmarkSchedule) DeepCopy() *BenchmarkSchedule {
 	if in == nil {
 		return nil
 	}
-	out := new(Ioping)
+	out := new(BenchmarkSchedule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Ioping) DeepCopyObject() runtime.Object {
+func (in *BenchmarkSchedule) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -427,31 +531,31 @@ func (in *Ioping) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IopingList) DeepCopyInto(out *IopingList) {
+func (in *BenchmarkScheduleList) DeepCopyInto(out *BenchmarkScheduleList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Ioping, len(*in))
+		*out = make([]BenchmarkSchedule, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IopingList.
-func (in *IopingList) DeepCopy() *IopingList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkScheduleList.
+func (in *BenchmarkScheduleList) DeepCopy() *BenchmarkScheduleList {
 	if in == nil {
 		return nil
 	}
-	out := new(IopingList)
+	out := new(BenchmarkScheduleList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *IopingList) DeepCopyObject() runtime.Object {
+func (in *BenchmarkScheduleList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -459,101 +563,1379 @@ func (in *IopingList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IopingSpec) DeepCopyInto(out *IopingSpec) {
+func (in *BenchmarkScheduleSpec) DeepCopyInto(out *BenchmarkScheduleSpec) {
 	*out = *in
-	out.Image = in.Image
-	in.PodConfig.DeepCopyInto(&out.PodConfig)
-	in.Volume.DeepCopyInto(&out.Volume)
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.SuccessfulRunsHistoryLimit != nil {
+		in, out := &in.SuccessfulRunsHistoryLimit, &out.SuccessfulRunsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedRunsHistoryLimit != nil {
+		in, out := &in.FailedRunsHistoryLimit, &out.FailedRunsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IopingSpec.
-func (in *IopingSpec) DeepCopy() *IopingSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkScheduleSpec.
+func (in *BenchmarkScheduleSpec) DeepCopy() *BenchmarkScheduleSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(IopingSpec)
+	out := new(BenchmarkScheduleSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Iperf3) DeepCopyInto(out *Iperf3) {
+func (in *BenchmarkScheduleStatus) DeepCopyInto(out *BenchmarkScheduleStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]ChildReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkScheduleStatus.
+func (in *BenchmarkScheduleStatus) DeepCopy() *BenchmarkScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkStatus) DeepCopyInto(out *BenchmarkStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]BenchmarkCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.PhaseTransitionTime != nil {
+		in, out := &in.PhaseTransitionTime, &out.PhaseTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Children != nil {
+		in, out := &in.Children, &out.Children
+		*out = make([]ChildReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = new(BenchmarkResults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Iterations != nil {
+		in, out := &in.Iterations, &out.Iterations
+		*out = make([]BenchmarkIteration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Comparison != nil {
+		in, out := &in.Comparison, &out.Comparison
+		*out = new(BaselineComparison)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Assertions != nil {
+		in, out := &in.Assertions, &out.Assertions
+		*out = make([]AssertionResult, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkStatus.
+func (in *BenchmarkStatus) DeepCopy() *BenchmarkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkSuite) DeepCopyInto(out *BenchmarkSuite) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkSuite.
+func (in *BenchmarkSuite) DeepCopy() *BenchmarkSuite {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkSuite)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BenchmarkSuite) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkSuiteList) DeepCopyInto(out *BenchmarkSuiteList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BenchmarkSuite, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkSuiteList.
+func (in *BenchmarkSuiteList) DeepCopy() *BenchmarkSuiteList {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkSuiteList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BenchmarkSuiteList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkSuiteSpec) DeepCopyInto(out *BenchmarkSuiteSpec) {
+	*out = *in
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]BenchmarkSuiteStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Parallelism != nil {
+		in, out := &in.Parallelism, &out.Parallelism
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkSuiteSpec.
+func (in *BenchmarkSuiteSpec) DeepCopy() *BenchmarkSuiteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkSuiteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkSuiteStatus) DeepCopyInto(out *BenchmarkSuiteStatus) {
+	*out = *in
+	in.BenchmarkStatus.DeepCopyInto(&out.BenchmarkStatus)
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]BenchmarkSuiteStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkSuiteStatus.
+func (in *BenchmarkSuiteStatus) DeepCopy() *BenchmarkSuiteStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkSuiteStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkSuiteStep) DeepCopyInto(out *BenchmarkSuiteStep) {
+	*out = *in
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BenchmarkRef != nil {
+		in, out := &in.BenchmarkRef, &out.BenchmarkRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkSuiteStep.
+func (in *BenchmarkSuiteStep) DeepCopy() *BenchmarkSuiteStep {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkSuiteStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkSuiteStepStatus) DeepCopyInto(out *BenchmarkSuiteStepStatus) {
+	*out = *in
+	out.ChildBenchmarkStatus = in.ChildBenchmarkStatus
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = new(BenchmarkResults)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkSuiteStepStatus.
+func (in *BenchmarkSuiteStepStatus) DeepCopy() *BenchmarkSuiteStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkSuiteStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkSweep) DeepCopyInto(out *BenchmarkSweep) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkSweep.
+func (in *BenchmarkSweep) DeepCopy() *BenchmarkSweep {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkSweep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BenchmarkSweep) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkSweepList) DeepCopyInto(out *BenchmarkSweepList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BenchmarkSweep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkSweepList.
+func (in *BenchmarkSweepList) DeepCopy() *BenchmarkSweepList {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkSweepList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BenchmarkSweepList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkSweepRun) DeepCopyInto(out *BenchmarkSweepRun) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.ChildBenchmarkStatus = in.ChildBenchmarkStatus
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]BenchmarkMetric, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkSweepRun.
+func (in *BenchmarkSweepRun) DeepCopy() *BenchmarkSweepRun {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkSweepRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkSweepSpec) DeepCopyInto(out *BenchmarkSweepSpec) {
+	*out = *in
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]SweepParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Parallelism != nil {
+		in, out := &in.Parallelism, &out.Parallelism
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkSweepSpec.
+func (in *BenchmarkSweepSpec) DeepCopy() *BenchmarkSweepSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkSweepSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkSweepStatus) DeepCopyInto(out *BenchmarkSweepStatus) {
+	*out = *in
+	in.BenchmarkStatus.DeepCopyInto(&out.BenchmarkStatus)
+	if in.Runs != nil {
+		in, out := &in.Runs, &out.Runs
+		*out = make([]BenchmarkSweepRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkSweepStatus.
+func (in *BenchmarkSweepStatus) DeepCopy() *BenchmarkSweepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkSweepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkTemplate) DeepCopyInto(out *BenchmarkTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkTemplate.
+func (in *BenchmarkTemplate) DeepCopy() *BenchmarkTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChildBenchmarkStatus) DeepCopyInto(out *ChildBenchmarkStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChildBenchmarkStatus.
+func (in *ChildBenchmarkStatus) DeepCopy() *ChildBenchmarkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChildBenchmarkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChildReference) DeepCopyInto(out *ChildReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChildReference.
+func (in *ChildReference) DeepCopy() *ChildReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ChildReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommonSpec) DeepCopyInto(out *CommonSpec) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Repetitions != nil {
+		in, out := &in.Repetitions, &out.Repetitions
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Baseline != nil {
+		in, out := &in.Baseline, &out.Baseline
+		*out = new(BaselineSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Assertions != nil {
+		in, out := &in.Assertions, &out.Assertions
+		*out = make([]Assertion, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonSpec.
+func (in *CommonSpec) DeepCopy() *CommonSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CommonSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerImage) DeepCopyInto(out *ContainerImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerImage.
+func (in *ContainerImage) DeepCopy() *ContainerImage {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Drill) DeepCopyInto(out *Drill) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Drill.
+func (in *Drill) DeepCopy() *Drill {
+	if in == nil {
+		return nil
+	}
+	out := new(Drill)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Drill) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrillList) DeepCopyInto(out *DrillList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Drill, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrillList.
+func (in *DrillList) DeepCopy() *DrillList {
+	if in == nil {
+		return nil
+	}
+	out := new(DrillList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DrillList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrillSpec) DeepCopyInto(out *DrillSpec) {
+	*out = *in
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
+	out.Image = in.Image
+	if in.BenchmarksVolume != nil {
+		in, out := &in.BenchmarksVolume, &out.BenchmarksVolume
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.PodConfig.DeepCopyInto(&out.PodConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrillSpec.
+func (in *DrillSpec) DeepCopy() *DrillSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DrillSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EsRally) DeepCopyInto(out *EsRally) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EsRally.
+func (in *EsRally) DeepCopy() *EsRally {
+	if in == nil {
+		return nil
+	}
+	out := new(EsRally)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EsRally) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EsRallyList) DeepCopyInto(out *EsRallyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EsRally, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EsRallyList.
+func (in *EsRallyList) DeepCopy() *EsRallyList {
+	if in == nil {
+		return nil
+	}
+	out := new(EsRallyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EsRallyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EsRallySecurity) DeepCopyInto(out *EsRallySecurity) {
+	*out = *in
+	if in.BasicAuth != nil {
+		in, out := &in.BasicAuth, &out.BasicAuth
+		*out = new(BasicAuth)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EsRallySecurity.
+func (in *EsRallySecurity) DeepCopy() *EsRallySecurity {
+	if in == nil {
+		return nil
+	}
+	out := new(EsRallySecurity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EsRallySpec) DeepCopyInto(out *EsRallySpec) {
+	*out = *in
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
+	out.Image = in.Image
+	in.PodConfig.DeepCopyInto(&out.PodConfig)
+	if in.TrackRepository != nil {
+		in, out := &in.TrackRepository, &out.TrackRepository
+		*out = new(string)
+		**out = **in
+	}
+	if in.TrackParams != nil {
+		in, out := &in.TrackParams, &out.TrackParams
+		*out = new(map[string]string)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make(map[string]string, len(*in))
+			for key, val := range *in {
+				(*out)[key] = val
+			}
+		}
+	}
+	if in.Challenge != nil {
+		in, out := &in.Challenge, &out.Challenge
+		*out = new(string)
+		**out = **in
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = new(int32)
+		**out = **in
+	}
+	out.Persistence = in.Persistence
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(EsRallySecurity)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EsRallySpec.
+func (in *EsRallySpec) DeepCopy() *EsRallySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EsRallySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EsRallyVolConfig) DeepCopyInto(out *EsRallyVolConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EsRallyVolConfig.
+func (in *EsRallyVolConfig) DeepCopy() *EsRallyVolConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EsRallyVolConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Fio) DeepCopyInto(out *Fio) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Fio.
+func (in *Fio) DeepCopy() *Fio {
+	if in == nil {
+		return nil
+	}
+	out := new(Fio)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Fio) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FioDirectionResult) DeepCopyInto(out *FioDirectionResult) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FioDirectionResult.
+func (in *FioDirectionResult) DeepCopy() *FioDirectionResult {
+	if in == nil {
+		return nil
+	}
+	out := new(FioDirectionResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FioJobResult) DeepCopyInto(out *FioJobResult) {
+	*out = *in
+	if in.Read != nil {
+		in, out := &in.Read, &out.Read
+		*out = new(FioDirectionResult)
+		**out = **in
+	}
+	if in.Write != nil {
+		in, out := &in.Write, &out.Write
+		*out = new(FioDirectionResult)
+		**out = **in
+	}
+	if in.Trim != nil {
+		in, out := &in.Trim, &out.Trim
+		*out = new(FioDirectionResult)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FioJobResult.
+func (in *FioJobResult) DeepCopy() *FioJobResult {
+	if in == nil {
+		return nil
+	}
+	out := new(FioJobResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FioList) DeepCopyInto(out *FioList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Fio, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FioList.
+func (in *FioList) DeepCopy() *FioList {
+	if in == nil {
+		return nil
+	}
+	out := new(FioList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FioList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FioResults) DeepCopyInto(out *FioResults) {
+	*out = *in
+	if in.Jobs != nil {
+		in, out := &in.Jobs, &out.Jobs
+		*out = make([]FioJobResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AllClients != nil {
+		in, out := &in.AllClients, &out.AllClients
+		*out = new(FioJobResult)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FioResults.
+func (in *FioResults) DeepCopy() *FioResults {
+	if in == nil {
+		return nil
+	}
+	out := new(FioResults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FioSpec) DeepCopyInto(out *FioSpec) {
+	*out = *in
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
+	out.Image = in.Image
+	if in.BuiltinJobFiles != nil {
+		in, out := &in.BuiltinJobFiles, &out.BuiltinJobFiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CustomJobFiles != nil {
+		in, out := &in.CustomJobFiles, &out.CustomJobFiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.PodConfig.DeepCopyInto(&out.PodConfig)
+	in.Volume.DeepCopyInto(&out.Volume)
+	if in.Workers != nil {
+		in, out := &in.Workers, &out.Workers
+		*out = new(FioWorkersSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FioSpec.
+func (in *FioSpec) DeepCopy() *FioSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FioSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FioStatus) DeepCopyInto(out *FioStatus) {
+	*out = *in
+	in.BenchmarkStatus.DeepCopyInto(&out.BenchmarkStatus)
+	if in.WorkerNodes != nil {
+		in, out := &in.WorkerNodes, &out.WorkerNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FioStatus.
+func (in *FioStatus) DeepCopy() *FioStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FioStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FioWorkersSpec) DeepCopyInto(out *FioWorkersSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.PodConfig.DeepCopyInto(&out.PodConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FioWorkersSpec.
+func (in *FioWorkersSpec) DeepCopy() *FioWorkersSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FioWorkersSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageSpec) DeepCopyInto(out *ImageSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSpec.
+func (in *ImageSpec) DeepCopy() *ImageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ioping) DeepCopyInto(out *Ioping) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ioping.
+func (in *Ioping) DeepCopy() *Ioping {
+	if in == nil {
+		return nil
+	}
+	out := new(Ioping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Ioping) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IopingList) DeepCopyInto(out *IopingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Ioping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IopingList.
+func (in *IopingList) DeepCopy() *IopingList {
+	if in == nil {
+		return nil
+	}
+	out := new(IopingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IopingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IopingSpec) DeepCopyInto(out *IopingSpec) {
+	*out = *in
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
+	out.Image = in.Image
+	in.PodConfig.DeepCopyInto(&out.PodConfig)
+	in.Volume.DeepCopyInto(&out.Volume)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IopingSpec.
+func (in *IopingSpec) DeepCopy() *IopingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IopingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Iperf3) DeepCopyInto(out *Iperf3) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Iperf3.
+func (in *Iperf3) DeepCopy() *Iperf3 {
+	if in == nil {
+		return nil
+	}
+	out := new(Iperf3)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Iperf3) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Iperf3ConfigurationSpec) DeepCopyInto(out *Iperf3ConfigurationSpec) {
+	*out = *in
+	in.PodConfigurationSpec.DeepCopyInto(&out.PodConfigurationSpec)
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Parallelism != nil {
+		in, out := &in.Parallelism, &out.Parallelism
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Iperf3ConfigurationSpec.
+func (in *Iperf3ConfigurationSpec) DeepCopy() *Iperf3ConfigurationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(Iperf3ConfigurationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Iperf3List) DeepCopyInto(out *Iperf3List) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Iperf3, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Iperf3List.
+func (in *Iperf3List) DeepCopy() *Iperf3List {
+	if in == nil {
+		return nil
+	}
+	out := new(Iperf3List)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Iperf3List) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Iperf3Matrix) DeepCopyInto(out *Iperf3Matrix) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Iperf3Matrix.
+func (in *Iperf3Matrix) DeepCopy() *Iperf3Matrix {
+	if in == nil {
+		return nil
+	}
+	out := new(Iperf3Matrix)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Iperf3Matrix) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Iperf3MatrixList) DeepCopyInto(out *Iperf3MatrixList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Iperf3Matrix, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Iperf3MatrixList.
+func (in *Iperf3MatrixList) DeepCopy() *Iperf3MatrixList {
+	if in == nil {
+		return nil
+	}
+	out := new(Iperf3MatrixList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Iperf3MatrixList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Iperf3MatrixPair) DeepCopyInto(out *Iperf3MatrixPair) {
+	*out = *in
+	out.Iperf3NodePair = in.Iperf3NodePair
+	out.ChildBenchmarkStatus = in.ChildBenchmarkStatus
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]BenchmarkMetric, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Iperf3MatrixPair.
+func (in *Iperf3MatrixPair) DeepCopy() *Iperf3MatrixPair {
+	if in == nil {
+		return nil
+	}
+	out := new(Iperf3MatrixPair)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Iperf3MatrixRow) DeepCopyInto(out *Iperf3MatrixRow) {
+	*out = *in
+	if in.Throughput != nil {
+		in, out := &in.Throughput, &out.Throughput
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Iperf3MatrixRow.
+func (in *Iperf3MatrixRow) DeepCopy() *Iperf3MatrixRow {
+	if in == nil {
+		return nil
+	}
+	out := new(Iperf3MatrixRow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Iperf3MatrixSpec) DeepCopyInto(out *Iperf3MatrixSpec) {
+	*out = *in
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
+	in.Template.DeepCopyInto(&out.Template)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Pairs != nil {
+		in, out := &in.Pairs, &out.Pairs
+		*out = make([]Iperf3NodePair, len(*in))
+		copy(*out, *in)
+	}
+	if in.SlowTolerancePercent != nil {
+		in, out := &in.SlowTolerancePercent, &out.SlowTolerancePercent
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Iperf3MatrixSpec.
+func (in *Iperf3MatrixSpec) DeepCopy() *Iperf3MatrixSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(Iperf3MatrixSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Iperf3MatrixStatus) DeepCopyInto(out *Iperf3MatrixStatus) {
+	*out = *in
+	in.BenchmarkStatus.DeepCopyInto(&out.BenchmarkStatus)
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pairs != nil {
+		in, out := &in.Pairs, &out.Pairs
+		*out = make([]Iperf3MatrixPair, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Matrix != nil {
+		in, out := &in.Matrix, &out.Matrix
+		*out = make([]Iperf3MatrixRow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Iperf3.
-func (in *Iperf3) DeepCopy() *Iperf3 {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Iperf3MatrixStatus.
+func (in *Iperf3MatrixStatus) DeepCopy() *Iperf3MatrixStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Iperf3)
+	out := new(Iperf3MatrixStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Iperf3) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Iperf3ConfigurationSpec) DeepCopyInto(out *Iperf3ConfigurationSpec) {
+func (in *Iperf3NodePair) DeepCopyInto(out *Iperf3NodePair) {
 	*out = *in
-	in.PodConfigurationSpec.DeepCopyInto(&out.PodConfigurationSpec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Iperf3ConfigurationSpec.
-func (in *Iperf3ConfigurationSpec) DeepCopy() *Iperf3ConfigurationSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Iperf3NodePair.
+func (in *Iperf3NodePair) DeepCopy() *Iperf3NodePair {
 	if in == nil {
 		return nil
 	}
-	out := new(Iperf3ConfigurationSpec)
+	out := new(Iperf3NodePair)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Iperf3List) DeepCopyInto(out *Iperf3List) {
+func (in *Iperf3Results) DeepCopyInto(out *Iperf3Results) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	out.ListMeta = in.ListMeta
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Iperf3, len(*in))
+	if in.Streams != nil {
+		in, out := &in.Streams, &out.Streams
+		*out = make([]Iperf3StreamResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Sum.DeepCopyInto(&out.Sum)
+	if in.Clients != nil {
+		in, out := &in.Clients, &out.Clients
+		*out = make([]Iperf3StreamResult, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Iperf3List.
-func (in *Iperf3List) DeepCopy() *Iperf3List {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Iperf3Results.
+func (in *Iperf3Results) DeepCopy() *Iperf3Results {
 	if in == nil {
 		return nil
 	}
-	out := new(Iperf3List)
+	out := new(Iperf3Results)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Iperf3List) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Iperf3Spec) DeepCopyInto(out *Iperf3Spec) {
 	*out = *in
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
 	out.Image = in.Image
 	in.ServerConfiguration.DeepCopyInto(&out.ServerConfiguration)
 	in.ClientConfiguration.DeepCopyInto(&out.ClientConfiguration)
@@ -569,13 +1951,33 @@ func (in *Iperf3Spec) DeepCopy() *Iperf3Spec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Iperf3StreamResult) DeepCopyInto(out *Iperf3StreamResult) {
+	*out = *in
+	if in.Retransmits != nil {
+		in, out := &in.Retransmits, &out.Retransmits
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Iperf3StreamResult.
+func (in *Iperf3StreamResult) DeepCopy() *Iperf3StreamResult {
+	if in == nil {
+		return nil
+	}
+	out := new(Iperf3StreamResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaBench) DeepCopyInto(out *KafkaBench) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaBench.
@@ -631,6 +2033,7 @@ func (in *KafkaBenchList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaBenchSpec) DeepCopyInto(out *KafkaBenchSpec) {
 	*out = *in
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
 	out.Image = in.Image
 	in.PodConfig.DeepCopyInto(&out.PodConfig)
 	in.KafkaClusterInfo.DeepCopyInto(&out.KafkaClusterInfo)
@@ -666,6 +2069,11 @@ func (in *KafkaClusterInfo) DeepCopyInto(out *KafkaClusterInfo) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.SASL != nil {
+		in, out := &in.SASL, &out.SASL
+		*out = new(KafkaSASLSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaClusterInfo.
@@ -678,6 +2086,23 @@ func (in *KafkaClusterInfo) DeepCopy() *KafkaClusterInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaSASLSpec) DeepCopyInto(out *KafkaSASLSpec) {
+	*out = *in
+	in.UsernameSecretRef.DeepCopyInto(&out.UsernameSecretRef)
+	in.PasswordSecretRef.DeepCopyInto(&out.PasswordSecretRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaSASLSpec.
+func (in *KafkaSASLSpec) DeepCopy() *KafkaSASLSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaSASLSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaTestSpec) DeepCopyInto(out *KafkaTestSpec) {
 	*out = *in
@@ -708,6 +2133,56 @@ func (in *KafkaTestSpec) DeepCopy() *KafkaTestSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricComparison) DeepCopyInto(out *MetricComparison) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricComparison.
+func (in *MetricComparison) DeepCopy() *MetricComparison {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricComparison)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricStatistics) DeepCopyInto(out *MetricStatistics) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricStatistics.
+func (in *MetricStatistics) DeepCopy() *MetricStatistics {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricStatistics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricTolerance) DeepCopyInto(out *MetricTolerance) {
+	*out = *in
+	if in.TolerancePercent != nil {
+		in, out := &in.TolerancePercent, &out.TolerancePercent
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricTolerance.
+func (in *MetricTolerance) DeepCopy() *MetricTolerance {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricTolerance)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MixedDistributionOptions) DeepCopyInto(out *MixedDistributionOptions) {
 	*out = *in
@@ -729,7 +2204,7 @@ func (in *Nighthawk) DeepCopyInto(out *Nighthawk) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Nighthawk.
@@ -834,6 +2309,7 @@ func (in *NighthawkServerConfigurationSpec) DeepCopy() *NighthawkServerConfigura
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NighthawkSpec) DeepCopyInto(out *NighthawkSpec) {
 	*out = *in
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
 	out.Image = in.Image
 	in.ServerConfiguration.DeepCopyInto(&out.ServerConfiguration)
 	in.ClientConfiguration.DeepCopyInto(&out.ClientConfiguration)
@@ -855,7 +2331,7 @@ func (in *OcpLogtest) DeepCopyInto(out *OcpLogtest) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OcpLogtest.
@@ -911,6 +2387,7 @@ func (in *OcpLogtestList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OcpLogtestSpec) DeepCopyInto(out *OcpLogtestSpec) {
 	*out = *in
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
 	out.Image = in.Image
 	in.PodConfig.DeepCopyInto(&out.PodConfig)
 }
@@ -931,7 +2408,7 @@ func (in *Osbench) DeepCopyInto(out *Osbench) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Osbench.
@@ -987,6 +2464,7 @@ func (in *OsbenchList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OsbenchSpec) DeepCopyInto(out *OsbenchSpec) {
 	*out = *in
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
 	out.Image = in.Image
 	in.PodConfig.DeepCopyInto(&out.PodConfig)
 }
@@ -1001,13 +2479,166 @@ func (in *OsbenchSpec) DeepCopy() *OsbenchSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCLatency) DeepCopyInto(out *PVCLatency) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCLatency.
+func (in *PVCLatency) DeepCopy() *PVCLatency {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCLatency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PVCLatency) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCLatencyList) DeepCopyInto(out *PVCLatencyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PVCLatency, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCLatencyList.
+func (in *PVCLatencyList) DeepCopy() *PVCLatencyList {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCLatencyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PVCLatencyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCLatencySpec) DeepCopyInto(out *PVCLatencySpec) {
+	*out = *in
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PersistentVolumeClaimSpec != nil {
+		in, out := &in.PersistentVolumeClaimSpec, &out.PersistentVolumeClaimSpec
+		*out = new(corev1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Image = in.Image
+	in.PodConfig.DeepCopyInto(&out.PodConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCLatencySpec.
+func (in *PVCLatencySpec) DeepCopy() *PVCLatencySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCLatencySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCLatencyStatus) DeepCopyInto(out *PVCLatencyStatus) {
+	*out = *in
+	in.BenchmarkStatus.DeepCopyInto(&out.BenchmarkStatus)
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]PVCLatencyVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCLatencyStatus.
+func (in *PVCLatencyStatus) DeepCopy() *PVCLatencyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCLatencyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCLatencyVolume) DeepCopyInto(out *PVCLatencyVolume) {
+	*out = *in
+	if in.CreationTime != nil {
+		in, out := &in.CreationTime, &out.CreationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.BoundTime != nil {
+		in, out := &in.BoundTime, &out.BoundTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RunningTime != nil {
+		in, out := &in.RunningTime, &out.RunningTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PodDeletionTime != nil {
+		in, out := &in.PodDeletionTime, &out.PodDeletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.DetachedTime != nil {
+		in, out := &in.DetachedTime, &out.DetachedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.DeletionTime != nil {
+		in, out := &in.DeletionTime, &out.DeletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.DeletedTime != nil {
+		in, out := &in.DeletedTime, &out.DeletedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCLatencyVolume.
+func (in *PVCLatencyVolume) DeepCopy() *PVCLatencyVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCLatencyVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Perfbench) DeepCopyInto(out *Perfbench) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Perfbench.
@@ -1063,6 +2694,7 @@ func (in *PerfbenchList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PerfbenchSpec) DeepCopyInto(out *PerfbenchSpec) {
 	*out = *in
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
 	out.Image = in.Image
 	if in.CmdLineArgs != nil {
 		in, out := &in.CmdLineArgs, &out.CmdLineArgs
@@ -1088,7 +2720,7 @@ func (in *Pgbench) DeepCopyInto(out *Pgbench) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pgbench.
@@ -1144,8 +2776,9 @@ func (in *PgbenchList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PgbenchSpec) DeepCopyInto(out *PgbenchSpec) {
 	*out = *in
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
 	out.Image = in.Image
-	out.Postgres = in.Postgres
+	in.Postgres.DeepCopyInto(&out.Postgres)
 	in.PodConfig.DeepCopyInto(&out.PodConfig)
 }
 
@@ -1195,12 +2828,12 @@ func (in *PodSchedulingSpec) DeepCopyInto(out *PodSchedulingSpec) {
 	*out = *in
 	if in.Affinity != nil {
 		in, out := &in.Affinity, &out.Affinity
-		*out = new(v1.Affinity)
+		*out = new(corev1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
+		*out = make([]corev1.Toleration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1227,6 +2860,11 @@ func (in *PodSchedulingSpec) DeepCopy() *PodSchedulingSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresSpec) DeepCopyInto(out *PostgresSpec) {
 	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresSpec.
@@ -1245,7 +2883,7 @@ func (in *Qperf) DeepCopyInto(out *Qperf) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Qperf.
@@ -1317,6 +2955,7 @@ func (in *QperfList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *QperfSpec) DeepCopyInto(out *QperfSpec) {
 	*out = *in
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
 	out.Image = in.Image
 	if in.Tests != nil {
 		in, out := &in.Tests, &out.Tests
@@ -1373,7 +3012,7 @@ func (in *S3Bench) DeepCopyInto(out *S3Bench) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Bench.
@@ -1429,6 +3068,16 @@ func (in *S3BenchList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *S3BenchOptions) DeepCopyInto(out *S3BenchOptions) {
 	*out = *in
+	if in.AccessKeySecretRef != nil {
+		in, out := &in.AccessKeySecretRef, &out.AccessKeySecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeySecretRef != nil {
+		in, out := &in.SecretKeySecretRef, &out.SecretKeySecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BenchOptions.
@@ -1444,9 +3093,10 @@ func (in *S3BenchOptions) DeepCopy() *S3BenchOptions {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *S3BenchSpec) DeepCopyInto(out *S3BenchSpec) {
 	*out = *in
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
 	out.Image = in.Image
 	in.PodConfig.DeepCopyInto(&out.PodConfig)
-	out.S3BenchOptions = in.S3BenchOptions
+	in.S3BenchOptions.DeepCopyInto(&out.S3BenchOptions)
 	out.S3ObjectOptions = in.S3ObjectOptions
 	out.S3AutoTermOptions = in.S3AutoTermOptions
 	out.S3AnalysisOptions = in.S3AnalysisOptions
@@ -1478,13 +3128,78 @@ func (in *S3ObjectOptions) DeepCopy() *S3ObjectOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageClassResults) DeepCopyInto(out *StorageClassResults) {
+	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]BenchmarkMetric, len(*in))
+		copy(*out, *in)
+	}
+	if in.Fio != nil {
+		in, out := &in.Fio, &out.Fio
+		*out = new(FioResults)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageClassResults.
+func (in *StorageClassResults) DeepCopy() *StorageClassResults {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageClassResults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SweepParameter) DeepCopyInto(out *SweepParameter) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Range != nil {
+		in, out := &in.Range, &out.Range
+		*out = new(SweepRange)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SweepParameter.
+func (in *SweepParameter) DeepCopy() *SweepParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(SweepParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SweepRange) DeepCopyInto(out *SweepRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SweepRange.
+func (in *SweepRange) DeepCopy() *SweepRange {
+	if in == nil {
+		return nil
+	}
+	out := new(SweepRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Sysbench) DeepCopyInto(out *Sysbench) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Sysbench.
@@ -1540,6 +3255,7 @@ func (in *SysbenchList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SysbenchSpec) DeepCopyInto(out *SysbenchSpec) {
 	*out = *in
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
 	out.Image = in.Image
 	in.PodConfig.DeepCopyInto(&out.PodConfig)
 }
@@ -1560,9 +3276,14 @@ func (in *VolumeSpec) DeepCopyInto(out *VolumeSpec) {
 	in.VolumeSource.DeepCopyInto(&out.VolumeSource)
 	if in.PersistentVolumeClaimSpec != nil {
 		in, out := &in.PersistentVolumeClaimSpec, &out.PersistentVolumeClaimSpec
-		*out = new(v1.PersistentVolumeClaimSpec)
+		*out = new(corev1.PersistentVolumeClaimSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.StorageClassNames != nil {
+		in, out := &in.StorageClassNames, &out.StorageClassNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSpec.
@@ -1581,7 +3302,7 @@ func (in *YcsbBench) DeepCopyInto(out *YcsbBench) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new YcsbBench.
@@ -1652,6 +3373,7 @@ func (in *YcsbBenchOptions) DeepCopy() *YcsbBenchOptions {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *YcsbBenchSpec) DeepCopyInto(out *YcsbBenchSpec) {
 	*out = *in
+	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
 	out.Image = in.Image
 	out.Options = in.Options
 	if in.Properties != nil {
@@ -1661,6 +3383,13 @@ func (in *YcsbBenchSpec) DeepCopyInto(out *YcsbBenchSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.SecretProperties != nil {
+		in, out := &in.SecretProperties, &out.SecretProperties
+		*out = make(map[string]corev1.SecretKeySelector, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	in.PodConfig.DeepCopyInto(&out.PodConfig)
 }
 
@@ -0,0 +1,143 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarkfanout
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/lifecycle"
+	"github.com/xridge/kubestone/pkg/template"
+)
+
+// Reconciler provides fields from manager to reconciler
+type Reconciler struct {
+	K8S k8s.Access
+	Log logr.Logger
+}
+
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=drills;esrallies;fios;iopings;iperf3s;kafkabenches;nighthawks;ocplogtests;osbenches;perfbenches;pgbenches;pvclatencies;qperves;s3benches;sysbenches;ycsbbenches,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=benchmarkfanouts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=benchmarkfanouts/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=benchmarkfanouts/finalizers,verbs=update
+
+// Reconcile selects the nodes of the fan-out, creates a benchmark pinned
+// to each of them with limited parallelism, and collects their metrics
+// into the status of the fan-out
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+
+	var cr perfv1alpha1.BenchmarkFanout
+	if err := r.K8S.Client.Get(ctx, req.NamespacedName, &cr); err != nil {
+		return ctrl.Result{}, k8s.IgnoreNotFound(err)
+	}
+
+	// Run to one completion
+	if cr.Status.IsFinished() {
+		return ctrl.Result{}, nil
+	}
+
+	kinds := BenchmarkKinds(r.K8S.Scheme)
+	if valid, err := lifecycle.Validate(ctx, &r.K8S, &cr, func() (bool, error) {
+		return IsCrValid(&cr, kinds)
+	}); !valid || err != nil {
+		return ctrl.Result{}, err
+	}
+
+	original := cr.DeepCopy()
+
+	// The nodes are selected once, the nodes added later are not benchmarked
+	if len(cr.Status.Nodes) == 0 {
+		nodeNames, err := r.K8S.GetSchedulableNodes(ctx, cr.Spec.NodeSelector)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if len(nodeNames) == 0 {
+			return ctrl.Result{}, lifecycle.FailBenchmark(ctx, &r.K8S, &cr, k8s.NoMatchingNodes,
+				"no schedulable node matches the node selector")
+		}
+		if len(nodeNames) > MaxNodes {
+			return ctrl.Result{}, lifecycle.FailBenchmark(ctx, &r.K8S, &cr, k8s.NoMatchingNodes,
+				fmt.Sprintf("%d nodes match the node selector, the limit is %d", len(nodeNames), MaxNodes))
+		}
+		cr.Status.Nodes = make([]perfv1alpha1.BenchmarkFanoutNode, len(nodeNames))
+		for i, nodeName := range nodeNames {
+			cr.Status.Nodes[i] = perfv1alpha1.BenchmarkFanoutNode{NodeName: nodeName}
+		}
+	}
+
+	prototype := kinds[cr.Spec.Template.Kind]
+	statuses := make([]*perfv1alpha1.ChildBenchmarkStatus, len(cr.Status.Nodes))
+	for i := range cr.Status.Nodes {
+		statuses[i] = &cr.Status.Nodes[i].ChildBenchmarkStatus
+	}
+	children := lifecycle.Children{
+		Statuses:     statuses,
+		Parallelism:  cr.Spec.Parallelism,
+		Noun:         "nodes",
+		FailedReason: k8s.NodeFailed,
+		Describe: func(i int) string {
+			return fmt.Sprintf("node %s", cr.Status.Nodes[i].NodeName)
+		},
+		Prototype: func(i int) perfv1alpha1.Benchmark {
+			return prototype
+		},
+		New: func(ctx context.Context, i int) (perfv1alpha1.Benchmark, error) {
+			return NewBenchmark(&cr, i, prototype, cr.Status.Nodes[i].NodeName)
+		},
+		Sync: func(i int, benchmark perfv1alpha1.Benchmark) {
+			syncNodeStatus(&cr.Status.Nodes[i], benchmark)
+		},
+		Summarize: func(finished bool) {
+			if finished {
+				r.summarize(&cr)
+			}
+		},
+	}
+	return ctrl.Result{}, children.Reconcile(ctx, &r.K8S, &cr, original)
+}
+
+// summarize summarizes the results of the finished nodes. The outlier
+// nodes do not fail the fan-out, they are reported with a Warning event.
+func (r *Reconciler) summarize(cr *perfv1alpha1.BenchmarkFanout) {
+	var outliers []string
+	cr.Status.Results, outliers = Summarize(cr)
+	if len(outliers) > 0 {
+		_ = r.K8S.RecordEventf(cr, corev1.EventTypeWarning, k8s.Outliers,
+			"The metrics of nodes %s deviate from the median of the nodes", strings.Join(outliers, ", "))
+	}
+}
+
+// SetupWithManager registers the Reconciler with the provided manager.
+// The fan-out is reconciled whenever the benchmark of any of its nodes changes.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&perfv1alpha1.BenchmarkFanout{})
+
+	kinds := BenchmarkKinds(mgr.GetScheme())
+	for _, kind := range template.SortedKinds(kinds) {
+		builder = builder.Owns(kinds[kind])
+	}
+	return builder.Complete(lifecycle.NewReconciler(r, &r.K8S, &perfv1alpha1.BenchmarkFanout{}))
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarkfanout
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+)
+
+var _ = Describe("benchmark fan-out reconciler", func() {
+	var ctx context.Context
+	var cr *perfv1alpha1.BenchmarkFanout
+	var nodes []runtime.Object
+	var name types.NamespacedName
+	var reconciler *Reconciler
+	var recorder *record.FakeRecorder
+
+	newNode := func(name, pool string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"pool": pool}},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			}},
+		}
+	}
+
+	setup := func() {
+		scheme := runtime.NewScheme()
+		_ = k8sscheme.AddToScheme(scheme)
+		_ = perfv1alpha1.AddToScheme(scheme)
+		recorder = record.NewFakeRecorder(100)
+		reconciler = &Reconciler{K8S: k8s.Access{
+			Client:        fake.NewFakeClientWithScheme(scheme, append(nodes, cr.DeepCopy())...),
+			Scheme:        scheme,
+			EventRecorder: recorder,
+		}}
+	}
+
+	reconcile := func() {
+		_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	stored := func() *perfv1alpha1.BenchmarkFanout {
+		var fanout perfv1alpha1.BenchmarkFanout
+		Expect(reconciler.K8S.Client.Get(ctx, name, &fanout)).To(Succeed())
+		return &fanout
+	}
+
+	benchmarkName := func(index int) types.NamespacedName {
+		return types.NamespacedName{Namespace: name.Namespace, Name: fmt.Sprintf("%s-%d", name.Name, index)}
+	}
+
+	benchmarkExists := func(index int) bool {
+		var sysbench perfv1alpha1.Sysbench
+		err := reconciler.K8S.Client.Get(ctx, benchmarkName(index), &sysbench)
+		Expect(errors.IsNotFound(err) || err == nil).To(BeTrue())
+		return err == nil
+	}
+
+	finishNode := func(index int, phase perfv1alpha1.BenchmarkPhase, events float64) {
+		var sysbench perfv1alpha1.Sysbench
+		Expect(reconciler.K8S.Client.Get(ctx, benchmarkName(index), &sysbench)).To(Succeed())
+		sysbench.Status.SetPhase(phase, "", "")
+		sysbench.Status.Results = &perfv1alpha1.BenchmarkResults{}
+		sysbench.Status.Results.AddMetric("events", events, "events/s")
+		Expect(reconciler.K8S.Client.Status().Update(ctx, &sysbench)).To(Succeed())
+	}
+
+	events := func() []string {
+		var received []string
+		for len(recorder.Events) > 0 {
+			received = append(received, <-recorder.Events)
+		}
+		return received
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		name = types.NamespacedName{Namespace: "fanout", Name: "pool"}
+		nodes = []runtime.Object{
+			newNode("node-c", "storage"),
+			newNode("node-a", "storage"),
+			newNode("node-b", "storage"),
+			newNode("node-x", "compute"),
+		}
+		cr = &perfv1alpha1.BenchmarkFanout{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name.Name,
+				Namespace: name.Namespace,
+				SelfLink:  "/apis/perf.kubestone.xridge.io/v1alpha1/namespaces/fanout/benchmarkfanouts/pool",
+			},
+			Spec: perfv1alpha1.BenchmarkFanoutSpec{
+				Template: perfv1alpha1.BenchmarkTemplate{
+					Kind: "Sysbench",
+					Spec: &runtime.RawExtension{Raw: []byte(`{"testName": "cpu"}`)},
+				},
+				NodeSelector: map[string]string{"pool": "storage"},
+			},
+		}
+	})
+
+	Context("with serial nodes", func() {
+		BeforeEach(setup)
+
+		It("should benchmark the matching nodes one after the other", func() {
+			reconcile()
+			fanout := stored()
+			Expect(fanout.Status.Phase).To(Equal(perfv1alpha1.BenchmarkRunning))
+			Expect(fanout.Status.Nodes).To(HaveLen(3))
+			Expect(fanout.Status.Nodes[0].NodeName).To(Equal("node-a"))
+			Expect(fanout.Status.Nodes[2].NodeName).To(Equal("node-c"))
+			Expect(benchmarkExists(0)).To(BeTrue())
+			Expect(benchmarkExists(1)).To(BeFalse())
+
+			var sysbench perfv1alpha1.Sysbench
+			Expect(reconciler.K8S.Client.Get(ctx, benchmarkName(0), &sysbench)).To(Succeed())
+			Expect(sysbench.Spec.PodConfig.PodScheduling.NodeName).To(Equal("node-a"))
+
+			for i, events := range []float64{1000, 980, 500} {
+				finishNode(i, perfv1alpha1.BenchmarkSucceeded, events)
+				reconcile()
+			}
+
+			fanout = stored()
+			Expect(fanout.Status.Phase).To(Equal(perfv1alpha1.BenchmarkSucceeded))
+			Expect(fanout.Status.Nodes[1].BenchmarkName).To(Equal("pool-1"))
+			Expect(fanout.Status.Nodes[1].Metrics).To(ConsistOf(perfv1alpha1.BenchmarkMetric{
+				Name: "events", Value: "980", Unit: "events/s",
+			}))
+			Expect(fanout.Status.Nodes[2].Outliers).To(Equal([]string{"events"}))
+			Expect(fanout.Status.Results.Statistics[0].Min).To(Equal("500"))
+			Expect(fanout.Status.Children).To(HaveLen(3))
+			Expect(events()).To(ContainElement(ContainSubstring("nodes node-c deviate")))
+		})
+
+	})
+
+	Context("with assertions", func() {
+		BeforeEach(func() {
+			cr.Spec.Assertions = []perfv1alpha1.Assertion{
+				{Metric: "events", Operator: ">=", Value: "900"},
+			}
+			setup()
+		})
+
+		It("should evaluate the assertions on the mean of the nodes", func() {
+			reconcile()
+			for i, events := range []float64{1000, 980, 500} {
+				finishNode(i, perfv1alpha1.BenchmarkSucceeded, events)
+				reconcile()
+			}
+
+			fanout := stored()
+			Expect(fanout.Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+			Expect(fanout.Status.Reason).To(Equal(k8s.AssertionFailed))
+			Expect(fanout.Status.Assertions).To(HaveLen(1))
+			Expect(fanout.Status.Assertions[0].Passed).To(BeFalse())
+		})
+	})
+
+	Context("without matching nodes", func() {
+		BeforeEach(func() {
+			cr.Spec.NodeSelector = map[string]string{"pool": "gpu"}
+			setup()
+		})
+
+		It("should fail the fan-out", func() {
+			reconcile()
+
+			fanout := stored()
+			Expect(fanout.Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+			Expect(fanout.Status.Reason).To(Equal(k8s.NoMatchingNodes))
+		})
+	})
+
+	Context("with an unknown kind", func() {
+		BeforeEach(func() {
+			cr.Spec.Template.Kind = "Unknown"
+			setup()
+		})
+
+		It("should fail validation", func() {
+			reconcile()
+
+			fanout := stored()
+			Expect(fanout.Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+			Expect(fanout.Status.Reason).To(Equal(k8s.ValidationFailed))
+		})
+	})
+})
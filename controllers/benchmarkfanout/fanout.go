@@ -0,0 +1,210 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarkfanout
+
+import (
+	"fmt"
+	"math"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/stats"
+	"github.com/xridge/kubestone/pkg/template"
+)
+
+const (
+	// FanoutLabel is set on the benchmarks of the nodes to the name of the fan-out
+	FanoutLabel = "kubestone.xridge.io/fanout"
+	// NodeLabel is set on the benchmarks of the nodes to the name of the
+	// node, if the name is a valid label value
+	NodeLabel = "kubestone.xridge.io/fanout-node"
+
+	// MaxNodes limits the number of benchmarked nodes, as the results of
+	// every node are kept in the status of the fan-out
+	MaxNodes = 256
+
+	// DefaultOutlierTolerancePercent is the largest deviation of the metrics
+	// of a node from the median of all nodes without explicit tolerance
+	DefaultOutlierTolerancePercent = 10
+
+	// podNodeNamePath is the path of the node name in the benchmarks
+	// running a single job configured by podConfig
+	podNodeNamePath = ".podConfig.podScheduling.nodeName"
+	// clientNodeNamePath is the path of the node name of the client job
+	// in the client-server benchmarks
+	clientNodeNamePath = ".clientConfiguration.podScheduling.nodeName"
+)
+
+// clientServerKinds are the benchmark kinds, which pin their client job
+// to the node instead of their server
+var clientServerKinds = map[string]bool{"Iperf3": true, "Qperf": true, "Nighthawk": true}
+
+// BenchmarkKinds returns an empty custom resource for each benchmark kind
+// registered in the scheme, keyed by kind. The suites, sweeps and fan-outs
+// are not included, as they do not run pods on their own, neither are the
+// iperf3 matrices, which place their pods on the nodes themselves.
+func BenchmarkKinds(scheme *runtime.Scheme) map[string]perfv1alpha1.Benchmark {
+	kinds := template.Kinds(scheme)
+	delete(kinds, "BenchmarkSuite")
+	delete(kinds, "BenchmarkSweep")
+	delete(kinds, "BenchmarkFanout")
+	delete(kinds, "Iperf3Matrix")
+	return kinds
+}
+
+// NodeNamePath returns the path of the field of the template spec which
+// pins the benchmark to the node
+func NodeNamePath(cr *perfv1alpha1.BenchmarkFanout) string {
+	switch {
+	case cr.Spec.NodeNamePath != "":
+		return cr.Spec.NodeNamePath
+	case clientServerKinds[cr.Spec.Template.Kind]:
+		return clientNodeNamePath
+	default:
+		return podNodeNamePath
+	}
+}
+
+// IsCrValid validates the template and the node selector of the fan-out
+func IsCrValid(cr *perfv1alpha1.BenchmarkFanout, kinds map[string]perfv1alpha1.Benchmark) (valid bool, err error) {
+	prototype, ok := kinds[cr.Spec.Template.Kind]
+	if !ok {
+		return false, fmt.Errorf("unknown benchmark kind %q", cr.Spec.Template.Kind)
+	}
+	spec, err := template.SpecFromRaw(cr.Spec.Template.Spec)
+	if err != nil {
+		return false, fmt.Errorf("invalid template spec: %v", err)
+	}
+	if cr.Spec.Runs() > 1 {
+		return false, fmt.Errorf("fan-outs cannot be repeated, the repetitions can be set in the template")
+	}
+	// The node name would be silently dropped if the kind has no such field
+	if err := template.CheckField(prototype, spec, NodeNamePath(cr), "node"); err != nil {
+		return false, fmt.Errorf("invalid nodeNamePath: %v", err)
+	}
+	if err := k8s.ValidateNodeSelector(cr.Spec.NodeSelector); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// BenchmarkName returns the name of the benchmark created for the node
+// with the given index
+func BenchmarkName(cr *perfv1alpha1.BenchmarkFanout, index int) string {
+	return fmt.Sprintf("%s-%d", cr.Name, index)
+}
+
+// NewBenchmark creates the benchmark of the node with the given index from
+// the template of the fan-out, pinned to the node. The prototype is an
+// empty custom resource of the kind of the template.
+func NewBenchmark(cr *perfv1alpha1.BenchmarkFanout, index int, prototype perfv1alpha1.Benchmark,
+	nodeName string) (perfv1alpha1.Benchmark, error) {
+	spec, err := template.SpecFromRaw(cr.Spec.Template.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := template.SetField(spec, NodeNamePath(cr), nodeName); err != nil {
+		return nil, fmt.Errorf("setting the node name: %v", err)
+	}
+
+	labels := map[string]string{}
+	for key, value := range cr.Spec.Template.Labels {
+		labels[key] = value
+	}
+	labels[FanoutLabel] = cr.Name
+	if len(validation.IsValidLabelValue(nodeName)) == 0 {
+		labels[NodeLabel] = nodeName
+	}
+
+	return template.NewBenchmark(prototype, spec, BenchmarkName(cr, index), cr.Namespace, labels)
+}
+
+// syncNodeStatus copies the metrics of the benchmark of the node into the node status
+func syncNodeStatus(node *perfv1alpha1.BenchmarkFanoutNode, benchmark perfv1alpha1.Benchmark) {
+	status := benchmark.GetBenchmarkStatus()
+	node.Metrics = nil
+	if status.Results != nil {
+		node.Metrics = status.Results.Metrics
+	}
+}
+
+// Summarize marks the outlier metrics of the succeeded nodes, and returns
+// the results of the fan-out: the mean of the metrics over the succeeded
+// nodes, with their statistics describing the spread between the nodes.
+// A metric of a node is an outlier if it deviates from the median of the
+// nodes beyond the tolerance. Outliers need at least three nodes to tell
+// which of them deviates. It returns the names of the outlier nodes.
+func Summarize(cr *perfv1alpha1.BenchmarkFanout) (*perfv1alpha1.BenchmarkResults, []string) {
+	tolerance := float64(DefaultOutlierTolerancePercent)
+	if cr.Spec.OutlierTolerancePercent != nil {
+		tolerance = float64(*cr.Spec.OutlierTolerancePercent)
+	}
+
+	// The metrics of each node are handled as a run of a repeated benchmark
+	var iterations []perfv1alpha1.BenchmarkIteration
+	values := map[string][]float64{}
+	for i := range cr.Status.Nodes {
+		node := &cr.Status.Nodes[i]
+		node.Outliers = nil
+		if node.Phase != perfv1alpha1.BenchmarkSucceeded {
+			continue
+		}
+		iterations = append(iterations, perfv1alpha1.BenchmarkIteration{
+			Iteration: int32(len(iterations) + 1),
+			Metrics:   node.Metrics,
+		})
+		for j := range node.Metrics {
+			if value, err := node.Metrics[j].Float64(); err == nil {
+				values[node.Metrics[j].Name] = append(values[node.Metrics[j].Name], value)
+			}
+		}
+	}
+	if len(iterations) == 0 {
+		return nil, nil
+	}
+	means, statistics := k8s.Aggregate(iterations)
+	results := &perfv1alpha1.BenchmarkResults{Metrics: means, Statistics: statistics}
+
+	var outliers []string
+	for i := range cr.Status.Nodes {
+		node := &cr.Status.Nodes[i]
+		if node.Phase != perfv1alpha1.BenchmarkSucceeded {
+			continue
+		}
+		for j := range node.Metrics {
+			metric := &node.Metrics[j]
+			value, err := metric.Float64()
+			if err != nil || len(values[metric.Name]) < 3 {
+				continue
+			}
+			median := stats.Summarize(values[metric.Name]).Median
+			if median == 0 {
+				continue
+			}
+			if math.Abs(value-median)/math.Abs(median)*100 > tolerance {
+				node.Outliers = append(node.Outliers, metric.Name)
+			}
+		}
+		if len(node.Outliers) > 0 {
+			outliers = append(outliers, node.NodeName)
+		}
+	}
+	return results, outliers
+}
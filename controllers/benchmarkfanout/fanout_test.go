@@ -0,0 +1,221 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarkfanout
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("benchmark fan-out", func() {
+	var cr *perfv1alpha1.BenchmarkFanout
+	var kinds map[string]perfv1alpha1.Benchmark
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(perfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		kinds = BenchmarkKinds(scheme)
+
+		cr = &perfv1alpha1.BenchmarkFanout{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "kubestone"},
+			Spec: perfv1alpha1.BenchmarkFanoutSpec{
+				Template: perfv1alpha1.BenchmarkTemplate{
+					Kind:   "Fio",
+					Labels: map[string]string{"team": "storage"},
+					Spec:   &runtime.RawExtension{Raw: []byte(`{"cmdLineArgs": "--name=randread"}`)},
+				},
+				NodeSelector: map[string]string{"pool": "storage"},
+			},
+		}
+	})
+
+	It("should only fan out benchmarks running pods", func() {
+		Expect(kinds).To(HaveKey("Fio"))
+		Expect(kinds).NotTo(HaveKey("BenchmarkSuite"))
+		Expect(kinds).NotTo(HaveKey("BenchmarkSweep"))
+		Expect(kinds).NotTo(HaveKey("BenchmarkFanout"))
+		Expect(kinds).NotTo(HaveKey("Iperf3Matrix"))
+	})
+
+	Context("validating the CR", func() {
+		It("should accept a valid fan-out", func() {
+			Expect(IsCrValid(cr, kinds)).To(BeTrue())
+		})
+
+		It("should reject unknown kinds", func() {
+			cr.Spec.Template.Kind = "BenchmarkSweep"
+			valid, err := IsCrValid(cr, kinds)
+			Expect(valid).To(BeFalse())
+			Expect(err).To(MatchError(`unknown benchmark kind "BenchmarkSweep"`))
+		})
+
+		It("should reject invalid node selectors", func() {
+			cr.Spec.NodeSelector = map[string]string{"pool": "not valid"}
+			valid, _ := IsCrValid(cr, kinds)
+			Expect(valid).To(BeFalse())
+		})
+
+		It("should reject invalid node name paths", func() {
+			cr.Spec.NodeNamePath = ".podConfig[x]"
+			valid, _ := IsCrValid(cr, kinds)
+			Expect(valid).To(BeFalse())
+		})
+
+		It("should reject node name paths to fields the kind does not have", func() {
+			cr.Spec.NodeNamePath = ".podConfig.podScheduling.node"
+			valid, err := IsCrValid(cr, kinds)
+			Expect(valid).To(BeFalse())
+			Expect(err.Error()).To(ContainSubstring("no such field"))
+		})
+
+		It("should accept the default node name paths of the kinds", func() {
+			for _, kind := range []string{"Fio", "Iperf3", "Qperf", "Nighthawk"} {
+				cr.Spec.Template.Kind = kind
+				Expect(IsCrValid(cr, kinds)).To(BeTrue(), kind)
+			}
+		})
+
+		It("should reject repetitions", func() {
+			runs := int32(3)
+			cr.Spec.Repetitions = &runs
+			valid, _ := IsCrValid(cr, kinds)
+			Expect(valid).To(BeFalse())
+		})
+	})
+
+	Context("creating the benchmarks", func() {
+		It("should pin the benchmark to the node", func() {
+			benchmark, err := NewBenchmark(cr, 1, kinds["Fio"], "node-b")
+			Expect(err).NotTo(HaveOccurred())
+
+			fio := benchmark.(*perfv1alpha1.Fio)
+			Expect(fio.Name).To(Equal("pool-1"))
+			Expect(fio.Namespace).To(Equal("kubestone"))
+			Expect(fio.Spec.CmdLineArgs).To(Equal("--name=randread"))
+			Expect(fio.Spec.PodConfig.PodScheduling.NodeName).To(Equal("node-b"))
+			Expect(fio.Labels).To(Equal(map[string]string{
+				"team":      "storage",
+				FanoutLabel: "pool",
+				NodeLabel:   "node-b",
+			}))
+		})
+
+		It("should pin the client of client-server benchmarks", func() {
+			cr.Spec.Template = perfv1alpha1.BenchmarkTemplate{
+				Kind: "Iperf3",
+				Spec: &runtime.RawExtension{Raw: []byte(`{"udp": true}`)},
+			}
+			benchmark, err := NewBenchmark(cr, 0, kinds["Iperf3"], "node-a")
+			Expect(err).NotTo(HaveOccurred())
+
+			iperf3 := benchmark.(*perfv1alpha1.Iperf3)
+			Expect(iperf3.Spec.ClientConfiguration.PodScheduling.NodeName).To(Equal("node-a"))
+			Expect(iperf3.Spec.ServerConfiguration.PodScheduling.NodeName).To(BeEmpty())
+		})
+
+		It("should use the given node name path", func() {
+			cr.Spec.Template = perfv1alpha1.BenchmarkTemplate{
+				Kind: "Iperf3",
+				Spec: &runtime.RawExtension{Raw: []byte(`{}`)},
+			}
+			cr.Spec.NodeNamePath = ".serverConfiguration.podScheduling.nodeName"
+			benchmark, err := NewBenchmark(cr, 0, kinds["Iperf3"], "node-a")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(benchmark.(*perfv1alpha1.Iperf3).Spec.ServerConfiguration.PodScheduling.NodeName).
+				To(Equal("node-a"))
+		})
+
+		It("should not label the benchmark with long node names", func() {
+			nodeName := "ip-10-0-0-1.eu-west-1.compute.internal.with.a.rather.long.domain.example.com"
+			benchmark, err := NewBenchmark(cr, 0, kinds["Fio"], nodeName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(benchmark.GetLabels()).NotTo(HaveKey(NodeLabel))
+		})
+	})
+
+	Context("summarizing the results", func() {
+		node := func(name string, phase perfv1alpha1.BenchmarkPhase, iops, latency string) perfv1alpha1.BenchmarkFanoutNode {
+			return perfv1alpha1.BenchmarkFanoutNode{
+				NodeName:             name,
+				ChildBenchmarkStatus: perfv1alpha1.ChildBenchmarkStatus{Phase: phase},
+				Metrics: []perfv1alpha1.BenchmarkMetric{
+					{Name: "read.iops", Value: iops, Unit: "ops/s"},
+					{Name: "read.latency.p99", Value: latency, Unit: "us"},
+				},
+				Outliers: []string{"stale"},
+			}
+		}
+
+		It("should mark the metrics deviating from the median", func() {
+			cr.Status.Nodes = []perfv1alpha1.BenchmarkFanoutNode{
+				node("node-a", perfv1alpha1.BenchmarkSucceeded, "1000", "500"),
+				node("node-b", perfv1alpha1.BenchmarkSucceeded, "1050", "520"),
+				node("node-c", perfv1alpha1.BenchmarkSucceeded, "600", "530"),
+				node("node-d", perfv1alpha1.BenchmarkSucceeded, "980", "900"),
+				node("node-e", perfv1alpha1.BenchmarkFailed, "1", "1"),
+			}
+			results, outliers := Summarize(cr)
+
+			Expect(outliers).To(Equal([]string{"node-c", "node-d"}))
+			Expect(cr.Status.Nodes[0].Outliers).To(BeEmpty())
+			Expect(cr.Status.Nodes[2].Outliers).To(Equal([]string{"read.iops"}))
+			Expect(cr.Status.Nodes[3].Outliers).To(Equal([]string{"read.latency.p99"}))
+			Expect(cr.Status.Nodes[4].Outliers).To(BeEmpty())
+
+			Expect(results.GetMetric("read.iops").Value).To(Equal("907.5"))
+			Expect(results.Statistics).To(HaveLen(2))
+			Expect(results.Statistics[0].Samples).To(Equal(int32(4)))
+			Expect(results.Statistics[0].Min).To(Equal("600"))
+		})
+
+		It("should use the given tolerance", func() {
+			tolerance := int32(50)
+			cr.Spec.OutlierTolerancePercent = &tolerance
+			cr.Status.Nodes = []perfv1alpha1.BenchmarkFanoutNode{
+				node("node-a", perfv1alpha1.BenchmarkSucceeded, "1000", "500"),
+				node("node-b", perfv1alpha1.BenchmarkSucceeded, "1050", "520"),
+				node("node-c", perfv1alpha1.BenchmarkSucceeded, "600", "530"),
+			}
+			_, outliers := Summarize(cr)
+			Expect(outliers).To(BeEmpty())
+		})
+
+		It("should not report outliers of less than three nodes", func() {
+			cr.Status.Nodes = []perfv1alpha1.BenchmarkFanoutNode{
+				node("node-a", perfv1alpha1.BenchmarkSucceeded, "1000", "500"),
+				node("node-b", perfv1alpha1.BenchmarkSucceeded, "100", "5000"),
+			}
+			results, outliers := Summarize(cr)
+			Expect(outliers).To(BeEmpty())
+			Expect(results.GetMetric("read.iops").Value).To(Equal("550"))
+		})
+
+		It("should not have results without succeeded nodes", func() {
+			cr.Status.Nodes = []perfv1alpha1.BenchmarkFanoutNode{
+				node("node-a", perfv1alpha1.BenchmarkFailed, "1000", "500"),
+			}
+			results, outliers := Summarize(cr)
+			Expect(results).To(BeNil())
+			Expect(outliers).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,237 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarkschedule
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/template"
+)
+
+// Reconciler provides fields from manager to reconciler
+type Reconciler struct {
+	K8S k8s.Access
+	Log logr.Logger
+}
+
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=benchmarkfanouts;benchmarksuites;benchmarksweeps;drills;esrallies;fios;iopings;iperf3matrixes;iperf3s;kafkabenches;nighthawks;ocplogtests;osbenches;perfbenches;pgbenches;pvclatencies;qperves;s3benches;sysbenches;ycsbbenches,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=benchmarkschedules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=benchmarkschedules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=benchmarkschedules/finalizers,verbs=update
+
+// Reconcile keeps track of the runs of the schedule, deletes the runs
+// beyond the history limits and creates the run which is due. The
+// schedule is requeued for its next scheduled time.
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+
+	var cr perfv1alpha1.BenchmarkSchedule
+	if err := r.K8S.Client.Get(ctx, req.NamespacedName, &cr); err != nil {
+		return ctrl.Result{}, k8s.IgnoreNotFound(err)
+	}
+
+	prototype, ok := template.Kinds(r.K8S.Scheme)[cr.Spec.Template.Kind]
+	if !ok {
+		// Do not requeue invalid CRs
+		_ = r.K8S.RecordEventf(&cr, corev1.EventTypeWarning, k8s.ValidationFailed,
+			"Unknown benchmark kind %q in template", cr.Spec.Template.Kind)
+		return ctrl.Result{}, nil
+	}
+	schedule, err := ParseSchedule(&cr)
+	if err != nil {
+		_ = r.K8S.RecordEventf(&cr, corev1.EventTypeWarning, k8s.ValidationFailed, err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	runs, err := template.List(ctx, r.K8S.Client, r.K8S.Scheme, cr.Spec.Template.Kind,
+		cr.Namespace, map[string]string{ScheduleLabel: cr.Name})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	original := cr.Status.DeepCopy()
+	var active, succeeded, failed []perfv1alpha1.Benchmark
+	for _, run := range runs {
+		if !metav1.IsControlledBy(run, &cr) {
+			continue
+		}
+		status := run.GetBenchmarkStatus()
+		switch {
+		case !status.IsFinished():
+			active = append(active, run)
+		case status.Phase == perfv1alpha1.BenchmarkSucceeded:
+			succeeded = append(succeeded, run)
+			if completion := completionTime(run); cr.Status.LastSuccessfulTime == nil ||
+				cr.Status.LastSuccessfulTime.Before(&completion) {
+				cr.Status.LastSuccessfulTime = &completion
+			}
+		default:
+			failed = append(failed, run)
+		}
+	}
+	cr.Status.Active = nil
+	for _, run := range active {
+		cr.Status.Active = append(cr.Status.Active, perfv1alpha1.ChildReference{
+			APIVersion: perfv1alpha1.GroupVersion.String(),
+			Kind:       cr.Spec.Template.Kind,
+			Name:       run.GetName(),
+		})
+	}
+
+	succeededLimit, failedLimit := historyLimits(&cr)
+	if err := r.deleteOldRuns(ctx, &cr, succeeded, succeededLimit); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.deleteOldRuns(ctx, &cr, failed, failedLimit); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	result, err := r.startRun(ctx, &cr, schedule, prototype, active, time.Now())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !apiequality.Semantic.DeepEqual(original, &cr.Status) {
+		if err := r.K8S.Client.Status().Update(ctx, &cr); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return result, nil
+}
+
+// startRun creates the benchmark of the run which is due, according to
+// the concurrency policy of the schedule. The returned result requeues
+// the schedule for its next scheduled time.
+func (r *Reconciler) startRun(ctx context.Context, cr *perfv1alpha1.BenchmarkSchedule, schedule cron.Schedule,
+	prototype perfv1alpha1.Benchmark, active []perfv1alpha1.Benchmark, now time.Time) (ctrl.Result, error) {
+	if cr.Spec.Suspend {
+		return ctrl.Result{}, nil
+	}
+
+	result := ctrl.Result{}
+	if next := schedule.Next(now); !next.IsZero() {
+		result.RequeueAfter = next.Sub(now)
+	}
+
+	scheduled, err := MostRecentScheduleTime(cr, schedule, now)
+	if err != nil {
+		// The missed runs are skipped, the schedule is resumed
+		// from its next scheduled time
+		_ = r.K8S.RecordEventf(cr, corev1.EventTypeWarning, k8s.TooManyMissedRuns,
+			"Missed runs are skipped: %v", err)
+		cr.Status.LastScheduleTime = &metav1.Time{Time: now}
+		return result, nil
+	}
+	if scheduled == nil {
+		return result, nil
+	}
+
+	switch cr.Spec.ConcurrencyPolicy {
+	case perfv1alpha1.ForbidConcurrent:
+		if len(active) > 0 {
+			// The run is not started later either: the skipped time is
+			// kept as the last scheduled time, so it is reported once
+			_ = r.K8S.RecordEventf(cr, corev1.EventTypeNormal, k8s.AlreadyActive,
+				"Run scheduled at %v is skipped, as the previous run is still in progress", scheduled.UTC())
+			cr.Status.LastScheduleTime = &metav1.Time{Time: *scheduled}
+			return result, nil
+		}
+	case perfv1alpha1.ReplaceConcurrent:
+		for _, run := range active {
+			if err := r.K8S.DeleteObject(ctx, run, cr); err != nil {
+				return result, err
+			}
+		}
+		cr.Status.Active = nil
+	}
+
+	run, err := NewRun(cr, prototype, *scheduled)
+	if err != nil {
+		_ = r.K8S.RecordEventf(cr, corev1.EventTypeWarning, k8s.CreateFailed,
+			"Unable to create the run scheduled at %v: %v", scheduled.UTC(), err)
+		return result, nil
+	}
+	if err := r.K8S.CreateWithReference(ctx, run, cr); err != nil {
+		return result, err
+	}
+
+	// The run might be already listed, if its creation was not recorded
+	if !isActive(cr, run.GetName()) {
+		cr.Status.Active = append(cr.Status.Active, perfv1alpha1.ChildReference{
+			APIVersion: perfv1alpha1.GroupVersion.String(),
+			Kind:       cr.Spec.Template.Kind,
+			Name:       run.GetName(),
+		})
+	}
+	cr.Status.LastScheduleTime = &metav1.Time{Time: *scheduled}
+	return result, nil
+}
+
+// isActive returns true if the run with the given name is in progress
+func isActive(cr *perfv1alpha1.BenchmarkSchedule, name string) bool {
+	for _, run := range cr.Status.Active {
+		if run.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteOldRuns deletes the oldest finished runs above the limit. The
+// BenchmarkResults of the runs are not owned by the runs, so they are
+// kept for the analysis of the trends.
+func (r *Reconciler) deleteOldRuns(ctx context.Context, cr *perfv1alpha1.BenchmarkSchedule,
+	runs []perfv1alpha1.Benchmark, limit int) error {
+	if len(runs) <= limit {
+		return nil
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		completionI, completionJ := completionTime(runs[i]), completionTime(runs[j])
+		return completionI.Before(&completionJ)
+	})
+	for _, run := range runs[:len(runs)-limit] {
+		if err := r.K8S.DeleteObject(ctx, run, cr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetupWithManager registers the Reconciler with the provided manager.
+// The schedule is reconciled whenever any of its runs changes.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&perfv1alpha1.BenchmarkSchedule{})
+
+	kinds := template.Kinds(mgr.GetScheme())
+	for _, kind := range template.SortedKinds(kinds) {
+		builder = builder.Owns(kinds[kind])
+	}
+	return builder.Complete(r)
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarkschedule
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+)
+
+var _ = Describe("benchmark schedule reconciler", func() {
+	var ctx context.Context
+	var cr *perfv1alpha1.BenchmarkSchedule
+	var name types.NamespacedName
+	var reconciler *Reconciler
+	var objects []runtime.Object
+
+	// run creates a benchmark of a previous run of the schedule
+	run := func(runName string, phase perfv1alpha1.BenchmarkPhase, age time.Duration) *perfv1alpha1.Sysbench {
+		controller := true
+		sysbench := &perfv1alpha1.Sysbench{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      runName,
+				Namespace: name.Namespace,
+				Labels:    map[string]string{ScheduleLabel: name.Name},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: perfv1alpha1.GroupVersion.String(),
+					Kind:       "BenchmarkSchedule",
+					Name:       name.Name,
+					UID:        cr.UID,
+					Controller: &controller,
+				}},
+			},
+		}
+		if phase != "" {
+			sysbench.Status.SetPhase(phase, "", "")
+			completion := metav1.NewTime(time.Now().Add(-age))
+			sysbench.Status.CompletionTime = &completion
+		}
+		return sysbench
+	}
+
+	setup := func() {
+		scheme := runtime.NewScheme()
+		_ = k8sscheme.AddToScheme(scheme)
+		_ = perfv1alpha1.AddToScheme(scheme)
+		reconciler = &Reconciler{K8S: k8s.Access{
+			Client:        fake.NewFakeClientWithScheme(scheme, append(objects, cr.DeepCopy())...),
+			Scheme:        scheme,
+			EventRecorder: record.NewFakeRecorder(100),
+		}}
+	}
+
+	reconcile := func() ctrl.Result {
+		result, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	stored := func() *perfv1alpha1.BenchmarkSchedule {
+		var schedule perfv1alpha1.BenchmarkSchedule
+		Expect(reconciler.K8S.Client.Get(ctx, name, &schedule)).To(Succeed())
+		return &schedule
+	}
+
+	runNames := func() []string {
+		var list perfv1alpha1.SysbenchList
+		Expect(reconciler.K8S.Client.List(ctx, &list, client.InNamespace(name.Namespace))).To(Succeed())
+		var names []string
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		name = types.NamespacedName{Namespace: "kubestone", Name: "hourly"}
+		objects = nil
+		cr = &perfv1alpha1.BenchmarkSchedule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name.Name,
+				Namespace: name.Namespace,
+				UID:       "4d2b7c7e-2d7f-4a3e-8d8c-3f0e7c1b5a10",
+				// An hourly run is due since creation
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-90 * time.Minute)),
+			},
+			Spec: perfv1alpha1.BenchmarkScheduleSpec{
+				Schedule: "0 * * * *",
+				Template: perfv1alpha1.BenchmarkTemplate{
+					Kind: "Sysbench",
+					Spec: &runtime.RawExtension{Raw: []byte(`{"testName": "cpu"}`)},
+				},
+			},
+		}
+	})
+
+	It("should start the run which is due", func() {
+		setup()
+		result := reconcile()
+
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(result.RequeueAfter).To(BeNumerically("<=", time.Hour))
+		Expect(runNames()).To(HaveLen(1))
+
+		schedule := stored()
+		Expect(schedule.Status.Active).To(HaveLen(1))
+		Expect(schedule.Status.Active[0].Name).To(Equal(runNames()[0]))
+		Expect(schedule.Status.LastScheduleTime).NotTo(BeNil())
+
+		By("not starting the same run again")
+		reconcile()
+		Expect(runNames()).To(HaveLen(1))
+	})
+
+	It("should skip the runs if too many were missed", func() {
+		cr.CreationTimestamp = metav1.NewTime(time.Now().Add(-200 * time.Hour))
+		setup()
+		reconcile()
+		Expect(runNames()).To(BeEmpty())
+		Expect(stored().Status.LastScheduleTime).NotTo(BeNil())
+
+		By("resuming with the next scheduled time")
+		reconcile()
+		Expect(runNames()).To(BeEmpty())
+	})
+
+	It("should not start runs when suspended", func() {
+		cr.Spec.Suspend = true
+		setup()
+		reconcile()
+		Expect(runNames()).To(BeEmpty())
+	})
+
+	It("should not start runs for unknown kinds", func() {
+		cr.Spec.Template.Kind = "Unknown"
+		setup()
+		Expect(reconcile()).To(Equal(ctrl.Result{}))
+		Expect(runNames()).To(BeEmpty())
+	})
+
+	Context("with a run in progress", func() {
+		BeforeEach(func() {
+			objects = []runtime.Object{run("hourly-active", perfv1alpha1.BenchmarkRunning, 0)}
+			objects[0].(*perfv1alpha1.Sysbench).Status.CompletionTime = nil
+		})
+
+		It("should allow concurrent runs by default", func() {
+			setup()
+			reconcile()
+			Expect(runNames()).To(HaveLen(2))
+			Expect(stored().Status.Active).To(HaveLen(2))
+		})
+
+		It("should skip the new run when concurrency is forbidden", func() {
+			cr.Spec.ConcurrencyPolicy = perfv1alpha1.ForbidConcurrent
+			setup()
+			reconcile()
+			Expect(runNames()).To(ConsistOf("hourly-active"))
+			skipped := stored().Status.LastScheduleTime
+			Expect(skipped).NotTo(BeNil())
+			Expect(skipped.Minute()).To(BeZero())
+
+			By("not starting the skipped run once the active run finishes")
+			var active perfv1alpha1.Sysbench
+			Expect(reconciler.K8S.Client.Get(ctx,
+				types.NamespacedName{Namespace: name.Namespace, Name: "hourly-active"}, &active)).To(Succeed())
+			active.Status.SetPhase(perfv1alpha1.BenchmarkSucceeded, "", "")
+			Expect(reconciler.K8S.Client.Status().Update(ctx, &active)).To(Succeed())
+			reconcile()
+			Expect(runNames()).To(ConsistOf("hourly-active"))
+		})
+
+		It("should replace the active run when requested", func() {
+			cr.Spec.ConcurrencyPolicy = perfv1alpha1.ReplaceConcurrent
+			setup()
+			reconcile()
+			Expect(runNames()).To(HaveLen(1))
+			Expect(runNames()).NotTo(ContainElement("hourly-active"))
+		})
+	})
+
+	Context("with finished runs", func() {
+		BeforeEach(func() {
+			last := metav1.Now()
+			cr.Status.LastScheduleTime = &last
+			objects = []runtime.Object{
+				run("hourly-succeeded-1", perfv1alpha1.BenchmarkSucceeded, 4*time.Hour),
+				run("hourly-succeeded-2", perfv1alpha1.BenchmarkSucceeded, 3*time.Hour),
+				run("hourly-succeeded-3", perfv1alpha1.BenchmarkSucceeded, 2*time.Hour),
+				run("hourly-succeeded-4", perfv1alpha1.BenchmarkSucceeded, 1*time.Hour),
+				run("hourly-failed-1", perfv1alpha1.BenchmarkFailed, 2*time.Hour),
+				run("hourly-failed-2", perfv1alpha1.BenchmarkCancelled, 1*time.Hour),
+			}
+		})
+
+		It("should keep the runs within the history limits", func() {
+			setup()
+			reconcile()
+			Expect(runNames()).To(ConsistOf(
+				"hourly-succeeded-2", "hourly-succeeded-3", "hourly-succeeded-4", "hourly-failed-2"))
+			Expect(stored().Status.LastSuccessfulTime).NotTo(BeNil())
+		})
+
+		It("should apply the configured history limits", func() {
+			succeeded, failed := int32(1), int32(0)
+			cr.Spec.SuccessfulRunsHistoryLimit = &succeeded
+			cr.Spec.FailedRunsHistoryLimit = &failed
+			setup()
+			reconcile()
+			Expect(runNames()).To(ConsistOf("hourly-succeeded-4"))
+		})
+	})
+})
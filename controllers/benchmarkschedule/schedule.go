@@ -0,0 +1,136 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarkschedule
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/template"
+)
+
+const (
+	// ScheduleLabel is set on the benchmarks of the runs to the name of the schedule
+	ScheduleLabel = "kubestone.xridge.io/schedule"
+	// ScheduledTimeAnnotation is set on the benchmarks of the runs to
+	// the scheduled time of the run
+	ScheduledTimeAnnotation = "kubestone.xridge.io/scheduled-time"
+
+	defaultSuccessfulRunsHistoryLimit = 3
+	defaultFailedRunsHistoryLimit     = 1
+
+	// maxMissedRuns limits the number of missed scheduled times walked
+	// through to find the most recent one, like the CronJob controller
+	maxMissedRuns = 100
+)
+
+// ParseSchedule parses the cron expression of the schedule
+func ParseSchedule(cr *perfv1alpha1.BenchmarkSchedule) (cron.Schedule, error) {
+	schedule, err := cron.ParseStandard(cr.Spec.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %v", cr.Spec.Schedule, err)
+	}
+	return schedule, nil
+}
+
+// MostRecentScheduleTime returns the latest scheduled time of the schedule
+// which is due at now, or nil if no run is due. Runs which missed their
+// starting deadline are not considered. An error is returned if more than
+// maxMissedRuns runs were missed, e.g. when the schedule was suspended
+// for long without a starting deadline.
+func MostRecentScheduleTime(cr *perfv1alpha1.BenchmarkSchedule, schedule cron.Schedule,
+	now time.Time) (*time.Time, error) {
+	earliest := cr.CreationTimestamp.Time
+	if cr.Status.LastScheduleTime != nil {
+		earliest = cr.Status.LastScheduleTime.Time
+	}
+	if cr.Spec.StartingDeadlineSeconds != nil {
+		deadline := now.Add(-time.Duration(*cr.Spec.StartingDeadlineSeconds) * time.Second)
+		if earliest.Before(deadline) {
+			earliest = deadline
+		}
+	}
+
+	var latest *time.Time
+	missed := 0
+	for t := schedule.Next(earliest); !t.IsZero() && !t.After(now); t = schedule.Next(t) {
+		if missed++; missed > maxMissedRuns {
+			return nil, fmt.Errorf("more than %d runs were missed, set or decrease startingDeadlineSeconds",
+				maxMissedRuns)
+		}
+		scheduled := t
+		latest = &scheduled
+	}
+	return latest, nil
+}
+
+// RunName returns the name of the benchmark of the run scheduled at the
+// given time. The name is deterministic, so the run is created only once.
+func RunName(cr *perfv1alpha1.BenchmarkSchedule, scheduled time.Time) string {
+	return fmt.Sprintf("%s-%d", cr.Name, scheduled.Unix()/60)
+}
+
+// NewRun creates the benchmark of the run scheduled at the given time
+// from the template of the schedule. The prototype is an empty custom
+// resource of the kind of the template.
+func NewRun(cr *perfv1alpha1.BenchmarkSchedule, prototype perfv1alpha1.Benchmark,
+	scheduled time.Time) (perfv1alpha1.Benchmark, error) {
+	spec, err := template.SpecFromRaw(cr.Spec.Template.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := map[string]string{}
+	for key, value := range cr.Spec.Template.Labels {
+		labels[key] = value
+	}
+	labels[ScheduleLabel] = cr.Name
+
+	run, err := template.NewBenchmark(prototype, spec, RunName(cr, scheduled), cr.Namespace, labels)
+	if err != nil {
+		return nil, err
+	}
+	run.SetAnnotations(map[string]string{
+		ScheduledTimeAnnotation: scheduled.UTC().Format(time.RFC3339),
+	})
+	return run, nil
+}
+
+// historyLimits returns the number of succeeded and failed runs to keep
+func historyLimits(cr *perfv1alpha1.BenchmarkSchedule) (succeeded, failed int) {
+	succeeded, failed = defaultSuccessfulRunsHistoryLimit, defaultFailedRunsHistoryLimit
+	if cr.Spec.SuccessfulRunsHistoryLimit != nil {
+		succeeded = int(*cr.Spec.SuccessfulRunsHistoryLimit)
+	}
+	if cr.Spec.FailedRunsHistoryLimit != nil {
+		failed = int(*cr.Spec.FailedRunsHistoryLimit)
+	}
+	return succeeded, failed
+}
+
+// completionTime returns the time the run has finished, or its creation
+// time if it is not known
+func completionTime(run perfv1alpha1.Benchmark) metav1.Time {
+	if completion := run.GetBenchmarkStatus().CompletionTime; completion != nil {
+		return *completion
+	}
+	return run.GetCreationTimestamp()
+}
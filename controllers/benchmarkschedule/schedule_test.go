@@ -0,0 +1,140 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarkschedule
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("benchmark schedule", func() {
+	var cr *perfv1alpha1.BenchmarkSchedule
+	var created time.Time
+
+	BeforeEach(func() {
+		created = time.Date(2020, 3, 1, 10, 30, 0, 0, time.UTC)
+		cr = &perfv1alpha1.BenchmarkSchedule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "nightly",
+				Namespace:         "kubestone",
+				CreationTimestamp: metav1.Time{Time: created},
+			},
+			Spec: perfv1alpha1.BenchmarkScheduleSpec{
+				Schedule: "0 2 * * *",
+				Template: perfv1alpha1.BenchmarkTemplate{
+					Kind:   "Sysbench",
+					Labels: map[string]string{"pool": "blue"},
+					Spec:   &runtime.RawExtension{Raw: []byte(`{"testName": "cpu"}`)},
+				},
+			},
+		}
+	})
+
+	Describe("parsing", func() {
+		It("should accept cron expressions", func() {
+			_, err := ParseSchedule(cr)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject invalid expressions", func() {
+			cr.Spec.Schedule = "every night"
+			_, err := ParseSchedule(cr)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("most recent schedule time", func() {
+		var nightly time.Time
+
+		BeforeEach(func() {
+			nightly = time.Date(2020, 3, 2, 2, 0, 0, 0, time.UTC)
+		})
+
+		mostRecent := func(now time.Time) *time.Time {
+			schedule, err := ParseSchedule(cr)
+			Expect(err).NotTo(HaveOccurred())
+			scheduled, err := MostRecentScheduleTime(cr, schedule, now)
+			Expect(err).NotTo(HaveOccurred())
+			return scheduled
+		}
+
+		It("should not be due before the first scheduled time", func() {
+			Expect(mostRecent(nightly.Add(-time.Minute))).To(BeNil())
+		})
+
+		It("should be due at the scheduled time", func() {
+			Expect(*mostRecent(nightly.Add(time.Minute))).To(Equal(nightly))
+		})
+
+		It("should return the latest of the missed runs", func() {
+			Expect(*mostRecent(nightly.Add(50 * time.Hour))).To(Equal(nightly.Add(48 * time.Hour)))
+		})
+
+		It("should not be due again after the run was scheduled", func() {
+			cr.Status.LastScheduleTime = &metav1.Time{Time: nightly}
+			Expect(mostRecent(nightly.Add(time.Hour))).To(BeNil())
+		})
+
+		It("should skip the runs which missed the starting deadline", func() {
+			deadline := int64(600)
+			cr.Spec.StartingDeadlineSeconds = &deadline
+			Expect(mostRecent(nightly.Add(5 * time.Minute))).NotTo(BeNil())
+			Expect(mostRecent(nightly.Add(15 * time.Minute))).To(BeNil())
+		})
+
+		It("should give up after too many missed runs", func() {
+			schedule, err := ParseSchedule(cr)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = MostRecentScheduleTime(cr, schedule, nightly.Add(maxMissedRuns*24*time.Hour))
+			Expect(err).To(HaveOccurred())
+
+			deadline := int64(3600)
+			cr.Spec.StartingDeadlineSeconds = &deadline
+			scheduled, err := MostRecentScheduleTime(cr, schedule, nightly.Add(maxMissedRuns*24*time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*scheduled).To(Equal(nightly.Add(maxMissedRuns * 24 * time.Hour)))
+		})
+	})
+
+	Describe("new run", func() {
+		It("should be created from the template", func() {
+			scheduled := time.Date(2020, 3, 2, 2, 0, 0, 0, time.UTC)
+			run, err := NewRun(cr, &perfv1alpha1.Sysbench{}, scheduled)
+			Expect(err).NotTo(HaveOccurred())
+
+			sysbench := run.(*perfv1alpha1.Sysbench)
+			Expect(sysbench.Name).To(Equal(RunName(cr, scheduled)))
+			Expect(sysbench.Namespace).To(Equal("kubestone"))
+			Expect(sysbench.Labels).To(HaveKeyWithValue(ScheduleLabel, "nightly"))
+			Expect(sysbench.Labels).To(HaveKeyWithValue("pool", "blue"))
+			Expect(sysbench.Annotations).To(HaveKeyWithValue(ScheduledTimeAnnotation, "2020-03-02T02:00:00Z"))
+			Expect(sysbench.Spec.TestName).To(Equal("cpu"))
+		})
+
+		It("should have a distinct name for each scheduled time", func() {
+			scheduled := time.Date(2020, 3, 2, 2, 0, 0, 0, time.UTC)
+			Expect(RunName(cr, scheduled)).NotTo(Equal(RunName(cr, scheduled.Add(24*time.Hour))))
+		})
+	})
+})
@@ -0,0 +1,46 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarkschedule
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/template"
+	"github.com/xridge/kubestone/pkg/webhooks"
+)
+
+// +kubebuilder:webhook:path=/validate-perf-kubestone-xridge-io-v1alpha1-benchmarkschedule,mutating=false,failurePolicy=fail,groups=perf.kubestone.xridge.io,resources=benchmarkschedules,verbs=create;update,versions=v1alpha1,name=vbenchmarkschedule.kubestone.xridge.io
+
+// SetupWebhookWithManager registers the admission webhooks of BenchmarkSchedule.
+// The template kind and the cron expression of the schedules are validated.
+func SetupWebhookWithManager(mgr ctrl.Manager) error {
+	kinds := template.Kinds(mgr.GetScheme())
+	return webhooks.SetupWithManager(mgr, &perfv1alpha1.BenchmarkSchedule{}, webhooks.Hooks{
+		Validate: func(obj runtime.Object) error {
+			cr := obj.(*perfv1alpha1.BenchmarkSchedule)
+			if _, ok := kinds[cr.Spec.Template.Kind]; !ok {
+				return fmt.Errorf("unknown benchmark kind %q in template", cr.Spec.Template.Kind)
+			}
+			_, err := ParseSchedule(cr)
+			return err
+		},
+	})
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarksuite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/lifecycle"
+	"github.com/xridge/kubestone/pkg/template"
+)
+
+// Reconciler provides fields from manager to reconciler
+type Reconciler struct {
+	K8S k8s.Access
+	Log logr.Logger
+}
+
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=benchmarkfanouts;benchmarksweeps;drills;esrallies;fios;iopings;iperf3matrixes;iperf3s;kafkabenches;nighthawks;ocplogtests;osbenches;perfbenches;pgbenches;pvclatencies;qperves;s3benches;sysbenches;ycsbbenches,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=benchmarksuites,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=benchmarksuites/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=benchmarksuites/finalizers,verbs=update
+
+// Reconcile creates the benchmarks of the suite step by step, and
+// aggregates their state into the status of the suite
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+
+	var cr perfv1alpha1.BenchmarkSuite
+	if err := r.K8S.Client.Get(ctx, req.NamespacedName, &cr); err != nil {
+		return ctrl.Result{}, k8s.IgnoreNotFound(err)
+	}
+
+	// Run to one completion
+	if cr.Status.IsFinished() {
+		return ctrl.Result{}, nil
+	}
+
+	kinds := BenchmarkKinds(r.K8S.Scheme)
+	if valid, err := lifecycle.Validate(ctx, &r.K8S, &cr, func() (bool, error) {
+		return IsCrValid(&cr, kinds)
+	}); !valid || err != nil {
+		return ctrl.Result{}, err
+	}
+
+	original := cr.DeepCopy()
+	if len(cr.Status.Steps) != len(cr.Spec.Steps) {
+		cr.Status.Steps = make([]perfv1alpha1.BenchmarkSuiteStepStatus, len(cr.Spec.Steps))
+		for i, step := range cr.Spec.Steps {
+			cr.Status.Steps[i] = perfv1alpha1.BenchmarkSuiteStepStatus{Name: step.Name, Kind: step.Kind}
+		}
+	}
+
+	statuses := make([]*perfv1alpha1.ChildBenchmarkStatus, len(cr.Status.Steps))
+	for i := range cr.Status.Steps {
+		statuses[i] = &cr.Status.Steps[i].ChildBenchmarkStatus
+	}
+	children := lifecycle.Children{
+		Statuses:     statuses,
+		Parallelism:  cr.Spec.Parallelism,
+		Noun:         "steps",
+		FailedReason: k8s.StepFailed,
+		Describe: func(i int) string {
+			return fmt.Sprintf("step %q", cr.Spec.Steps[i].Name)
+		},
+		Prototype: func(i int) perfv1alpha1.Benchmark {
+			return kinds[cr.Spec.Steps[i].Kind]
+		},
+		New: func(ctx context.Context, i int) (perfv1alpha1.Benchmark, error) {
+			return r.newBenchmark(ctx, &cr, &cr.Spec.Steps[i], kinds[cr.Spec.Steps[i].Kind])
+		},
+		Sync: func(i int, benchmark perfv1alpha1.Benchmark) {
+			syncStepStatus(&cr.Status.Steps[i], benchmark)
+		},
+		Stopped: func() bool {
+			return isStopped(&cr)
+		},
+		Summarize: func(finished bool) {
+			cr.Status.Results = aggregateResults(cr.Status.Steps)
+		},
+	}
+	return ctrl.Result{}, children.Reconcile(ctx, &r.K8S, &cr, original)
+}
+
+// newBenchmark creates the benchmark of the step from its embedded spec,
+// or from the spec of the referred benchmark
+func (r *Reconciler) newBenchmark(ctx context.Context, cr *perfv1alpha1.BenchmarkSuite,
+	step *perfv1alpha1.BenchmarkSuiteStep, prototype perfv1alpha1.Benchmark) (perfv1alpha1.Benchmark, error) {
+	var spec map[string]interface{}
+	var err error
+	if step.BenchmarkRef != nil {
+		referred := prototype.DeepCopyObject().(perfv1alpha1.Benchmark)
+		if err := r.K8S.Client.Get(ctx, types.NamespacedName{
+			Namespace: cr.Namespace,
+			Name:      step.BenchmarkRef.Name,
+		}, referred); errors.IsNotFound(err) {
+			// The step cannot be started by retrying
+			return nil, fmt.Errorf("step %q: the referred benchmark %s does not exist",
+				step.Name, step.BenchmarkRef.Name)
+		} else if err != nil {
+			return nil, err
+		}
+		spec, err = template.SpecOf(referred)
+	} else {
+		spec, err = template.SpecFromRaw(step.Spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("step %q: %v", step.Name, err)
+	}
+	return NewBenchmark(cr, step, prototype, spec)
+}
+
+// isStopped returns true if a step without ContinueOnFailure has
+// finished without success, so no new steps are started
+func isStopped(cr *perfv1alpha1.BenchmarkSuite) bool {
+	for i, step := range cr.Spec.Steps {
+		stepStatus := &cr.Status.Steps[i]
+		if stepStatus.IsFinished() && stepStatus.Phase != perfv1alpha1.BenchmarkSucceeded &&
+			!step.ContinueOnFailure {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupWithManager registers the Reconciler with the provided manager.
+// The suite is reconciled whenever the benchmark of any of its steps changes.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&perfv1alpha1.BenchmarkSuite{})
+
+	kinds := BenchmarkKinds(mgr.GetScheme())
+	for _, kind := range template.SortedKinds(kinds) {
+		builder = builder.Owns(kinds[kind])
+	}
+	return builder.Complete(lifecycle.NewReconciler(r, &r.K8S, &perfv1alpha1.BenchmarkSuite{}))
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarksuite
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+)
+
+var _ = Describe("benchmark suite reconciler", func() {
+	var ctx context.Context
+	var cr *perfv1alpha1.BenchmarkSuite
+	var name types.NamespacedName
+	var reconciler *Reconciler
+
+	step := func(name string, continueOnFailure bool) perfv1alpha1.BenchmarkSuiteStep {
+		return perfv1alpha1.BenchmarkSuiteStep{
+			Name:              name,
+			Kind:              "Sysbench",
+			Spec:              &runtime.RawExtension{Raw: []byte(`{"testName": "cpu"}`)},
+			ContinueOnFailure: continueOnFailure,
+		}
+	}
+
+	setup := func() {
+		scheme := runtime.NewScheme()
+		_ = k8sscheme.AddToScheme(scheme)
+		_ = perfv1alpha1.AddToScheme(scheme)
+		reconciler = &Reconciler{K8S: k8s.Access{
+			Client:        fake.NewFakeClientWithScheme(scheme, cr.DeepCopy()),
+			Scheme:        scheme,
+			EventRecorder: record.NewFakeRecorder(100),
+		}}
+	}
+
+	reconcile := func() {
+		_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	stored := func() *perfv1alpha1.BenchmarkSuite {
+		var suite perfv1alpha1.BenchmarkSuite
+		Expect(reconciler.K8S.Client.Get(ctx, name, &suite)).To(Succeed())
+		return &suite
+	}
+
+	benchmarkExists := func(stepName string) bool {
+		var sysbench perfv1alpha1.Sysbench
+		err := reconciler.K8S.Client.Get(ctx, types.NamespacedName{
+			Namespace: name.Namespace,
+			Name:      name.Name + "-" + stepName,
+		}, &sysbench)
+		Expect(errors.IsNotFound(err) || err == nil).To(BeTrue())
+		return err == nil
+	}
+
+	finishStep := func(stepName string, phase perfv1alpha1.BenchmarkPhase) {
+		var sysbench perfv1alpha1.Sysbench
+		Expect(reconciler.K8S.Client.Get(ctx, types.NamespacedName{
+			Namespace: name.Namespace,
+			Name:      name.Name + "-" + stepName,
+		}, &sysbench)).To(Succeed())
+		sysbench.Status.SetPhase(phase, "", "")
+		sysbench.Status.Results = &perfv1alpha1.BenchmarkResults{}
+		sysbench.Status.Results.AddMetric("events", 100, "events/s")
+		Expect(reconciler.K8S.Client.Status().Update(ctx, &sysbench)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		name = types.NamespacedName{Namespace: "suite", Name: "nodepool"}
+		cr = &perfv1alpha1.BenchmarkSuite{
+			ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+			Spec: perfv1alpha1.BenchmarkSuiteSpec{
+				Steps: []perfv1alpha1.BenchmarkSuiteStep{step("first", false), step("second", false)},
+			},
+		}
+	})
+
+	Context("with serial steps", func() {
+		BeforeEach(setup)
+
+		It("should run the steps one after the other", func() {
+			reconcile()
+			Expect(stored().Status.Phase).To(Equal(perfv1alpha1.BenchmarkRunning))
+			Expect(benchmarkExists("first")).To(BeTrue())
+			Expect(benchmarkExists("second")).To(BeFalse())
+
+			finishStep("first", perfv1alpha1.BenchmarkSucceeded)
+			reconcile()
+			Expect(benchmarkExists("second")).To(BeTrue())
+
+			finishStep("second", perfv1alpha1.BenchmarkSucceeded)
+			reconcile()
+
+			suite := stored()
+			Expect(suite.Status.Phase).To(Equal(perfv1alpha1.BenchmarkSucceeded))
+			Expect(suite.Status.Steps).To(HaveLen(2))
+			Expect(suite.Status.Steps[0].BenchmarkName).To(Equal("nodepool-first"))
+			Expect(suite.Status.Steps[1].Phase).To(Equal(perfv1alpha1.BenchmarkSucceeded))
+			Expect(suite.Status.Results.GetMetric("first.events")).NotTo(BeNil())
+			Expect(suite.Status.Results.GetMetric("second.events")).NotTo(BeNil())
+			Expect(suite.Status.Children).To(HaveLen(2))
+		})
+
+		It("should stop at the first failed step", func() {
+			reconcile()
+			finishStep("first", perfv1alpha1.BenchmarkFailed)
+			reconcile()
+
+			suite := stored()
+			Expect(benchmarkExists("second")).To(BeFalse())
+			Expect(suite.Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+			Expect(suite.Status.Reason).To(Equal(k8s.StepFailed))
+			Expect(suite.Status.Steps[1].Phase).To(Equal(perfv1alpha1.BenchmarkCancelled))
+			Expect(suite.Status.Steps[1].Reason).To(Equal(k8s.Skipped))
+		})
+	})
+
+	Context("with a step continuing on failure", func() {
+		BeforeEach(func() {
+			cr.Spec.Steps[0].ContinueOnFailure = true
+			setup()
+		})
+
+		It("should run the next steps, but fail the suite", func() {
+			reconcile()
+			finishStep("first", perfv1alpha1.BenchmarkFailed)
+			reconcile()
+			Expect(benchmarkExists("second")).To(BeTrue())
+
+			finishStep("second", perfv1alpha1.BenchmarkSucceeded)
+			reconcile()
+			suite := stored()
+			Expect(suite.Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+			Expect(suite.Status.Message).To(Equal("1 of 2 steps failed"))
+		})
+	})
+
+	Context("with a missing referred benchmark", func() {
+		BeforeEach(func() {
+			cr.Spec.Steps[0].Spec = nil
+			cr.Spec.Steps[0].BenchmarkRef = &corev1.LocalObjectReference{Name: "missing"}
+			setup()
+		})
+
+		It("should fail the step", func() {
+			reconcile()
+
+			suite := stored()
+			Expect(suite.Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+			Expect(suite.Status.Steps[0].Reason).To(Equal(k8s.CreateFailed))
+			Expect(benchmarkExists("second")).To(BeFalse())
+		})
+	})
+
+	Context("with an invalid step", func() {
+		BeforeEach(func() {
+			cr.Spec.Steps[0].Kind = "Unknown"
+			setup()
+		})
+
+		It("should fail the validation", func() {
+			reconcile()
+
+			suite := stored()
+			Expect(suite.Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+			Expect(suite.Status.Reason).To(Equal(k8s.ValidationFailed))
+		})
+	})
+})
@@ -0,0 +1,121 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarksuite
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/template"
+)
+
+const (
+	// SuiteLabel is set on the benchmarks of the steps to the name of the suite
+	SuiteLabel = "kubestone.xridge.io/suite"
+	// StepLabel is set on the benchmarks of the steps to the name of the step
+	StepLabel = "kubestone.xridge.io/suite-step"
+)
+
+// BenchmarkKinds returns an empty custom resource for each benchmark kind
+// registered in the scheme, keyed by kind. The suites themselves are not
+// included, they cannot be nested.
+func BenchmarkKinds(scheme *runtime.Scheme) map[string]perfv1alpha1.Benchmark {
+	kinds := template.Kinds(scheme)
+	delete(kinds, "BenchmarkSuite")
+	return kinds
+}
+
+// IsCrValid validates the steps of the suite against the known benchmark kinds
+func IsCrValid(cr *perfv1alpha1.BenchmarkSuite, kinds map[string]perfv1alpha1.Benchmark) (valid bool, err error) {
+	if len(cr.Spec.Steps) == 0 {
+		return false, fmt.Errorf("the suite has no steps")
+	}
+	if cr.Spec.Runs() > 1 {
+		return false, fmt.Errorf("suites cannot be repeated, the repetitions can be set in the steps")
+	}
+
+	names := map[string]bool{}
+	for _, step := range cr.Spec.Steps {
+		if names[step.Name] {
+			return false, fmt.Errorf("step %q is defined more than once", step.Name)
+		}
+		names[step.Name] = true
+
+		if _, ok := kinds[step.Kind]; !ok {
+			return false, fmt.Errorf("step %q has unknown benchmark kind %q", step.Name, step.Kind)
+		}
+		if (step.Spec == nil) == (step.BenchmarkRef == nil) {
+			return false, fmt.Errorf("step %q must have exactly one of spec and benchmarkRef", step.Name)
+		}
+	}
+	return true, nil
+}
+
+// BenchmarkName returns the name of the benchmark created for the step
+func BenchmarkName(cr *perfv1alpha1.BenchmarkSuite, step *perfv1alpha1.BenchmarkSuiteStep) string {
+	return fmt.Sprintf("%s-%s", cr.Name, step.Name)
+}
+
+// NewBenchmark creates the benchmark of the step from the given spec,
+// which is either the embedded spec of the step or the spec of the
+// referred benchmark. The prototype is an empty custom resource of the
+// kind of the step.
+func NewBenchmark(cr *perfv1alpha1.BenchmarkSuite, step *perfv1alpha1.BenchmarkSuiteStep,
+	prototype perfv1alpha1.Benchmark, spec map[string]interface{}) (perfv1alpha1.Benchmark, error) {
+	benchmark, err := template.NewBenchmark(prototype, spec, BenchmarkName(cr, step), cr.Namespace,
+		map[string]string{
+			SuiteLabel: cr.Name,
+			StepLabel:  step.Name,
+		})
+	if err != nil {
+		return nil, fmt.Errorf("step %q: %v", step.Name, err)
+	}
+	return benchmark, nil
+}
+
+// syncStepStatus copies the times and the results of the benchmark of the
+// step into the step status
+func syncStepStatus(stepStatus *perfv1alpha1.BenchmarkSuiteStepStatus, benchmark perfv1alpha1.Benchmark) {
+	status := benchmark.GetBenchmarkStatus()
+	stepStatus.StartTime = status.StartTime
+	stepStatus.CompletionTime = status.CompletionTime
+	stepStatus.Results = status.Results
+}
+
+// aggregateResults collects the metrics of the steps, prefixed with the
+// name of the step
+func aggregateResults(steps []perfv1alpha1.BenchmarkSuiteStepStatus) *perfv1alpha1.BenchmarkResults {
+	var results perfv1alpha1.BenchmarkResults
+	for _, step := range steps {
+		if step.Results == nil {
+			continue
+		}
+		for _, metric := range step.Results.Metrics {
+			results.Metrics = append(results.Metrics, perfv1alpha1.BenchmarkMetric{
+				Name:  fmt.Sprintf("%s.%s", step.Name, metric.Name),
+				Value: metric.Value,
+				Unit:  metric.Unit,
+			})
+		}
+	}
+	if len(results.Metrics) == 0 {
+		return nil
+	}
+	return &results
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarksuite
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/template"
+)
+
+var _ = Describe("benchmark suite steps", func() {
+	var scheme *runtime.Scheme
+	var kinds map[string]perfv1alpha1.Benchmark
+	var cr *perfv1alpha1.BenchmarkSuite
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(perfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		kinds = BenchmarkKinds(scheme)
+		cr = &perfv1alpha1.BenchmarkSuite{
+			ObjectMeta: metav1.ObjectMeta{Name: "nodepool", Namespace: "suite"},
+			Spec: perfv1alpha1.BenchmarkSuiteSpec{
+				Steps: []perfv1alpha1.BenchmarkSuiteStep{
+					{
+						Name: "fio",
+						Kind: "Fio",
+						Spec: &runtime.RawExtension{Raw: []byte(`{"cmdLineArgs": "--name=randwrite"}`)},
+					},
+					{
+						Name:         "sysbench",
+						Kind:         "Sysbench",
+						BenchmarkRef: &corev1.LocalObjectReference{Name: "sysbench-template"},
+					},
+				},
+			},
+		}
+	})
+
+	Describe("benchmark kinds", func() {
+		It("should contain the benchmarks of the scheme", func() {
+			Expect(kinds).To(HaveKey("Fio"))
+			Expect(kinds).To(HaveKey("Iperf3"))
+			Expect(kinds).To(HaveKey("Pgbench"))
+		})
+
+		It("should not contain the suites and the results", func() {
+			Expect(kinds).NotTo(HaveKey("BenchmarkSuite"))
+			Expect(kinds).NotTo(HaveKey("BenchmarkResult"))
+			Expect(kinds).NotTo(HaveKey("FioList"))
+		})
+	})
+
+	Describe("validation", func() {
+		It("should accept embedded and referred specs", func() {
+			Expect(IsCrValid(cr, kinds)).To(BeTrue())
+		})
+
+		It("should reject unknown kinds", func() {
+			cr.Spec.Steps[0].Kind = "Unknown"
+			valid, err := IsCrValid(cr, kinds)
+			Expect(valid).To(BeFalse())
+			Expect(err).To(MatchError(ContainSubstring("unknown benchmark kind")))
+		})
+
+		It("should reject repetitions of the suite", func() {
+			repetitions := int32(3)
+			cr.Spec.Repetitions = &repetitions
+			valid, _ := IsCrValid(cr, kinds)
+			Expect(valid).To(BeFalse())
+		})
+
+		It("should reject duplicate step names", func() {
+			cr.Spec.Steps[1].Name = "fio"
+			valid, _ := IsCrValid(cr, kinds)
+			Expect(valid).To(BeFalse())
+		})
+
+		It("should reject steps with both spec and reference", func() {
+			cr.Spec.Steps[0].BenchmarkRef = &corev1.LocalObjectReference{Name: "fio"}
+			valid, _ := IsCrValid(cr, kinds)
+			Expect(valid).To(BeFalse())
+		})
+
+		It("should reject steps without spec and reference", func() {
+			cr.Spec.Steps[1].BenchmarkRef = nil
+			valid, _ := IsCrValid(cr, kinds)
+			Expect(valid).To(BeFalse())
+		})
+	})
+
+	Describe("new benchmark", func() {
+		It("should create the benchmark from the embedded spec", func() {
+			step := &cr.Spec.Steps[0]
+			spec, err := template.SpecFromRaw(step.Spec)
+			Expect(err).NotTo(HaveOccurred())
+
+			benchmark, err := NewBenchmark(cr, step, kinds["Fio"], spec)
+			Expect(err).NotTo(HaveOccurred())
+
+			fio, ok := benchmark.(*perfv1alpha1.Fio)
+			Expect(ok).To(BeTrue())
+			Expect(fio.Name).To(Equal("nodepool-fio"))
+			Expect(fio.Namespace).To(Equal("suite"))
+			Expect(fio.Labels).To(HaveKeyWithValue(SuiteLabel, "nodepool"))
+			Expect(fio.Labels).To(HaveKeyWithValue(StepLabel, "fio"))
+			Expect(fio.Spec.CmdLineArgs).To(Equal("--name=randwrite"))
+		})
+
+		It("should take the spec of the referred benchmark without cancellation", func() {
+			referred := &perfv1alpha1.Sysbench{
+				ObjectMeta: metav1.ObjectMeta{Name: "sysbench-template", Namespace: "suite"},
+				Spec: perfv1alpha1.SysbenchSpec{
+					CommonSpec: perfv1alpha1.CommonSpec{Cancel: true},
+					TestName:   "cpu",
+				},
+			}
+			spec, err := template.SpecOf(referred)
+			Expect(err).NotTo(HaveOccurred())
+
+			benchmark, err := NewBenchmark(cr, &cr.Spec.Steps[1], kinds["Sysbench"], spec)
+			Expect(err).NotTo(HaveOccurred())
+
+			sysbench := benchmark.(*perfv1alpha1.Sysbench)
+			Expect(sysbench.Name).To(Equal("nodepool-sysbench"))
+			Expect(sysbench.Spec.TestName).To(Equal("cpu"))
+			Expect(sysbench.Spec.Cancel).To(BeFalse())
+		})
+
+		It("should reject malformed specs", func() {
+			step := &cr.Spec.Steps[0]
+			step.Spec.Raw = []byte(`{"cmdLineArgs": 42}`)
+			spec, err := template.SpecFromRaw(step.Spec)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = NewBenchmark(cr, step, kinds["Fio"], spec)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("results", func() {
+		It("should prefix the metrics with the name of the step", func() {
+			steps := []perfv1alpha1.BenchmarkSuiteStepStatus{
+				{Name: "fio", Results: &perfv1alpha1.BenchmarkResults{
+					Metrics: []perfv1alpha1.BenchmarkMetric{{Name: "read.iops", Value: "1000", Unit: "ops/s"}},
+				}},
+				{Name: "sysbench"},
+			}
+			Expect(aggregateResults(steps).Metrics).To(Equal([]perfv1alpha1.BenchmarkMetric{
+				{Name: "fio.read.iops", Value: "1000", Unit: "ops/s"},
+			}))
+		})
+
+		It("should be empty without step results", func() {
+			Expect(aggregateResults(nil)).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,38 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarksuite
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/webhooks"
+)
+
+// +kubebuilder:webhook:path=/validate-perf-kubestone-xridge-io-v1alpha1-benchmarksuite,mutating=false,failurePolicy=fail,groups=perf.kubestone.xridge.io,resources=benchmarksuites,verbs=create;update,versions=v1alpha1,name=vbenchmarksuite.kubestone.xridge.io
+
+// SetupWebhookWithManager registers the admission webhooks of BenchmarkSuite
+func SetupWebhookWithManager(mgr ctrl.Manager) error {
+	kinds := BenchmarkKinds(mgr.GetScheme())
+	return webhooks.SetupWithManager(mgr, &perfv1alpha1.BenchmarkSuite{}, webhooks.Hooks{
+		Validate: func(obj runtime.Object) error {
+			_, err := IsCrValid(obj.(*perfv1alpha1.BenchmarkSuite), kinds)
+			return err
+		},
+	})
+}
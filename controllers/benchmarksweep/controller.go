@@ -0,0 +1,118 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarksweep
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/lifecycle"
+	"github.com/xridge/kubestone/pkg/template"
+)
+
+// Reconciler provides fields from manager to reconciler
+type Reconciler struct {
+	K8S k8s.Access
+	Log logr.Logger
+}
+
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=benchmarkfanouts;benchmarksuites;drills;esrallies;fios;iopings;iperf3matrixes;iperf3s;kafkabenches;nighthawks;ocplogtests;osbenches;perfbenches;pgbenches;pvclatencies;qperves;s3benches;sysbenches;ycsbbenches,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=benchmarksweeps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=benchmarksweeps/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=benchmarksweeps/finalizers,verbs=update
+
+// Reconcile expands the parameters of the sweep into runs, creates their
+// benchmarks with limited parallelism, and collects their metrics into
+// the status of the sweep
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+
+	var cr perfv1alpha1.BenchmarkSweep
+	if err := r.K8S.Client.Get(ctx, req.NamespacedName, &cr); err != nil {
+		return ctrl.Result{}, k8s.IgnoreNotFound(err)
+	}
+
+	// Run to one completion
+	if cr.Status.IsFinished() {
+		return ctrl.Result{}, nil
+	}
+
+	kinds := BenchmarkKinds(r.K8S.Scheme)
+	if valid, err := lifecycle.Validate(ctx, &r.K8S, &cr, func() (bool, error) {
+		return IsCrValid(&cr, kinds)
+	}); !valid || err != nil {
+		return ctrl.Result{}, err
+	}
+
+	original := cr.DeepCopy()
+	if len(cr.Status.Runs) == 0 {
+		runs, err := Expand(&cr)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		cr.Status.Runs = make([]perfv1alpha1.BenchmarkSweepRun, len(runs))
+		for i, parameters := range runs {
+			cr.Status.Runs[i] = perfv1alpha1.BenchmarkSweepRun{Parameters: parameters}
+		}
+	}
+
+	prototype := kinds[cr.Spec.Template.Kind]
+	statuses := make([]*perfv1alpha1.ChildBenchmarkStatus, len(cr.Status.Runs))
+	for i := range cr.Status.Runs {
+		statuses[i] = &cr.Status.Runs[i].ChildBenchmarkStatus
+	}
+	children := lifecycle.Children{
+		Statuses:     statuses,
+		Parallelism:  cr.Spec.Parallelism,
+		Noun:         "runs",
+		FailedReason: k8s.RunFailed,
+		Describe: func(i int) string {
+			return fmt.Sprintf("run %d", i)
+		},
+		Prototype: func(i int) perfv1alpha1.Benchmark {
+			return prototype
+		},
+		New: func(ctx context.Context, i int) (perfv1alpha1.Benchmark, error) {
+			return NewBenchmark(&cr, i, prototype, cr.Status.Runs[i].Parameters)
+		},
+		Sync: func(i int, benchmark perfv1alpha1.Benchmark) {
+			syncRunStatus(&cr.Status.Runs[i], benchmark)
+		},
+		Summarize: func(finished bool) {
+			cr.Status.Results = aggregateResults(cr.Status.Runs)
+		},
+	}
+	return ctrl.Result{}, children.Reconcile(ctx, &r.K8S, &cr, original)
+}
+
+// SetupWithManager registers the Reconciler with the provided manager.
+// The sweep is reconciled whenever the benchmark of any of its runs changes.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&perfv1alpha1.BenchmarkSweep{})
+
+	kinds := BenchmarkKinds(mgr.GetScheme())
+	for _, kind := range template.SortedKinds(kinds) {
+		builder = builder.Owns(kinds[kind])
+	}
+	return builder.Complete(lifecycle.NewReconciler(r, &r.K8S, &perfv1alpha1.BenchmarkSweep{}))
+}
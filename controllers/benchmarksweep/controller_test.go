@@ -0,0 +1,191 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarksweep
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+)
+
+var _ = Describe("benchmark sweep reconciler", func() {
+	var ctx context.Context
+	var cr *perfv1alpha1.BenchmarkSweep
+	var name types.NamespacedName
+	var reconciler *Reconciler
+
+	setup := func() {
+		scheme := runtime.NewScheme()
+		_ = k8sscheme.AddToScheme(scheme)
+		_ = perfv1alpha1.AddToScheme(scheme)
+		reconciler = &Reconciler{K8S: k8s.Access{
+			Client:        fake.NewFakeClientWithScheme(scheme, cr.DeepCopy()),
+			Scheme:        scheme,
+			EventRecorder: record.NewFakeRecorder(100),
+		}}
+	}
+
+	reconcile := func() {
+		_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	stored := func() *perfv1alpha1.BenchmarkSweep {
+		var sweep perfv1alpha1.BenchmarkSweep
+		Expect(reconciler.K8S.Client.Get(ctx, name, &sweep)).To(Succeed())
+		return &sweep
+	}
+
+	runName := func(index int) types.NamespacedName {
+		return types.NamespacedName{Namespace: name.Namespace, Name: fmt.Sprintf("%s-%d", name.Name, index)}
+	}
+
+	benchmarkExists := func(index int) bool {
+		var sysbench perfv1alpha1.Sysbench
+		err := reconciler.K8S.Client.Get(ctx, runName(index), &sysbench)
+		Expect(errors.IsNotFound(err) || err == nil).To(BeTrue())
+		return err == nil
+	}
+
+	finishRun := func(index int, phase perfv1alpha1.BenchmarkPhase) {
+		var sysbench perfv1alpha1.Sysbench
+		Expect(reconciler.K8S.Client.Get(ctx, runName(index), &sysbench)).To(Succeed())
+		sysbench.Status.SetPhase(phase, "", "")
+		sysbench.Status.Results = &perfv1alpha1.BenchmarkResults{}
+		sysbench.Status.Results.AddMetric("events", float64(100*(index+1)), "events/s")
+		Expect(reconciler.K8S.Client.Status().Update(ctx, &sysbench)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		name = types.NamespacedName{Namespace: "sweep", Name: "threads"}
+		cr = &perfv1alpha1.BenchmarkSweep{
+			ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+			Spec: perfv1alpha1.BenchmarkSweepSpec{
+				Template: perfv1alpha1.BenchmarkTemplate{
+					Kind: "Sysbench",
+					Spec: &runtime.RawExtension{Raw: []byte(
+						`{"testName": "cpu", "options": "--threads=$(threads)"}`)},
+				},
+				Parameters: []perfv1alpha1.SweepParameter{
+					{Name: "threads", Values: []string{"1", "2", "4"}},
+				},
+			},
+		}
+	})
+
+	Context("with serial runs", func() {
+		BeforeEach(setup)
+
+		It("should run the combinations one after the other", func() {
+			reconcile()
+			sweep := stored()
+			Expect(sweep.Status.Phase).To(Equal(perfv1alpha1.BenchmarkRunning))
+			Expect(sweep.Status.Runs).To(HaveLen(3))
+			Expect(benchmarkExists(0)).To(BeTrue())
+			Expect(benchmarkExists(1)).To(BeFalse())
+
+			for i := 0; i < 3; i++ {
+				finishRun(i, perfv1alpha1.BenchmarkSucceeded)
+				reconcile()
+			}
+
+			sweep = stored()
+			Expect(sweep.Status.Phase).To(Equal(perfv1alpha1.BenchmarkSucceeded))
+			Expect(sweep.Status.Runs[2].Parameters).To(HaveKeyWithValue("threads", "4"))
+			Expect(sweep.Status.Runs[2].BenchmarkName).To(Equal("threads-2"))
+			Expect(sweep.Status.Runs[2].Metrics).To(ConsistOf(perfv1alpha1.BenchmarkMetric{
+				Name: "events", Value: "300", Unit: "events/s",
+			}))
+			Expect(sweep.Status.Children).To(HaveLen(3))
+		})
+
+	})
+
+	Context("with assertions", func() {
+		BeforeEach(func() {
+			cr.Spec.Assertions = []perfv1alpha1.Assertion{
+				{Metric: "run-2.events", Operator: ">=", Value: "400"},
+			}
+			setup()
+		})
+
+		It("should evaluate the assertions on the metrics of the runs", func() {
+			reconcile()
+			for i := 0; i < 3; i++ {
+				finishRun(i, perfv1alpha1.BenchmarkSucceeded)
+				reconcile()
+			}
+
+			sweep := stored()
+			Expect(sweep.Status.Results.Metrics).To(HaveLen(3))
+			Expect(sweep.Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+			Expect(sweep.Status.Reason).To(Equal(k8s.AssertionFailed))
+			Expect(sweep.Status.Assertions).To(HaveLen(1))
+			Expect(sweep.Status.Assertions[0].Observed).To(Equal("300"))
+		})
+	})
+
+	Context("with values not matching the type of the field", func() {
+		BeforeEach(func() {
+			cr.Spec.Template.Kind = "Iperf3"
+			cr.Spec.Template.Spec = &runtime.RawExtension{Raw: []byte(`{"udp": false}`)}
+			cr.Spec.Parameters[0] = perfv1alpha1.SweepParameter{
+				Name: "udp", Path: "udp", Values: []string{"yes", "true"},
+			}
+			setup()
+		})
+
+		It("should fail the run, but continue the sweep", func() {
+			reconcile()
+
+			sweep := stored()
+			Expect(sweep.Status.Runs[0].Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+			Expect(sweep.Status.Runs[0].Reason).To(Equal(k8s.CreateFailed))
+			Expect(sweep.Status.Runs[1].BenchmarkName).To(Equal("threads-1"))
+		})
+	})
+
+	Context("with an unknown kind", func() {
+		BeforeEach(func() {
+			cr.Spec.Template.Kind = "Unknown"
+			setup()
+		})
+
+		It("should fail validation", func() {
+			reconcile()
+
+			sweep := stored()
+			Expect(sweep.Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+			Expect(sweep.Status.Reason).To(Equal(k8s.ValidationFailed))
+		})
+	})
+})
@@ -0,0 +1,253 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarksweep
+
+import (
+	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/template"
+)
+
+const (
+	// SweepLabel is set on the benchmarks of the runs to the name of the sweep
+	SweepLabel = "kubestone.xridge.io/sweep"
+	// RunLabel is set on the benchmarks of the runs to the index of the run
+	RunLabel = "kubestone.xridge.io/sweep-run"
+
+	// MaxRuns limits the number of combinations of the parameter values,
+	// as the results of every run are kept in the status of the sweep
+	MaxRuns = 256
+)
+
+// BenchmarkKinds returns an empty custom resource for each benchmark kind
+// registered in the scheme, keyed by kind. The sweeps themselves are not
+// included, they cannot be nested.
+func BenchmarkKinds(scheme *runtime.Scheme) map[string]perfv1alpha1.Benchmark {
+	kinds := template.Kinds(scheme)
+	delete(kinds, "BenchmarkSweep")
+	return kinds
+}
+
+// IsCrValid validates the template and the parameters of the sweep
+func IsCrValid(cr *perfv1alpha1.BenchmarkSweep, kinds map[string]perfv1alpha1.Benchmark) (valid bool, err error) {
+	prototype, ok := kinds[cr.Spec.Template.Kind]
+	if !ok {
+		return false, fmt.Errorf("unknown benchmark kind %q", cr.Spec.Template.Kind)
+	}
+	spec, err := template.SpecFromRaw(cr.Spec.Template.Spec)
+	if err != nil {
+		return false, fmt.Errorf("invalid template spec: %v", err)
+	}
+	if len(cr.Spec.Parameters) == 0 {
+		return false, fmt.Errorf("the sweep has no parameters")
+	}
+	if cr.Spec.Runs() > 1 {
+		return false, fmt.Errorf("sweeps cannot be repeated, the repetitions can be set in the template")
+	}
+
+	names := map[string]bool{}
+	values := map[string][]string{}
+	runs := 1
+	for i := range cr.Spec.Parameters {
+		parameter := &cr.Spec.Parameters[i]
+		if names[parameter.Name] {
+			return false, fmt.Errorf("parameter %q is defined more than once", parameter.Name)
+		}
+		names[parameter.Name] = true
+
+		if parameter.Path != "" {
+			if _, err := template.ParsePath(parameter.Path); err != nil {
+				return false, fmt.Errorf("parameter %q: %v", parameter.Name, err)
+			}
+		}
+		values[parameter.Name], err = Values(parameter)
+		if err != nil {
+			return false, fmt.Errorf("parameter %q: %v", parameter.Name, err)
+		}
+		runs *= len(values[parameter.Name])
+		if runs > MaxRuns {
+			return false, fmt.Errorf("the sweep has more than %d runs", MaxRuns)
+		}
+	}
+
+	// The fields unknown to the kind would be silently dropped
+	first := map[string]string{}
+	for name, parameterValues := range values {
+		first[name] = parameterValues[0]
+	}
+	template.Substitute(spec, first)
+	for _, parameter := range cr.Spec.Parameters {
+		if parameter.Path == "" {
+			continue
+		}
+		if err := checkField(prototype, spec, parameter.Path, values[parameter.Name]); err != nil {
+			return false, fmt.Errorf("parameter %q: %v", parameter.Name, err)
+		}
+	}
+	return true, nil
+}
+
+// checkField checks that the kind has the field at the path. As zero
+// values might not survive the check, it is enough if one of the values
+// of the parameter does. The values not matching the type of the field
+// fail their runs only.
+func checkField(prototype perfv1alpha1.Benchmark, spec map[string]interface{}, path string,
+	values []string) (err error) {
+	for _, value := range values {
+		if err = template.CheckField(prototype, spec, path, value); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// Values returns the values of the parameter, either listed or generated
+// from its range
+func Values(parameter *perfv1alpha1.SweepParameter) ([]string, error) {
+	if (len(parameter.Values) == 0) == (parameter.Range == nil) {
+		return nil, fmt.Errorf("exactly one of values and range must be given")
+	}
+	if parameter.Range == nil {
+		return parameter.Values, nil
+	}
+
+	r := parameter.Range
+	if r.From > r.To {
+		return nil, fmt.Errorf("range starts after its end")
+	}
+	if r.Step != 0 && r.Factor != 0 {
+		return nil, fmt.Errorf("range must not have both step and factor")
+	}
+	if r.Factor != 0 && (r.Factor < 2 || r.From < 1) {
+		return nil, fmt.Errorf("geometric range needs a factor of at least 2 and a positive start")
+	}
+	if r.Step < 0 {
+		return nil, fmt.Errorf("range step must be positive")
+	}
+
+	var values []string
+	for value := r.From; value <= r.To; {
+		values = append(values, strconv.FormatInt(value, 10))
+		if len(values) > MaxRuns {
+			return nil, fmt.Errorf("range has more than %d values", MaxRuns)
+		}
+		if r.Factor != 0 {
+			value *= r.Factor
+		} else if r.Step != 0 {
+			value += r.Step
+		} else {
+			value++
+		}
+	}
+	return values, nil
+}
+
+// Expand returns the parameter values of the runs of the sweep: the
+// cartesian product of the values of the parameters, the values of the
+// first parameter changing the slowest
+func Expand(cr *perfv1alpha1.BenchmarkSweep) ([]map[string]string, error) {
+	runs := []map[string]string{{}}
+	for i := range cr.Spec.Parameters {
+		parameter := &cr.Spec.Parameters[i]
+		values, err := Values(parameter)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q: %v", parameter.Name, err)
+		}
+
+		expanded := make([]map[string]string, 0, len(runs)*len(values))
+		for _, run := range runs {
+			for _, value := range values {
+				next := make(map[string]string, len(run)+1)
+				for name, v := range run {
+					next[name] = v
+				}
+				next[parameter.Name] = value
+				expanded = append(expanded, next)
+			}
+		}
+		runs = expanded
+	}
+	return runs, nil
+}
+
+// BenchmarkName returns the name of the benchmark created for the run
+// with the given index
+func BenchmarkName(cr *perfv1alpha1.BenchmarkSweep, index int) string {
+	return fmt.Sprintf("%s-%d", cr.Name, index)
+}
+
+// NewBenchmark creates the benchmark of the run with the given index from
+// the template of the sweep. The prototype is an empty custom resource of
+// the kind of the template.
+func NewBenchmark(cr *perfv1alpha1.BenchmarkSweep, index int, prototype perfv1alpha1.Benchmark,
+	parameters map[string]string) (perfv1alpha1.Benchmark, error) {
+	spec, err := template.SpecFromRaw(cr.Spec.Template.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Substitute(spec, parameters)
+	for _, parameter := range cr.Spec.Parameters {
+		if parameter.Path == "" {
+			continue
+		}
+		if err := template.SetField(spec, parameter.Path, parameters[parameter.Name]); err != nil {
+			return nil, fmt.Errorf("parameter %q: %v", parameter.Name, err)
+		}
+	}
+
+	labels := map[string]string{}
+	for key, value := range cr.Spec.Template.Labels {
+		labels[key] = value
+	}
+	labels[SweepLabel] = cr.Name
+	labels[RunLabel] = strconv.Itoa(index)
+
+	return template.NewBenchmark(prototype, spec, BenchmarkName(cr, index), cr.Namespace, labels)
+}
+
+// syncRunStatus copies the metrics of the benchmark of the run into the run status
+func syncRunStatus(run *perfv1alpha1.BenchmarkSweepRun, benchmark perfv1alpha1.Benchmark) {
+	status := benchmark.GetBenchmarkStatus()
+	run.Metrics = nil
+	if status.Results != nil {
+		run.Metrics = status.Results.Metrics
+	}
+}
+
+// aggregateResults collects the metrics of the runs, prefixed with the
+// index of the run (e.g. run-2.tps)
+func aggregateResults(runs []perfv1alpha1.BenchmarkSweepRun) *perfv1alpha1.BenchmarkResults {
+	var results perfv1alpha1.BenchmarkResults
+	for i, run := range runs {
+		for _, metric := range run.Metrics {
+			results.Metrics = append(results.Metrics, perfv1alpha1.BenchmarkMetric{
+				Name:  fmt.Sprintf("run-%d.%s", i, metric.Name),
+				Value: metric.Value,
+				Unit:  metric.Unit,
+			})
+		}
+	}
+	if len(results.Metrics) == 0 {
+		return nil
+	}
+	return &results
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarksweep
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("benchmark sweep", func() {
+	var cr *perfv1alpha1.BenchmarkSweep
+	var kinds map[string]perfv1alpha1.Benchmark
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(perfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		kinds = BenchmarkKinds(scheme)
+
+		cr = &perfv1alpha1.BenchmarkSweep{
+			ObjectMeta: metav1.ObjectMeta{Name: "knee", Namespace: "kubestone"},
+			Spec: perfv1alpha1.BenchmarkSweepSpec{
+				Template: perfv1alpha1.BenchmarkTemplate{
+					Kind:   "Sysbench",
+					Labels: map[string]string{"team": "storage"},
+					Spec: &runtime.RawExtension{Raw: []byte(
+						`{"testName": "cpu", "options": "--threads=$(threads) --time=10"}`)},
+				},
+				Parameters: []perfv1alpha1.SweepParameter{
+					{Name: "threads", Range: &perfv1alpha1.SweepRange{From: 1, To: 8, Factor: 2}},
+					{Name: "test", Path: ".testName", Values: []string{"cpu", "memory"}},
+				},
+			},
+		}
+	})
+
+	It("should not allow nested sweeps", func() {
+		Expect(kinds).NotTo(HaveKey("BenchmarkSweep"))
+		Expect(kinds).To(HaveKey("BenchmarkSuite"))
+	})
+
+	It("should generate the values of ranges", func() {
+		values := func(r perfv1alpha1.SweepRange) []string {
+			v, err := Values(&perfv1alpha1.SweepParameter{Range: &r})
+			Expect(err).NotTo(HaveOccurred())
+			return v
+		}
+		Expect(values(perfv1alpha1.SweepRange{From: 1, To: 64, Factor: 2})).To(
+			Equal([]string{"1", "2", "4", "8", "16", "32", "64"}))
+		Expect(values(perfv1alpha1.SweepRange{From: 10, To: 35, Step: 10})).To(
+			Equal([]string{"10", "20", "30"}))
+		Expect(values(perfv1alpha1.SweepRange{From: 1, To: 3})).To(
+			Equal([]string{"1", "2", "3"}))
+	})
+
+	It("should reject invalid ranges", func() {
+		for _, r := range []perfv1alpha1.SweepRange{
+			{From: 2, To: 1},
+			{From: 1, To: 8, Step: 1, Factor: 2},
+			{From: 0, To: 8, Factor: 2},
+			{From: 1, To: 100000},
+		} {
+			r := r
+			_, err := Values(&perfv1alpha1.SweepParameter{Range: &r})
+			Expect(err).To(HaveOccurred())
+		}
+		_, err := Values(&perfv1alpha1.SweepParameter{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should expand the cartesian product of the parameters", func() {
+		runs, err := Expand(cr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(runs).To(HaveLen(8))
+		Expect(runs[0]).To(Equal(map[string]string{"threads": "1", "test": "cpu"}))
+		Expect(runs[1]).To(Equal(map[string]string{"threads": "1", "test": "memory"}))
+		Expect(runs[7]).To(Equal(map[string]string{"threads": "8", "test": "memory"}))
+	})
+
+	Context("validation", func() {
+		It("should accept valid sweeps", func() {
+			Expect(IsCrValid(cr, kinds)).To(BeTrue())
+		})
+
+		It("should reject unknown kinds", func() {
+			cr.Spec.Template.Kind = "BenchmarkSweep"
+			valid, err := IsCrValid(cr, kinds)
+			Expect(valid).To(BeFalse())
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject repetitions of the sweep", func() {
+			cr.Spec.WarmupRuns = 1
+			valid, _ := IsCrValid(cr, kinds)
+			Expect(valid).To(BeFalse())
+		})
+
+		It("should reject duplicate parameters", func() {
+			cr.Spec.Parameters[1].Name = "threads"
+			valid, _ := IsCrValid(cr, kinds)
+			Expect(valid).To(BeFalse())
+		})
+
+		It("should reject invalid paths", func() {
+			cr.Spec.Parameters[1].Path = "tests[x]"
+			valid, _ := IsCrValid(cr, kinds)
+			Expect(valid).To(BeFalse())
+		})
+
+		It("should reject paths to fields the kind does not have", func() {
+			cr.Spec.Parameters[1].Path = ".podConfig.bogusField"
+			valid, err := IsCrValid(cr, kinds)
+			Expect(valid).To(BeFalse())
+			Expect(err.Error()).To(ContainSubstring("no such field"))
+		})
+
+		It("should accept paths to fields missing from the template", func() {
+			cr.Spec.Parameters[1] = perfv1alpha1.SweepParameter{
+				Name: "cpu", Path: ".podConfig.resources.limits.cpu", Values: []string{"1", "2"},
+			}
+			Expect(IsCrValid(cr, kinds)).To(BeTrue())
+		})
+
+		It("should limit the number of runs", func() {
+			cr.Spec.Parameters[0].Range = &perfv1alpha1.SweepRange{From: 1, To: 200}
+			valid, err := IsCrValid(cr, kinds)
+			Expect(valid).To(BeFalse())
+			Expect(err.Error()).To(ContainSubstring("more than"))
+		})
+	})
+
+	It("should create the benchmarks of the runs", func() {
+		benchmark, err := NewBenchmark(cr, 3, kinds["Sysbench"],
+			map[string]string{"threads": "2", "test": "memory"})
+		Expect(err).NotTo(HaveOccurred())
+
+		sysbench := benchmark.(*perfv1alpha1.Sysbench)
+		Expect(sysbench.Name).To(Equal("knee-3"))
+		Expect(sysbench.Namespace).To(Equal("kubestone"))
+		Expect(sysbench.Labels).To(HaveKeyWithValue(SweepLabel, "knee"))
+		Expect(sysbench.Labels).To(HaveKeyWithValue(RunLabel, "3"))
+		Expect(sysbench.Labels).To(HaveKeyWithValue("team", "storage"))
+		Expect(sysbench.Spec.TestName).To(Equal("memory"))
+		Expect(sysbench.Spec.Options).To(Equal("--threads=2 --time=10"))
+	})
+})
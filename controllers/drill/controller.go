@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 
@@ -27,6 +28,8 @@ import (
 
 	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
 	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/lifecycle"
+	"github.com/xridge/kubestone/pkg/results"
 )
 
 // Reconciler reconciles a Drill object
@@ -49,24 +52,24 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	}
 
 	// Run to one completion
-	if cr.Status.Completed {
+	if cr.Status.IsFinished() {
 		return ctrl.Result{}, nil
 	}
 
 	// Validate on first entry
-	if !cr.Status.Completed && !cr.Status.Running {
+	if cr.Status.Phase == "" {
+		if err := r.K8S.UpdatePhase(ctx, &cr, perfv1alpha1.BenchmarkValidating, "", ""); err != nil {
+			return ctrl.Result{}, err
+		}
 		if valid, err := IsCrValid(&cr); !valid {
 			_ = r.K8S.RecordEventf(&cr, corev1.EventTypeWarning, k8s.CreateFailed,
 				"CR validation failed: %v", err)
 
 			// Do not requeue invalid CRs
-			return ctrl.Result{}, nil
+			return ctrl.Result{}, r.K8S.UpdatePhase(ctx, &cr, perfv1alpha1.BenchmarkFailed,
+				k8s.ValidationFailed, err.Error())
 		}
-	}
-
-	cr.Status.Running = true
-	if err := r.K8S.Client.Status().Update(ctx, &cr); err != nil {
-		return ctrl.Result{}, err
+		cr.Status.SetCondition(perfv1alpha1.ConditionValidated, corev1.ConditionTrue, "", "")
 	}
 
 	configMap := NewConfigMap(&cr)
@@ -79,35 +82,52 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, err
 	}
 
-	// Check if finished
-	jobFinished, err := r.K8S.IsJobFinished(types.NamespacedName{
+	jobName := types.NamespacedName{
 		Namespace: cr.Namespace,
 		Name:      cr.Name,
-	})
+	}
+	if err := r.K8S.SyncRunningPhase(ctx, &cr, jobName); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Check if finished
+	jobFinished, err := r.K8S.IsJobFinished(jobName)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 	if !jobFinished {
 		// Wait for the job to be completed
-		return ctrl.Result{Requeue: true}, nil
+		return k8s.WaitForJobs(&cr), nil
+	}
+
+	jobFailure, err := r.K8S.GetJobFailure(jobName)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 
 	// The cr could have been modified since the last time we got it
 	if err := r.K8S.Client.Get(ctx, req.NamespacedName, &cr); err != nil {
 		return ctrl.Result{}, k8s.IgnoreNotFound(err)
 	}
-	cr.Status.Running = false
-	cr.Status.Completed = true
-	if err := r.K8S.Client.Status().Update(ctx, &cr); err != nil {
+	// The results are parsed from the logs of the successful benchmark
+	if jobFailure == nil {
+		if err := results.Collect(&r.K8S, &cr, jobName); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.K8S.FinishBenchmark(ctx, &cr, jobFailure); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	// Keep a record of the run, which outlives the benchmark
+	return ctrl.Result{}, results.Record(ctx, &r.K8S, &cr, jobName)
 }
 
 // SetupWithManager registers the Reconciler with the provided manager
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&perfv1alpha1.Drill{}).
-		Complete(r)
+		Owns(&batchv1.Job{}).
+		Complete(lifecycle.NewReconciler(r, &r.K8S, &perfv1alpha1.Drill{}))
 }
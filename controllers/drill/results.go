@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drill
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/results"
+)
+
+func init() {
+	results.Register("Drill", results.ParserFunc(ParseResults))
+}
+
+var rpsRegexp = regexp.MustCompile(`Requests per second\s+([0-9.]+)`)
+
+// drillMetric describes a value of the drill statistics
+type drillMetric struct {
+	name    string
+	regexp  *regexp.Regexp
+	latency bool
+}
+
+var drillMetrics = []drillMetric{
+	{"requests", regexp.MustCompile(`Total requests\s+([0-9]+)`), false},
+	{"requests.failed", regexp.MustCompile(`Failed requests\s+([0-9]+)`), false},
+	{"latency.median", regexp.MustCompile(`Median time per request\s+([0-9.]+\s*[a-z]+)`), true},
+	{"latency.avg", regexp.MustCompile(`Average time per request\s+([0-9.]+\s*[a-z]+)`), true},
+	{"latency.stddev", regexp.MustCompile(`Sample standard deviation\s+([0-9.]+\s*[a-z]+)`), true},
+	{"latency.p99", regexp.MustCompile(`99(?:\.0)?'th percentile\s+([0-9.]+\s*[a-z]+)`), true},
+	{"latency.p995", regexp.MustCompile(`99\.5'th percentile\s+([0-9.]+\s*[a-z]+)`), true},
+	{"latency.p999", regexp.MustCompile(`99\.9'th percentile\s+([0-9.]+\s*[a-z]+)`), true},
+}
+
+// ParseResults parses the statistics printed by drill with the --stats
+// option. Latencies are reported in microseconds.
+func ParseResults(logs string) (*perfv1alpha1.BenchmarkResults, error) {
+	match := rpsRegexp.FindStringSubmatch(logs)
+	if match == nil {
+		return nil, errors.New("statistics are missing from drill output, is --stats enabled?")
+	}
+
+	res := &perfv1alpha1.BenchmarkResults{}
+	rps, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return nil, err
+	}
+	res.AddMetric("rps", rps, "req/s")
+
+	for _, metric := range drillMetrics {
+		match := metric.regexp.FindStringSubmatch(logs)
+		if match == nil {
+			continue
+		}
+		if metric.latency {
+			latency, err := results.ParseMicroseconds(match[1])
+			if err != nil {
+				return nil, err
+			}
+			res.AddMetric(metric.name, latency, "us")
+			continue
+		}
+		count, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return nil, err
+		}
+		res.AddMetric(metric.name, count, "")
+	}
+
+	return res, nil
+}
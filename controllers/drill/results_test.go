@@ -0,0 +1,70 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drill
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const drillOutput = `+ cd /benchmarks
++ drill --stats --benchmark benchmark.yml
+Fetch index               http://nginx/                           200 OK 3ms
+Fetch index               http://nginx/                           200 OK 2ms
+
+Time taken for tests      1.2 seconds
+Total requests            100
+Successful requests       98
+Failed requests           2
+Requests per second       81.44 [#/sec]
+Median time per request   11ms
+Average time per request  12ms
+Sample standard deviation 3ms
+99.0'th percentile        22ms
+99.5'th percentile        23ms
+99.9'th percentile        25ms
+`
+
+var _ = Describe("drill results", func() {
+	Context("with the output of a successful run", func() {
+		results, err := ParseResults(drillOutput)
+
+		It("should not fail", func() {
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reports the request rate and counts", func() {
+			Expect(results.GetMetric("rps").Value).To(Equal("81.44"))
+			Expect(results.GetMetric("requests").Value).To(Equal("100"))
+			Expect(results.GetMetric("requests.failed").Value).To(Equal("2"))
+		})
+
+		It("reports the latencies in microseconds", func() {
+			Expect(results.GetMetric("latency.median").Value).To(Equal("11000"))
+			Expect(results.GetMetric("latency.avg").Value).To(Equal("12000"))
+			Expect(results.GetMetric("latency.p99").Value).To(Equal("22000"))
+			Expect(results.GetMetric("latency.p999").Value).To(Equal("25000"))
+		})
+	})
+
+	Context("without statistics", func() {
+		It("should fail", func() {
+			_, err := ParseResults("Fetch index http://nginx/ 200 OK 3ms\n")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
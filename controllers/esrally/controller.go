@@ -18,8 +18,11 @@ package esrally
 
 import (
 	"context"
-	"github.com/xridge/kubestone/api/v1alpha1"
 	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/lifecycle"
+	"github.com/xridge/kubestone/pkg/results"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -36,8 +39,8 @@ type Reconciler struct {
 	Log logr.Logger
 }
 
-// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;create;delete
-// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;create;delete
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;create;delete;watch
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;create;delete;watch
 
 // +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=esrallies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=esrallies/status,verbs=get;update;patch
@@ -53,7 +56,7 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	}
 
 	// Return if its completed
-	if cr.Status.Completed {
+	if cr.Status.IsFinished() {
 		return ctrl.Result{}, nil
 	}
 
@@ -62,37 +65,37 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		Name:      cr.Name,
 	}
 
-	if cr.Spec.Image == (v1alpha1.ImageSpec{}) {
-		cr.Spec.Image = v1alpha1.ImageSpec{
-			Name:       "diamantisolutions/esrally:kubestone",
-			PullPolicy: "Always",
-		}
-	}
+	Default(&cr)
 
-	// If its not running, create job and mark it as running
-	if !cr.Status.Running {
+	// If its not started yet, create job and start deploying the StatefulSet.
+	// The job is created again for each run of a repeated benchmark.
+	if cr.Status.Phase == "" || cr.Status.Phase == perfv1alpha1.BenchmarkPending {
 		return esRallyJobHandler(cr, r, ctx, namespaceName)
 	}
 
+	// A failed coordinator job terminates the benchmark, even if the
+	// StatefulSet has not been deployed or is not ready yet
+	jobFailure, err := r.K8S.GetJobFailure(namespaceName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if jobFailure != nil {
+		return esRallyCompletionHandler(cr, r, ctx, namespaceName, jobFailure)
+	}
+
 	// Grab the job pod to pass to statefulset
 	pods, err := r.K8S.GetJobPods(namespaceName)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	if len(pods.Items) == 0 || pods.Items[0].Status.PodIP == "" {
+	if pods == nil || len(pods.Items) == 0 || pods.Items[0].Status.PodIP == "" {
 		logger.Info("waiting for pod ip")
-		return ctrl.Result{Requeue: true}, nil
+		return k8s.RequeueWithBackoff(&cr), nil
 	}
 
 	// Deploy statefulset
-	if !cr.Status.Deployed {
-		return esRallyDeployHandler(cr, r, ctx, pods.Items[0].Status.PodIP)
-	}
-
-	_, ready, _ := r.K8S.IsStatefulSetReady(namespaceName)
-	if !ready {
-		// We need to wait for the StatefulSet to be ready, so requeue
-		return ctrl.Result{Requeue: true}, nil
+	if !cr.Status.IsConditionTrue(perfv1alpha1.ConditionServerReady) {
+		return esRallyDeployHandler(cr, r, ctx, namespaceName, pods.Items[0].Status.PodIP)
 	}
 
 	jobFinished, err := r.K8S.IsJobFinished(namespaceName)
@@ -102,25 +105,39 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	if !jobFinished {
 		// Wait for the job to be completed
-		return ctrl.Result{Requeue: true}, nil
+		return k8s.WaitForJobs(&cr), nil
 	}
 
+	jobFailure, err = r.K8S.GetJobFailure(namespaceName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return esRallyCompletionHandler(cr, r, ctx, namespaceName, jobFailure)
+}
+
+func esRallyCompletionHandler(cr perfv1alpha1.EsRally, r *Reconciler, ctx context.Context, namespaceName types.NamespacedName, jobFailure *k8s.JobFailure) (ctrl.Result, error) {
 	// The cr could have been modified since the last time we got it
-	if err := r.K8S.Client.Get(ctx, req.NamespacedName, &cr); err != nil {
+	if err := r.K8S.Client.Get(ctx, namespaceName, &cr); err != nil {
 		return ctrl.Result{}, k8s.IgnoreNotFound(err)
 	}
 
-	cr.Status.Running = false
-	cr.Status.Completed = true
+	// The results are parsed from the logs of the successful benchmark
+	if jobFailure == nil {
+		if err := results.Collect(&r.K8S, &cr, namespaceName); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 
-	if err := r.K8S.Client.Status().Update(ctx, &cr); err != nil {
+	if err := r.K8S.FinishBenchmark(ctx, &cr, jobFailure); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	// Keep a record of the run, which outlives the benchmark
+	return ctrl.Result{}, results.Record(ctx, &r.K8S, &cr, namespaceName)
 }
 
-func esRallyDeployHandler(cr perfv1alpha1.EsRally, r *Reconciler, ctx context.Context, ip string) (ctrl.Result, error) {
+func esRallyDeployHandler(cr perfv1alpha1.EsRally, r *Reconciler, ctx context.Context, namespaceName types.NamespacedName, ip string) (ctrl.Result, error) {
 	statefulSet, sError := NewStatefulSet(&cr, ip)
 	if sError != nil {
 		return ctrl.Result{}, sError
@@ -151,25 +168,46 @@ func esRallyDeployHandler(cr perfv1alpha1.EsRally, r *Reconciler, ctx context.Co
 		return ctrl.Result{}, err
 	}
 
-	// set Deployed as true
-	cr.Status.Deployed = true
-	if err := r.K8S.Client.Status().Update(ctx, &cr); err != nil {
+	_, ready, err := r.K8S.IsStatefulSetReady(namespaceName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !ready {
+		// The owned StatefulSet triggers the reconciliation when its
+		// replicas become ready
+		return ctrl.Result{}, nil
+	}
+
+	// The benchmark is executed by the job once the StatefulSet is ready
+	cr.Status.SetCondition(perfv1alpha1.ConditionServerReady, corev1.ConditionTrue, "", "")
+	if err := r.K8S.UpdatePhase(ctx, &cr, perfv1alpha1.BenchmarkRunning, "", ""); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	// We need to wait for the StatefulSet to be ready, so requeue
 	return ctrl.Result{Requeue: true}, nil
 }
 
 func esRallyJobHandler(cr perfv1alpha1.EsRally, r *Reconciler, ctx context.Context, namespaceName types.NamespacedName) (ctrl.Result, error) {
+	// Validate on first entry
+	if cr.Status.Phase == "" {
+		if valid, err := IsCrValid(&cr); !valid {
+			_ = r.K8S.RecordEventf(&cr, corev1.EventTypeWarning, k8s.CreateFailed,
+				"CR validation failed: %v", err)
+
+			// Do not requeue invalid CRs
+			return ctrl.Result{}, r.K8S.UpdatePhase(ctx, &cr, perfv1alpha1.BenchmarkFailed,
+				k8s.ValidationFailed, err.Error())
+		}
+		cr.Status.SetCondition(perfv1alpha1.ConditionValidated, corev1.ConditionTrue, "", "")
+	}
+
 	job := NewJob(&cr)
 	if err := r.K8S.CreateWithReference(ctx, job, &cr); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	// Mark it as running
-	cr.Status.Running = true
-	if err := r.K8S.Client.Status().Update(ctx, &cr); err != nil {
+	// The StatefulSet is deployed once the job pod gets its IP address
+	if err := r.K8S.UpdatePhase(ctx, &cr, perfv1alpha1.BenchmarkDeployingServer, "", ""); err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -179,5 +217,8 @@ func esRallyJobHandler(cr perfv1alpha1.EsRally, r *Reconciler, ctx context.Conte
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&perfv1alpha1.EsRally{}).
-		Complete(r)
+		Owns(&batchv1.Job{}).
+		Owns(&appsv1.StatefulSet{}).
+		Owns(&corev1.Service{}).
+		Complete(lifecycle.NewReconciler(r, &r.K8S, &perfv1alpha1.EsRally{}))
 }
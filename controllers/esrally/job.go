@@ -21,11 +21,57 @@ import (
 	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
 	"github.com/xridge/kubestone/pkg/k8s"
 	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"strconv"
 	"strings"
 )
 
+// basicAuthPasswordEnv holds the basic auth password in the coordinator
+// container. It is expanded by the shell running esrally, so the password
+// is not part of the pod spec.
+const basicAuthPasswordEnv = "ES_BASIC_AUTH_PASSWORD"
+
+// basicAuthInvalidChars cannot be part of the basic auth credentials, as
+// esrally splits its client options at the commas and the credentials are
+// quoted in them. The check of the password in the container matches the
+// same characters.
+const basicAuthInvalidChars = `'",`
+
+// DefaultImage is the esrally image used when the image is not specified in the CR
+var DefaultImage = perfv1alpha1.ImageSpec{
+	Name:       "diamantisolutions/esrally:kubestone",
+	PullPolicy: "Always",
+}
+
+// Default sets the image of the CR to DefaultImage and the number of
+// nodes to one, if they are not specified
+func Default(cr *perfv1alpha1.EsRally) {
+	if cr.Spec.Image == (perfv1alpha1.ImageSpec{}) {
+		cr.Spec.Image = DefaultImage
+	}
+	if cr.Spec.Nodes == nil {
+		nodes := int32(1)
+		cr.Spec.Nodes = &nodes
+	}
+}
+
+// IsCrValid validates the given CR and raises error if semantic errors detected
+// For esrally, the size of the persistent volumes is checked
+func IsCrValid(cr *perfv1alpha1.EsRally) (valid bool, err error) {
+	if _, err := resource.ParseQuantity(cr.Spec.Persistence.Size); err != nil {
+		return false, fmt.Errorf("invalid persistence size %q: %v", cr.Spec.Persistence.Size, err)
+	}
+	if cr.Spec.Security != nil && cr.Spec.Security.BasicAuth != nil {
+		auth := cr.Spec.Security.BasicAuth
+		if strings.ContainsAny(auth.Username, basicAuthInvalidChars) ||
+			strings.ContainsAny(auth.Password, basicAuthInvalidChars) {
+			return false, fmt.Errorf("the basic auth credentials cannot contain quotes or commas")
+		}
+	}
+	return true, nil
+}
+
 func NewJob(cr *perfv1alpha1.EsRally) *batchv1.Job {
 	objectMeta := metav1.ObjectMeta{
 		Name:      cr.Name,
@@ -44,24 +90,40 @@ func NewJob(cr *perfv1alpha1.EsRally) *batchv1.Job {
 		job.Spec.Template.Labels[k] = v
 	}
 
+	command := []string{"/kubestone.sh", "coordinator", "60", "&&"}
+	if cr.Spec.Security != nil && cr.Spec.Security.BasicAuth != nil {
+		command = append(command, BasicAuthPasswordCheck(), "&&")
+	}
+	command = append(command, strings.Join(CreateEsRallyCmd(&cr.Spec, &objectMeta), " "))
+
 	esJobContainer := createEsRallyContainer(cr,
 		job.Spec.Template.Spec.Containers[0].Name,
 		[]string{"/bin/sh", "-c"},
-		strings.Join([]string{
-			"/kubestone.sh", "coordinator", "60",
-			"&&",
-			strings.Join(CreateEsRallyCmd(&cr.Spec, &objectMeta), " "),
-		}, " "),
+		strings.Join(command, " "),
 		fmt.Sprintf("%s-coordinator", cr.Name),
 	)
 
 	job.Spec.Template.Spec.Containers[0].Command = esJobContainer.Command
 	job.Spec.Template.Spec.Containers[0].Args = esJobContainer.Args
 	job.Spec.Template.Spec.Containers[0].Env = esJobContainer.Env
+	if cr.Spec.Security != nil && cr.Spec.Security.BasicAuth != nil {
+		auth := cr.Spec.Security.BasicAuth
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env,
+			k8s.NewSecretEnvVar(basicAuthPasswordEnv, auth.Password, auth.PasswordSecretRef))
+	}
 
 	return job
 }
 
+// BasicAuthPasswordCheck returns the shell command which fails if the basic
+// auth password contains any of the characters which would break the client
+// options of esrally. The password might come from a Secret, therefore it
+// is checked in the container after its expansion.
+func BasicAuthPasswordCheck() string {
+	return fmt.Sprintf(`case "$%s" in *[\'\",]*) echo "the basic auth password cannot contain quotes or commas" >&2; exit 1;; esac`,
+		basicAuthPasswordEnv)
+}
+
 func CreateEsRallyCmd(spec *perfv1alpha1.EsRallySpec, objectMeta *metav1.ObjectMeta) []string {
 	var cmdArgs []string
 
@@ -94,7 +156,7 @@ func CreateEsRallyCmd(spec *perfv1alpha1.EsRallySpec, objectMeta *metav1.ObjectM
 
 		if spec.Security.BasicAuth != nil {
 			clientOptions = append(clientOptions, fmt.Sprintf("basic_auth_user:'%s'", spec.Security.BasicAuth.Username))
-			clientOptions = append(clientOptions, fmt.Sprintf("basic_auth_password:'%s'", spec.Security.BasicAuth.Password))
+			clientOptions = append(clientOptions, fmt.Sprintf("basic_auth_password:'${%s}'", basicAuthPasswordEnv))
 		}
 	}
 
@@ -0,0 +1,87 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package esrally
+
+import (
+	"os/exec"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("esrally job", func() {
+	var cr perfv1alpha1.EsRally
+
+	BeforeEach(func() {
+		cr = perfv1alpha1.EsRally{
+			ObjectMeta: metav1.ObjectMeta{Name: "esrally-sample", Namespace: "kubestone"},
+			Spec: perfv1alpha1.EsRallySpec{
+				Hosts: "elasticsearch:9200",
+				Track: "geonames",
+				Persistence: perfv1alpha1.EsRallyVolConfig{
+					Size: "1Gi",
+				},
+				Security: &perfv1alpha1.EsRallySecurity{
+					BasicAuth: &perfv1alpha1.BasicAuth{Username: "elastic"},
+				},
+			},
+		}
+		Default(&cr)
+	})
+
+	It("should read the basic auth password from the environment", func() {
+		Expect(CreateEsRallyCmd(&cr.Spec, &cr.ObjectMeta)).To(ContainElement(
+			`--client-options="use_ssl:false,verify_certs:false,basic_auth_user:'elastic',basic_auth_password:'${ES_BASIC_AUTH_PASSWORD}'"`))
+	})
+
+	It("should check the basic auth password before running esrally", func() {
+		args := NewJob(&cr).Spec.Template.Spec.Containers[0].Args
+		Expect(args).To(HaveLen(1))
+		Expect(args[0]).To(ContainSubstring(BasicAuthPasswordCheck() + " && /usr/local/bin/esrally"))
+	})
+
+	It("should accept passwords without quotes and commas", func() {
+		cmd := exec.Command("/bin/sh", "-c", BasicAuthPasswordCheck())
+		cmd.Env = []string{`ES_BASIC_AUTH_PASSWORD=pa$s:wo\rd`}
+		Expect(cmd.Run()).To(Succeed())
+	})
+
+	It("should reject passwords with quotes or commas", func() {
+		for _, password := range []string{`pa"ss`, `pa'ss`, `pa,ss`} {
+			cmd := exec.Command("/bin/sh", "-c", BasicAuthPasswordCheck())
+			cmd.Env = []string{"ES_BASIC_AUTH_PASSWORD=" + password}
+			Expect(cmd.Run()).NotTo(Succeed(), password)
+		}
+	})
+
+	It("should reject credentials with quotes or commas in the CR", func() {
+		Expect(IsCrValid(&cr)).To(BeTrue())
+
+		cr.Spec.Security.BasicAuth.Password = `pa"ss`
+		valid, err := IsCrValid(&cr)
+		Expect(valid).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+
+		cr.Spec.Security.BasicAuth.Password = ""
+		cr.Spec.Security.BasicAuth.Username = "ela'stic"
+		valid, _ = IsCrValid(&cr)
+		Expect(valid).To(BeFalse())
+	})
+})
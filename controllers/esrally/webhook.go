@@ -0,0 +1,41 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package esrally
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/webhooks"
+)
+
+// +kubebuilder:webhook:path=/mutate-perf-kubestone-xridge-io-v1alpha1-esrally,mutating=true,failurePolicy=fail,groups=perf.kubestone.xridge.io,resources=esrallies,verbs=create;update,versions=v1alpha1,name=mesrally.kubestone.xridge.io
+// +kubebuilder:webhook:path=/validate-perf-kubestone-xridge-io-v1alpha1-esrally,mutating=false,failurePolicy=fail,groups=perf.kubestone.xridge.io,resources=esrallies,verbs=create;update,versions=v1alpha1,name=vesrally.kubestone.xridge.io
+
+// SetupWebhookWithManager registers the admission webhooks of EsRally
+func SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return webhooks.SetupWithManager(mgr, &perfv1alpha1.EsRally{}, webhooks.Hooks{
+		Default: func(obj runtime.Object) {
+			Default(obj.(*perfv1alpha1.EsRally))
+		},
+		Validate: func(obj runtime.Object) error {
+			_, err := IsCrValid(obj.(*perfv1alpha1.EsRally))
+			return err
+		},
+	})
+}
@@ -25,7 +25,8 @@ import (
 	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
 )
 
-// NewConfigMap creates a new configmap for the fio benchmark job
+// NewConfigMap creates a new configmap for the fio benchmark job, which
+// contains the custom job files and the addresses of the workers
 func NewConfigMap(cr *perfv1alpha1.Fio) *corev1.ConfigMap {
 
 	data := make(map[string]string)
@@ -34,6 +35,9 @@ func NewConfigMap(cr *perfv1alpha1.Fio) *corev1.ConfigMap {
 		key := CustomJobName(i)
 		data[key] = customJobFile
 	}
+	if cr.Spec.Workers != nil {
+		data[WorkerHostsFile] = WorkerHosts(cr)
+	}
 
 	configMap := corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
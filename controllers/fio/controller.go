@@ -20,12 +20,16 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
 	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/lifecycle"
+	"github.com/xridge/kubestone/pkg/results"
 )
 
 // Reconciler provides fields from manager to reconciler
@@ -36,12 +40,16 @@ type Reconciler struct {
 
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=create
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=create
-// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;create;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;create;delete;watch
 // +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=fios,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=fios/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=fios/finalizers,verbs=update
 
-// Reconcile creates fio job(s) based on the custom resource(s)
+// Reconcile creates fio job(s) based on the custom resource(s). In
+// distributed mode the fio servers of the workers are deployed first, and
+// the job running the fio client is created once all of them are ready.
+// The workers are removed once the client job is completed. In
+// StorageClass comparison mode one job is run for each StorageClass.
 func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := context.Background()
 
@@ -51,24 +59,24 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	}
 
 	// Run to one completion
-	if cr.Status.Completed {
+	if cr.Status.IsFinished() {
 		return ctrl.Result{}, nil
 	}
 
 	// Validate on first entry
-	if !cr.Status.Completed && !cr.Status.Running {
+	if cr.Status.Phase == "" {
+		if err := r.K8S.UpdatePhase(ctx, &cr, perfv1alpha1.BenchmarkValidating, "", ""); err != nil {
+			return ctrl.Result{}, err
+		}
 		if valid, err := IsCrValid(&cr); !valid {
 			_ = r.K8S.RecordEventf(&cr, corev1.EventTypeWarning, k8s.CreateFailed,
 				"CR validation failed: %v", err)
 
 			// Do not requeue invalid CRs
-			return ctrl.Result{}, nil
+			return ctrl.Result{}, r.K8S.UpdatePhase(ctx, &cr, perfv1alpha1.BenchmarkFailed,
+				k8s.ValidationFailed, err.Error())
 		}
-	}
-
-	cr.Status.Running = true
-	if err := r.K8S.Client.Status().Update(ctx, &cr); err != nil {
-		return ctrl.Result{}, err
+		cr.Status.SetCondition(perfv1alpha1.ConditionValidated, corev1.ConditionTrue, "", "")
 	}
 
 	configMap := NewConfigMap(&cr)
@@ -76,50 +84,197 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, err
 	}
 
-	if cr.Spec.Volume.PersistentVolumeClaimSpec != nil {
-		pvc := k8s.NewPersistentVolumeClaim(*cr.Spec.Volume.PersistentVolumeClaimSpec,
-			cr.Name, cr.Namespace)
-		if err := r.K8S.CreateWithReference(ctx, pvc, &cr); err != nil {
+	if cr.Spec.Workers != nil {
+		// The workers are deployed for each run, as the children of the
+		// previous run are deleted before the next run is started
+		if cr.Status.Phase != perfv1alpha1.BenchmarkRunning {
+			if message, err := r.deployWorkers(ctx, &cr); err != nil {
+				return ctrl.Result{}, err
+			} else if message != "" {
+				_ = r.K8S.RecordEventf(&cr, corev1.EventTypeWarning, k8s.NoMatchingNodes, "%s", message)
+				return ctrl.Result{}, r.K8S.UpdatePhase(ctx, &cr, perfv1alpha1.BenchmarkFailed,
+					k8s.NoMatchingNodes, message)
+			}
+		}
+
+		ready, err := r.areWorkersReady(&cr)
+		if err != nil {
 			return ctrl.Result{}, err
 		}
-		// Change ClaimName (from GENERATED) to the PVC was created
-		cr.Spec.Volume.VolumeSource.PersistentVolumeClaim.ClaimName = cr.Name
+		if !ready {
+			if cr.Status.Phase != perfv1alpha1.BenchmarkDeployingServer {
+				if err := r.K8S.UpdatePhase(ctx, &cr, perfv1alpha1.BenchmarkDeployingServer, "", ""); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			// Wait for all the workers to be connected to the service endpoint
+			return k8s.RequeueWithBackoff(&cr), nil
+		}
+		cr.Status.SetCondition(perfv1alpha1.ConditionServerReady, corev1.ConditionTrue, "", "")
 	}
 
-	job := NewJob(&cr)
-	if err := r.K8S.CreateWithReference(ctx, job, &cr); err != nil {
+	var jobNames []types.NamespacedName
+	if cr.Spec.Volume.IsStorageClassComparison() {
+		names, err := r.K8S.CreateStorageClassJobs(ctx, &cr, &cr.Spec.Volume,
+			func(storageClass string) *batchv1.Job {
+				return NewStorageClassJob(&cr, storageClass)
+			})
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		jobNames = names
+	} else {
+		if cr.Spec.Workers == nil && cr.Spec.Volume.PersistentVolumeClaimSpec != nil {
+			pvc := k8s.NewPersistentVolumeClaim(*cr.Spec.Volume.PersistentVolumeClaimSpec,
+				cr.Name, cr.Namespace)
+			if err := r.K8S.CreateWithReference(ctx, pvc, &cr); err != nil {
+				return ctrl.Result{}, err
+			}
+			// Change ClaimName (from GENERATED) to the PVC was created
+			cr.Spec.Volume.VolumeSource.PersistentVolumeClaim.ClaimName = cr.Name
+		}
+
+		job := NewJob(&cr)
+		if err := r.K8S.CreateWithReference(ctx, job, &cr); err != nil {
+			return ctrl.Result{}, err
+		}
+		jobNames = append(jobNames, types.NamespacedName{
+			Namespace: cr.Namespace,
+			Name:      job.Name,
+		})
+	}
+	if err := r.K8S.SyncRunningPhase(ctx, &cr, jobNames...); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	// Check if finished
-	jobFinished, err := r.K8S.IsJobFinished(types.NamespacedName{
-		Namespace: cr.Namespace,
-		Name:      cr.Name,
-	})
-	if err != nil {
-		return ctrl.Result{}, err
+	// Check all the job statuses
+	for _, jobName := range jobNames {
+		jobFinished, err := r.K8S.IsJobFinished(jobName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !jobFinished {
+			// Wait for the job to be completed
+			return k8s.WaitForJobs(&cr), nil
+		}
 	}
-	if !jobFinished {
-		// Wait for the job to be completed
-		return ctrl.Result{Requeue: true}, nil
+
+	// The first failed job determines the failure of the benchmark
+	var jobFailure *k8s.JobFailure
+	for _, jobName := range jobNames {
+		failure, err := r.K8S.GetJobFailure(jobName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if failure != nil {
+			jobFailure = failure
+			break
+		}
+	}
+
+	if cr.Spec.Workers != nil {
+		if err := r.deleteWorkers(ctx, &cr); err != nil {
+			return ctrl.Result{}, err
+		}
 	}
 
 	// The cr could have been modified since the last time we got it
 	if err := r.K8S.Client.Get(ctx, req.NamespacedName, &cr); err != nil {
 		return ctrl.Result{}, k8s.IgnoreNotFound(err)
 	}
-	cr.Status.Running = false
-	cr.Status.Completed = true
-	if err := r.K8S.Client.Status().Update(ctx, &cr); err != nil {
+	// The results are parsed from the logs of the successful benchmark
+	if jobFailure == nil {
+		if err := collectResults(&r.K8S, &cr, jobNames); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.K8S.FinishBenchmark(ctx, &cr, jobFailure); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	// Keep a record of the run, which outlives the benchmark
+	return ctrl.Result{}, results.Record(ctx, &r.K8S, &cr, jobNames...)
+}
+
+// collectResults parses the results of the finished jobs. The results of
+// the StorageClass comparison are reported side by side.
+func collectResults(access *k8s.Access, cr *perfv1alpha1.Fio, jobNames []types.NamespacedName) error {
+	if cr.Spec.Volume.IsStorageClassComparison() {
+		return results.CollectStorageClasses(access, cr, cr.Spec.Volume.StorageClassNames, jobNames...)
+	}
+	return results.Collect(access, cr, jobNames...)
+}
+
+// deployWorkers creates the deployments, PVCs and service of the workers.
+// The nodes of the workers are selected on first entry and are kept in the
+// status, so all the runs use the same nodes. It returns the reason if there
+// are not enough nodes for the workers.
+func (r *Reconciler) deployWorkers(ctx context.Context, cr *perfv1alpha1.Fio) (string, error) {
+	nodes := cr.Status.WorkerNodes
+	if len(nodes) == 0 {
+		schedulable, err := r.K8S.GetSchedulableNodes(ctx, cr.Spec.Workers.NodeSelector)
+		if err != nil {
+			return "", err
+		}
+		if nodes, err = SelectWorkerNodes(cr, schedulable); err != nil {
+			return err.Error(), nil
+		}
+		// The nodes are persisted by the next status update
+		cr.Status.WorkerNodes = nodes
+	}
+
+	for i, node := range nodes {
+		if pvc := NewWorkerPersistentVolumeClaim(cr, i); pvc != nil {
+			if err := r.K8S.CreateWithReference(ctx, pvc, cr); err != nil {
+				return "", err
+			}
+		}
+		if err := r.K8S.CreateWithReference(ctx, NewWorkerDeployment(cr, i, node), cr); err != nil {
+			return "", err
+		}
+	}
+	return "", r.K8S.CreateWithReference(ctx, NewWorkersService(cr), cr)
+}
+
+// areWorkersReady returns true if the fio servers of all the workers
+// are ready and reachable via the workers service
+func (r *Reconciler) areWorkersReady(cr *perfv1alpha1.Fio) (bool, error) {
+	for i := 0; i < int(cr.Spec.Workers.Count); i++ {
+		ready, err := r.K8S.IsDeploymentReady(types.NamespacedName{
+			Namespace: cr.Namespace,
+			Name:      WorkerName(cr, i),
+		})
+		if err != nil || !ready {
+			return false, err
+		}
+	}
+	return r.K8S.IsEndpointReady(types.NamespacedName{
+		Namespace: cr.Namespace,
+		Name:      workersServiceName(cr),
+	})
+}
+
+// deleteWorkers removes the deployments and the service of the workers.
+// The PVCs of the workers are removed according to the cleanup policy.
+func (r *Reconciler) deleteWorkers(ctx context.Context, cr *perfv1alpha1.Fio) error {
+	if err := r.K8S.DeleteObject(ctx, NewWorkersService(cr), cr); err != nil {
+		return err
+	}
+	for i := 0; i < int(cr.Spec.Workers.Count); i++ {
+		if err := r.K8S.DeleteObject(ctx, NewWorkerDeployment(cr, i, ""), cr); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // SetupWithManager registers the Reconciler with the provided manager
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&perfv1alpha1.Fio{}).
-		Complete(r)
+		Owns(&batchv1.Job{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.Service{}).
+		Complete(lifecycle.NewReconciler(r, &r.K8S, &perfv1alpha1.Fio{}))
 }
@@ -0,0 +1,138 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fio
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+)
+
+var _ = Describe("fio reconciler", func() {
+	var ctx context.Context
+	var name types.NamespacedName
+	var reconciler *Reconciler
+
+	reconcile := func() {
+		_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	stored := func() *perfv1alpha1.Fio {
+		var cr perfv1alpha1.Fio
+		Expect(reconciler.K8S.Client.Get(ctx, name, &cr)).To(Succeed())
+		return &cr
+	}
+
+	newNode := func(name string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				},
+			},
+		}
+	}
+
+	workerNodes := func() []string {
+		var nodes []string
+		for i := 0; i < 2; i++ {
+			var deployment appsv1.Deployment
+			Expect(reconciler.K8S.Client.Get(ctx, types.NamespacedName{
+				Namespace: name.Namespace,
+				Name:      WorkerName(stored(), i),
+			}, &deployment)).To(Succeed())
+			nodes = append(nodes, deployment.Spec.Template.Spec.NodeName)
+		}
+		return nodes
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		name = types.NamespacedName{Namespace: "kubestone", Name: "fio"}
+		cr := &perfv1alpha1.Fio{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name.Name,
+				Namespace: name.Namespace,
+				UID:       "fio-uid",
+				// Needed by the event recorder to reference the object
+				SelfLink: "/apis/perf.kubestone.xridge.io/v1alpha1/namespaces/kubestone/fios/fio",
+			},
+			Spec: perfv1alpha1.FioSpec{
+				Image:       perfv1alpha1.ImageSpec{Name: "xridge/fio:3.13"},
+				CmdLineArgs: "--name=randread --rw=randread --directory=/data",
+				Volume: perfv1alpha1.VolumeSpec{
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
+				Workers: &perfv1alpha1.FioWorkersSpec{Count: 2},
+			},
+		}
+
+		scheme := runtime.NewScheme()
+		_ = k8sscheme.AddToScheme(scheme)
+		_ = perfv1alpha1.AddToScheme(scheme)
+		reconciler = &Reconciler{K8S: k8s.Access{
+			Client:        fake.NewFakeClientWithScheme(scheme, cr, newNode("node-b"), newNode("node-c")),
+			Scheme:        scheme,
+			EventRecorder: record.NewFakeRecorder(100),
+		}}
+	})
+
+	Context("with workers", func() {
+		It("should deploy the workers and wait for them", func() {
+			reconcile()
+			Expect(stored().Status.Phase).To(Equal(perfv1alpha1.BenchmarkDeployingServer))
+			Expect(stored().Status.WorkerNodes).To(Equal([]string{"node-b", "node-c"}))
+			Expect(workerNodes()).To(Equal([]string{"node-b", "node-c"}))
+		})
+
+		It("should deploy the workers again on the same nodes for the next run", func() {
+			reconcile()
+
+			// The children of the previous run are deleted before the next run
+			for i := 0; i < 2; i++ {
+				Expect(reconciler.K8S.Client.Delete(ctx, NewWorkerDeployment(stored(), i, ""))).To(Succeed())
+			}
+			Expect(reconciler.K8S.Client.Create(ctx, newNode("node-a"))).To(Succeed())
+			cr := stored()
+			cr.Status.Children = nil
+			cr.Status.SetPhase(perfv1alpha1.BenchmarkPending, k8s.Repeating, "")
+			Expect(reconciler.K8S.Client.Status().Update(ctx, cr)).To(Succeed())
+
+			reconcile()
+			Expect(stored().Status.Phase).To(Equal(perfv1alpha1.BenchmarkDeployingServer))
+			Expect(workerNodes()).To(Equal([]string{"node-b", "node-c"}))
+		})
+	})
+})
@@ -17,6 +17,9 @@ limitations under the License.
 package fio
 
 import (
+	"errors"
+	"strings"
+
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -26,7 +29,20 @@ import (
 	"github.com/xridge/kubestone/pkg/k8s"
 )
 
-// NewJob creates a fio benchmark job
+// DefaultOutputFormat is the output format of fio when it is not
+// specified in the CR. The results are parsed from the json+ output.
+const DefaultOutputFormat = "json+"
+
+// Default sets the output format of the CR to DefaultOutputFormat, unless
+// it is specified in the CR or in the command line arguments
+func Default(cr *perfv1alpha1.Fio) {
+	if cr.Spec.OutputFormat == "" && !strings.Contains(cr.Spec.CmdLineArgs, "--output-format") {
+		cr.Spec.OutputFormat = DefaultOutputFormat
+	}
+}
+
+// NewJob creates a fio benchmark job. In distributed mode the job runs
+// the fio client, which drives the fio servers of the workers.
 func NewJob(cr *perfv1alpha1.Fio) *batchv1.Job {
 	objectMeta := metav1.ObjectMeta{
 		Name:      cr.Name,
@@ -34,6 +50,18 @@ func NewJob(cr *perfv1alpha1.Fio) *batchv1.Job {
 	}
 
 	fioCmdLineArgs := []string{}
+	// In distributed mode the job files are executed by the workers
+	if cr.Spec.Workers != nil {
+		fioCmdLineArgs = append(fioCmdLineArgs, "--client=/clients/"+WorkerHostsFile)
+	}
+	// Output format provided via CmdLineArgs takes precedence
+	if !strings.Contains(cr.Spec.CmdLineArgs, "--output-format") {
+		outputFormat := cr.Spec.OutputFormat
+		if outputFormat == "" {
+			outputFormat = DefaultOutputFormat
+		}
+		fioCmdLineArgs = append(fioCmdLineArgs, "--output-format="+outputFormat)
+	}
 	fioCmdLineArgs = append(fioCmdLineArgs,
 		qsplit.ToStrings([]byte(cr.Spec.CmdLineArgs))...)
 	fioCmdLineArgs = append(fioCmdLineArgs, cr.Spec.BuiltinJobFiles...)
@@ -61,12 +89,31 @@ func NewJob(cr *perfv1alpha1.Fio) *batchv1.Job {
 		})
 	}
 
-	volumes = append(volumes, corev1.Volume{
-		Name: "data", VolumeSource: cr.Spec.Volume.VolumeSource,
-	})
-	volumeMounts = append(volumeMounts, corev1.VolumeMount{
-		Name: "data", MountPath: "/data",
-	})
+	if cr.Spec.Workers != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: "clients",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: cr.Name,
+					},
+					Items: []corev1.KeyToPath{
+						{Key: WorkerHostsFile, Path: WorkerHostsFile},
+					},
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name: "clients", MountPath: "/clients",
+		})
+	} else {
+		volumes = append(volumes, corev1.Volume{
+			Name: "data", VolumeSource: cr.Spec.Volume.VolumeSource,
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name: "data", MountPath: "/data",
+		})
+	}
 
 	job := k8s.NewPerfJob(objectMeta, "fio", cr.Spec.Image, cr.Spec.PodConfig)
 	job.Spec.Template.Spec.Volumes = volumes
@@ -75,9 +122,32 @@ func NewJob(cr *perfv1alpha1.Fio) *batchv1.Job {
 	return job
 }
 
+// NewStorageClassJob creates the fio benchmark job which runs against
+// the PVC generated for the given StorageClass in the StorageClass comparison
+func NewStorageClassJob(cr *perfv1alpha1.Fio, storageClass string) *batchv1.Job {
+	name := k8s.StorageClassObjectName(cr.Name, storageClass)
+	classCr := cr.DeepCopy()
+	classCr.Spec.Volume.VolumeSource.PersistentVolumeClaim.ClaimName = name
+
+	job := NewJob(classCr)
+	job.Name = name
+	return job
+}
+
 // IsCrValid validates the given CR and raises error if semantic errors detected
-// For fio, the VolumeSpec validity is checked
+// For fio, the VolumeSpec and the workers validity is checked
 func IsCrValid(cr *perfv1alpha1.Fio) (valid bool, err error) {
 	// TODO: Add check here for CustomJobs
-	return cr.Spec.Volume.Validate()
+	if valid, err := cr.Spec.Volume.Validate(); !valid {
+		return false, err
+	}
+	if cr.Spec.Workers != nil {
+		if cr.Spec.Volume.IsStorageClassComparison() {
+			return false, errors.New("StorageClassNames cannot be used together with workers")
+		}
+		if err := validateWorkers(cr); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
 }
@@ -20,6 +20,7 @@ import (
 	. "github.com/onsi/gomega"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
 )
@@ -53,6 +54,44 @@ var _ = Describe("fio job", func() {
 					ContainElement("--size=256M"))
 			})
 		})
+
+		Context("without output format specified", func() {
+			It("should use json+ output", func() {
+				Expect(job.Spec.Template.Spec.Containers[0].Args).To(
+					ContainElement("--output-format=json+"))
+			})
+		})
+
+		Context("with output format specified", func() {
+			It("should use the given output format", func() {
+				cr.Spec.OutputFormat = "normal"
+				job = NewJob(&cr)
+				Expect(job.Spec.Template.Spec.Containers[0].Args).To(
+					ContainElement("--output-format=normal"))
+			})
+		})
+
+		Context("with output format specified in the command line args", func() {
+			It("should not override the command line args", func() {
+				cr.Spec.CmdLineArgs = "--name=randwrite --output-format=terse"
+				job = NewJob(&cr)
+				Expect(job.Spec.Template.Spec.Containers[0].Args).To(
+					Equal([]string{"--name=randwrite", "--output-format=terse"}))
+			})
+		})
+
+		Context("when defaulted", func() {
+			It("should set the output format", func() {
+				Default(&cr)
+				Expect(cr.Spec.OutputFormat).To(Equal(DefaultOutputFormat))
+			})
+
+			It("should keep the output format of the command line args", func() {
+				cr.Spec.CmdLineArgs = "--name=randwrite --output-format=terse"
+				Default(&cr)
+				Expect(cr.Spec.OutputFormat).To(BeEmpty())
+			})
+		})
 	})
 
 	Describe("cr with builtin job files and volume", func() {
@@ -137,4 +176,44 @@ var _ = Describe("fio job", func() {
 			})
 		})
 	})
+
+	Describe("cr with StorageClass comparison", func() {
+		var cr perfv1alpha1.Fio
+
+		BeforeEach(func() {
+			cr = perfv1alpha1.Fio{
+				ObjectMeta: metav1.ObjectMeta{Name: "fio", Namespace: "storage"},
+				Spec: perfv1alpha1.FioSpec{
+					Image:           perfv1alpha1.ImageSpec{Name: "xridge/fio:test"},
+					BuiltinJobFiles: []string{"/jobs/rand-read.fio"},
+					Volume: perfv1alpha1.VolumeSpec{
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+								ClaimName: perfv1alpha1.GeneratedPVC,
+							},
+						},
+						StorageClassNames: []string{"standard", "fast"},
+					},
+				},
+			}
+		})
+
+		It("should run the identical job against the PVC of the StorageClass", func() {
+			job := NewStorageClassJob(&cr, "standard")
+			Expect(job.Name).To(Equal("fio-standard"))
+			Expect(job.Spec.Template.Spec.Containers[0].Args).To(
+				Equal(NewJob(&cr).Spec.Template.Spec.Containers[0].Args))
+			Expect(job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(
+				Equal("fio-standard"))
+		})
+
+		It("should not be combined with workers", func() {
+			Expect(IsCrValid(&cr)).To(BeTrue())
+			cr.Spec.Workers = &perfv1alpha1.FioWorkersSpec{Count: 2}
+			valid, err := IsCrValid(&cr)
+			Expect(valid).To(BeFalse())
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
+
@@ -0,0 +1,223 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fio
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/results"
+)
+
+func init() {
+	results.Register("Fio", results.ParserFunc(ParseResults))
+}
+
+// fioOutput is the subset of fio's json / json+ output used by kubestone
+type fioOutput struct {
+	Version string   `json:"fio version"`
+	Jobs    []fioJob `json:"jobs"`
+	// ClientStats replaces Jobs in the output of the fio client
+	ClientStats []fioJob `json:"client_stats"`
+}
+
+type fioJob struct {
+	JobName  string       `json:"jobname"`
+	Hostname string       `json:"hostname"`
+	Read     fioDirection `json:"read"`
+	Write    fioDirection `json:"write"`
+	Trim     fioDirection `json:"trim"`
+}
+
+type fioDirection struct {
+	TotalIOs int64       `json:"total_ios"`
+	IOPS     float64     `json:"iops"`
+	BwBytes  int64       `json:"bw_bytes"`
+	Bw       int64       `json:"bw"`
+	ClatNs   *fioLatency `json:"clat_ns"`
+	Clat     *fioLatency `json:"clat"`
+}
+
+type fioLatency struct {
+	Percentile map[string]float64 `json:"percentile"`
+}
+
+// Percentile keys used by fio
+const (
+	p50  = "50.000000"
+	p95  = "95.000000"
+	p99  = "99.000000"
+	p999 = "99.900000"
+)
+
+// allClientsJobName is the name of the merged results of the workers
+// in the output of the fio client
+const allClientsJobName = "All clients"
+
+// ParseResults parses the json (or json+) output of fio. The detailed
+// results are reported per job and direction, while the metrics
+// aggregate the jobs: IOPS and bandwidth are summed, and the worst
+// latency percentile of the jobs is reported. In distributed mode the
+// metrics are the merged results of all the workers reported by the
+// fio client, if available.
+func ParseResults(logs string) (*perfv1alpha1.BenchmarkResults, error) {
+	// fio might print warnings before the JSON document
+	start := strings.Index(logs, "{")
+	if start < 0 {
+		return nil, errors.New("JSON output is missing from fio logs, is --output-format=json+ set?")
+	}
+
+	var output fioOutput
+	if err := json.NewDecoder(strings.NewReader(logs[start:])).Decode(&output); err != nil {
+		return nil, err
+	}
+	jobs := output.Jobs
+	if len(output.ClientStats) > 0 {
+		jobs = output.ClientStats
+	}
+	if len(jobs) == 0 {
+		return nil, errors.New("no jobs found in fio output")
+	}
+
+	fioResults := &perfv1alpha1.FioResults{Version: output.Version}
+	res := &perfv1alpha1.BenchmarkResults{Fio: fioResults}
+	totals := newDirectionTotals()
+	allClients := newDirectionTotals()
+
+	for _, job := range jobs {
+		jobResult := perfv1alpha1.FioJobResult{Name: job.JobName, Hostname: job.Hostname}
+		if job.JobName == allClientsJobName {
+			allClients.add(&jobResult, job)
+			jobResult.Hostname = ""
+			fioResults.AllClients = &jobResult
+			continue
+		}
+		totals.add(&jobResult, job)
+		fioResults.Jobs = append(fioResults.Jobs, jobResult)
+	}
+
+	if fioResults.AllClients != nil {
+		totals = allClients
+	}
+	totals.addMetrics(res)
+
+	return res, nil
+}
+
+// directionTotals aggregates the directions of fio jobs
+type directionTotals struct {
+	totals map[string]*perfv1alpha1.FioDirectionResult
+	iops   map[string]float64
+}
+
+func newDirectionTotals() *directionTotals {
+	return &directionTotals{
+		totals: map[string]*perfv1alpha1.FioDirectionResult{},
+		iops:   map[string]float64{},
+	}
+}
+
+// add converts the directions of the fio job into the job result, and
+// adds them to the totals
+func (t *directionTotals) add(jobResult *perfv1alpha1.FioJobResult, job fioJob) {
+	for _, d := range []struct {
+		direction string
+		fioDir    fioDirection
+		target    **perfv1alpha1.FioDirectionResult
+	}{
+		{"read", job.Read, &jobResult.Read},
+		{"write", job.Write, &jobResult.Write},
+		{"trim", job.Trim, &jobResult.Trim},
+	} {
+		direction, fioDir := d.direction, d.fioDir
+		if fioDir.TotalIOs == 0 {
+			continue
+		}
+
+		result := newDirectionResult(fioDir)
+		*d.target = result
+
+		total, ok := t.totals[direction]
+		if !ok {
+			total = &perfv1alpha1.FioDirectionResult{}
+			t.totals[direction] = total
+		}
+		t.iops[direction] += fioDir.IOPS
+		total.Bandwidth += result.Bandwidth
+		total.ClatP50 = maxInt64(total.ClatP50, result.ClatP50)
+		total.ClatP95 = maxInt64(total.ClatP95, result.ClatP95)
+		total.ClatP99 = maxInt64(total.ClatP99, result.ClatP99)
+		total.ClatP999 = maxInt64(total.ClatP999, result.ClatP999)
+	}
+}
+
+// addMetrics adds the summary metrics of the directions to the results
+func (t *directionTotals) addMetrics(res *perfv1alpha1.BenchmarkResults) {
+	for _, direction := range []string{"read", "write", "trim"} {
+		total, ok := t.totals[direction]
+		if !ok {
+			continue
+		}
+		res.AddMetric(direction+".iops", t.iops[direction], "ops/s")
+		res.AddMetric(direction+".bw", float64(total.Bandwidth), "bytes/s")
+		res.AddMetric(direction+".clat.p50", float64(total.ClatP50)/1000, "us")
+		res.AddMetric(direction+".clat.p95", float64(total.ClatP95)/1000, "us")
+		res.AddMetric(direction+".clat.p99", float64(total.ClatP99)/1000, "us")
+		res.AddMetric(direction+".clat.p999", float64(total.ClatP999)/1000, "us")
+	}
+}
+
+// newDirectionResult converts the direction of a fio job to its API representation
+func newDirectionResult(fioDir fioDirection) *perfv1alpha1.FioDirectionResult {
+	// Percentiles are reported in nanoseconds since fio 3.0,
+	// and in microseconds before
+	percentiles, multiplier := map[string]float64{}, 1.0
+	if fioDir.ClatNs != nil {
+		percentiles = fioDir.ClatNs.Percentile
+	} else if fioDir.Clat != nil {
+		percentiles, multiplier = fioDir.Clat.Percentile, 1000
+	}
+	percentile := func(key string) int64 {
+		return int64(math.Round(percentiles[key] * multiplier))
+	}
+
+	// bw_bytes is missing from older fio versions, which report KiB/s only
+	bandwidth := fioDir.BwBytes
+	if bandwidth == 0 {
+		bandwidth = fioDir.Bw * 1024
+	}
+
+	return &perfv1alpha1.FioDirectionResult{
+		IOPS:      strconv.FormatFloat(fioDir.IOPS, 'f', -1, 64),
+		Bandwidth: bandwidth,
+		ClatP50:   percentile(p50),
+		ClatP95:   percentile(p95),
+		ClatP99:   percentile(p99),
+		ClatP999:  percentile(p999),
+	}
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
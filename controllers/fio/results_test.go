@@ -0,0 +1,136 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fio
+
+import (
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+func parseFixture(name string) (*perfv1alpha1.BenchmarkResults, error) {
+	logs, err := ioutil.ReadFile("testdata/" + name)
+	Expect(err).NotTo(HaveOccurred())
+	return ParseResults(string(logs))
+}
+
+var _ = Describe("fio results", func() {
+	Context("with json+ output of a single write job", func() {
+		var results *perfv1alpha1.BenchmarkResults
+		var err error
+
+		BeforeEach(func() {
+			results, err = parseFixture("randwrite.json")
+		})
+
+		It("should not fail", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.Fio.Version).To(Equal("fio-3.13"))
+		})
+
+		It("reports the write direction only", func() {
+			Expect(results.Fio.Jobs).To(HaveLen(1))
+			job := results.Fio.Jobs[0]
+			Expect(job.Name).To(Equal("randwrite"))
+			Expect(job.Read).To(BeNil())
+			Expect(job.Trim).To(BeNil())
+			Expect(job.Write).NotTo(BeNil())
+		})
+
+		It("reports iops, bandwidth and completion latency percentiles", func() {
+			write := results.Fio.Jobs[0].Write
+			Expect(write.IOPS).To(Equal("470.588235"))
+			Expect(write.Bandwidth).To(Equal(int64(1973790117)))
+			Expect(write.ClatP50).To(Equal(int64(2023424)))
+			Expect(write.ClatP95).To(Equal(int64(2408448)))
+			Expect(write.ClatP99).To(Equal(int64(2605056)))
+			Expect(write.ClatP999).To(Equal(int64(2605056)))
+		})
+
+		It("reports the summary metrics", func() {
+			Expect(results.GetMetric("write.iops").Value).To(Equal("470.588235"))
+			Expect(results.GetMetric("write.bw").Value).To(Equal("1973790117"))
+			Expect(results.GetMetric("write.clat.p99").Value).To(Equal("2605.056"))
+			Expect(results.GetMetric("write.clat.p99").Unit).To(Equal("us"))
+			Expect(results.GetMetric("read.iops")).To(BeNil())
+		})
+	})
+
+	Context("with json output of two mixed read/write jobs", func() {
+		var results *perfv1alpha1.BenchmarkResults
+		var err error
+
+		BeforeEach(func() {
+			results, err = parseFixture("randrw-two-jobs.log")
+		})
+
+		It("should skip the warnings printed before the JSON output", func() {
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reports both directions per job", func() {
+			Expect(results.Fio.Jobs).To(HaveLen(2))
+			Expect(results.Fio.Jobs[1].Name).To(Equal("randrw-2"))
+			Expect(results.Fio.Jobs[1].Read.IOPS).To(Equal("1501.866667"))
+			Expect(results.Fio.Jobs[1].Write.ClatP999).To(Equal(int64(905216)))
+		})
+
+		It("sums iops and bandwidth of the jobs", func() {
+			Expect(results.GetMetric("read.iops").Float64()).To(BeNumerically("~", 3037.866667, 1e-6))
+			Expect(results.GetMetric("read.bw").Value).To(Equal("12443101"))
+			Expect(results.GetMetric("write.iops").Float64()).To(BeNumerically("~", 1302.4, 1e-6))
+		})
+
+		It("reports the worst latency percentiles of the jobs", func() {
+			Expect(results.GetMetric("read.clat.p50").Value).To(Equal("125.44"))
+			Expect(results.GetMetric("write.clat.p99").Value).To(Equal("366.592"))
+		})
+	})
+
+	Context("with json output of the fio client driving two workers", func() {
+		var results *perfv1alpha1.BenchmarkResults
+		var err error
+
+		BeforeEach(func() {
+			results, err = parseFixture("distributed.log")
+		})
+
+		It("reports the jobs per worker", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.Fio.Jobs).To(HaveLen(2))
+			Expect(results.Fio.Jobs[1].Hostname).To(Equal("fio-nfs-worker-1.fio-nfs-workers"))
+			Expect(results.Fio.Jobs[1].Read.IOPS).To(Equal("9000"))
+		})
+
+		It("reports the merged results of all clients", func() {
+			Expect(results.Fio.AllClients.Name).To(Equal("All clients"))
+			Expect(results.Fio.AllClients.Read.Bandwidth).To(Equal(int64(77963264)))
+			Expect(results.GetMetric("read.iops").Value).To(Equal("19034"))
+			Expect(results.GetMetric("read.clat.p99").Value).To(Equal("325.632"))
+		})
+	})
+
+	Context("with normal output", func() {
+		It("should fail", func() {
+			_, err := ParseResults("randwrite: (groupid=0, jobs=1): err= 0: pid=47\n")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,225 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fio
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+)
+
+// FioServerPort is the TCP port where the fio servers of the workers listen
+const FioServerPort = 8765
+
+// WorkerHostsFile is the key of the list of the worker addresses in the
+// config map, which is passed to the fio client
+const WorkerHostsFile = "hosts"
+
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;create;delete;watch
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;create;delete;watch
+
+// WorkerName returns the name of the deployment, the pod hostname and the
+// generated PVC of the worker with the given index
+func WorkerName(cr *perfv1alpha1.Fio, index int) string {
+	return fmt.Sprintf("%s-worker-%d", cr.Name, index)
+}
+
+func workersServiceName(cr *perfv1alpha1.Fio) string {
+	return cr.Name + "-workers"
+}
+
+// WorkerHosts returns the addresses of the workers, one per line, in the
+// format of the host list file of fio's --client option. The workers are
+// addressed via the headless workers service by their hostname.
+func WorkerHosts(cr *perfv1alpha1.Fio) string {
+	var hosts strings.Builder
+	for i := 0; i < int(cr.Spec.Workers.Count); i++ {
+		fmt.Fprintf(&hosts, "%s.%s\n", WorkerName(cr, i), workersServiceName(cr))
+	}
+	return hosts.String()
+}
+
+// SelectWorkerNodes returns the nodes of the workers: the first nodes of the
+// given schedulable nodes, one for each worker
+func SelectWorkerNodes(cr *perfv1alpha1.Fio, nodes []string) ([]string, error) {
+	count := int(cr.Spec.Workers.Count)
+	if len(nodes) < count {
+		return nil, fmt.Errorf("%d workers are requested, but only %d schedulable nodes match the node selector",
+			count, len(nodes))
+	}
+	return nodes[:count], nil
+}
+
+func workerLabels(cr *perfv1alpha1.Fio) map[string]string {
+	return map[string]string{
+		"kubestone.xridge.io/app":     "fio-worker",
+		"kubestone.xridge.io/cr-name": cr.Name,
+	}
+}
+
+// NewWorkerDeployment creates the deployment of the fio server of the worker
+// with the given index, which is pinned to the given node. The worker runs
+// the jobs on the volume of the CR, or on its own PVC if the PVC is generated.
+func NewWorkerDeployment(cr *perfv1alpha1.Fio, index int, nodeName string) *appsv1.Deployment {
+	replicas := int32(1)
+	podConfig := cr.Spec.Workers.PodConfig
+
+	// The selector of the deployment should not overlap with the other workers
+	selector := workerLabels(cr)
+	selector["kubestone.xridge.io/fio-worker"] = WorkerName(cr, index)
+	labels := map[string]string{}
+	for k, v := range podConfig.PodLabels {
+		labels[k] = v
+	}
+	for k, v := range selector {
+		labels[k] = v
+	}
+
+	volumeSource := *cr.Spec.Volume.VolumeSource.DeepCopy()
+	if cr.Spec.Volume.PersistentVolumeClaimSpec != nil {
+		volumeSource.PersistentVolumeClaim.ClaimName = WorkerName(cr, index)
+	}
+
+	// The fio server does not tolerate the probe connections, therefore the
+	// listening port is looked up in the socket table of the container
+	readinessAwkCmd := fmt.Sprintf("BEGIN{err=1}toupper($2)~/:%04X$/{err=0}END{exit err}", FioServerPort)
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        WorkerName(cr, index),
+			Namespace:   cr.Namespace,
+			Annotations: podConfig.Annotations,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selector,
+			},
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      labels,
+					Annotations: podConfig.Annotations,
+				},
+				Spec: corev1.PodSpec{
+					Hostname:  WorkerName(cr, index),
+					Subdomain: workersServiceName(cr),
+					ImagePullSecrets: []corev1.LocalObjectReference{
+						{
+							Name: cr.Spec.Image.PullSecret,
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:            "fio-server",
+							Image:           cr.Spec.Image.Name,
+							ImagePullPolicy: corev1.PullPolicy(cr.Spec.Image.PullPolicy),
+							Args:            []string{"--server"},
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          "fio-server",
+									ContainerPort: FioServerPort,
+									Protocol:      corev1.ProtocolTCP,
+								},
+							},
+							ReadinessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									Exec: &corev1.ExecAction{
+										Command: []string{
+											"awk",
+											readinessAwkCmd,
+											"/proc/1/net/tcp",
+											"/proc/1/net/tcp6",
+										},
+									},
+								},
+								InitialDelaySeconds: 2,
+								TimeoutSeconds:      2,
+								PeriodSeconds:       2,
+							},
+							Resources: podConfig.Resources,
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "data", MountPath: "/data"},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{Name: "data", VolumeSource: volumeSource},
+					},
+					Affinity:     podConfig.PodScheduling.Affinity,
+					Tolerations:  podConfig.PodScheduling.Tolerations,
+					NodeSelector: podConfig.PodScheduling.NodeSelector,
+					NodeName:     nodeName,
+				},
+			},
+		},
+	}
+}
+
+// NewWorkersService creates the headless service of the workers, which
+// provides the DNS names of the workers for the fio client
+func NewWorkersService(cr *perfv1alpha1.Fio) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workersServiceName(cr),
+			Namespace: cr.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name:     "fio-server",
+					Protocol: corev1.ProtocolTCP,
+					Port:     FioServerPort,
+				},
+			},
+			Selector:  workerLabels(cr),
+			ClusterIP: "None", // Headless service!
+		},
+	}
+}
+
+// NewWorkerPersistentVolumeClaim creates the generated PVC of the worker
+// with the given index, or returns nil if the PVC is not generated
+func NewWorkerPersistentVolumeClaim(cr *perfv1alpha1.Fio, index int) *corev1.PersistentVolumeClaim {
+	if cr.Spec.Volume.PersistentVolumeClaimSpec == nil {
+		return nil
+	}
+	return k8s.NewPersistentVolumeClaim(*cr.Spec.Volume.PersistentVolumeClaimSpec,
+		WorkerName(cr, index), cr.Namespace)
+}
+
+// validateWorkers checks the workers of the distributed benchmark
+func validateWorkers(cr *perfv1alpha1.Fio) error {
+	workers := cr.Spec.Workers
+	if workers.Count < 1 {
+		return errors.New("at least one worker is required")
+	}
+	if workers.PodConfig.PodScheduling.NodeName != "" {
+		return errors.New("the node name of the workers cannot be set, use the node selector of the workers")
+	}
+	if cr.Spec.Volume.PersistentVolumeClaimSpec != nil && cr.Spec.Volume.VolumeSource.PersistentVolumeClaim == nil {
+		return errors.New("the volume source of the workers must refer to the " +
+			perfv1alpha1.GeneratedPVC + " persistent volume claim")
+	}
+	return k8s.ValidateNodeSelector(workers.NodeSelector)
+}
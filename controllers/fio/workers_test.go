@@ -0,0 +1,160 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fio
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("fio workers", func() {
+	var cr perfv1alpha1.Fio
+
+	BeforeEach(func() {
+		cr = perfv1alpha1.Fio{
+			ObjectMeta: metav1.ObjectMeta{Name: "fio-nfs", Namespace: "kubestone"},
+			Spec: perfv1alpha1.FioSpec{
+				Image:       perfv1alpha1.ImageSpec{Name: "xridge/fio:3.13"},
+				CmdLineArgs: "--name=randread --rw=randread --directory=/data",
+				Volume: perfv1alpha1.VolumeSpec{
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: perfv1alpha1.GeneratedPVC,
+						},
+					},
+					PersistentVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceStorage: resource.MustParse("10Gi"),
+							},
+						},
+					},
+				},
+				Workers: &perfv1alpha1.FioWorkersSpec{
+					Count:        2,
+					NodeSelector: map[string]string{"pool": "storage"},
+					PodConfig: perfv1alpha1.PodConfigurationSpec{
+						PodLabels: map[string]string{"team": "storage"},
+					},
+				},
+			},
+		}
+	})
+
+	Context("validating the CR", func() {
+		It("should accept the workers", func() {
+			Expect(IsCrValid(&cr)).To(BeTrue())
+		})
+
+		It("should reject the node name of the workers", func() {
+			cr.Spec.Workers.PodConfig.PodScheduling.NodeName = "storage-1"
+			valid, err := IsCrValid(&cr)
+			Expect(valid).To(BeFalse())
+			Expect(err).To(MatchError(ContainSubstring("node name")))
+		})
+
+		It("should reject invalid node selectors", func() {
+			cr.Spec.Workers.NodeSelector = map[string]string{"pool": "not valid"}
+			valid, err := IsCrValid(&cr)
+			Expect(valid).To(BeFalse())
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("selecting the nodes", func() {
+		It("should place each worker on a different node", func() {
+			Expect(SelectWorkerNodes(&cr, []string{"storage-1", "storage-2", "storage-3"})).To(
+				Equal([]string{"storage-1", "storage-2"}))
+		})
+
+		It("should fail without enough nodes", func() {
+			_, err := SelectWorkerNodes(&cr, []string{"storage-1"})
+			Expect(err).To(MatchError(ContainSubstring("only 1 schedulable nodes")))
+		})
+	})
+
+	Context("creating the worker deployment", func() {
+		It("should run a fio server pinned to the node", func() {
+			deployment := NewWorkerDeployment(&cr, 1, "storage-2")
+			Expect(deployment.Name).To(Equal("fio-nfs-worker-1"))
+			podSpec := deployment.Spec.Template.Spec
+			Expect(podSpec.NodeName).To(Equal("storage-2"))
+			Expect(podSpec.Hostname).To(Equal("fio-nfs-worker-1"))
+			Expect(podSpec.Subdomain).To(Equal("fio-nfs-workers"))
+			Expect(podSpec.Containers[0].Args).To(Equal([]string{"--server"}))
+			Expect(podSpec.Containers[0].Ports[0].ContainerPort).To(Equal(int32(FioServerPort)))
+			Expect(deployment.Spec.Template.Labels).To(HaveKeyWithValue("team", "storage"))
+		})
+
+		It("should mount the generated PVC of the worker", func() {
+			deployment := NewWorkerDeployment(&cr, 1, "storage-2")
+			volume := deployment.Spec.Template.Spec.Volumes[0]
+			Expect(volume.PersistentVolumeClaim.ClaimName).To(Equal("fio-nfs-worker-1"))
+			Expect(cr.Spec.Volume.VolumeSource.PersistentVolumeClaim.ClaimName).To(
+				Equal(perfv1alpha1.GeneratedPVC))
+
+			pvc := NewWorkerPersistentVolumeClaim(&cr, 1)
+			Expect(pvc.Name).To(Equal("fio-nfs-worker-1"))
+		})
+
+		It("should share the other volumes between the workers", func() {
+			cr.Spec.Volume = perfv1alpha1.VolumeSpec{
+				VolumeSource: corev1.VolumeSource{
+					NFS: &corev1.NFSVolumeSource{Server: "nfs", Path: "/export"},
+				},
+			}
+			deployment := NewWorkerDeployment(&cr, 0, "storage-1")
+			Expect(deployment.Spec.Template.Spec.Volumes[0].NFS.Server).To(Equal("nfs"))
+			Expect(NewWorkerPersistentVolumeClaim(&cr, 0)).To(BeNil())
+		})
+
+		It("should select the pods of the worker only", func() {
+			first := NewWorkerDeployment(&cr, 0, "storage-1")
+			second := NewWorkerDeployment(&cr, 1, "storage-2")
+			Expect(first.Spec.Selector.MatchLabels).NotTo(Equal(second.Spec.Selector.MatchLabels))
+
+			service := NewWorkersService(&cr)
+			for key, value := range service.Spec.Selector {
+				Expect(first.Spec.Template.Labels).To(HaveKeyWithValue(key, value))
+				Expect(second.Spec.Template.Labels).To(HaveKeyWithValue(key, value))
+			}
+		})
+	})
+
+	Context("creating the client job", func() {
+		It("should drive the workers listed in the config map", func() {
+			configMap := NewConfigMap(&cr)
+			Expect(strings.Split(strings.TrimSpace(configMap.Data[WorkerHostsFile]), "\n")).To(Equal([]string{
+				"fio-nfs-worker-0.fio-nfs-workers",
+				"fio-nfs-worker-1.fio-nfs-workers",
+			}))
+
+			job := NewJob(&cr)
+			Expect(job.Spec.Template.Spec.Containers[0].Args[0]).To(Equal("--client=/clients/hosts"))
+			Expect(job.Spec.Template.Spec.Containers[0].Args).To(ContainElement("--rw=randread"))
+			Expect(job.Spec.Template.Spec.Volumes).To(HaveLen(1))
+			Expect(job.Spec.Template.Spec.Volumes[0].ConfigMap.Name).To(Equal("fio-nfs"))
+		})
+	})
+})
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 
@@ -27,6 +28,8 @@ import (
 
 	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
 	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/lifecycle"
+	"github.com/xridge/kubestone/pkg/results"
 )
 
 // Reconciler provides fields from manager to reconciler
@@ -39,7 +42,8 @@ type Reconciler struct {
 // +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=iopings/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=iopings/finalizers,verbs=update
 
-// Reconcile creates ioping job based on the custom resource
+// Reconcile creates ioping job based on the custom resource. In
+// StorageClass comparison mode one job is run for each StorageClass.
 func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := context.Background()
 
@@ -49,71 +53,117 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	}
 
 	// Run to one completion
-	if cr.Status.Completed {
+	if cr.Status.IsFinished() {
 		return ctrl.Result{}, nil
 	}
 
 	// Validate on first entry
-	if !cr.Status.Completed && !cr.Status.Running {
+	if cr.Status.Phase == "" {
+		if err := r.K8S.UpdatePhase(ctx, &cr, perfv1alpha1.BenchmarkValidating, "", ""); err != nil {
+			return ctrl.Result{}, err
+		}
 		if valid, err := IsCrValid(&cr); !valid {
 			_ = r.K8S.RecordEventf(&cr, corev1.EventTypeWarning, k8s.CreateFailed,
 				"CR validation failed: %v", err)
 
 			// Do not requeue invalid CRs
-			return ctrl.Result{}, nil
+			return ctrl.Result{}, r.K8S.UpdatePhase(ctx, &cr, perfv1alpha1.BenchmarkFailed,
+				k8s.ValidationFailed, err.Error())
 		}
+		cr.Status.SetCondition(perfv1alpha1.ConditionValidated, corev1.ConditionTrue, "", "")
 	}
 
-	cr.Status.Running = true
-	if err := r.K8S.Client.Status().Update(ctx, &cr); err != nil {
-		return ctrl.Result{}, err
-	}
+	var jobNames []types.NamespacedName
+	if cr.Spec.Volume.IsStorageClassComparison() {
+		names, err := r.K8S.CreateStorageClassJobs(ctx, &cr, &cr.Spec.Volume,
+			func(storageClass string) *batchv1.Job {
+				return NewStorageClassJob(&cr, storageClass)
+			})
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		jobNames = names
+	} else {
+		if cr.Spec.Volume.PersistentVolumeClaimSpec != nil {
+			pvc := k8s.NewPersistentVolumeClaim(*cr.Spec.Volume.PersistentVolumeClaimSpec,
+				cr.Name, cr.Namespace)
+			if err := r.K8S.CreateWithReference(ctx, pvc, &cr); err != nil {
+				return ctrl.Result{}, err
+			}
+			// Change ClaimName (from GENERATED) to the PVC was created
+			cr.Spec.Volume.VolumeSource.PersistentVolumeClaim.ClaimName = cr.Name
+		}
 
-	if cr.Spec.Volume.PersistentVolumeClaimSpec != nil {
-		pvc := k8s.NewPersistentVolumeClaim(*cr.Spec.Volume.PersistentVolumeClaimSpec,
-			cr.Name, cr.Namespace)
-		if err := r.K8S.CreateWithReference(ctx, pvc, &cr); err != nil {
+		job := NewJob(&cr)
+		if err := r.K8S.CreateWithReference(ctx, job, &cr); err != nil {
 			return ctrl.Result{}, err
 		}
-		// Change ClaimName (from GENERATED) to the PVC was created
-		cr.Spec.Volume.VolumeSource.PersistentVolumeClaim.ClaimName = cr.Name
+		jobNames = append(jobNames, types.NamespacedName{
+			Namespace: cr.Namespace,
+			Name:      job.Name,
+		})
 	}
-
-	job := NewJob(&cr)
-	if err := r.K8S.CreateWithReference(ctx, job, &cr); err != nil {
+	if err := r.K8S.SyncRunningPhase(ctx, &cr, jobNames...); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	// Check if finished
-	jobFinished, err := r.K8S.IsJobFinished(types.NamespacedName{
-		Namespace: cr.Namespace,
-		Name:      cr.Name,
-	})
-	if err != nil {
-		return ctrl.Result{}, err
+	// Check all the job statuses
+	for _, jobName := range jobNames {
+		jobFinished, err := r.K8S.IsJobFinished(jobName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !jobFinished {
+			// Wait for the job to be completed
+			return k8s.WaitForJobs(&cr), nil
+		}
 	}
-	if !jobFinished {
-		// Wait for the job to be completed
-		return ctrl.Result{Requeue: true}, nil
+
+	// The first failed job determines the failure of the benchmark
+	var jobFailure *k8s.JobFailure
+	for _, jobName := range jobNames {
+		failure, err := r.K8S.GetJobFailure(jobName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if failure != nil {
+			jobFailure = failure
+			break
+		}
 	}
 
 	// The cr could have been modified since the last time we got it
 	if err := r.K8S.Client.Get(ctx, req.NamespacedName, &cr); err != nil {
 		return ctrl.Result{}, k8s.IgnoreNotFound(err)
 	}
-	cr.Status.Running = false
-	cr.Status.Completed = true
-	if err := r.K8S.Client.Status().Update(ctx, &cr); err != nil {
+	// The results are parsed from the logs of the successful benchmark
+	if jobFailure == nil {
+		if err := collectResults(&r.K8S, &cr, jobNames); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.K8S.FinishBenchmark(ctx, &cr, jobFailure); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	// Keep a record of the run, which outlives the benchmark
+	return ctrl.Result{}, results.Record(ctx, &r.K8S, &cr, jobNames...)
+}
 
+// collectResults parses the results of the finished jobs. The results of
+// the StorageClass comparison are reported side by side.
+func collectResults(access *k8s.Access, cr *perfv1alpha1.Ioping, jobNames []types.NamespacedName) error {
+	if cr.Spec.Volume.IsStorageClassComparison() {
+		return results.CollectStorageClasses(access, cr, cr.Spec.Volume.StorageClassNames, jobNames...)
+	}
+	return results.Collect(access, cr, jobNames...)
 }
 
 // SetupWithManager registers the Reconciler with the provided manager
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&perfv1alpha1.Ioping{}).
-		Complete(r)
+		Owns(&batchv1.Job{}).
+		Complete(lifecycle.NewReconciler(r, &r.K8S, &perfv1alpha1.Ioping{}))
 }
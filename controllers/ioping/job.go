@@ -50,6 +50,18 @@ func NewJob(cr *perfv1alpha1.Ioping) *batchv1.Job {
 	return job
 }
 
+// NewStorageClassJob creates the ioping benchmark job which runs against
+// the PVC generated for the given StorageClass in the StorageClass comparison
+func NewStorageClassJob(cr *perfv1alpha1.Ioping, storageClass string) *batchv1.Job {
+	name := k8s.StorageClassObjectName(cr.Name, storageClass)
+	classCr := cr.DeepCopy()
+	classCr.Spec.Volume.VolumeSource.PersistentVolumeClaim.ClaimName = name
+
+	job := NewJob(classCr)
+	job.Name = name
+	return job
+}
+
 // IsCrValid validates the given CR and raises error if semantic errors detected
 // For IOPing, the VolumeSpec validity is checked
 func IsCrValid(cr *perfv1alpha1.Ioping) (valid bool, err error) {
@@ -20,6 +20,7 @@ import (
 	. "github.com/onsi/gomega"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
 )
@@ -89,4 +90,54 @@ var _ = Describe("ioping job", func() {
 			})
 		})
 	})
+
+	Describe("with StorageClass comparison", func() {
+		var cr perfv1alpha1.Ioping
+
+		BeforeEach(func() {
+			cr = perfv1alpha1.Ioping{
+				ObjectMeta: metav1.ObjectMeta{Name: "ioping", Namespace: "storage"},
+				Spec: perfv1alpha1.IopingSpec{
+					Image: perfv1alpha1.ImageSpec{Name: "xridge/ioping:test"},
+					Volume: perfv1alpha1.VolumeSpec{
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+								ClaimName: perfv1alpha1.GeneratedPVC,
+							},
+						},
+						StorageClassNames: []string{"standard", "fast"},
+					},
+				},
+			}
+		})
+
+		It("should be valid", func() {
+			Expect(IsCrValid(&cr)).To(BeTrue())
+		})
+
+		It("should run the job against the PVC of the StorageClass", func() {
+			job := NewStorageClassJob(&cr, "fast")
+			Expect(job.Name).To(Equal("ioping-fast"))
+			Expect(job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(
+				Equal("ioping-fast"))
+			Expect(cr.Spec.Volume.VolumeSource.PersistentVolumeClaim.ClaimName).To(
+				Equal(perfv1alpha1.GeneratedPVC))
+		})
+
+		It("should reject StorageClasses without generated PVC", func() {
+			cr.Spec.Volume.VolumeSource = corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			}
+			valid, err := IsCrValid(&cr)
+			Expect(valid).To(BeFalse())
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject duplicate StorageClasses", func() {
+			cr.Spec.Volume.StorageClassNames = []string{"fast", "standard", "fast"}
+			valid, err := IsCrValid(&cr)
+			Expect(valid).To(BeFalse())
+			Expect(err).To(MatchError(ContainSubstring(`"fast"`)))
+		})
+	})
 })
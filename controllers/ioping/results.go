@@ -0,0 +1,86 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ioping
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/results"
+)
+
+func init() {
+	results.Register("Ioping", results.ParserFunc(ParseResults))
+}
+
+var (
+	latencyRegexp = regexp.MustCompile(
+		`min/avg/max/mdev = ([0-9.]+) (\S+) / ([0-9.]+) (\S+) / ([0-9.]+) (\S+) / ([0-9.]+) (\S+)`)
+	iopsRegexp       = regexp.MustCompile(`requests completed in .*?, ([0-9.]+) (k |M )?iops`)
+	throughputRegexp = regexp.MustCompile(`requests completed in .*iops, ([0-9.]+) (\S*)/s`)
+)
+
+// ParseResults parses the statistics printed by ioping at the end of the run
+func ParseResults(logs string) (*perfv1alpha1.BenchmarkResults, error) {
+	match := latencyRegexp.FindStringSubmatch(logs)
+	if match == nil {
+		return nil, errors.New("latency statistics are missing from ioping output")
+	}
+
+	res := &perfv1alpha1.BenchmarkResults{}
+	for i, name := range []string{"latency.min", "latency.avg", "latency.max", "latency.mdev"} {
+		value, err := strconv.ParseFloat(match[1+2*i], 64)
+		if err != nil {
+			return nil, err
+		}
+		latency, err := results.Microseconds(value, match[2+2*i])
+		if err != nil {
+			return nil, err
+		}
+		res.AddMetric(name, latency, "us")
+	}
+
+	if match := iopsRegexp.FindStringSubmatch(logs); match != nil {
+		iops, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return nil, err
+		}
+		switch match[2] {
+		case "k ":
+			iops *= 1000
+		case "M ":
+			iops *= 1000 * 1000
+		}
+		res.AddMetric("iops", iops, "ops/s")
+	}
+
+	if match := throughputRegexp.FindStringSubmatch(logs); match != nil {
+		value, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return nil, err
+		}
+		throughput, err := results.Bytes(value, match[2])
+		if err != nil {
+			return nil, err
+		}
+		res.AddMetric("throughput", throughput, "bytes/s")
+	}
+
+	return res, nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ioping
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const iopingOutput = `4 KiB <<< /mnt/ (overlay overlay 58.4 GiB): request=1 time=180.2 us (warmup)
+4 KiB <<< /mnt/ (overlay overlay 58.4 GiB): request=2 time=111.3 us
+4 KiB <<< /mnt/ (overlay overlay 58.4 GiB): request=3 time=1.02 ms
+
+--- /mnt/ (overlay overlay 58.4 GiB) ioping statistics ---
+9 requests completed in 1.46 ms, 36 KiB read, 6.16 k iops, 24.1 MiB/s
+generated 10 requests in 9.00 s, 40 KiB, 1 iops, 4.44 KiB/s
+min/avg/max/mdev = 111.3 us / 162.3 us / 1.02 ms / 48.1 us
+`
+
+var _ = Describe("ioping results", func() {
+	Context("with the output of a successful run", func() {
+		results, err := ParseResults(iopingOutput)
+
+		It("should not fail", func() {
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reports the latencies in microseconds", func() {
+			Expect(results.GetMetric("latency.min").Value).To(Equal("111.3"))
+			Expect(results.GetMetric("latency.avg").Value).To(Equal("162.3"))
+			Expect(results.GetMetric("latency.max").Value).To(Equal("1020"))
+			Expect(results.GetMetric("latency.mdev").Value).To(Equal("48.1"))
+			Expect(results.GetMetric("latency.avg").Unit).To(Equal("us"))
+		})
+
+		It("reports iops and throughput of the completed requests", func() {
+			Expect(results.GetMetric("iops").Value).To(Equal("6160"))
+			Expect(results.GetMetric("throughput").Float64()).To(BeNumerically("~", 24.1*1024*1024))
+		})
+	})
+
+	Context("with unrelated output", func() {
+		It("should fail", func() {
+			_, err := ParseResults("ioping: No such file or directory\n")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -17,6 +17,8 @@ limitations under the License.
 package iperf3
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 
 	batchv1 "k8s.io/api/batch/v1"
@@ -29,20 +31,25 @@ import (
 
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;create;delete
 
-func clientJobName(cr *perfv1alpha1.Iperf3) string {
+func clientJobName(cr *perfv1alpha1.Iperf3, index int) string {
 	// Should not match with service name as the pod's
 	// hostname is set to it's name. If the two matches
 	// the destination ip will resolve to 127.0.0.1 and
 	// the server will be unreachable.
-	return serverServiceName(cr) + "-client"
+	name := serverServiceName(cr) + "-client"
+	if cr.Spec.ClientParallelism() > 1 {
+		name = fmt.Sprintf("%s-%d", name, index)
+	}
+	return name
 }
 
-// NewClientJob creates an Iperf3 Client Job (targeting the
-// Server Deployment via the Server Service) from the provided
-// IPerf3 Benchmark Definition.
-func NewClientJob(cr *perfv1alpha1.Iperf3) *batchv1.Job {
+// NewClientJob creates the Iperf3 Client Job with the given index
+// (targeting the Server Deployment via the Server Service) from the
+// provided IPerf3 Benchmark Definition. Each of the parallel clients
+// connects to the port of its own server instance.
+func NewClientJob(cr *perfv1alpha1.Iperf3, index int) *batchv1.Job {
 	objectMeta := metav1.ObjectMeta{
-		Name:      clientJobName(cr),
+		Name:      clientJobName(cr, index),
 		Namespace: cr.Namespace,
 	}
 
@@ -50,13 +57,17 @@ func NewClientJob(cr *perfv1alpha1.Iperf3) *batchv1.Job {
 
 	iperfCmdLineArgs := []string{
 		"--client", serverAddress,
-		"--port", strconv.Itoa(Iperf3ServerPort),
+		"--port", strconv.Itoa(serverPort(index)),
 	}
 
 	if cr.Spec.UDP {
 		iperfCmdLineArgs = append(iperfCmdLineArgs, "--udp")
 	}
 
+	if cr.Spec.JSON {
+		iperfCmdLineArgs = append(iperfCmdLineArgs, "--json")
+	}
+
 	iperfCmdLineArgs = append(iperfCmdLineArgs,
 		qsplit.ToStrings([]byte(cr.Spec.ClientConfiguration.CmdLineArgs))...)
 
@@ -69,3 +80,17 @@ func NewClientJob(cr *perfv1alpha1.Iperf3) *batchv1.Job {
 
 	return job
 }
+
+// IsCrValid validates the given CR and raises error if semantic errors detected
+// For iperf3 it checks that the replicas and the parallelism are set on the
+// side of the benchmark they apply to
+func IsCrValid(cr *perfv1alpha1.Iperf3) (valid bool, err error) {
+	if cr.Spec.ServerConfiguration.Parallelism != nil {
+		return false, errors.New("parallelism can only be set in the client configuration")
+	}
+	if cr.Spec.ClientConfiguration.Replicas != nil {
+		return false, errors.New("replicas can only be set in the server configuration")
+	}
+
+	return true, nil
+}
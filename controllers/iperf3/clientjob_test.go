@@ -49,7 +49,7 @@ var _ = Describe("Client Pod", func() {
 					},
 				},
 			}
-			job = NewClientJob(&cr)
+			job = NewClientJob(&cr, 0)
 		})
 
 		Context("with default settings", func() {
@@ -67,6 +67,10 @@ var _ = Describe("Client Pod", func() {
 				Expect(job.Spec.Template.Spec.Containers[0].Args).NotTo(
 					ContainElement("--udp"))
 			})
+			It("should not contain --json flag", func() {
+				Expect(job.Spec.Template.Spec.Containers[0].Args).NotTo(
+					ContainElement("--json"))
+			})
 		})
 
 		Context("with cmdLineArgs specified", func() {
@@ -78,13 +82,22 @@ var _ = Describe("Client Pod", func() {
 
 		Context("with UDP mode specified", func() {
 			cr.Spec.UDP = true
-			job := NewClientJob(&cr)
+			job := NewClientJob(&cr, 0)
 			It("should contain --udp flag in iperf args", func() {
 				Expect(job.Spec.Template.Spec.Containers[0].Args).To(
 					ContainElement("--udp"))
 			})
 		})
 
+		Context("with JSON mode specified", func() {
+			cr.Spec.JSON = true
+			job := NewClientJob(&cr, 0)
+			It("should contain --json flag in iperf args", func() {
+				Expect(job.Spec.Template.Spec.Containers[0].Args).To(
+					ContainElement("--json"))
+			})
+		})
+
 		Context("with HostNetwork specified", func() {
 			It("should match with HostNetwork", func() {
 				Expect(job.Spec.Template.Spec.HostNetwork).To(
@@ -113,10 +126,58 @@ var _ = Describe("Client Pod", func() {
 			})
 		})
 
+		Context("with parallel clients specified", func() {
+			BeforeEach(func() {
+				cr.Name = "iperf3"
+				parallelism := int32(3)
+				cr.Spec.ClientConfiguration.Parallelism = &parallelism
+			})
+
+			It("should create a job for each client", func() {
+				Expect(NewClientJob(&cr, 0).Name).To(Equal("iperf3-client-0"))
+				Expect(NewClientJob(&cr, 2).Name).To(Equal("iperf3-client-2"))
+			})
+			It("should connect each client to its own server", func() {
+				job := NewClientJob(&cr, 2)
+				Expect(strings.Join(job.Spec.Template.Spec.Containers[0].Args, " ")).To(
+					ContainSubstring("--port " + strconv.Itoa(Iperf3ServerPort+2)))
+			})
+		})
+
 		Context("with added annotations", func() {
 			It("should contain pod annotations", func() {
 				Expect(job.ObjectMeta.Annotations).To(HaveKey("annotation_one"))
 			})
 		})
 	})
+
+	Describe("validating the CR", func() {
+		var cr ksapi.Iperf3
+		var count int32
+
+		BeforeEach(func() {
+			count = 2
+			cr = ksapi.Iperf3{}
+		})
+
+		It("should accept parallel clients and server replicas", func() {
+			cr.Spec.ClientConfiguration.Parallelism = &count
+			cr.Spec.ServerConfiguration.Replicas = &count
+			Expect(IsCrValid(&cr)).To(BeTrue())
+		})
+
+		It("should reject parallelism in the server configuration", func() {
+			cr.Spec.ServerConfiguration.Parallelism = &count
+			valid, err := IsCrValid(&cr)
+			Expect(valid).To(BeFalse())
+			Expect(err).To(MatchError(ContainSubstring("client configuration")))
+		})
+
+		It("should reject replicas in the client configuration", func() {
+			cr.Spec.ClientConfiguration.Replicas = &count
+			valid, err := IsCrValid(&cr)
+			Expect(valid).To(BeFalse())
+			Expect(err).To(MatchError(ContainSubstring("server configuration")))
+		})
+	})
 })
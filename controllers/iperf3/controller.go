@@ -20,10 +20,15 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/lifecycle"
+	"github.com/xridge/kubestone/pkg/results"
 
 	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
 )
@@ -41,9 +46,9 @@ type Reconciler struct {
 // Reconcile Iperf3 Benchmark Requests by creating:
 //   - iperf3 server deployment
 //   - iperf3 server service
-//   - iperf3 client pod
-// The creation of iperf3 client pod is postponed until the server
-// deployment completes. Once the iperf3 client pod is completed,
+//   - iperf3 client job(s)
+// The creation of iperf3 client jobs is postponed until the server
+// deployment completes. Once the iperf3 client jobs are completed,
 // the server deployment and service objects are removed from k8s.
 func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := context.Background()
@@ -54,13 +59,24 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	}
 
 	// Run to one completion
-	if cr.Status.Completed {
+	if cr.Status.IsFinished() {
 		return ctrl.Result{}, nil
 	}
 
-	cr.Status.Running = true
-	if err := r.K8S.Client.Status().Update(ctx, &cr); err != nil {
-		return ctrl.Result{}, err
+	// Validate on first entry
+	if cr.Status.Phase == "" {
+		if err := r.K8S.UpdatePhase(ctx, &cr, perfv1alpha1.BenchmarkValidating, "", ""); err != nil {
+			return ctrl.Result{}, err
+		}
+		if valid, err := IsCrValid(&cr); !valid {
+			_ = r.K8S.RecordEventf(&cr, corev1.EventTypeWarning, k8s.CreateFailed,
+				"CR validation failed: %v", err)
+
+			// Do not requeue invalid CRs
+			return ctrl.Result{}, r.K8S.UpdatePhase(ctx, &cr, perfv1alpha1.BenchmarkFailed,
+				k8s.ValidationFailed, err.Error())
+		}
+		cr.Status.SetCondition(perfv1alpha1.ConditionValidated, corev1.ConditionTrue, "", "")
 	}
 
 	serverDeployment := NewServerDeployment(&cr)
@@ -73,31 +89,69 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, err
 	}
 
+	if cr.Status.Phase == perfv1alpha1.BenchmarkValidating {
+		if err := r.K8S.UpdatePhase(ctx, &cr, perfv1alpha1.BenchmarkDeployingServer, "", ""); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	endpointReady, err := r.K8S.IsEndpointReady(types.NamespacedName{
 		Namespace: cr.Namespace,
 		Name:      cr.Name})
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	if !endpointReady {
-		// Wait for deployment to be connected to the service endpoint
-		return ctrl.Result{Requeue: true}, nil
+	deploymentReady, err := r.K8S.IsDeploymentReady(types.NamespacedName{
+		Namespace: cr.Namespace,
+		Name:      serverDeploymentName(&cr)})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !endpointReady || !deploymentReady {
+		// Wait for all the server pods to be connected to the service endpoint
+		return k8s.RequeueWithBackoff(&cr), nil
+	}
+	cr.Status.SetCondition(perfv1alpha1.ConditionServerReady, corev1.ConditionTrue, "", "")
+
+	// The parallel clients are started at the same time
+	var jobNames []types.NamespacedName
+	for i := 0; i < int(cr.Spec.ClientParallelism()); i++ {
+		if err := r.K8S.CreateWithReference(ctx, NewClientJob(&cr, i), &cr); err != nil {
+			return ctrl.Result{}, err
+		}
+		jobNames = append(jobNames, types.NamespacedName{
+			Namespace: cr.Namespace,
+			Name:      clientJobName(&cr, i),
+		})
 	}
 
-	if err := r.K8S.CreateWithReference(ctx, NewClientJob(&cr), &cr); err != nil {
+	if err := r.K8S.SyncRunningPhase(ctx, &cr, jobNames...); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	jobFinished, err := r.K8S.IsJobFinished(types.NamespacedName{
-		Namespace: cr.Namespace,
-		Name:      clientJobName(&cr),
-	})
-	if err != nil {
-		return ctrl.Result{}, err
+	// Check if finished
+	for _, jobName := range jobNames {
+		jobFinished, err := r.K8S.IsJobFinished(jobName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !jobFinished {
+			// Wait for the jobs to be completed
+			return k8s.WaitForJobs(&cr), nil
+		}
 	}
-	if !jobFinished {
-		// Wait for the job to be completed
-		return ctrl.Result{Requeue: true}, nil
+
+	// The first failed client determines the failure of the benchmark
+	var jobFailure *k8s.JobFailure
+	for _, jobName := range jobNames {
+		failure, err := r.K8S.GetJobFailure(jobName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if failure != nil {
+			jobFailure = failure
+			break
+		}
 	}
 
 	if err := r.K8S.DeleteObject(ctx, serverService, &cr); err != nil {
@@ -108,18 +162,28 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, err
 	}
 
-	cr.Status.Running = false
-	cr.Status.Completed = true
-	if err := r.K8S.Client.Status().Update(ctx, &cr); err != nil {
+	// The results are parsed from the logs of the successful benchmark,
+	// which are only machine-readable in JSON mode
+	if jobFailure == nil && cr.Spec.JSON {
+		if err := results.Collect(&r.K8S, &cr, jobNames...); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.K8S.FinishBenchmark(ctx, &cr, jobFailure); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	// Keep a record of the run, which outlives the benchmark
+	return ctrl.Result{}, results.Record(ctx, &r.K8S, &cr, jobNames...)
 }
 
 // SetupWithManager registers the Iperf3Reconciler with the provided manager
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&perfv1alpha1.Iperf3{}).
-		Complete(r)
+		Owns(&batchv1.Job{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.Service{}).
+		Complete(lifecycle.NewReconciler(r, &r.K8S, &perfv1alpha1.Iperf3{}))
 }
@@ -0,0 +1,217 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iperf3
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/results"
+	"github.com/xridge/kubestone/pkg/stats"
+)
+
+func init() {
+	results.Register("Iperf3", results.ParserFunc(ParseResults))
+}
+
+// iperf3Output is the subset of iperf3's --json output used by kubestone
+type iperf3Output struct {
+	Start struct {
+		TestStart struct {
+			Protocol string `json:"protocol"`
+		} `json:"test_start"`
+	} `json:"start"`
+	End struct {
+		Streams []struct {
+			Sender   *iperf3Summary `json:"sender"`
+			Receiver *iperf3Summary `json:"receiver"`
+			UDP      *iperf3Summary `json:"udp"`
+		} `json:"streams"`
+		SumSent     *iperf3Summary `json:"sum_sent"`
+		SumReceived *iperf3Summary `json:"sum_received"`
+		Sum         *iperf3Summary `json:"sum"`
+		CPU         *struct {
+			HostTotal   float64 `json:"host_total"`
+			RemoteTotal float64 `json:"remote_total"`
+		} `json:"cpu_utilization_percent"`
+	} `json:"end"`
+	Error string `json:"error"`
+}
+
+type iperf3Summary struct {
+	Socket        int64    `json:"socket"`
+	BitsPerSecond float64  `json:"bits_per_second"`
+	Retransmits   *int64   `json:"retransmits"`
+	JitterMs      *float64 `json:"jitter_ms"`
+	LostPercent   *float64 `json:"lost_percent"`
+}
+
+// ParseResults parses the --json output of the iperf3 client(s). The
+// outputs of parallel clients follow each other in the logs, their
+// summaries are totalled into the summary of the benchmark.
+func ParseResults(logs string) (*perfv1alpha1.BenchmarkResults, error) {
+	start := strings.Index(logs, "{")
+	if start < 0 {
+		return nil, errors.New("JSON output is missing from iperf3 logs, is json enabled?")
+	}
+
+	var outputs []iperf3Output
+	decoder := json.NewDecoder(strings.NewReader(logs[start:]))
+	for {
+		var output iperf3Output
+		if err := decoder.Decode(&output); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if output.Error != "" {
+			return nil, errors.New(output.Error)
+		}
+		outputs = append(outputs, output)
+	}
+
+	iperf3Results := &perfv1alpha1.Iperf3Results{
+		Protocol: outputs[0].Start.TestStart.Protocol,
+	}
+	res := &perfv1alpha1.BenchmarkResults{Iperf3: iperf3Results}
+
+	var localCPU, remoteCPU []float64
+	for i := range outputs {
+		output := &outputs[i]
+		for _, stream := range output.End.Streams {
+			streamResult := perfv1alpha1.Iperf3StreamResult{}
+			addSummary(&streamResult, stream.Sender, true)
+			addSummary(&streamResult, stream.Receiver, false)
+			// UDP streams of older iperf3 versions are reported from
+			// the point of view of the client only
+			addSummary(&streamResult, stream.UDP, true)
+			iperf3Results.Streams = append(iperf3Results.Streams, streamResult)
+		}
+
+		sum := perfv1alpha1.Iperf3StreamResult{}
+		addSummary(&sum, output.End.SumSent, true)
+		addSummary(&sum, output.End.SumReceived, false)
+		addSummary(&sum, output.End.Sum, true)
+		if sum.SenderBitsPerSecond == 0 && sum.ReceiverBitsPerSecond == 0 {
+			return nil, errors.New("summary is missing from iperf3 output")
+		}
+		iperf3Results.Clients = append(iperf3Results.Clients, sum)
+
+		if cpu := output.End.CPU; cpu != nil {
+			localCPU = append(localCPU, cpu.HostTotal)
+			remoteCPU = append(remoteCPU, cpu.RemoteTotal)
+		}
+	}
+
+	if len(iperf3Results.Clients) == 1 {
+		iperf3Results.Sum = iperf3Results.Clients[0]
+		iperf3Results.Clients = nil
+	} else {
+		iperf3Results.Sum = totalSummary(iperf3Results.Clients)
+	}
+
+	sum := &iperf3Results.Sum
+	res.AddMetric("sender.bps", float64(sum.SenderBitsPerSecond), "bits/s")
+	if sum.ReceiverBitsPerSecond != 0 {
+		res.AddMetric("receiver.bps", float64(sum.ReceiverBitsPerSecond), "bits/s")
+	}
+	if sum.Retransmits != nil {
+		res.AddMetric("sender.retransmits", float64(*sum.Retransmits), "")
+	}
+	if sum.JitterMs != "" {
+		jitter, _ := strconv.ParseFloat(sum.JitterMs, 64)
+		res.AddMetric("udp.jitter", jitter, "ms")
+	}
+	if sum.LostPercent != "" {
+		lost, _ := strconv.ParseFloat(sum.LostPercent, 64)
+		res.AddMetric("udp.lost_percent", lost, "%")
+	}
+
+	if len(localCPU) > 0 {
+		iperf3Results.LocalCPUPercent = formatFloat(stats.Summarize(localCPU).Mean)
+		iperf3Results.RemoteCPUPercent = formatFloat(stats.Summarize(remoteCPU).Mean)
+		res.AddMetric("cpu.local", stats.Summarize(localCPU).Mean, "%")
+		res.AddMetric("cpu.remote", stats.Summarize(remoteCPU).Mean, "%")
+	}
+
+	return res, nil
+}
+
+// totalSummary returns the summary of the parallel clients: the total
+// of their throughput and retransmits, and the mean of their jitter
+// and packet loss
+func totalSummary(clients []perfv1alpha1.Iperf3StreamResult) perfv1alpha1.Iperf3StreamResult {
+	total := perfv1alpha1.Iperf3StreamResult{}
+	var jitters, losses []float64
+	for _, client := range clients {
+		total.SenderBitsPerSecond += client.SenderBitsPerSecond
+		total.ReceiverBitsPerSecond += client.ReceiverBitsPerSecond
+		if client.Retransmits != nil {
+			retransmits := *client.Retransmits
+			if total.Retransmits != nil {
+				retransmits += *total.Retransmits
+			}
+			total.Retransmits = &retransmits
+		}
+		if jitter, err := strconv.ParseFloat(client.JitterMs, 64); err == nil {
+			jitters = append(jitters, jitter)
+		}
+		if lost, err := strconv.ParseFloat(client.LostPercent, 64); err == nil {
+			losses = append(losses, lost)
+		}
+	}
+	if len(jitters) > 0 {
+		total.JitterMs = formatFloat(stats.Summarize(jitters).Mean)
+	}
+	if len(losses) > 0 {
+		total.LostPercent = formatFloat(stats.Summarize(losses).Mean)
+	}
+	return total
+}
+
+// addSummary merges the sender or receiver side summary into the result
+func addSummary(result *perfv1alpha1.Iperf3StreamResult, summary *iperf3Summary, sender bool) {
+	if summary == nil {
+		return
+	}
+
+	result.Socket = summary.Socket
+	bps := int64(math.Round(summary.BitsPerSecond))
+	if sender {
+		result.SenderBitsPerSecond = bps
+		if summary.Retransmits != nil {
+			result.Retransmits = summary.Retransmits
+		}
+	} else {
+		result.ReceiverBitsPerSecond = bps
+	}
+	if summary.JitterMs != nil {
+		result.JitterMs = formatFloat(*summary.JitterMs)
+	}
+	if summary.LostPercent != nil {
+		result.LostPercent = formatFloat(*summary.LostPercent)
+	}
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
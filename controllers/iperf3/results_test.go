@@ -0,0 +1,133 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iperf3
+
+import (
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+func parseFixture(name string) (*perfv1alpha1.BenchmarkResults, error) {
+	logs, err := ioutil.ReadFile("testdata/" + name)
+	Expect(err).NotTo(HaveOccurred())
+	return ParseResults(string(logs))
+}
+
+var _ = Describe("iperf3 results", func() {
+	Context("with the output of a TCP test with two streams", func() {
+		var results *perfv1alpha1.BenchmarkResults
+		var err error
+
+		BeforeEach(func() {
+			results, err = parseFixture("tcp.json")
+		})
+
+		It("should not fail", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.Iperf3.Protocol).To(Equal("TCP"))
+		})
+
+		It("reports the throughput and retransmits per stream", func() {
+			Expect(results.Iperf3.Streams).To(HaveLen(2))
+			stream := results.Iperf3.Streams[1]
+			Expect(stream.Socket).To(Equal(int64(7)))
+			Expect(stream.SenderBitsPerSecond).To(Equal(int64(4560892340)))
+			Expect(stream.ReceiverBitsPerSecond).To(Equal(int64(4559683212)))
+			Expect(*stream.Retransmits).To(Equal(int64(31)))
+		})
+
+		It("reports the summary of the streams", func() {
+			Expect(results.GetMetric("sender.bps").Value).To(Equal("9272891342"))
+			Expect(results.GetMetric("receiver.bps").Value).To(Equal("9270495557"))
+			Expect(results.GetMetric("sender.retransmits").Value).To(Equal("43"))
+			Expect(results.GetMetric("udp.jitter")).To(BeNil())
+		})
+
+		It("reports the cpu utilization of both ends", func() {
+			Expect(results.Iperf3.LocalCPUPercent).To(Equal("38.514242"))
+			Expect(results.Iperf3.RemoteCPUPercent).To(Equal("61.203315"))
+			Expect(results.GetMetric("cpu.remote").Value).To(Equal("61.203315"))
+		})
+	})
+
+	Context("with the output of an UDP test", func() {
+		var results *perfv1alpha1.BenchmarkResults
+		var err error
+
+		BeforeEach(func() {
+			results, err = parseFixture("udp.json")
+		})
+
+		It("reports jitter and packet loss", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.Iperf3.Protocol).To(Equal("UDP"))
+			Expect(results.Iperf3.Streams[0].JitterMs).To(Equal("0.012345"))
+			Expect(results.Iperf3.Sum.LostPercent).To(Equal("0.319829"))
+			Expect(results.GetMetric("udp.jitter").Value).To(Equal("0.012345"))
+			Expect(results.GetMetric("udp.lost_percent").Value).To(Equal("0.319829"))
+			Expect(results.GetMetric("sender.bps").Value).To(Equal("1048330"))
+		})
+	})
+
+	Context("with the outputs of parallel clients", func() {
+		var results *perfv1alpha1.BenchmarkResults
+		var err error
+
+		BeforeEach(func() {
+			logs, readErr := ioutil.ReadFile("testdata/tcp.json")
+			Expect(readErr).NotTo(HaveOccurred())
+			results, err = ParseResults(string(logs) + string(logs))
+		})
+
+		It("reports the summary of each client", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.Iperf3.Clients).To(HaveLen(2))
+			Expect(results.Iperf3.Clients[1].SenderBitsPerSecond).To(Equal(int64(9272891342)))
+			Expect(results.Iperf3.Streams).To(HaveLen(4))
+		})
+
+		It("reports the total throughput of the clients", func() {
+			Expect(results.Iperf3.Sum.SenderBitsPerSecond).To(Equal(int64(2 * 9272891342)))
+			Expect(results.GetMetric("sender.bps").Value).To(Equal("18545782684"))
+			Expect(results.GetMetric("receiver.bps").Value).To(Equal("18540991114"))
+			Expect(results.GetMetric("sender.retransmits").Value).To(Equal("86"))
+		})
+
+		It("reports the mean cpu utilization of the clients", func() {
+			Expect(results.Iperf3.LocalCPUPercent).To(Equal("38.514242"))
+		})
+	})
+
+	Context("with an error reported by iperf3", func() {
+		It("should fail with the error", func() {
+			_, err := ParseResults(`{"start": {}, "intervals": [], "end": {},
+				"error": "unable to connect to server: Connection refused"}`)
+			Expect(err).To(MatchError("unable to connect to server: Connection refused"))
+		})
+	})
+
+	Context("with the human readable output", func() {
+		It("should fail", func() {
+			_, err := ParseResults("Connecting to host iperf3-sample, port 5201\n")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
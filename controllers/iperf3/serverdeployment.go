@@ -39,10 +39,27 @@ func serverDeploymentName(cr *perfv1alpha1.Iperf3) string {
 	return cr.Name
 }
 
+// serverPort returns the port of the server instance serving the client
+// with the given index
+func serverPort(index int) int {
+	return Iperf3ServerPort + index
+}
+
+// serverPortName returns the name of the port of the server instance
+// serving the client with the given index
+func serverPortName(index int) string {
+	if index == 0 {
+		return "iperf-server"
+	}
+	return fmt.Sprintf("iperf-server-%d", index)
+}
+
 // NewServerDeployment create a iperf3 server deployment from the
-// provided Iperf3 Benchmark Definition.
+// provided Iperf3 Benchmark Definition. Each server pod runs a
+// separate iperf3 server container for each of the parallel clients,
+// listening on consecutive ports starting from Iperf3ServerPort.
 func NewServerDeployment(cr *perfv1alpha1.Iperf3) *appsv1.Deployment {
-	replicas := int32(1)
+	replicas := cr.Spec.ServerReplicas()
 
 	labels := map[string]string{
 		"kubestone.xridge.io/app":     "iperf3",
@@ -53,24 +70,11 @@ func NewServerDeployment(cr *perfv1alpha1.Iperf3) *appsv1.Deployment {
 		labels[k] = v
 	}
 
-	iperfCmdLineArgs := []string{
-		"--server",
-		"--port", strconv.Itoa(Iperf3ServerPort)}
-
-	protocol := corev1.Protocol(corev1.ProtocolTCP)
-	if cr.Spec.UDP {
-		iperfCmdLineArgs = append(iperfCmdLineArgs, "--udp")
-		protocol = corev1.Protocol(corev1.ProtocolUDP)
+	var containers []corev1.Container
+	for i := 0; i < int(cr.Spec.ClientParallelism()); i++ {
+		containers = append(containers, newServerContainer(cr, i))
 	}
 
-	iperfCmdLineArgs = append(iperfCmdLineArgs,
-		qsplit.ToStrings([]byte(cr.Spec.ServerConfiguration.CmdLineArgs))...)
-
-	// Iperf3 Server does not like if probe connections are made to the port,
-	// therefore we are checking if the port if open or not via shell script
-	// the solution does not assume to have netstat installed in the container
-	readinessAwkCmd := fmt.Sprintf("BEGIN{err=1}toupper($2)~/:%04X$/{err=0}END{exit err}", Iperf3ServerPort)
-
 	deployment := appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        serverDeploymentName(cr),
@@ -93,38 +97,7 @@ func NewServerDeployment(cr *perfv1alpha1.Iperf3) *appsv1.Deployment {
 							Name: cr.Spec.Image.PullSecret,
 						},
 					},
-					Containers: []corev1.Container{
-						{
-							Name:            "server",
-							Image:           cr.Spec.Image.Name,
-							ImagePullPolicy: corev1.PullPolicy(cr.Spec.Image.PullPolicy),
-							Command:         []string{"iperf3"},
-							Args:            iperfCmdLineArgs,
-							Ports: []corev1.ContainerPort{
-								{
-									Name:          "iperf-server",
-									ContainerPort: Iperf3ServerPort,
-									Protocol:      protocol,
-								},
-							},
-							ReadinessProbe: &corev1.Probe{
-								Handler: corev1.Handler{
-									Exec: &corev1.ExecAction{
-										Command: []string{
-											"awk",
-											readinessAwkCmd,
-											"/proc/1/net/tcp",
-											"/proc/1/net/tcp6",
-										},
-									},
-								},
-								InitialDelaySeconds: 5,
-								TimeoutSeconds:      2,
-								PeriodSeconds:       2,
-							},
-							Resources: cr.Spec.ServerConfiguration.Resources,
-						},
-					},
+					Containers:   containers,
 					Affinity:     cr.Spec.ServerConfiguration.PodScheduling.Affinity,
 					Tolerations:  cr.Spec.ServerConfiguration.PodScheduling.Tolerations,
 					NodeSelector: cr.Spec.ServerConfiguration.PodScheduling.NodeSelector,
@@ -137,3 +110,62 @@ func NewServerDeployment(cr *perfv1alpha1.Iperf3) *appsv1.Deployment {
 
 	return &deployment
 }
+
+// newServerContainer creates the iperf3 server container serving the
+// client with the given index
+func newServerContainer(cr *perfv1alpha1.Iperf3, index int) corev1.Container {
+	port := serverPort(index)
+	iperfCmdLineArgs := []string{
+		"--server",
+		"--port", strconv.Itoa(port)}
+
+	protocol := corev1.Protocol(corev1.ProtocolTCP)
+	if cr.Spec.UDP {
+		iperfCmdLineArgs = append(iperfCmdLineArgs, "--udp")
+		protocol = corev1.Protocol(corev1.ProtocolUDP)
+	}
+
+	iperfCmdLineArgs = append(iperfCmdLineArgs,
+		qsplit.ToStrings([]byte(cr.Spec.ServerConfiguration.CmdLineArgs))...)
+
+	// Iperf3 Server does not like if probe connections are made to the port,
+	// therefore we are checking if the port if open or not via shell script
+	// the solution does not assume to have netstat installed in the container
+	readinessAwkCmd := fmt.Sprintf("BEGIN{err=1}toupper($2)~/:%04X$/{err=0}END{exit err}", port)
+
+	name := "server"
+	if index > 0 {
+		name = fmt.Sprintf("server-%d", index)
+	}
+
+	return corev1.Container{
+		Name:            name,
+		Image:           cr.Spec.Image.Name,
+		ImagePullPolicy: corev1.PullPolicy(cr.Spec.Image.PullPolicy),
+		Command:         []string{"iperf3"},
+		Args:            iperfCmdLineArgs,
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          serverPortName(index),
+				ContainerPort: int32(port),
+				Protocol:      protocol,
+			},
+		},
+		ReadinessProbe: &corev1.Probe{
+			Handler: corev1.Handler{
+				Exec: &corev1.ExecAction{
+					Command: []string{
+						"awk",
+						readinessAwkCmd,
+						"/proc/1/net/tcp",
+						"/proc/1/net/tcp6",
+					},
+				},
+			},
+			InitialDelaySeconds: 5,
+			TimeoutSeconds:      2,
+			PeriodSeconds:       2,
+		},
+		Resources: cr.Spec.ServerConfiguration.Resources,
+	}
+}
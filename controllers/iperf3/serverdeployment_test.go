@@ -178,6 +178,37 @@ var _ = Describe("Server Deployment", func() {
 			})
 		})
 
+		Context("by default", func() {
+			It("should run a single server pod with a single server", func() {
+				Expect(*deployment.Spec.Replicas).To(Equal(int32(1)))
+				Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(1))
+				Expect(deployment.Spec.Template.Spec.Containers[0].Name).To(Equal("server"))
+			})
+		})
+
+		Context("with replicas and parallel clients specified", func() {
+			BeforeEach(func() {
+				replicas, parallelism := int32(2), int32(3)
+				cr.Spec.ServerConfiguration.Replicas = &replicas
+				cr.Spec.ClientConfiguration.Parallelism = &parallelism
+				deployment = NewServerDeployment(&cr)
+			})
+
+			It("should run the given number of server pods", func() {
+				Expect(*deployment.Spec.Replicas).To(Equal(int32(2)))
+			})
+			It("should run a server for each client on its own port", func() {
+				containers := deployment.Spec.Template.Spec.Containers
+				Expect(containers).To(HaveLen(3))
+				Expect(containers[2].Name).To(Equal("server-2"))
+				Expect(strings.Join(containers[2].Args, " ")).To(
+					ContainSubstring("--port " + strconv.Itoa(Iperf3ServerPort+2)))
+				Expect(containers[2].Args).To(ContainElement("--testing"))
+				Expect(containers[2].Ports[0].ContainerPort).To(Equal(int32(Iperf3ServerPort + 2)))
+				Expect(containers[1].Ports[0].Name).To(Equal("iperf-server-1"))
+			})
+		})
+
 		Context("with resources specified", func() {
 			It("should request the given CPU", func() {
 				Expect(deployment.Spec.Template.Spec.Containers[0].Resources.Requests.Cpu()).To(
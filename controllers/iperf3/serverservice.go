@@ -17,6 +17,8 @@ limitations under the License.
 package iperf3
 
 import (
+	"fmt"
+
 	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,7 +31,7 @@ func serverServiceName(cr *perfv1alpha1.Iperf3) string {
 }
 
 // NewServerService creates k8s headless service (which targets the server deployment)
-// from the Iperf3 Benchmark Definition
+// from the Iperf3 Benchmark Definition, with a port for each server instance
 func NewServerService(cr *perfv1alpha1.Iperf3) *corev1.Service {
 	labels := map[string]string{
 		"kubestone.xridge.io/app":     "iperf3",
@@ -39,6 +41,19 @@ func NewServerService(cr *perfv1alpha1.Iperf3) *corev1.Service {
 	if cr.Spec.UDP {
 		protocol = corev1.Protocol(corev1.ProtocolUDP)
 	}
+	var ports []corev1.ServicePort
+	for i := 0; i < int(cr.Spec.ClientParallelism()); i++ {
+		name := "iperf3"
+		if i > 0 {
+			name = fmt.Sprintf("iperf3-%d", i)
+		}
+		ports = append(ports, corev1.ServicePort{
+			Name:     name,
+			Protocol: protocol,
+			Port:     int32(serverPort(i)),
+		})
+	}
+
 	service := corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        serverServiceName(cr),
@@ -46,13 +61,7 @@ func NewServerService(cr *perfv1alpha1.Iperf3) *corev1.Service {
 			Annotations: cr.Spec.ClientConfiguration.PodConfigurationSpec.Annotations,
 		},
 		Spec: corev1.ServiceSpec{
-			Ports: []corev1.ServicePort{
-				{
-					Name:     "iperf3",
-					Protocol: protocol,
-					Port:     Iperf3ServerPort,
-				},
-			},
+			Ports:     ports,
 			Selector:  labels,
 			ClusterIP: "None", // Headless service!
 		},
@@ -55,6 +55,20 @@ var _ = Describe("Server Service", func() {
 			})
 		})
 
+		Context("with parallel clients specified", func() {
+			It("should expose the port of each server", func() {
+				parallelism := int32(3)
+				cr.Spec.ClientConfiguration.Parallelism = &parallelism
+				service := NewServerService(&cr)
+				deployment := NewServerDeployment(&cr)
+				Expect(service.Spec.Ports).To(HaveLen(3))
+				for i, port := range service.Spec.Ports {
+					Expect(port.Port).To(
+						Equal(deployment.Spec.Template.Spec.Containers[i].Ports[0].ContainerPort))
+				}
+			})
+		})
+
 		Context("crosschecked with server deployment", func() {
 			service := NewServerService(&cr)
 			deployment := NewServerDeployment(&cr)
@@ -0,0 +1,165 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iperf3matrix
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/lifecycle"
+)
+
+// Reconciler provides fields from manager to reconciler
+type Reconciler struct {
+	K8S k8s.Access
+	Log logr.Logger
+}
+
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=iperf3s,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=iperf3matrixes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=iperf3matrixes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=iperf3matrixes/finalizers,verbs=update
+
+// Reconcile selects the node pairs of the matrix, creates an iperf3
+// benchmark for each pair one after the other, and collects their
+// throughput into the matrix in the status. The pairs are measured one
+// after the other, so the links of the nodes are not contended.
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+
+	var cr perfv1alpha1.Iperf3Matrix
+	if err := r.K8S.Client.Get(ctx, req.NamespacedName, &cr); err != nil {
+		return ctrl.Result{}, k8s.IgnoreNotFound(err)
+	}
+
+	// Run to one completion
+	if cr.Status.IsFinished() {
+		return ctrl.Result{}, nil
+	}
+
+	if valid, err := lifecycle.Validate(ctx, &r.K8S, &cr, func() (bool, error) {
+		return IsCrValid(&cr)
+	}); !valid || err != nil {
+		return ctrl.Result{}, err
+	}
+
+	original := cr.DeepCopy()
+
+	// The pairs are selected once, the nodes added later are not measured
+	if len(cr.Status.Pairs) == 0 {
+		if message, err := r.selectPairs(ctx, &cr); err != nil {
+			return ctrl.Result{}, err
+		} else if message != "" {
+			return ctrl.Result{}, lifecycle.FailBenchmark(ctx, &r.K8S, &cr, k8s.NoMatchingNodes, message)
+		}
+	}
+
+	statuses := make([]*perfv1alpha1.ChildBenchmarkStatus, len(cr.Status.Pairs))
+	for i := range cr.Status.Pairs {
+		statuses[i] = &cr.Status.Pairs[i].ChildBenchmarkStatus
+	}
+	children := lifecycle.Children{
+		Statuses:     statuses,
+		Noun:         "pairs",
+		FailedReason: k8s.PairFailed,
+		Describe: func(i int) string {
+			return fmt.Sprintf("pair %s->%s", cr.Status.Pairs[i].Server, cr.Status.Pairs[i].Client)
+		},
+		Prototype: func(i int) perfv1alpha1.Benchmark {
+			return &perfv1alpha1.Iperf3{}
+		},
+		New: func(ctx context.Context, i int) (perfv1alpha1.Benchmark, error) {
+			return NewBenchmark(&cr, i, cr.Status.Pairs[i].Iperf3NodePair), nil
+		},
+		Sync: func(i int, benchmark perfv1alpha1.Benchmark) {
+			syncPairStatus(&cr.Status.Pairs[i], benchmark)
+		},
+		Summarize: func(finished bool) {
+			if finished {
+				r.summarize(&cr)
+			}
+			cr.Status.Matrix = Matrix(&cr)
+		},
+	}
+	return ctrl.Result{}, children.Reconcile(ctx, &r.K8S, &cr, original)
+}
+
+// selectPairs fills the pairs and the nodes of the matrix. The given pairs
+// are checked against the schedulable nodes matching the node selector,
+// and the pairs with other nodes are failed. It returns the reason if the
+// matrix cannot be measured.
+func (r *Reconciler) selectPairs(ctx context.Context, cr *perfv1alpha1.Iperf3Matrix) (string, error) {
+	nodes, err := r.K8S.GetSchedulableNodes(ctx, cr.Spec.NodeSelector)
+	if err != nil {
+		return "", err
+	}
+	if len(cr.Spec.Pairs) == 0 && len(nodes) < 2 {
+		return "fewer than two schedulable nodes match the node selector", nil
+	}
+
+	pairs := Pairs(cr, nodes)
+	if len(pairs) > MaxPairs {
+		return fmt.Sprintf("the %d nodes matching the node selector have %d pairs, the limit is %d",
+			len(nodes), len(pairs), MaxPairs), nil
+	}
+
+	schedulable := map[string]bool{}
+	for _, node := range nodes {
+		schedulable[node] = true
+	}
+	cr.Status.Nodes = PairNodes(pairs)
+	cr.Status.Pairs = make([]perfv1alpha1.Iperf3MatrixPair, len(pairs))
+	for i, pair := range pairs {
+		cr.Status.Pairs[i] = perfv1alpha1.Iperf3MatrixPair{Iperf3NodePair: pair}
+		for _, node := range []string{pair.Server, pair.Client} {
+			if !schedulable[node] {
+				cr.Status.Pairs[i].Phase = perfv1alpha1.BenchmarkFailed
+				cr.Status.Pairs[i].Reason = k8s.NoMatchingNodes
+				cr.Status.Pairs[i].Message = fmt.Sprintf(
+					"node %s is not schedulable or does not match the node selector", node)
+			}
+		}
+	}
+	return "", nil
+}
+
+// summarize summarizes the throughput of the finished pairs. The slow
+// pairs do not fail the matrix, they are reported with a Warning event.
+func (r *Reconciler) summarize(cr *perfv1alpha1.Iperf3Matrix) {
+	var slow []string
+	cr.Status.Results, slow = Summarize(cr)
+	if len(slow) > 0 {
+		_ = r.K8S.RecordEventf(cr, corev1.EventTypeWarning, k8s.Outliers,
+			"The throughput of pairs %s falls short of the median of the pairs", strings.Join(slow, ", "))
+	}
+}
+
+// SetupWithManager registers the Reconciler with the provided manager.
+// The matrix is reconciled whenever the benchmark of any of its pairs changes.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&perfv1alpha1.Iperf3Matrix{}).
+		Owns(&perfv1alpha1.Iperf3{}).
+		Complete(lifecycle.NewReconciler(r, &r.K8S, &perfv1alpha1.Iperf3Matrix{}))
+}
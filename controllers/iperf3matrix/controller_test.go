@@ -0,0 +1,242 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iperf3matrix
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+)
+
+var _ = Describe("iperf3 matrix reconciler", func() {
+	var ctx context.Context
+	var cr *perfv1alpha1.Iperf3Matrix
+	var nodes []runtime.Object
+	var name types.NamespacedName
+	var reconciler *Reconciler
+	var recorder *record.FakeRecorder
+
+	newNode := func(name, pool string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"pool": pool}},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			}},
+		}
+	}
+
+	setup := func() {
+		scheme := runtime.NewScheme()
+		_ = k8sscheme.AddToScheme(scheme)
+		_ = perfv1alpha1.AddToScheme(scheme)
+		recorder = record.NewFakeRecorder(100)
+		reconciler = &Reconciler{K8S: k8s.Access{
+			Client:        fake.NewFakeClientWithScheme(scheme, append(nodes, cr.DeepCopy())...),
+			Scheme:        scheme,
+			EventRecorder: recorder,
+		}}
+	}
+
+	reconcile := func() {
+		_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	stored := func() *perfv1alpha1.Iperf3Matrix {
+		var matrix perfv1alpha1.Iperf3Matrix
+		Expect(reconciler.K8S.Client.Get(ctx, name, &matrix)).To(Succeed())
+		return &matrix
+	}
+
+	benchmarkName := func(index int) types.NamespacedName {
+		return types.NamespacedName{Namespace: name.Namespace, Name: fmt.Sprintf("%s-%d", name.Name, index)}
+	}
+
+	benchmarkExists := func(index int) bool {
+		var iperf3 perfv1alpha1.Iperf3
+		err := reconciler.K8S.Client.Get(ctx, benchmarkName(index), &iperf3)
+		Expect(errors.IsNotFound(err) || err == nil).To(BeTrue())
+		return err == nil
+	}
+
+	finishPair := func(index int, phase perfv1alpha1.BenchmarkPhase, bps float64) {
+		var iperf3 perfv1alpha1.Iperf3
+		Expect(reconciler.K8S.Client.Get(ctx, benchmarkName(index), &iperf3)).To(Succeed())
+		iperf3.Status.SetPhase(phase, "", "")
+		iperf3.Status.Results = &perfv1alpha1.BenchmarkResults{}
+		iperf3.Status.Results.AddMetric("receiver.bps", bps, "bits/s")
+		Expect(reconciler.K8S.Client.Status().Update(ctx, &iperf3)).To(Succeed())
+	}
+
+	events := func() []string {
+		var received []string
+		for len(recorder.Events) > 0 {
+			received = append(received, <-recorder.Events)
+		}
+		return received
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		name = types.NamespacedName{Namespace: "matrix", Name: "network"}
+		nodes = []runtime.Object{
+			newNode("node-c", "storage"),
+			newNode("node-a", "storage"),
+			newNode("node-b", "storage"),
+			newNode("node-x", "compute"),
+		}
+		cr = &perfv1alpha1.Iperf3Matrix{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name.Name,
+				Namespace: name.Namespace,
+				SelfLink:  "/apis/perf.kubestone.xridge.io/v1alpha1/namespaces/matrix/iperf3matrixes/network",
+			},
+			Spec: perfv1alpha1.Iperf3MatrixSpec{
+				Template: perfv1alpha1.Iperf3Spec{
+					Image: perfv1alpha1.ImageSpec{Name: "xridge/iperf3:3.7.0"},
+				},
+				NodeSelector: map[string]string{"pool": "storage"},
+			},
+		}
+	})
+
+	Context("with all the pairs", func() {
+		BeforeEach(setup)
+
+		It("should measure the pairs one after the other", func() {
+			reconcile()
+			matrix := stored()
+			Expect(matrix.Status.Phase).To(Equal(perfv1alpha1.BenchmarkRunning))
+			Expect(matrix.Status.Nodes).To(Equal([]string{"node-a", "node-b", "node-c"}))
+			Expect(matrix.Status.Pairs).To(HaveLen(6))
+			Expect(benchmarkExists(0)).To(BeTrue())
+			Expect(benchmarkExists(1)).To(BeFalse())
+
+			var iperf3 perfv1alpha1.Iperf3
+			Expect(reconciler.K8S.Client.Get(ctx, benchmarkName(0), &iperf3)).To(Succeed())
+			Expect(iperf3.Spec.ServerConfiguration.PodScheduling.NodeName).To(Equal("node-a"))
+			Expect(iperf3.Spec.ClientConfiguration.PodScheduling.NodeName).To(Equal("node-b"))
+
+			finishPair(0, perfv1alpha1.BenchmarkSucceeded, 9e9)
+			reconcile()
+			Expect(benchmarkExists(1)).To(BeTrue())
+			Expect(benchmarkExists(2)).To(BeFalse())
+			matrix = stored()
+			Expect(matrix.Status.Matrix[0].Throughput).To(Equal([]string{"", "9000000000", ""}))
+
+			for i, bps := range []float64{9.2e9, 9.1e9, 3e9, 9.3e9, 9e9} {
+				finishPair(i+1, perfv1alpha1.BenchmarkSucceeded, bps)
+				reconcile()
+			}
+
+			matrix = stored()
+			Expect(matrix.Status.Phase).To(Equal(perfv1alpha1.BenchmarkSucceeded))
+			Expect(matrix.Status.Matrix).To(HaveLen(3))
+			Expect(matrix.Status.Matrix[1]).To(Equal(perfv1alpha1.Iperf3MatrixRow{
+				Server:     "node-b",
+				Throughput: []string{"9100000000", "", "3000000000"},
+			}))
+			Expect(matrix.Status.Pairs[3].Slow).To(BeTrue())
+			Expect(matrix.Status.Results.Statistics[0].Min).To(Equal("3000000000"))
+			Expect(matrix.Status.Children).To(HaveLen(6))
+			Expect(events()).To(ContainElement(ContainSubstring("pairs node-b->node-c falls short")))
+		})
+
+	})
+
+	Context("with assertions", func() {
+		BeforeEach(func() {
+			cr.Spec.Assertions = []perfv1alpha1.Assertion{
+				{Metric: "receiver.bps", Operator: ">=", Value: "9e9"},
+			}
+			setup()
+		})
+
+		It("should evaluate the assertions on the mean of the pairs", func() {
+			reconcile()
+			for i, bps := range []float64{9e9, 9.2e9, 9.1e9, 3e9, 9.3e9, 9e9} {
+				finishPair(i, perfv1alpha1.BenchmarkSucceeded, bps)
+				reconcile()
+			}
+
+			matrix := stored()
+			Expect(matrix.Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+			Expect(matrix.Status.Reason).To(Equal(k8s.AssertionFailed))
+			Expect(matrix.Status.Assertions).To(HaveLen(1))
+			Expect(matrix.Status.Assertions[0].Passed).To(BeFalse())
+		})
+	})
+
+	Context("with the given pairs", func() {
+		BeforeEach(func() {
+			cr.Spec.Pairs = []perfv1alpha1.Iperf3NodePair{
+				{Server: "node-c", Client: "node-a"},
+				{Server: "node-a", Client: "node-x"},
+			}
+			setup()
+		})
+
+		It("should only measure the given pairs of the matching nodes", func() {
+			reconcile()
+			matrix := stored()
+			Expect(matrix.Status.Nodes).To(Equal([]string{"node-a", "node-c", "node-x"}))
+			Expect(matrix.Status.Pairs[1].Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+			Expect(matrix.Status.Pairs[1].Reason).To(Equal(k8s.NoMatchingNodes))
+
+			finishPair(0, perfv1alpha1.BenchmarkSucceeded, 9e9)
+			reconcile()
+			Expect(benchmarkExists(1)).To(BeFalse())
+
+			matrix = stored()
+			Expect(matrix.Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+			Expect(matrix.Status.Matrix).To(Equal([]perfv1alpha1.Iperf3MatrixRow{
+				{Server: "node-a", Throughput: []string{"", "", ""}},
+				{Server: "node-c", Throughput: []string{"9000000000", "", ""}},
+			}))
+		})
+	})
+
+	Context("without enough matching nodes", func() {
+		BeforeEach(func() {
+			cr.Spec.NodeSelector = map[string]string{"pool": "compute"}
+			setup()
+		})
+
+		It("should fail the matrix", func() {
+			reconcile()
+
+			matrix := stored()
+			Expect(matrix.Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+			Expect(matrix.Status.Reason).To(Equal(k8s.NoMatchingNodes))
+		})
+	})
+})
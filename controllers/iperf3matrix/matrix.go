@@ -0,0 +1,239 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iperf3matrix
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/stats"
+)
+
+const (
+	// MatrixLabel is set on the benchmarks of the pairs to the name of the matrix
+	MatrixLabel = "kubestone.xridge.io/iperf3-matrix"
+
+	// MaxPairs limits the number of measured node pairs, as the results of
+	// every pair are kept in the status of the matrix
+	MaxPairs = 256
+
+	// DefaultSlowTolerancePercent is the largest shortfall of the throughput
+	// of a pair from the median of all pairs without explicit tolerance
+	DefaultSlowTolerancePercent = 20
+)
+
+// IsCrValid validates the node selector and the node pairs of the matrix
+func IsCrValid(cr *perfv1alpha1.Iperf3Matrix) (valid bool, err error) {
+	if cr.Spec.Runs() > 1 {
+		return false, fmt.Errorf("matrices cannot be repeated, the repetitions can be set in the template")
+	}
+	if err := k8s.ValidateNodeSelector(cr.Spec.NodeSelector); err != nil {
+		return false, err
+	}
+	if len(cr.Spec.Pairs) > MaxPairs {
+		return false, fmt.Errorf("the matrix has more than %d pairs", MaxPairs)
+	}
+
+	pairs := map[perfv1alpha1.Iperf3NodePair]bool{}
+	for _, pair := range cr.Spec.Pairs {
+		if pair.Server == "" || pair.Client == "" {
+			return false, fmt.Errorf("the server and the client of the pairs must be given")
+		}
+		if pair.Server == pair.Client {
+			return false, fmt.Errorf("the server and the client of pair %s are the same", pair.Server)
+		}
+		if pairs[pair] {
+			return false, fmt.Errorf("pair %s-%s is defined more than once", pair.Server, pair.Client)
+		}
+		pairs[pair] = true
+	}
+	return true, nil
+}
+
+// Pairs returns the node pairs to measure: the given pairs of the matrix,
+// or all the ordered pairs of the nodes. The pairs are ordered by server
+// node, so each server is measured by all of its clients in turn.
+func Pairs(cr *perfv1alpha1.Iperf3Matrix, nodes []string) []perfv1alpha1.Iperf3NodePair {
+	if len(cr.Spec.Pairs) > 0 {
+		return cr.Spec.Pairs
+	}
+
+	var pairs []perfv1alpha1.Iperf3NodePair
+	for _, server := range nodes {
+		for _, client := range nodes {
+			if server != client {
+				pairs = append(pairs, perfv1alpha1.Iperf3NodePair{Server: server, Client: client})
+			}
+		}
+	}
+	return pairs
+}
+
+// PairNodes returns the nodes of the given pairs ordered by name
+func PairNodes(pairs []perfv1alpha1.Iperf3NodePair) []string {
+	seen := map[string]bool{}
+	var nodes []string
+	for _, pair := range pairs {
+		for _, node := range []string{pair.Server, pair.Client} {
+			if !seen[node] {
+				seen[node] = true
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// BenchmarkName returns the name of the benchmark created for the pair
+// with the given index
+func BenchmarkName(cr *perfv1alpha1.Iperf3Matrix, index int) string {
+	return fmt.Sprintf("%s-%d", cr.Name, index)
+}
+
+// NewBenchmark creates the iperf3 benchmark of the pair with the given
+// index from the template of the matrix. The server and the client are
+// pinned to the nodes of the pair.
+func NewBenchmark(cr *perfv1alpha1.Iperf3Matrix, index int, pair perfv1alpha1.Iperf3NodePair) *perfv1alpha1.Iperf3 {
+	spec := cr.Spec.Template.DeepCopy()
+	spec.ServerConfiguration.PodScheduling.NodeName = pair.Server
+	spec.ClientConfiguration.PodScheduling.NodeName = pair.Client
+	spec.JSON = true
+
+	// The server of the pair must be gone before the next pair is
+	// measured, as host network servers would compete for the same port
+	if spec.CleanupPolicy == "" {
+		spec.CleanupPolicy = perfv1alpha1.CleanupDeleteAlways
+	}
+
+	return &perfv1alpha1.Iperf3{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      BenchmarkName(cr, index),
+			Namespace: cr.Namespace,
+			Labels:    map[string]string{MatrixLabel: cr.Name},
+		},
+		Spec: *spec,
+	}
+}
+
+// syncPairStatus copies the metrics and the throughput of the benchmark
+// of the pair into the pair status
+func syncPairStatus(pair *perfv1alpha1.Iperf3MatrixPair, benchmark perfv1alpha1.Benchmark) {
+	status := benchmark.GetBenchmarkStatus()
+	pair.Metrics = nil
+	pair.Throughput = ""
+	if status.Results == nil {
+		return
+	}
+	pair.Metrics = status.Results.Metrics
+	if metric := status.Results.GetMetric("receiver.bps"); metric != nil {
+		pair.Throughput = metric.Value
+	} else if metric := status.Results.GetMetric("sender.bps"); metric != nil {
+		pair.Throughput = metric.Value
+	}
+}
+
+// Matrix returns the throughput matrix of the succeeded pairs, with a row
+// for each server node and a column for each node of the matrix
+func Matrix(cr *perfv1alpha1.Iperf3Matrix) []perfv1alpha1.Iperf3MatrixRow {
+	servers := map[string]bool{}
+	for _, pair := range cr.Status.Pairs {
+		servers[pair.Server] = true
+	}
+
+	var matrix []perfv1alpha1.Iperf3MatrixRow
+	rows := map[string]int{}
+	columns := map[string]int{}
+	for i, node := range cr.Status.Nodes {
+		columns[node] = i
+		if servers[node] {
+			rows[node] = len(matrix)
+			matrix = append(matrix, perfv1alpha1.Iperf3MatrixRow{
+				Server:     node,
+				Throughput: make([]string, len(cr.Status.Nodes)),
+			})
+		}
+	}
+
+	for _, pair := range cr.Status.Pairs {
+		row, hasRow := rows[pair.Server]
+		column, hasColumn := columns[pair.Client]
+		if pair.Phase == perfv1alpha1.BenchmarkSucceeded && hasRow && hasColumn {
+			matrix[row].Throughput[column] = pair.Throughput
+		}
+	}
+	return matrix
+}
+
+// Summarize marks the slow pairs, and returns the results of the matrix:
+// the mean of the metrics over the succeeded pairs, with their statistics
+// describing the spread between the pairs. A pair is slow if its
+// throughput falls short of the median throughput of the pairs beyond the
+// tolerance. At least three measured pairs are needed to tell which of
+// them is slow. It returns the slow pairs in SERVER->CLIENT format.
+func Summarize(cr *perfv1alpha1.Iperf3Matrix) (*perfv1alpha1.BenchmarkResults, []string) {
+	tolerance := float64(DefaultSlowTolerancePercent)
+	if cr.Spec.SlowTolerancePercent != nil {
+		tolerance = float64(*cr.Spec.SlowTolerancePercent)
+	}
+
+	// The metrics of each pair are handled as a run of a repeated benchmark
+	var iterations []perfv1alpha1.BenchmarkIteration
+	var throughputs []float64
+	for i := range cr.Status.Pairs {
+		pair := &cr.Status.Pairs[i]
+		pair.Slow = false
+		if pair.Phase != perfv1alpha1.BenchmarkSucceeded {
+			continue
+		}
+		iterations = append(iterations, perfv1alpha1.BenchmarkIteration{
+			Iteration: int32(len(iterations) + 1),
+			Metrics:   pair.Metrics,
+		})
+		if throughput, err := strconv.ParseFloat(pair.Throughput, 64); err == nil {
+			throughputs = append(throughputs, throughput)
+		}
+	}
+	if len(iterations) == 0 {
+		return nil, nil
+	}
+	means, statistics := k8s.Aggregate(iterations)
+	results := &perfv1alpha1.BenchmarkResults{Metrics: means, Statistics: statistics}
+	if len(throughputs) < 3 {
+		return results, nil
+	}
+
+	var slow []string
+	median := stats.Summarize(throughputs).Median
+	for i := range cr.Status.Pairs {
+		pair := &cr.Status.Pairs[i]
+		throughput, err := strconv.ParseFloat(pair.Throughput, 64)
+		if err != nil || pair.Phase != perfv1alpha1.BenchmarkSucceeded || median <= 0 {
+			continue
+		}
+		if (median-throughput)/median*100 > tolerance {
+			pair.Slow = true
+			slow = append(slow, pair.Server+"->"+pair.Client)
+		}
+	}
+	return results, slow
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iperf3matrix
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("iperf3 matrix", func() {
+	var cr *perfv1alpha1.Iperf3Matrix
+
+	BeforeEach(func() {
+		cr = &perfv1alpha1.Iperf3Matrix{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "kubestone"},
+			Spec: perfv1alpha1.Iperf3MatrixSpec{
+				Template: perfv1alpha1.Iperf3Spec{
+					Image: perfv1alpha1.ImageSpec{Name: "xridge/iperf3:3.7.0"},
+					ClientConfiguration: perfv1alpha1.Iperf3ConfigurationSpec{
+						CmdLineArgs: "--time 10",
+					},
+				},
+			},
+		}
+	})
+
+	Context("validating the CR", func() {
+		It("should accept a matrix without pairs", func() {
+			Expect(IsCrValid(cr)).To(BeTrue())
+		})
+
+		It("should reject pairs with the same server and client", func() {
+			cr.Spec.Pairs = []perfv1alpha1.Iperf3NodePair{{Server: "node-a", Client: "node-a"}}
+			valid, err := IsCrValid(cr)
+			Expect(valid).To(BeFalse())
+			Expect(err).To(MatchError(ContainSubstring("are the same")))
+		})
+
+		It("should reject duplicate pairs", func() {
+			cr.Spec.Pairs = []perfv1alpha1.Iperf3NodePair{
+				{Server: "node-a", Client: "node-b"},
+				{Server: "node-a", Client: "node-b"},
+			}
+			valid, err := IsCrValid(cr)
+			Expect(valid).To(BeFalse())
+			Expect(err).To(MatchError(ContainSubstring("more than once")))
+		})
+
+		It("should reject incomplete pairs", func() {
+			cr.Spec.Pairs = []perfv1alpha1.Iperf3NodePair{{Server: "node-a"}}
+			valid, err := IsCrValid(cr)
+			Expect(valid).To(BeFalse())
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject repeated matrices", func() {
+			runs := int32(2)
+			cr.Spec.Repetitions = &runs
+			valid, err := IsCrValid(cr)
+			Expect(valid).To(BeFalse())
+			Expect(err).To(MatchError(ContainSubstring("cannot be repeated")))
+		})
+	})
+
+	Context("selecting the pairs", func() {
+		It("should pair every node with every other node", func() {
+			pairs := Pairs(cr, []string{"node-a", "node-b", "node-c"})
+			Expect(pairs).To(HaveLen(6))
+			Expect(pairs[0]).To(Equal(perfv1alpha1.Iperf3NodePair{Server: "node-a", Client: "node-b"}))
+			Expect(pairs[1]).To(Equal(perfv1alpha1.Iperf3NodePair{Server: "node-a", Client: "node-c"}))
+			Expect(pairs[2]).To(Equal(perfv1alpha1.Iperf3NodePair{Server: "node-b", Client: "node-a"}))
+		})
+
+		It("should use the given pairs", func() {
+			cr.Spec.Pairs = []perfv1alpha1.Iperf3NodePair{{Server: "node-c", Client: "node-a"}}
+			Expect(Pairs(cr, []string{"node-a", "node-b", "node-c"})).To(Equal(cr.Spec.Pairs))
+			Expect(PairNodes(cr.Spec.Pairs)).To(Equal([]string{"node-a", "node-c"}))
+		})
+	})
+
+	Context("creating the benchmark of a pair", func() {
+		It("should pin the server and the client to the nodes of the pair", func() {
+			iperf3 := NewBenchmark(cr, 2, perfv1alpha1.Iperf3NodePair{Server: "node-a", Client: "node-b"})
+			Expect(iperf3.Name).To(Equal("network-2"))
+			Expect(iperf3.Namespace).To(Equal("kubestone"))
+			Expect(iperf3.Labels).To(HaveKeyWithValue(MatrixLabel, "network"))
+			Expect(iperf3.Spec.ServerConfiguration.PodScheduling.NodeName).To(Equal("node-a"))
+			Expect(iperf3.Spec.ClientConfiguration.PodScheduling.NodeName).To(Equal("node-b"))
+			Expect(iperf3.Spec.ClientConfiguration.CmdLineArgs).To(Equal("--time 10"))
+			Expect(iperf3.Spec.JSON).To(BeTrue())
+			Expect(iperf3.Spec.CleanupPolicy).To(Equal(perfv1alpha1.CleanupDeleteAlways))
+			Expect(cr.Spec.Template.ServerConfiguration.PodScheduling.NodeName).To(BeEmpty())
+		})
+
+		It("should keep the cleanup policy of the template", func() {
+			cr.Spec.Template.CleanupPolicy = perfv1alpha1.CleanupRetain
+			iperf3 := NewBenchmark(cr, 0, perfv1alpha1.Iperf3NodePair{Server: "node-a", Client: "node-b"})
+			Expect(iperf3.Spec.CleanupPolicy).To(Equal(perfv1alpha1.CleanupRetain))
+		})
+	})
+
+	Context("summarizing the pairs", func() {
+		pair := func(server, client, throughput string) perfv1alpha1.Iperf3MatrixPair {
+			return perfv1alpha1.Iperf3MatrixPair{
+				Iperf3NodePair:       perfv1alpha1.Iperf3NodePair{Server: server, Client: client},
+				ChildBenchmarkStatus: perfv1alpha1.ChildBenchmarkStatus{Phase: perfv1alpha1.BenchmarkSucceeded},
+				Throughput:           throughput,
+				Metrics: []perfv1alpha1.BenchmarkMetric{
+					{Name: "receiver.bps", Value: throughput, Unit: "bits/s"},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			cr.Status.Nodes = []string{"node-a", "node-b", "node-c"}
+			cr.Status.Pairs = []perfv1alpha1.Iperf3MatrixPair{
+				pair("node-a", "node-b", "9000000000"),
+				pair("node-a", "node-c", "9400000000"),
+				pair("node-b", "node-a", "9200000000"),
+				pair("node-b", "node-c", "4000000000"),
+				pair("node-c", "node-a", "9100000000"),
+			}
+			cr.Status.Pairs = append(cr.Status.Pairs, perfv1alpha1.Iperf3MatrixPair{
+				Iperf3NodePair:       perfv1alpha1.Iperf3NodePair{Server: "node-c", Client: "node-b"},
+				ChildBenchmarkStatus: perfv1alpha1.ChildBenchmarkStatus{Phase: perfv1alpha1.BenchmarkFailed},
+			})
+		})
+
+		It("should build the throughput matrix of the succeeded pairs", func() {
+			matrix := Matrix(cr)
+			Expect(matrix).To(HaveLen(3))
+			Expect(matrix[0]).To(Equal(perfv1alpha1.Iperf3MatrixRow{
+				Server:     "node-a",
+				Throughput: []string{"", "9000000000", "9400000000"},
+			}))
+			Expect(matrix[2].Throughput).To(Equal([]string{"9100000000", "", ""}))
+		})
+
+		It("should mark the pairs falling short of the median", func() {
+			results, slow := Summarize(cr)
+			Expect(slow).To(Equal([]string{"node-b->node-c"}))
+			Expect(cr.Status.Pairs[3].Slow).To(BeTrue())
+			Expect(cr.Status.Pairs[0].Slow).To(BeFalse())
+			Expect(results.Statistics).To(HaveLen(1))
+			Expect(results.Statistics[0].Min).To(Equal("4000000000"))
+		})
+
+		It("should not mark slow pairs within the tolerance", func() {
+			tolerance := int32(60)
+			cr.Spec.SlowTolerancePercent = &tolerance
+			_, slow := Summarize(cr)
+			Expect(slow).To(BeEmpty())
+		})
+	})
+})
@@ -55,6 +55,7 @@ func NewConsumerJob(cr *perfv1alpha1.KafkaBench, ts *perfv1alpha1.KafkaTestSpec)
 
 	job.Spec.Template.Spec.Containers[0].Command = []string{"/bin/sh"}
 	job.Spec.Template.Spec.Containers[0].Args = ConsumerJobArgs(cr, ts)
+	AddSASLConfig(job, cr.Spec.SASL, "--consumer.config")
 
 	// Add pod affinity
 	AddPodAffinity(job, jobName)
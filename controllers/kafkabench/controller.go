@@ -21,6 +21,8 @@ import (
 	"github.com/go-logr/logr"
 	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
 	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/lifecycle"
+	"github.com/xridge/kubestone/pkg/results"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -49,20 +51,14 @@ func (r *KafkaBenchReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 	}
 
 	// If its already completed then return
-	if cr.Status.Completed {
+	if cr.Status.IsFinished() {
 		return ctrl.Result{}, nil
 	}
 
-	// Set status to running
-	cr.Status.Running = true
-	if err := r.K8S.Client.Status().Update(ctx, &cr); err != nil {
-		return ctrl.Result{}, err
-	}
-
 	// Create new jobs for each test
 	var jobs []*batchv1.Job
 	for _, testSpec := range cr.Spec.Tests {
-		result, err, kjobs := r.ProcessKafkaTest(cr, testSpec, ctx)
+		result, err, kjobs := r.ProcessKafkaTest(&cr, testSpec, ctx)
 		if err != nil {
 			return result, err
 		}
@@ -70,12 +66,20 @@ func (r *KafkaBenchReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 		jobs = append(jobs, kjobs...)
 	}
 
-	// Check all the job statuses
+	var jobNames []types.NamespacedName
 	for _, job := range jobs {
-		jobFinished, err := r.K8S.IsJobFinished(types.NamespacedName{
+		jobNames = append(jobNames, types.NamespacedName{
 			Namespace: cr.Namespace,
 			Name:      job.Name,
 		})
+	}
+	if err := r.K8S.SyncRunningPhase(ctx, &cr, jobNames...); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Check all the job statuses
+	for _, jobName := range jobNames {
+		jobFinished, err := r.K8S.IsJobFinished(jobName)
 
 		if err != nil {
 			return ctrl.Result{}, err
@@ -83,35 +87,54 @@ func (r *KafkaBenchReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 
 		if !jobFinished {
 			// Wait for the job to be completed
-			return ctrl.Result{Requeue: true}, nil
+			return k8s.WaitForJobs(&cr), nil
 		}
 
 	}
 
+	// The first failed job determines the failure of the benchmark
+	var jobFailure *k8s.JobFailure
+	for _, jobName := range jobNames {
+		failure, err := r.K8S.GetJobFailure(jobName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if failure != nil {
+			jobFailure = failure
+			break
+		}
+	}
+
 	// The cr could have been modified since the last time we got it
 	if err := r.K8S.Client.Get(ctx, req.NamespacedName, &cr); err != nil {
 		return ctrl.Result{}, k8s.IgnoreNotFound(err)
 	}
-	cr.Status.Running = false
-	cr.Status.Completed = true
-	if err := r.K8S.Client.Status().Update(ctx, &cr); err != nil {
+	// The results are parsed from the logs of the successful benchmark
+	if jobFailure == nil {
+		if err := results.Collect(&r.K8S, &cr, jobNames...); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.K8S.FinishBenchmark(ctx, &cr, jobFailure); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	// Keep a record of the run, which outlives the benchmark
+	return ctrl.Result{}, results.Record(ctx, &r.K8S, &cr, jobNames...)
 }
 
-func (r *KafkaBenchReconciler) ProcessKafkaTest(cr perfv1alpha1.KafkaBench, testSpec perfv1alpha1.KafkaTestSpec, ctx context.Context) (ctrl.Result, error, []*batchv1.Job) {
+func (r *KafkaBenchReconciler) ProcessKafkaTest(cr *perfv1alpha1.KafkaBench, testSpec perfv1alpha1.KafkaTestSpec, ctx context.Context) (ctrl.Result, error, []*batchv1.Job) {
 
 	// Create producer job
-	producerJob := NewProducerJob(&cr, &testSpec)
-	if err := r.K8S.CreateWithReference(ctx, producerJob, &cr); err != nil {
+	producerJob := NewProducerJob(cr, &testSpec)
+	if err := r.K8S.CreateWithReference(ctx, producerJob, cr); err != nil {
 		return ctrl.Result{}, err, nil
 	}
 
 	// Create consumer job
-	consumerJob := NewConsumerJob(&cr, &testSpec)
-	if err := r.K8S.CreateWithReference(ctx, consumerJob, &cr); err != nil {
+	consumerJob := NewConsumerJob(cr, &testSpec)
+	if err := r.K8S.CreateWithReference(ctx, consumerJob, cr); err != nil {
 		return ctrl.Result{}, err, nil
 	}
 
@@ -121,7 +144,8 @@ func (r *KafkaBenchReconciler) ProcessKafkaTest(cr perfv1alpha1.KafkaBench, test
 func (r *KafkaBenchReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&perfv1alpha1.KafkaBench{}).
-		Complete(r)
+		Owns(&batchv1.Job{}).
+		Complete(lifecycle.NewReconciler(r, &r.K8S, &perfv1alpha1.KafkaBench{}))
 }
 
 func AddPodAffinity(job *batchv1.Job, jobName string) {
@@ -49,6 +49,7 @@ func NewProducerJob(cr *perfv1alpha1.KafkaBench, ts *perfv1alpha1.KafkaTestSpec)
 
 	job.Spec.Template.Spec.Containers[0].Command = []string{"/bin/sh"}
 	job.Spec.Template.Spec.Containers[0].Args = ProducerJobCmd(cr, ts)
+	AddSASLConfig(job, cr.Spec.SASL, "--producer.config")
 
 	// Add pod affinity
 	AddPodAffinity(job, jobName)
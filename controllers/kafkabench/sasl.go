@@ -0,0 +1,106 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkabench
+
+import (
+	"fmt"
+	"strings"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+const (
+	saslUsernameEnv  = "KAFKA_SASL_USERNAME"
+	saslPasswordEnv  = "KAFKA_SASL_PASSWORD"
+	clientConfigPath = "/tmp/kafka-client.properties"
+
+	// escapeFunction defines the shell function escaping the credentials for
+	// the double quoted strings of the JAAS configuration. The backslashes
+	// and the double quotes are escaped for JAAS, then the backslashes are
+	// escaped once more, as the client configuration is a properties file.
+	escapeFunction = `escape() { printf '%s' "$1" | sed -e 's/[\\"]/\\&/g' -e 's/\\/\\\\/g'; }`
+
+	// DefaultSASLMechanism is used when the SASL mechanism is not specified in the CR
+	DefaultSASLMechanism = "PLAIN"
+	// DefaultSecurityProtocol is used when the security protocol is not specified in the CR
+	DefaultSecurityProtocol = "SASL_PLAINTEXT"
+)
+
+// saslLoginModules maps the supported SASL mechanisms to their JAAS login module
+var saslLoginModules = map[string]string{
+	"PLAIN":         "org.apache.kafka.common.security.plain.PlainLoginModule",
+	"SCRAM-SHA-256": "org.apache.kafka.common.security.scram.ScramLoginModule",
+	"SCRAM-SHA-512": "org.apache.kafka.common.security.scram.ScramLoginModule",
+}
+
+// ClientConfig returns the contents of the Kafka client configuration used to
+// authenticate with the brokers. The credentials are left as shell variables,
+// they are only expanded and escaped inside the container.
+func ClientConfig(sasl *perfv1alpha1.KafkaSASLSpec) string {
+	mechanism := sasl.Mechanism
+	if mechanism == "" {
+		mechanism = DefaultSASLMechanism
+	}
+	protocol := sasl.SecurityProtocol
+	if protocol == "" {
+		protocol = DefaultSecurityProtocol
+	}
+
+	return strings.Join([]string{
+		fmt.Sprintf("security.protocol=%s", protocol),
+		fmt.Sprintf("sasl.mechanism=%s", mechanism),
+		fmt.Sprintf("sasl.jaas.config=%s required username=\"$(escape \"$%s\")\" password=\"$(escape \"$%s\")\";",
+			saslLoginModules[mechanism], saslUsernameEnv, saslPasswordEnv),
+	}, "\n")
+}
+
+// Default sets the SASL mechanism and security protocol of the CR to
+// their defaults, if SASL authentication is used but they are not set
+func Default(cr *perfv1alpha1.KafkaBench) {
+	sasl := cr.Spec.SASL
+	if sasl == nil {
+		return
+	}
+	if sasl.Mechanism == "" {
+		sasl.Mechanism = DefaultSASLMechanism
+	}
+	if sasl.SecurityProtocol == "" {
+		sasl.SecurityProtocol = DefaultSecurityProtocol
+	}
+}
+
+// AddSASLConfig makes the perf test in the main container of the job
+// authenticate with the brokers. The client configuration is written by the
+// container's shell from the Secret backed environment and passed to the
+// perf test with configFlag.
+func AddSASLConfig(job *batchv1.Job, sasl *perfv1alpha1.KafkaSASLSpec, configFlag string) {
+	if sasl == nil {
+		return
+	}
+
+	container := &job.Spec.Template.Spec.Containers[0]
+	script := fmt.Sprintf("%s\ncat > %s <<EOF\n%s\nEOF\nexec /bin/sh \"$@\" %s %s",
+		escapeFunction, clientConfigPath, ClientConfig(sasl), configFlag, clientConfigPath)
+
+	container.Args = append([]string{"-c", script, "kafkabench"}, container.Args...)
+	container.Env = append(container.Env,
+		k8s.NewSecretEnvVar(saslUsernameEnv, "", &sasl.UsernameSecretRef),
+		k8s.NewSecretEnvVar(saslPasswordEnv, "", &sasl.PasswordSecretRef),
+	)
+}
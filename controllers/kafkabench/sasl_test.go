@@ -0,0 +1,124 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkabench
+
+import (
+	"os/exec"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ksapi "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("SASL authentication", func() {
+	var cr ksapi.KafkaBench
+	var test ksapi.KafkaTestSpec
+
+	BeforeEach(func() {
+		cr = ksapi.KafkaBench{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "kafkabench-sample",
+			},
+			Spec: ksapi.KafkaBenchSpec{
+				Image: ksapi.ImageSpec{
+					Name: "confluentinc/cp-kafka:5.2.1",
+				},
+				KafkaClusterInfo: ksapi.KafkaClusterInfo{
+					ZooKeepers: []string{"zookeeper:2181"},
+					Brokers:    []string{"kafka:9092"},
+				},
+			},
+		}
+		test = ksapi.KafkaTestSpec{Name: "sasl", Threads: 1, Records: 100, RecordSize: 100}
+	})
+
+	Context("without SASL", func() {
+		It("should run the perf test directly", func() {
+			job := NewProducerJob(&cr, &test)
+			Expect(job.Spec.Template.Spec.Containers[0].Args).To(
+				Equal(ProducerJobCmd(&cr, &test)))
+			Expect(job.Spec.Template.Spec.Containers[0].Env).To(BeEmpty())
+		})
+	})
+
+	Context("with SASL", func() {
+		BeforeEach(func() {
+			cr.Spec.SASL = &ksapi.KafkaSASLSpec{
+				Mechanism: "SCRAM-SHA-512",
+				UsernameSecretRef: corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "kafka-user"},
+					Key:                  "username",
+				},
+				PasswordSecretRef: corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "kafka-user"},
+					Key:                  "password",
+				},
+			}
+		})
+
+		It("should default the security protocol", func() {
+			Default(&cr)
+			Expect(cr.Spec.SASL.Mechanism).To(Equal("SCRAM-SHA-512"))
+			Expect(cr.Spec.SASL.SecurityProtocol).To(Equal(DefaultSecurityProtocol))
+		})
+
+		It("should configure the client from the secret backed environment", func() {
+			config := ClientConfig(cr.Spec.SASL)
+			Expect(config).To(ContainSubstring("security.protocol=SASL_PLAINTEXT"))
+			Expect(config).To(ContainSubstring("sasl.mechanism=SCRAM-SHA-512"))
+			Expect(config).To(ContainSubstring(
+				`ScramLoginModule required username="$(escape "$KAFKA_SASL_USERNAME")" password="$(escape "$KAFKA_SASL_PASSWORD")";`))
+		})
+
+		It("should escape the credentials in the client config", func() {
+			container := NewProducerJob(&cr, &test).Spec.Template.Spec.Containers[0]
+			// The perf test is replaced by printing the client config
+			cmd := exec.Command("/bin/sh", append(container.Args[:3], "-c", `cat "$1"`)...)
+			cmd.Env = []string{`KAFKA_SASL_USERNAME=user`, `KAFKA_SASL_PASSWORD=pa"ss\word'`}
+			output, err := cmd.Output()
+			Expect(err).NotTo(HaveOccurred())
+
+			// The properties file unescapes the backslashes, then JAAS the quotes
+			Expect(string(output)).To(ContainSubstring(
+				`username="user" password="pa\\"ss\\\\word'";`))
+		})
+
+		It("should pass the client config to the producer", func() {
+			container := NewProducerJob(&cr, &test).Spec.Template.Spec.Containers[0]
+			Expect(container.Args[0]).To(Equal("-c"))
+			Expect(container.Args[1]).To(HaveSuffix("--producer.config " + clientConfigPath))
+			Expect(container.Args[3:]).To(Equal(ProducerJobCmd(&cr, &test)))
+		})
+
+		It("should pass the client config to the consumer", func() {
+			container := NewConsumerJob(&cr, &test).Spec.Template.Spec.Containers[0]
+			Expect(container.Args[1]).To(HaveSuffix("--consumer.config " + clientConfigPath))
+			Expect(container.Args[3:]).To(Equal(ConsumerJobArgs(&cr, &test)))
+		})
+
+		It("should read the credentials from the secret", func() {
+			container := NewConsumerJob(&cr, &test).Spec.Template.Spec.Containers[0]
+			Expect(container.Env).To(HaveLen(2))
+			Expect(container.Env[0].ValueFrom.SecretKeyRef.Key).To(Equal("username"))
+			Expect(container.Env[1].ValueFrom.SecretKeyRef.Key).To(Equal("password"))
+			Expect(container.Args).NotTo(ContainElement(ContainSubstring("kafka-user")))
+		})
+	})
+})
@@ -0,0 +1,37 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkabench
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/webhooks"
+)
+
+// +kubebuilder:webhook:path=/mutate-perf-kubestone-xridge-io-v1alpha1-kafkabench,mutating=true,failurePolicy=fail,groups=perf.kubestone.xridge.io,resources=kafkabenches,verbs=create;update,versions=v1alpha1,name=mkafkabench.kubestone.xridge.io
+// +kubebuilder:webhook:path=/validate-perf-kubestone-xridge-io-v1alpha1-kafkabench,mutating=false,failurePolicy=fail,groups=perf.kubestone.xridge.io,resources=kafkabenches,verbs=create;update,versions=v1alpha1,name=vkafkabench.kubestone.xridge.io
+
+// SetupWebhookWithManager registers the admission webhooks of KafkaBench
+func SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return webhooks.SetupWithManager(mgr, &perfv1alpha1.KafkaBench{}, webhooks.Hooks{
+		Default: func(obj runtime.Object) {
+			Default(obj.(*perfv1alpha1.KafkaBench))
+		},
+	})
+}
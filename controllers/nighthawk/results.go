@@ -0,0 +1,143 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nighthawk
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/results"
+)
+
+func init() {
+	results.Register("Nighthawk", results.ParserFunc(ParseResults))
+}
+
+// The request latency statistics of nighthawk
+const requestToResponse = "benchmark_http_client.request_to_response"
+
+var (
+	statisticRegexp  = regexp.MustCompile(`^(\S+) \([0-9]+ samples\)`)
+	meanRegexp       = regexp.MustCompile(`mean: ([0-9smu ]+?) \|`)
+	percentileRegexp = regexp.MustCompile(`^\s+([0-9.]+)\s+[0-9]+\s+([0-9smu ]+?)\s*$`)
+	counterRegexp    = regexp.MustCompile(`^(\S+)\s+([0-9]+)\s+([0-9.]+)$`)
+	durationRegexp   = regexp.MustCompile(`([0-9]+)(ms|us|s)`)
+)
+
+// Reported percentiles: the first percentile of nighthawk's table which
+// is greater or equal to the given quantile is used
+var percentiles = []struct {
+	quantile float64
+	name     string
+}{
+	{0.5, "p50"},
+	{0.75, "p75"},
+	{0.9, "p90"},
+	{0.95, "p95"},
+	{0.99, "p99"},
+	{0.999, "p999"},
+}
+
+// ParseResults parses the human readable output of the nighthawk client.
+// The latencies of the request_to_response statistic are reported
+// in microseconds.
+func ParseResults(logs string) (*perfv1alpha1.BenchmarkResults, error) {
+	res := &perfv1alpha1.BenchmarkResults{}
+
+	statistic := ""
+	next := 0
+	scanner := bufio.NewScanner(strings.NewReader(logs))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := statisticRegexp.FindStringSubmatch(line); match != nil {
+			statistic = match[1]
+			continue
+		}
+		if match := counterRegexp.FindStringSubmatch(line); match != nil {
+			if match[1] == "upstream_rq_total" {
+				rps, err := strconv.ParseFloat(match[3], 64)
+				if err != nil {
+					return nil, err
+				}
+				res.AddMetric("rps", rps, "req/s")
+			}
+			continue
+		}
+		if statistic != requestToResponse {
+			continue
+		}
+		if match := meanRegexp.FindStringSubmatch(line); match != nil {
+			mean, err := parseDuration(match[1])
+			if err != nil {
+				return nil, err
+			}
+			res.AddMetric("mean", mean, "us")
+			continue
+		}
+		if match := percentileRegexp.FindStringSubmatch(line); match != nil && next < len(percentiles) {
+			quantile, err := strconv.ParseFloat(match[1], 64)
+			if err != nil {
+				return nil, err
+			}
+			latency, err := parseDuration(match[2])
+			if err != nil {
+				return nil, err
+			}
+			for next < len(percentiles) && quantile >= percentiles[next].quantile {
+				res.AddMetric(percentiles[next].name, latency, "us")
+				next++
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if res.GetMetric("mean") == nil {
+		return nil, fmt.Errorf("%s statistic is missing from nighthawk output", requestToResponse)
+	}
+
+	return res, nil
+}
+
+// parseDuration converts nighthawk's duration format (e.g. 0s 001ms 382us)
+// to microseconds
+func parseDuration(duration string) (float64, error) {
+	matches := durationRegexp.FindAllStringSubmatch(duration, -1)
+	if matches == nil {
+		return 0, errors.New("invalid duration: " + duration)
+	}
+
+	total := 0.0
+	for _, match := range matches {
+		value, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return 0, err
+		}
+		microseconds, err := results.Microseconds(value, match[2])
+		if err != nil {
+			return 0, err
+		}
+		total += microseconds
+	}
+	return total, nil
+}
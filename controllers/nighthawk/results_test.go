@@ -0,0 +1,80 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nighthawk
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const nighthawkOutput = `[21:30:02.285405][1][I] Starting 1 threads / event loops. Time limit: 5 seconds.
+Nighthawk - A layer 7 protocol benchmarking tool.
+
+benchmark_http_client.queue_to_connect (1250 samples)
+  min: 0s 000ms 010us | mean: 0s 000ms 025us | max: 0s 000ms 433us | pstdev: 0s 000ms 019us
+
+  Percentile  Count       Value          
+  0.5         625         0s 000ms 022us 
+  0.99        1238        0s 000ms 090us 
+
+benchmark_http_client.request_to_response (1250 samples)
+  min: 0s 000ms 238us | mean: 0s 000ms 437us | max: 0s 006ms 178us | pstdev: 0s 000ms 271us
+
+  Percentile  Count       Value          
+  0.5         625         0s 000ms 394us 
+  0.75        938         0s 000ms 456us 
+  0.8         1000        0s 000ms 479us 
+  0.9         1125        0s 000ms 572us 
+  0.95        1188        0s 000ms 706us 
+  0.990625    1239        0s 001ms 382us 
+  0.99902344  1249        0s 005ms 025us 
+
+Counter                                 Value       Per second
+benchmark.http_2xx                      1250        249.99
+upstream_cx_total                       1           0.20
+upstream_rq_total                       1250        249.99
+`
+
+var _ = Describe("nighthawk results", func() {
+	Context("with the output of a successful run", func() {
+		results, err := ParseResults(nighthawkOutput)
+
+		It("should not fail", func() {
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reports the request latencies in microseconds", func() {
+			Expect(results.GetMetric("mean").Value).To(Equal("437"))
+			Expect(results.GetMetric("p50").Value).To(Equal("394"))
+			Expect(results.GetMetric("p90").Value).To(Equal("572"))
+			Expect(results.GetMetric("p99").Value).To(Equal("1382"))
+			Expect(results.GetMetric("p999").Value).To(Equal("5025"))
+			Expect(results.GetMetric("p99").Unit).To(Equal("us"))
+		})
+
+		It("reports the request rate", func() {
+			Expect(results.GetMetric("rps").Value).To(Equal("249.99"))
+		})
+	})
+
+	Context("with unrelated output", func() {
+		It("should fail", func() {
+			_, err := ParseResults("Connection refused\n")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -1,6 +1,7 @@
 package ocplogtest
 
 import (
+	"errors"
 	"fmt"
 	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -42,3 +43,12 @@ func NewJob(cr *perfv1alpha1.OcpLogtest) *batchv1.Job {
 
 	return job
 }
+
+// IsCrValid validates the given CR and raises error if semantic errors detected
+// For ocplogtest, the line length, number of lines and rate cannot be negative
+func IsCrValid(cr *perfv1alpha1.OcpLogtest) (valid bool, err error) {
+	if cr.Spec.LineLength < 0 || cr.Spec.NumLines < 0 || cr.Spec.Rate < 0 {
+		return false, errors.New("the line length, number of lines and rate cannot be negative")
+	}
+	return true, nil
+}
@@ -66,3 +66,16 @@ var _ = Describe("ocplogtest job", func() {
 		})
 	})
 })
+
+var _ = Describe("ocplogtest validation", func() {
+	It("should accept the defaults", func() {
+		Expect(IsCrValid(&perfv1alpha1.OcpLogtest{})).To(BeTrue())
+	})
+
+	It("should reject negative rates", func() {
+		cr := perfv1alpha1.OcpLogtest{Spec: perfv1alpha1.OcpLogtestSpec{Rate: -1}}
+		valid, err := IsCrValid(&cr)
+		Expect(valid).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+	})
+})
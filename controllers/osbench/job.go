@@ -1,6 +1,7 @@
 package osbench
 
 import (
+	"errors"
 	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -21,3 +22,12 @@ func NewJob(cr *perfv1alpha1.Osbench) *batchv1.Job {
 	job.Spec.Template.Spec.Containers[0].Args = qsplit.ToStrings([]byte(cr.Spec.Options))
 	return job
 }
+
+// IsCrValid validates the given CR and raises error if semantic errors detected
+// For osbench, the test name is checked
+func IsCrValid(cr *perfv1alpha1.Osbench) (valid bool, err error) {
+	if cr.Spec.TestName == "" {
+		return false, errors.New("the test name is required")
+	}
+	return true, nil
+}
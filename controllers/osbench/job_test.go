@@ -38,3 +38,16 @@ var _ = Describe("osbench job", func() {
 		})
 	})
 })
+
+var _ = Describe("osbench validation", func() {
+	It("should accept the test name", func() {
+		cr := perfv1alpha1.Osbench{Spec: perfv1alpha1.OsbenchSpec{TestName: "create_files"}}
+		Expect(IsCrValid(&cr)).To(BeTrue())
+	})
+
+	It("should require the test name", func() {
+		valid, err := IsCrValid(&perfv1alpha1.Osbench{})
+		Expect(valid).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -1,6 +1,7 @@
 package perfbench
 
 import (
+	"errors"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -25,3 +26,12 @@ func NewJob(cr *perfv1alpha1.Perfbench) *batchv1.Job {
 	}
 	return job
 }
+
+// IsCrValid validates the given CR and raises error if semantic errors detected
+// For perfbench, the benchmark to run has to be given in the arguments
+func IsCrValid(cr *perfv1alpha1.Perfbench) (valid bool, err error) {
+	if len(cr.Spec.CmdLineArgs) == 0 {
+		return false, errors.New("the command line arguments of perf bench are required")
+	}
+	return true, nil
+}
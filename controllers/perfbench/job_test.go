@@ -48,3 +48,18 @@ var _ = Describe("perfbench job", func() {
 		})
 	})
 })
+
+var _ = Describe("perfbench validation", func() {
+	It("should accept the command line arguments", func() {
+		cr := perfv1alpha1.Perfbench{
+			Spec: perfv1alpha1.PerfbenchSpec{CmdLineArgs: []string{"sched", "pipe"}},
+		}
+		Expect(IsCrValid(&cr)).To(BeTrue())
+	})
+
+	It("should require the command line arguments", func() {
+		valid, err := IsCrValid(&perfv1alpha1.Perfbench{})
+		Expect(valid).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -17,6 +17,7 @@ limitations under the License.
 package pgbench
 
 import (
+	"errors"
 	"fmt"
 
 	batchv1 "k8s.io/api/batch/v1"
@@ -39,7 +40,7 @@ func NewJob(cr *perfv1alpha1.Pgbench) *batchv1.Job {
 		{Name: "PGHOST", Value: cr.Spec.Postgres.Host},
 		{Name: "PGPORT", Value: fmt.Sprintf("%d", cr.Spec.Postgres.Port)},
 		{Name: "PGUSER", Value: cr.Spec.Postgres.User},
-		{Name: "PGPASSWORD", Value: cr.Spec.Postgres.Password},
+		k8s.NewSecretEnvVar("PGPASSWORD", cr.Spec.Postgres.Password, cr.Spec.Postgres.PasswordSecretRef),
 		{Name: "PGDATABASE", Value: cr.Spec.Postgres.Database},
 	}
 
@@ -60,3 +61,19 @@ func NewJob(cr *perfv1alpha1.Pgbench) *batchv1.Job {
 	job.Spec.Template.Spec.Containers[0].Env = env
 	return job
 }
+
+// IsCrValid validates the given CR and raises error if semantic errors detected
+// For pgbench, the connection settings of the PostgreSQL server are checked
+func IsCrValid(cr *perfv1alpha1.Pgbench) (valid bool, err error) {
+	postgres := cr.Spec.Postgres
+	if postgres.Host == "" || postgres.User == "" || postgres.Database == "" {
+		return false, errors.New("the host, user and database of the PostgreSQL server are required")
+	}
+	if postgres.Port < 1 || postgres.Port > 65535 {
+		return false, fmt.Errorf("invalid PostgreSQL port: %d", postgres.Port)
+	}
+	if ref := postgres.PasswordSecretRef; ref != nil && (ref.Name == "" || ref.Key == "") {
+		return false, errors.New("the name and key of the password secret are required")
+	}
+	return true, nil
+}
@@ -121,4 +121,81 @@ var _ = Describe("pgbench job", func() {
 		})
 
 	})
+
+	Describe("with a password secret", func() {
+		passwordRef := &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "postgres"},
+			Key:                  "password",
+		}
+		cr := perfv1alpha1.Pgbench{
+			Spec: perfv1alpha1.PgbenchSpec{
+				Image: perfv1alpha1.ImageSpec{
+					Name: "postgres:latest",
+				},
+				Postgres: perfv1alpha1.PostgresSpec{
+					Host:              "postgres",
+					Port:              5432,
+					User:              "admin",
+					PasswordSecretRef: passwordRef,
+					Database:          "benchdb",
+				},
+			},
+		}
+		job := NewJob(&cr)
+
+		It("should read the password from the secret in both containers", func() {
+			containers := append(job.Spec.Template.Spec.InitContainers,
+				job.Spec.Template.Spec.Containers...)
+			for _, container := range containers {
+				Expect(container.Env).To(ContainElement(corev1.EnvVar{
+					Name:      "PGPASSWORD",
+					ValueFrom: &corev1.EnvVarSource{SecretKeyRef: passwordRef},
+				}))
+			}
+		})
+	})
+})
+
+var _ = Describe("pgbench validation", func() {
+	var cr perfv1alpha1.Pgbench
+
+	BeforeEach(func() {
+		cr = perfv1alpha1.Pgbench{
+			Spec: perfv1alpha1.PgbenchSpec{
+				Postgres: perfv1alpha1.PostgresSpec{
+					Host:     "postgres",
+					Port:     5432,
+					User:     "admin",
+					Database: "benchdb",
+				},
+			},
+		}
+	})
+
+	It("should accept the connection settings", func() {
+		Expect(IsCrValid(&cr)).To(BeTrue())
+	})
+
+	It("should require the host", func() {
+		cr.Spec.Postgres.Host = ""
+		valid, err := IsCrValid(&cr)
+		Expect(valid).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject invalid ports", func() {
+		cr.Spec.Postgres.Port = 0
+		valid, err := IsCrValid(&cr)
+		Expect(valid).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("port")))
+	})
+
+	It("should reject incomplete password secret references", func() {
+		cr.Spec.Postgres.PasswordSecretRef = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "postgres"},
+		}
+		valid, err := IsCrValid(&cr)
+		Expect(valid).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+	})
 })
@@ -0,0 +1,82 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pgbench
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/results"
+)
+
+func init() {
+	results.Register("Pgbench", results.ParserFunc(ParseResults))
+}
+
+var (
+	tpsRegexp          = regexp.MustCompile(`(?m)^tps = ([0-9.]+) \((.*)\)`)
+	latencyRegexp      = regexp.MustCompile(`(?m)^latency (average|stddev) = ([0-9.]+) ms`)
+	transactionsRegexp = regexp.MustCompile(`(?m)^number of transactions actually processed: ([0-9]+)`)
+)
+
+// ParseResults parses the summary printed by pgbench. The reported tps
+// excludes the time spent on establishing the connections, when pgbench
+// reports it separately.
+func ParseResults(logs string) (*perfv1alpha1.BenchmarkResults, error) {
+	res := &perfv1alpha1.BenchmarkResults{}
+
+	for _, match := range tpsRegexp.FindAllStringSubmatch(logs, -1) {
+		tps, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return nil, err
+		}
+		if match[2] == "including connections establishing" {
+			res.AddMetric("tps.including_connections", tps, "tx/s")
+			if res.GetMetric("tps") != nil {
+				continue
+			}
+		}
+		res.AddMetric("tps", tps, "tx/s")
+	}
+	if res.GetMetric("tps") == nil {
+		return nil, errors.New("tps is missing from pgbench output")
+	}
+
+	for _, match := range latencyRegexp.FindAllStringSubmatch(logs, -1) {
+		latency, err := strconv.ParseFloat(match[2], 64)
+		if err != nil {
+			return nil, err
+		}
+		name := "latency.avg"
+		if match[1] == "stddev" {
+			name = "latency.stddev"
+		}
+		res.AddMetric(name, latency, "ms")
+	}
+
+	if match := transactionsRegexp.FindStringSubmatch(logs); match != nil {
+		transactions, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return nil, err
+		}
+		res.AddMetric("transactions", transactions, "")
+	}
+
+	return res, nil
+}
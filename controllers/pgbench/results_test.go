@@ -0,0 +1,77 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pgbench
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const pgbenchOutput = `starting vacuum...end.
+transaction type: <builtin: TPC-B (sort of)>
+scaling factor: 5
+query mode: simple
+number of clients: 10
+number of threads: 1
+number of transactions per client: 100
+number of transactions actually processed: 1000/1000
+latency average = 15.844 ms
+latency stddev = 5.123 ms
+tps = 631.150886 (including connections establishing)
+tps = 632.064349 (excluding connections establishing)
+`
+
+var _ = Describe("pgbench results", func() {
+	Context("with the output of a successful run", func() {
+		results, err := ParseResults(pgbenchOutput)
+
+		It("should not fail", func() {
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reports tps without the connection establishment", func() {
+			Expect(results.GetMetric("tps").Value).To(Equal("632.064349"))
+			Expect(results.GetMetric("tps.including_connections").Value).To(Equal("631.150886"))
+		})
+
+		It("reports the latency", func() {
+			Expect(results.GetMetric("latency.avg").Value).To(Equal("15.844"))
+			Expect(results.GetMetric("latency.stddev").Value).To(Equal("5.123"))
+			Expect(results.GetMetric("latency.avg").Unit).To(Equal("ms"))
+		})
+
+		It("reports the number of processed transactions", func() {
+			Expect(results.GetMetric("transactions").Value).To(Equal("1000"))
+		})
+	})
+
+	Context("with the output of newer pgbench versions", func() {
+		results, err := ParseResults("tps = 1520.5 (without initial connection time)\n")
+
+		It("reports tps", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.GetMetric("tps").Value).To(Equal("1520.5"))
+		})
+	})
+
+	Context("with unrelated output", func() {
+		It("should fail", func() {
+			_, err := ParseResults("connection refused\n")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
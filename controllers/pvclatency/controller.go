@@ -0,0 +1,390 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pvclatency
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/lifecycle"
+	"github.com/xridge/kubestone/pkg/results"
+)
+
+// PollInterval is the delay between the checks of the volumes. The PVCs
+// and the pods are not watched, as the watches would cache all the PVCs
+// and pods of the cluster, so all the progress is observed by the checks.
+const PollInterval = time.Second
+
+// Reconciler provides fields from manager to reconciler
+type Reconciler struct {
+	K8S k8s.Access
+	Log logr.Logger
+}
+
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;create;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;create;delete
+// +kubebuilder:rbac:groups="",resources=persistentvolumes,verbs=get
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=volumeattachments,verbs=list
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=pvclatencies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=pvclatencies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=pvclatencies/finalizers,verbs=update
+
+// Reconcile creates the PVCs and their consumer pods at once, and records
+// when each PVC is bound and each pod is running. Once all the pods are
+// running, the pods and then the PVCs are deleted, recording when the
+// volumes are detached and deleted. The latencies are computed from the
+// recorded timeline of the volumes. The PVCs, pods, PersistentVolumes and
+// VolumeAttachments are accessed via the Clientset, so they are not cached.
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+
+	var cr perfv1alpha1.PVCLatency
+	if err := r.K8S.Client.Get(ctx, req.NamespacedName, &cr); err != nil {
+		return ctrl.Result{}, k8s.IgnoreNotFound(err)
+	}
+
+	// Run to one completion
+	if cr.Status.IsFinished() {
+		return ctrl.Result{}, nil
+	}
+
+	// Validate on first entry
+	if cr.Status.Phase == "" {
+		if err := r.K8S.UpdatePhase(ctx, &cr, perfv1alpha1.BenchmarkValidating, "", ""); err != nil {
+			return ctrl.Result{}, err
+		}
+		if valid, err := IsCrValid(&cr); !valid {
+			_ = r.K8S.RecordEventf(&cr, corev1.EventTypeWarning, k8s.CreateFailed,
+				"CR validation failed: %v", err)
+
+			// Do not requeue invalid CRs
+			return ctrl.Result{}, r.K8S.UpdatePhase(ctx, &cr, perfv1alpha1.BenchmarkFailed,
+				k8s.ValidationFailed, err.Error())
+		}
+		cr.Status.SetCondition(perfv1alpha1.ConditionValidated, corev1.ConditionTrue, "", "")
+	}
+
+	// The objects of each run (including the repetitions) are created afresh
+	if len(cr.Status.Children) == 0 {
+		if err := r.createVolumes(&cr); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: PollInterval},
+			r.K8S.UpdatePhase(ctx, &cr, perfv1alpha1.BenchmarkRunning, "", "")
+	}
+
+	original := cr.Status.DeepCopy()
+
+	failure, err := r.syncVolumes(&cr)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if failure == nil && !AreVolumesDeleted(cr.Status.Volumes) {
+		if apiequality.Semantic.DeepEqual(original, &cr.Status) {
+			return ctrl.Result{RequeueAfter: PollInterval}, nil
+		}
+		return ctrl.Result{RequeueAfter: PollInterval}, r.K8S.Client.Status().Update(ctx, &cr)
+	}
+
+	if failure == nil {
+		// The consumer pods are deleted by now,
+		// their labels are taken from their template
+		if err := results.Publish(&r.K8S, &cr, Summarize(cr.Status.Volumes),
+			NewPod(&cr, 0).Labels); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if err := r.K8S.FinishBenchmark(ctx, &cr, failure); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Keep a record of the run, which outlives the benchmark
+	return ctrl.Result{}, results.Record(ctx, &r.K8S, &cr)
+}
+
+// createVolumes creates the PVCs and their consumer pods of the run. The
+// creation time of the volume is the creation timestamp of its PVC.
+func (r *Reconciler) createVolumes(cr *perfv1alpha1.PVCLatency) error {
+	cr.Status.Volumes = nil
+	for i := 0; i < cr.Spec.VolumeCount(); i++ {
+		pvc := NewPersistentVolumeClaim(cr, i)
+		if err := controllerutil.SetControllerReference(cr, pvc, r.K8S.Scheme); err != nil {
+			return err
+		}
+		claims := r.K8S.Clientset.CoreV1().PersistentVolumeClaims(cr.Namespace)
+		created, err := claims.Create(pvc)
+		if errors.IsAlreadyExists(err) {
+			created, err = claims.Get(pvc.Name, metav1.GetOptions{})
+		}
+		if err != nil {
+			return err
+		}
+		cr.Status.AddChild("v1", "PersistentVolumeClaim", pvc.Name)
+
+		pod := NewPod(cr, i)
+		if err := controllerutil.SetControllerReference(cr, pod, r.K8S.Scheme); err != nil {
+			return err
+		}
+		if _, err := r.K8S.Clientset.CoreV1().Pods(cr.Namespace).Create(pod); k8s.IgnoreAlreadyExists(err) != nil {
+			return err
+		}
+		cr.Status.AddChild("v1", "Pod", pod.Name)
+
+		cr.Status.Volumes = append(cr.Status.Volumes, perfv1alpha1.PVCLatencyVolume{
+			Name:         pvc.Name,
+			CreationTime: serverTime(created.CreationTimestamp),
+		})
+	}
+	_ = r.K8S.RecordEventf(cr, corev1.EventTypeNormal, k8s.Created,
+		"Created %d PVCs and their pods", len(cr.Status.Volumes))
+	return nil
+}
+
+// syncVolumes records the progress of the volumes in their timeline.
+// Until all the pods are running, the binding of the PVCs and the start
+// of the pods are checked. Then the pods are deleted, and the PVC of each
+// pod is deleted once its volume is detached. It returns the failure if
+// any of the pods has terminated.
+func (r *Reconciler) syncVolumes(cr *perfv1alpha1.PVCLatency) (*k8s.JobFailure, error) {
+	if !AreVolumesRunning(cr.Status.Volumes) {
+		for i := range cr.Status.Volumes {
+			if failure, err := r.syncStart(cr, &cr.Status.Volumes[i]); failure != nil || err != nil {
+				return failure, err
+			}
+		}
+		if !AreVolumesRunning(cr.Status.Volumes) {
+			return nil, nil
+		}
+	}
+
+	for i := range cr.Status.Volumes {
+		if err := r.syncDeletion(cr, &cr.Status.Volumes[i]); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// syncStart records the binding of the PVC and the start of the pod. The
+// PVC does not record when it was bound, so the binding is observed by
+// the checks. The start of the pod is the start time of its container.
+func (r *Reconciler) syncStart(cr *perfv1alpha1.PVCLatency,
+	volume *perfv1alpha1.PVCLatencyVolume) (*k8s.JobFailure, error) {
+	if volume.BoundTime == nil {
+		pvc, err := r.K8S.Clientset.CoreV1().PersistentVolumeClaims(cr.Namespace).Get(
+			volume.Name, metav1.GetOptions{})
+		if k8s.IgnoreNotFound(err) != nil {
+			return nil, err
+		} else if err == nil && pvc.Status.Phase == corev1.ClaimBound {
+			volume.BoundTime = now()
+			volume.VolumeName = pvc.Spec.VolumeName
+		}
+	}
+
+	if volume.RunningTime == nil {
+		pod, err := r.K8S.Clientset.CoreV1().Pods(cr.Namespace).Get(volume.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, k8s.IgnoreNotFound(err)
+		}
+		switch pod.Status.Phase {
+		case corev1.PodRunning:
+			volume.RunningTime = startTime(pod)
+		case corev1.PodSucceeded, corev1.PodFailed:
+			return &k8s.JobFailure{
+				Reason:  k8s.PodFailed,
+				Message: fmt.Sprintf("Pod %s terminated: %s", pod.Name, pod.Status.Message),
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// syncDeletion deletes the pod, then the PVC once its volume is detached,
+// and records their deletion. The deletions are timed by the deletion
+// timestamps of the objects, while the detach of the volume and the
+// removal of the PVC and its volume are observed by the checks.
+func (r *Reconciler) syncDeletion(cr *perfv1alpha1.PVCLatency, volume *perfv1alpha1.PVCLatencyVolume) error {
+	pods := r.K8S.Clientset.CoreV1().Pods(cr.Namespace)
+	claims := r.K8S.Clientset.CoreV1().PersistentVolumeClaims(cr.Namespace)
+
+	if volume.PodDeletionTime == nil {
+		if err := pods.Delete(volume.Name, &metav1.DeleteOptions{}); k8s.IgnoreNotFound(err) != nil {
+			return err
+		}
+		pod, err := pods.Get(volume.Name, metav1.GetOptions{})
+		if k8s.IgnoreNotFound(err) != nil {
+			return err
+		}
+		volume.PodDeletionTime = deletionTime(pod, err)
+		return nil
+	}
+
+	if volume.DetachedTime == nil {
+		detached, err := r.isDetached(cr.Namespace, volume)
+		if err != nil || !detached {
+			return err
+		}
+		volume.DetachedTime = now()
+	}
+
+	if volume.DeletionTime == nil {
+		if err := claims.Delete(volume.Name, &metav1.DeleteOptions{}); k8s.IgnoreNotFound(err) != nil {
+			return err
+		}
+		pvc, err := claims.Get(volume.Name, metav1.GetOptions{})
+		if k8s.IgnoreNotFound(err) != nil {
+			return err
+		}
+		volume.DeletionTime = deletionTime(pvc, err)
+		return nil
+	}
+
+	if volume.DeletedTime == nil {
+		deleted, err := r.isDeleted(cr.Namespace, volume)
+		if err != nil || !deleted {
+			return err
+		}
+		volume.DeletedTime = now()
+	}
+	return nil
+}
+
+// isDetached returns true if the pod is gone and the volume is not
+// attached to any node
+func (r *Reconciler) isDetached(namespace string, volume *perfv1alpha1.PVCLatencyVolume) (bool, error) {
+	_, err := r.K8S.Clientset.CoreV1().Pods(namespace).Get(volume.Name, metav1.GetOptions{})
+	if err == nil || !errors.IsNotFound(err) {
+		return false, k8s.IgnoreNotFound(err)
+	}
+	if volume.VolumeName == "" {
+		return true, nil
+	}
+
+	attachments, err := r.K8S.Clientset.StorageV1().VolumeAttachments().List(metav1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+	for _, attachment := range attachments.Items {
+		source := attachment.Spec.Source.PersistentVolumeName
+		if source != nil && *source == volume.VolumeName {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// isDeleted returns true if the PVC is gone, and its persistent volume is
+// gone as well or is not deleted with the PVC (e.g. Retain reclaim policy)
+func (r *Reconciler) isDeleted(namespace string, volume *perfv1alpha1.PVCLatencyVolume) (bool, error) {
+	_, err := r.K8S.Clientset.CoreV1().PersistentVolumeClaims(namespace).Get(volume.Name, metav1.GetOptions{})
+	if err == nil || !errors.IsNotFound(err) {
+		return false, k8s.IgnoreNotFound(err)
+	}
+	if volume.VolumeName == "" {
+		return true, nil
+	}
+
+	pv, err := r.K8S.Clientset.CoreV1().PersistentVolumes().Get(volume.VolumeName, metav1.GetOptions{})
+	if err != nil {
+		return errors.IsNotFound(err), k8s.IgnoreNotFound(err)
+	}
+	return pv.Spec.PersistentVolumeReclaimPolicy != corev1.PersistentVolumeReclaimDelete, nil
+}
+
+// AreVolumesRunning returns true if the pods of all the volumes are running
+func AreVolumesRunning(volumes []perfv1alpha1.PVCLatencyVolume) bool {
+	for i := range volumes {
+		if volumes[i].RunningTime == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// AreVolumesDeleted returns true if all the volumes are deleted
+func AreVolumesDeleted(volumes []perfv1alpha1.PVCLatencyVolume) bool {
+	for i := range volumes {
+		if volumes[i].DeletedTime == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// now returns the current time with microsecond precision. It is used for
+// the steps of the timeline which are only observed by the checks.
+func now() *metav1.MicroTime {
+	t := metav1.NowMicro()
+	return &t
+}
+
+// serverTime converts the timestamp recorded by the API server or the
+// kubelet, falling back to the current time if it is not set
+func serverTime(t metav1.Time) *metav1.MicroTime {
+	if t.IsZero() {
+		return now()
+	}
+	return &metav1.MicroTime{Time: t.Time}
+}
+
+// startTime returns the time the consumer container of the running pod
+// was started, or the time the pod became ready if it is not known
+func startTime(pod *corev1.Pod) *metav1.MicroTime {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Running != nil {
+			return serverTime(status.State.Running.StartedAt)
+		}
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+			return serverTime(condition.LastTransitionTime)
+		}
+	}
+	return now()
+}
+
+// deletionTime returns the time the deletion of the object was requested:
+// its deletion timestamp less its grace period. If the object is already
+// gone (err is NotFound), the current time is returned.
+func deletionTime(object metav1.Object, err error) *metav1.MicroTime {
+	if err != nil || object.GetDeletionTimestamp() == nil {
+		return now()
+	}
+	requested := object.GetDeletionTimestamp().Time
+	if grace := object.GetDeletionGracePeriodSeconds(); grace != nil {
+		requested = requested.Add(-time.Duration(*grace) * time.Second)
+	}
+	return &metav1.MicroTime{Time: requested}
+}
+
+// SetupWithManager registers the Reconciler with the provided manager
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&perfv1alpha1.PVCLatency{}).
+		Complete(lifecycle.NewReconciler(r, &r.K8S, &perfv1alpha1.PVCLatency{}))
+}
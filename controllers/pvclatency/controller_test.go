@@ -0,0 +1,240 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pvclatency
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+)
+
+var _ = Describe("pvc latency reconciler", func() {
+	var ctx context.Context
+	var name types.NamespacedName
+	var reconciler *Reconciler
+	var clientset *k8sfake.Clientset
+	var created time.Time
+
+	reconcile := func() {
+		_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	stored := func() *perfv1alpha1.PVCLatency {
+		var cr perfv1alpha1.PVCLatency
+		Expect(reconciler.K8S.Client.Get(ctx, name, &cr)).To(Succeed())
+		return &cr
+	}
+
+	podName := func(index int) string {
+		return fmt.Sprintf("%s-%d", name.Name, index)
+	}
+
+	podExists := func(index int) bool {
+		_, err := clientset.CoreV1().Pods(name.Namespace).Get(podName(index), metav1.GetOptions{})
+		Expect(errors.IsNotFound(err) || err == nil).To(BeTrue())
+		return err == nil
+	}
+
+	pvcExists := func(index int) bool {
+		_, err := clientset.CoreV1().PersistentVolumeClaims(name.Namespace).Get(podName(index), metav1.GetOptions{})
+		Expect(errors.IsNotFound(err) || err == nil).To(BeTrue())
+		return err == nil
+	}
+
+	bind := func(index int) {
+		claims := clientset.CoreV1().PersistentVolumeClaims(name.Namespace)
+		pvc, err := claims.Get(podName(index), metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		pvc.Spec.VolumeName = fmt.Sprintf("pv-%d", index)
+		pvc.Status.Phase = corev1.ClaimBound
+		_, err = claims.Update(pvc)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = clientset.CoreV1().PersistentVolumes().Create(&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: pvc.Spec.VolumeName},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	setPodPhase := func(index int, phase corev1.PodPhase, started time.Time) {
+		pods := clientset.CoreV1().Pods(name.Namespace)
+		pod, err := pods.Get(podName(index), metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		pod.Status.Phase = phase
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name: "consumer",
+			State: corev1.ContainerState{
+				Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(started)},
+			},
+		}}
+		_, err = pods.Update(pod)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		name = types.NamespacedName{Namespace: "storage", Name: "pvc"}
+		count := int32(2)
+		cr := &perfv1alpha1.PVCLatency{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name.Name,
+				Namespace: name.Namespace,
+				UID:       "pvc-uid",
+				// Needed by the event recorder to reference the object
+				SelfLink: "/apis/perf.kubestone.xridge.io/v1alpha1/namespaces/storage/pvclatencies/pvc",
+			},
+			Spec: perfv1alpha1.PVCLatencySpec{
+				Count: &count,
+				Image: perfv1alpha1.ImageSpec{Name: "busybox:test"},
+			},
+		}
+
+		// The creation timestamps are set by the API server
+		created = time.Now().Add(-time.Minute).Truncate(time.Second)
+		clientset = k8sfake.NewSimpleClientset()
+		clientset.PrependReactor("create", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			object := action.(k8stesting.CreateAction).GetObject().(metav1.Object)
+			object.SetCreationTimestamp(metav1.NewTime(created))
+			return false, nil, nil
+		})
+
+		scheme := runtime.NewScheme()
+		_ = k8sscheme.AddToScheme(scheme)
+		_ = perfv1alpha1.AddToScheme(scheme)
+		reconciler = &Reconciler{K8S: k8s.Access{
+			Client:        fake.NewFakeClientWithScheme(scheme, cr),
+			Clientset:     clientset,
+			Scheme:        scheme,
+			EventRecorder: record.NewFakeRecorder(100),
+		}}
+
+		reconcile()
+	})
+
+	It("should create the PVCs and their pods at once", func() {
+		cr := stored()
+		Expect(cr.Status.Phase).To(Equal(perfv1alpha1.BenchmarkRunning))
+		Expect(cr.Status.IsConditionTrue(perfv1alpha1.ConditionValidated)).To(BeTrue())
+		Expect(cr.Status.Volumes).To(HaveLen(2))
+		Expect(cr.Status.Children).To(HaveLen(4))
+		for i := 0; i < 2; i++ {
+			Expect(pvcExists(i)).To(BeTrue())
+			Expect(podExists(i)).To(BeTrue())
+			Expect(cr.Status.Volumes[i].CreationTime.Time).To(BeTemporally("==", created))
+		}
+	})
+
+	It("should measure the timeline of the volumes", func() {
+		started := created.Add(10 * time.Second)
+		bind(0)
+		setPodPhase(0, corev1.PodRunning, started)
+		reconcile()
+		cr := stored()
+		Expect(cr.Status.Volumes[0].BoundTime).NotTo(BeNil())
+		Expect(cr.Status.Volumes[0].VolumeName).To(Equal("pv-0"))
+		Expect(cr.Status.Volumes[0].RunningTime.Time).To(BeTemporally("==", started))
+		Expect(cr.Status.Volumes[1].BoundTime).To(BeNil())
+		// The pods are deleted only when all of them are running
+		Expect(podExists(0)).To(BeTrue())
+
+		bind(1)
+		setPodPhase(1, corev1.PodRunning, started)
+		_, err := clientset.StorageV1().VolumeAttachments().Create(&storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: "attachment-0"},
+			Spec: storagev1.VolumeAttachmentSpec{
+				Source: storagev1.VolumeAttachmentSource{PersistentVolumeName: &cr.Status.Volumes[0].VolumeName},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		reconcile()
+		Expect(podExists(0)).To(BeFalse())
+		Expect(podExists(1)).To(BeFalse())
+		Expect(stored().Status.Volumes[1].PodDeletionTime).NotTo(BeNil())
+
+		// The PVC is deleted once its volume is detached
+		reconcile()
+		Expect(pvcExists(0)).To(BeTrue())
+		Expect(pvcExists(1)).To(BeFalse())
+		volume := stored().Status.Volumes[1]
+		Expect(volume.DetachedTime).NotTo(BeNil())
+		Expect(volume.DeletionTime).NotTo(BeNil())
+
+		Expect(clientset.StorageV1().VolumeAttachments().Delete("attachment-0", &metav1.DeleteOptions{})).To(Succeed())
+		reconcile()
+		Expect(pvcExists(0)).To(BeFalse())
+
+		// The deletion is finished when the persistent volumes are gone
+		reconcile()
+		Expect(stored().Status.Phase).To(Equal(perfv1alpha1.BenchmarkRunning))
+		for i := 0; i < 2; i++ {
+			Expect(clientset.CoreV1().PersistentVolumes().Delete(fmt.Sprintf("pv-%d", i),
+				&metav1.DeleteOptions{})).To(Succeed())
+		}
+		reconcile()
+
+		cr = stored()
+		Expect(cr.Status.Phase).To(Equal(perfv1alpha1.BenchmarkSucceeded))
+		for _, metric := range []string{"bound.p50", "attach.p90", "running.p99", "detach.max", "delete.mean"} {
+			Expect(cr.Status.Results.GetMetric(metric)).NotTo(BeNil(), metric)
+		}
+		Expect(cr.Status.Results.GetMetric("running.max").Value).To(Equal("10000"))
+
+		var records perfv1alpha1.BenchmarkResultList
+		Expect(reconciler.K8S.Client.List(ctx, &records)).To(Succeed())
+		Expect(records.Items).To(HaveLen(1))
+	})
+
+	It("should fail when a pod terminates", func() {
+		setPodPhase(1, corev1.PodFailed, created)
+		reconcile()
+		cr := stored()
+		Expect(cr.Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+		Expect(cr.Status.Reason).To(Equal(k8s.PodFailed))
+		Expect(cr.Status.Results).To(BeNil())
+	})
+
+	It("should time the deletion of the objects by their deletion timestamp", func() {
+		pod := &corev1.Pod{}
+		deleted := metav1.NewTime(created.Add(5 * time.Second))
+		grace := int64(5)
+		pod.SetDeletionTimestamp(&deleted)
+		pod.SetDeletionGracePeriodSeconds(&grace)
+		Expect(deletionTime(pod, nil).Time).To(BeTemporally("==", created))
+	})
+})
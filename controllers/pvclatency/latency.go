@@ -0,0 +1,105 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pvclatency
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/stats"
+)
+
+// percentiles are the percentiles reported for each latency
+var percentiles = []struct {
+	name    string
+	percent float64
+}{
+	{"p50", 50},
+	{"p90", 90},
+	{"p99", 99},
+	{"max", 100},
+}
+
+// latency is a measured interval of the timeline of the volumes
+type latency struct {
+	// name is the prefix of the metrics of the latency
+	name string
+	// from and to select the start and the end of the interval
+	from, to func(v *perfv1alpha1.PVCLatencyVolume) *metav1.MicroTime
+}
+
+// latencies are the intervals measured by the benchmark
+var latencies = []latency{
+	{
+		// Provisioning (or binding an existing PV) of the PVC
+		name: "bound",
+		from: func(v *perfv1alpha1.PVCLatencyVolume) *metav1.MicroTime { return v.CreationTime },
+		to:   func(v *perfv1alpha1.PVCLatencyVolume) *metav1.MicroTime { return v.BoundTime },
+	},
+	{
+		// Attach and mount of the bound volume, and the start of the pod
+		name: "attach",
+		from: func(v *perfv1alpha1.PVCLatencyVolume) *metav1.MicroTime { return v.BoundTime },
+		to:   func(v *perfv1alpha1.PVCLatencyVolume) *metav1.MicroTime { return v.RunningTime },
+	},
+	{
+		// The whole time until the consumer pod is running
+		name: "running",
+		from: func(v *perfv1alpha1.PVCLatencyVolume) *metav1.MicroTime { return v.CreationTime },
+		to:   func(v *perfv1alpha1.PVCLatencyVolume) *metav1.MicroTime { return v.RunningTime },
+	},
+	{
+		// Termination of the pod, unmount and detach of the volume
+		name: "detach",
+		from: func(v *perfv1alpha1.PVCLatencyVolume) *metav1.MicroTime { return v.PodDeletionTime },
+		to:   func(v *perfv1alpha1.PVCLatencyVolume) *metav1.MicroTime { return v.DetachedTime },
+	},
+	{
+		// Deletion of the PVC and its dynamically provisioned volume
+		name: "delete",
+		from: func(v *perfv1alpha1.PVCLatencyVolume) *metav1.MicroTime { return v.DeletionTime },
+		to:   func(v *perfv1alpha1.PVCLatencyVolume) *metav1.MicroTime { return v.DeletedTime },
+	},
+}
+
+// Summarize computes the percentiles and the mean of the latencies of
+// the volumes in milliseconds (e.g. bound.p99, attach.mean). The volumes
+// missing either end of an interval are skipped.
+func Summarize(volumes []perfv1alpha1.PVCLatencyVolume) *perfv1alpha1.BenchmarkResults {
+	results := &perfv1alpha1.BenchmarkResults{}
+	for _, l := range latencies {
+		var values []float64
+		for i := range volumes {
+			from, to := l.from(&volumes[i]), l.to(&volumes[i])
+			if from == nil || to == nil {
+				continue
+			}
+			values = append(values, float64(to.Sub(from.Time))/float64(time.Millisecond))
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		for _, p := range percentiles {
+			results.AddMetric(l.name+"."+p.name, stats.Percentile(values, p.percent), "ms")
+		}
+		results.AddMetric(l.name+".mean", stats.Summarize(values).Mean, "ms")
+	}
+	return results
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pvclatency
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("pvc latency results", func() {
+	start := time.Date(2019, 11, 5, 10, 0, 0, 0, time.UTC)
+	at := func(ms int) *metav1.MicroTime {
+		t := metav1.NewMicroTime(start.Add(time.Duration(ms) * time.Millisecond))
+		return &t
+	}
+
+	It("should summarize the latencies of the volumes", func() {
+		volumes := []perfv1alpha1.PVCLatencyVolume{}
+		for i := 0; i < 5; i++ {
+			// Bound after 1, 2, 3, 4, 5 seconds, running 500ms later
+			bound := 1000 * (i + 1)
+			volumes = append(volumes, perfv1alpha1.PVCLatencyVolume{
+				Name:            "volume",
+				CreationTime:    at(0),
+				BoundTime:       at(bound),
+				RunningTime:     at(bound + 500),
+				PodDeletionTime: at(10000),
+				DetachedTime:    at(10250),
+				DeletionTime:    at(10250),
+				DeletedTime:     at(12250),
+			})
+		}
+
+		results := Summarize(volumes)
+		Expect(results.GetMetric("bound.p50").Value).To(Equal("3000"))
+		Expect(results.GetMetric("bound.p90").Value).To(Equal("4600"))
+		Expect(results.GetMetric("bound.max").Value).To(Equal("5000"))
+		Expect(results.GetMetric("bound.mean").Value).To(Equal("3000"))
+		Expect(results.GetMetric("attach.p99").Value).To(Equal("500"))
+		Expect(results.GetMetric("running.max").Value).To(Equal("5500"))
+		Expect(results.GetMetric("detach.p50").Value).To(Equal("250"))
+		Expect(results.GetMetric("delete.p50").Value).To(Equal("2000"))
+		Expect(results.GetMetric("delete.p50").Unit).To(Equal("ms"))
+	})
+
+	It("should skip the unfinished intervals", func() {
+		results := Summarize([]perfv1alpha1.PVCLatencyVolume{
+			{Name: "volume", CreationTime: at(0), BoundTime: at(1500)},
+		})
+		Expect(results.GetMetric("bound.p50").Value).To(Equal("1500"))
+		Expect(results.GetMetric("attach.p50")).To(BeNil())
+		Expect(results.GetMetric("delete.p50")).To(BeNil())
+	})
+})
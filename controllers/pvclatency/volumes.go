@@ -0,0 +1,147 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pvclatency
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+// DefaultVolumeSize is the size of the PVCs when the
+// PersistentVolumeClaimSpec is not specified in the CR
+const DefaultVolumeSize = "1Gi"
+
+// consumerCommand keeps the consumer pod running with the volume
+// mounted. The pod terminates promptly on deletion, so the deletion of
+// the pod is dominated by the unmount of the volume.
+var consumerCommand = []string{"sh", "-c", "trap 'exit 0' TERM; while true; do sleep 1; done"}
+
+// terminationGracePeriod is the grace period of the consumer pods
+var terminationGracePeriod = int64(5)
+
+// ClaimName returns the name of the PVC and the consumer pod
+// with the given index
+func ClaimName(cr *perfv1alpha1.PVCLatency, index int) string {
+	return fmt.Sprintf("%s-%d", cr.Name, index)
+}
+
+// NewPersistentVolumeClaim creates the PVC with the given index from the
+// PVC template and the StorageClass of the CR
+func NewPersistentVolumeClaim(cr *perfv1alpha1.PVCLatency, index int) *corev1.PersistentVolumeClaim {
+	var spec corev1.PersistentVolumeClaimSpec
+	if cr.Spec.PersistentVolumeClaimSpec != nil {
+		cr.Spec.PersistentVolumeClaimSpec.DeepCopyInto(&spec)
+	} else {
+		spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+		spec.Resources.Requests = corev1.ResourceList{
+			corev1.ResourceStorage: resource.MustParse(DefaultVolumeSize),
+		}
+	}
+	if cr.Spec.StorageClassName != "" {
+		storageClassName := cr.Spec.StorageClassName
+		spec.StorageClassName = &storageClassName
+	}
+
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ClaimName(cr, index),
+			Namespace: cr.Namespace,
+			Labels:    labels(cr),
+		},
+		Spec: spec,
+	}
+}
+
+// NewPod creates the pod consuming the PVC with the given index
+func NewPod(cr *perfv1alpha1.PVCLatency, index int) *corev1.Pod {
+	podLabels := labels(cr)
+	for key, value := range cr.Spec.PodConfig.PodLabels {
+		podLabels[key] = value
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ClaimName(cr, index),
+			Namespace:   cr.Namespace,
+			Labels:      podLabels,
+			Annotations: cr.Spec.PodConfig.Annotations,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:            "consumer",
+					Image:           cr.Spec.Image.Name,
+					ImagePullPolicy: corev1.PullPolicy(cr.Spec.Image.PullPolicy),
+					Command:         consumerCommand,
+					Resources:       cr.Spec.PodConfig.Resources,
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "data", MountPath: "/data"},
+					},
+				},
+			},
+			ImagePullSecrets: []corev1.LocalObjectReference{
+				{Name: cr.Spec.Image.PullSecret},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: ClaimName(cr, index),
+						},
+					},
+				},
+			},
+			RestartPolicy:                 corev1.RestartPolicyNever,
+			TerminationGracePeriodSeconds: &terminationGracePeriod,
+			Affinity:                      cr.Spec.PodConfig.PodScheduling.Affinity,
+			Tolerations:                   cr.Spec.PodConfig.PodScheduling.Tolerations,
+			NodeSelector:                  cr.Spec.PodConfig.PodScheduling.NodeSelector,
+			NodeName:                      cr.Spec.PodConfig.PodScheduling.NodeName,
+		},
+	}
+}
+
+func labels(cr *perfv1alpha1.PVCLatency) map[string]string {
+	return map[string]string{
+		"kubestone.xridge.io/app":     "pvclatency",
+		"kubestone.xridge.io/cr-name": cr.Name,
+	}
+}
+
+// IsCrValid validates the given CR and raises error if semantic errors detected
+// For PVCLatency, the StorageClass name and the count are checked
+func IsCrValid(cr *perfv1alpha1.PVCLatency) (valid bool, err error) {
+	if cr.Spec.VolumeCount() < 1 {
+		return false, errors.New("count must be at least 1")
+	}
+	if cr.Spec.StorageClassName != "" {
+		if errs := validation.IsDNS1123Subdomain(cr.Spec.StorageClassName); len(errs) > 0 {
+			return false, fmt.Errorf("invalid storageClassName %q: %s",
+				cr.Spec.StorageClassName, strings.Join(errs, ", "))
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pvclatency
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("pvc latency volumes", func() {
+	var cr perfv1alpha1.PVCLatency
+
+	BeforeEach(func() {
+		cr = perfv1alpha1.PVCLatency{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc", Namespace: "storage"},
+			Spec: perfv1alpha1.PVCLatencySpec{
+				StorageClassName: "fast",
+				Image: perfv1alpha1.ImageSpec{
+					Name:       "busybox:test",
+					PullPolicy: "IfNotPresent",
+				},
+				PodConfig: perfv1alpha1.PodConfigurationSpec{
+					PodLabels: map[string]string{"team": "storage"},
+					PodScheduling: perfv1alpha1.PodSchedulingSpec{
+						NodeName: "node-a",
+					},
+				},
+			},
+		}
+	})
+
+	Context("without PVC template", func() {
+		It("should request the default size from the StorageClass", func() {
+			pvc := NewPersistentVolumeClaim(&cr, 3)
+			Expect(pvc.Name).To(Equal("pvc-3"))
+			Expect(pvc.Namespace).To(Equal("storage"))
+			Expect(*pvc.Spec.StorageClassName).To(Equal("fast"))
+			Expect(pvc.Spec.AccessModes).To(Equal(
+				[]corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}))
+			Expect(pvc.Spec.Resources.Requests[corev1.ResourceStorage]).To(
+				Equal(resource.MustParse(DefaultVolumeSize)))
+		})
+	})
+
+	Context("with PVC template", func() {
+		BeforeEach(func() {
+			templateClass := "slow"
+			cr.Spec.PersistentVolumeClaimSpec = &corev1.PersistentVolumeClaimSpec{
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+				StorageClassName: &templateClass,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("5Gi")},
+				},
+			}
+		})
+
+		It("should override the StorageClass of the template", func() {
+			pvc := NewPersistentVolumeClaim(&cr, 0)
+			Expect(*pvc.Spec.StorageClassName).To(Equal("fast"))
+			Expect(*cr.Spec.PersistentVolumeClaimSpec.StorageClassName).To(Equal("slow"))
+			Expect(pvc.Spec.AccessModes).To(Equal(
+				[]corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}))
+		})
+
+		It("should keep the StorageClass of the template if not given", func() {
+			cr.Spec.StorageClassName = ""
+			pvc := NewPersistentVolumeClaim(&cr, 0)
+			Expect(*pvc.Spec.StorageClassName).To(Equal("slow"))
+		})
+	})
+
+	Context("consumer pod", func() {
+		It("should mount the PVC of the same index", func() {
+			pod := NewPod(&cr, 1)
+			Expect(pod.Name).To(Equal("pvc-1"))
+			Expect(pod.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("pvc-1"))
+			Expect(pod.Spec.Containers[0].VolumeMounts[0].Name).To(Equal(pod.Spec.Volumes[0].Name))
+		})
+
+		It("should follow the image and pod configuration", func() {
+			pod := NewPod(&cr, 0)
+			Expect(pod.Spec.Containers[0].Image).To(Equal("busybox:test"))
+			Expect(pod.Spec.Containers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+			Expect(pod.Spec.NodeName).To(Equal("node-a"))
+			Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+			Expect(pod.Labels).To(HaveKeyWithValue("team", "storage"))
+			Expect(pod.Labels).To(HaveKeyWithValue("kubestone.xridge.io/cr-name", "pvc"))
+		})
+	})
+
+	Context("validation", func() {
+		It("should accept the CR", func() {
+			Expect(IsCrValid(&cr)).To(BeTrue())
+		})
+
+		It("should reject invalid StorageClass names", func() {
+			cr.Spec.StorageClassName = "Fast_SSD"
+			valid, err := IsCrValid(&cr)
+			Expect(valid).To(BeFalse())
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject zero count", func() {
+			count := int32(0)
+			cr.Spec.Count = &count
+			valid, err := IsCrValid(&cr)
+			Expect(valid).To(BeFalse())
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -17,6 +17,7 @@ limitations under the License.
 package qperf
 
 import (
+	"errors"
 	"strconv"
 
 	batchv1 "k8s.io/api/batch/v1"
@@ -63,3 +64,17 @@ func NewClientJob(cr *perfv1alpha1.Qperf) *batchv1.Job {
 
 	return job
 }
+
+// IsCrValid validates the given CR and raises error if semantic errors detected
+// For qperf, at least one test has to be run
+func IsCrValid(cr *perfv1alpha1.Qperf) (valid bool, err error) {
+	if len(cr.Spec.Tests) == 0 {
+		return false, errors.New("at least one test is required")
+	}
+	for _, test := range cr.Spec.Tests {
+		if test == "" {
+			return false, errors.New("the name of the tests cannot be empty")
+		}
+	}
+	return true, nil
+}
@@ -105,3 +105,16 @@ var _ = Describe("Client Pod", func() {
 		})
 	})
 })
+
+var _ = Describe("qperf validation", func() {
+	It("should accept the tests", func() {
+		cr := ksapi.Qperf{Spec: ksapi.QperfSpec{Tests: []string{"tcp_bw"}}}
+		Expect(IsCrValid(&cr)).To(BeTrue())
+	})
+
+	It("should require at least one test", func() {
+		valid, err := IsCrValid(&ksapi.Qperf{})
+		Expect(valid).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,72 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qperf
+
+import (
+	"bufio"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/results"
+)
+
+func init() {
+	results.Register("Qperf", results.ParserFunc(ParseResults))
+}
+
+var (
+	testRegexp  = regexp.MustCompile(`^(\w+):$`)
+	valueRegexp = regexp.MustCompile(`^\s+(\w+)\s+=\s+([0-9.]+)\s+(\S+)`)
+)
+
+// ParseResults parses the output of the qperf client. Each value is
+// reported as <test>.<name> (e.g. tcp_bw.bw, tcp_lat.latency) in
+// the unit printed by qperf.
+func ParseResults(logs string) (*perfv1alpha1.BenchmarkResults, error) {
+	res := &perfv1alpha1.BenchmarkResults{}
+
+	test := ""
+	scanner := bufio.NewScanner(strings.NewReader(logs))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := testRegexp.FindStringSubmatch(line); match != nil {
+			test = match[1]
+			continue
+		}
+		match := valueRegexp.FindStringSubmatch(line)
+		if match == nil || test == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(match[2], 64)
+		if err != nil {
+			return nil, err
+		}
+		res.AddMetric(test+"."+match[1], value, match[3])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(res.Metrics) == 0 {
+		return nil, errors.New("no test results found in qperf output")
+	}
+
+	return res, nil
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qperf
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const qperfOutput = `tcp_bw:
+    bw  =  1.17 GB/sec
+tcp_lat:
+    latency  =  51.4 us
+`
+
+var _ = Describe("qperf results", func() {
+	Context("with the output of a successful run", func() {
+		results, err := ParseResults(qperfOutput)
+
+		It("should not fail", func() {
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reports the values per test", func() {
+			Expect(results.GetMetric("tcp_bw.bw").Value).To(Equal("1.17"))
+			Expect(results.GetMetric("tcp_bw.bw").Unit).To(Equal("GB/sec"))
+			Expect(results.GetMetric("tcp_lat.latency").Value).To(Equal("51.4"))
+			Expect(results.GetMetric("tcp_lat.latency").Unit).To(Equal("us"))
+		})
+	})
+
+	Context("with unrelated output", func() {
+		It("should fail", func() {
+			_, err := ParseResults("failed to connect\n")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
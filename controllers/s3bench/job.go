@@ -20,10 +20,24 @@ import (
 	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
 	"github.com/xridge/kubestone/pkg/k8s"
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"strconv"
 )
 
+// DefaultImage is the warp image used when the image is not specified in the CR
+var DefaultImage = perfv1alpha1.ImageSpec{
+	Name:       "minio/warp:v0.3.5",
+	PullPolicy: "IfNotPresent",
+}
+
+// Default sets the image of the CR to DefaultImage, if it is not specified
+func Default(cr *perfv1alpha1.S3Bench) {
+	if cr.Spec.Image == (perfv1alpha1.ImageSpec{}) {
+		cr.Spec.Image = DefaultImage
+	}
+}
+
 // NewJob creates a s3bench benchmark job
 func NewJob(cr *perfv1alpha1.S3Bench) *batchv1.Job {
 	objectMeta := metav1.ObjectMeta{
@@ -37,17 +51,28 @@ func NewJob(cr *perfv1alpha1.S3Bench) *batchv1.Job {
 
 	image := cr.Spec.Image
 	if (image == perfv1alpha1.ImageSpec{}) {
-		image = perfv1alpha1.ImageSpec{
-			Name:       "minio/warp:v0.3.5",
-			PullPolicy: "IfNotPresent",
-		}
+		image = DefaultImage
 	}
 
 	job := k8s.NewPerfJob(objectMeta, "s3bench", image, cr.Spec.PodConfig)
 	job.Spec.Template.Spec.Containers[0].Args = s3benchCmdLineArgs
+	job.Spec.Template.Spec.Containers[0].Env = credentialsEnv(&cr.Spec.S3BenchOptions)
 	return job
 }
 
+// credentialsEnv passes the S3 credentials to warp through its environment,
+// so they never show up on the command line
+func credentialsEnv(opts *perfv1alpha1.S3BenchOptions) []corev1.EnvVar {
+	var env []corev1.EnvVar
+	if opts.AccessKey != "" || opts.AccessKeySecretRef != nil {
+		env = append(env, k8s.NewSecretEnvVar("WARP_ACCESS_KEY", opts.AccessKey, opts.AccessKeySecretRef))
+	}
+	if opts.SecretKey != "" || opts.SecretKeySecretRef != nil {
+		env = append(env, k8s.NewSecretEnvVar("WARP_SECRET_KEY", opts.SecretKey, opts.SecretKeySecretRef))
+	}
+	return env
+}
+
 func ProcessS3BenchArgs(spec *perfv1alpha1.S3BenchSpec) []string {
 	var cmdArgs []string
 	cmdArgs = append(cmdArgs, "--host", spec.Host)
@@ -58,8 +83,6 @@ func ProcessS3BenchArgs(spec *perfv1alpha1.S3BenchSpec) []string {
 		checkAndAppendBool(&cmdArgs, opts.NoColor, "--no-color")
 		checkAndAppendBool(&cmdArgs, opts.Debug, "--debug")
 		checkAndAppendBool(&cmdArgs, opts.Debug, "--insecure")
-		checkAndAppendString(&cmdArgs, opts.AccessKey, "--access-key")
-		checkAndAppendString(&cmdArgs, opts.SecretKey, "--secret-key")
 		checkAndAppendBool(&cmdArgs, opts.Tls, "--tls")
 		checkAndAppendString(&cmdArgs, opts.Region, "--region")
 		checkAndAppendBool(&cmdArgs, opts.Encrypt, "--encrypt")
@@ -19,6 +19,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 
 	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
 )
@@ -52,5 +53,56 @@ var _ = Describe("s3bench job", func() {
 					ContainElement("minio-test.minio.svc.sol1.diamanti.com:9000"))
 			})
 		})
+
+		Context("without image", func() {
+			It("should use the default image", func() {
+				Expect(job.Spec.Template.Spec.Containers[0].Image).To(Equal(DefaultImage.Name))
+				Default(&cr)
+				Expect(cr.Spec.Image).To(Equal(DefaultImage))
+			})
+		})
+
+		Context("with credentials", func() {
+			It("should not pass them on the command line", func() {
+				Expect(job.Spec.Template.Spec.Containers[0].Args).NotTo(
+					ContainElement(cr.Spec.S3BenchOptions.AccessKey))
+				Expect(job.Spec.Template.Spec.Containers[0].Args).NotTo(
+					ContainElement(cr.Spec.S3BenchOptions.SecretKey))
+			})
+			It("should pass them in the environment", func() {
+				Expect(job.Spec.Template.Spec.Containers[0].Env).To(ConsistOf(
+					corev1.EnvVar{Name: "WARP_ACCESS_KEY", Value: cr.Spec.S3BenchOptions.AccessKey},
+					corev1.EnvVar{Name: "WARP_SECRET_KEY", Value: cr.Spec.S3BenchOptions.SecretKey},
+				))
+			})
+		})
+	})
+
+	Describe("cr with credential secrets", func() {
+		It("should read the credentials from the secrets", func() {
+			accessKeyRef := &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "minio"},
+				Key:                  "accesskey",
+			}
+			secretKeyRef := &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "minio"},
+				Key:                  "secretkey",
+			}
+			cr := perfv1alpha1.S3Bench{Spec: perfv1alpha1.S3BenchSpec{
+				Mode: "get",
+				Host: "minio:9000",
+				S3BenchOptions: perfv1alpha1.S3BenchOptions{
+					AccessKeySecretRef: accessKeyRef,
+					SecretKeySecretRef: secretKeyRef,
+				},
+			}}
+			env := NewJob(&cr).Spec.Template.Spec.Containers[0].Env
+
+			Expect(env).To(HaveLen(2))
+			Expect(env[0].Name).To(Equal("WARP_ACCESS_KEY"))
+			Expect(env[0].ValueFrom.SecretKeyRef).To(Equal(accessKeyRef))
+			Expect(env[1].Name).To(Equal("WARP_SECRET_KEY"))
+			Expect(env[1].ValueFrom.SecretKeyRef).To(Equal(secretKeyRef))
+		})
 	})
 })
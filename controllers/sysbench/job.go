@@ -17,6 +17,7 @@ limitations under the License.
 package sysbench
 
 import (
+	"errors"
 	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -40,3 +41,12 @@ func NewJob(cr *perfv1alpha1.Sysbench) *batchv1.Job {
 	job.Spec.Template.Spec.Containers[0].Args = sysbenchCmdLineArgs
 	return job
 }
+
+// IsCrValid validates the given CR and raises error if semantic errors detected
+// For sysbench, the test name is checked
+func IsCrValid(cr *perfv1alpha1.Sysbench) (valid bool, err error) {
+	if cr.Spec.TestName == "" {
+		return false, errors.New("the test name is required")
+	}
+	return true, nil
+}
@@ -60,3 +60,16 @@ var _ = Describe("sysbench job", func() {
 		})
 	})
 })
+
+var _ = Describe("sysbench validation", func() {
+	It("should accept the test name", func() {
+		cr := perfv1alpha1.Sysbench{Spec: perfv1alpha1.SysbenchSpec{TestName: "cpu"}}
+		Expect(IsCrValid(&cr)).To(BeTrue())
+	})
+
+	It("should require the test name", func() {
+		valid, err := IsCrValid(&perfv1alpha1.Sysbench{})
+		Expect(valid).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,74 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sysbench
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/results"
+)
+
+func init() {
+	results.Register("Sysbench", results.ParserFunc(ParseResults))
+}
+
+// sysbenchMetric describes a value of the sysbench report
+type sysbenchMetric struct {
+	regexp *regexp.Regexp
+	name   string
+	unit   string
+}
+
+var sysbenchMetrics = []sysbenchMetric{
+	{regexp.MustCompile(`events per second:\s+([0-9.]+)`), "events_per_second", "events/s"},
+	{regexp.MustCompile(`total number of events:\s+([0-9.]+)`), "events", ""},
+	{regexp.MustCompile(`transactions:\s+[0-9]+\s+\(([0-9.]+) per sec\.\)`), "transactions_per_second", "tx/s"},
+	{regexp.MustCompile(`queries:\s+[0-9]+\s+\(([0-9.]+) per sec\.\)`), "queries_per_second", "queries/s"},
+	{regexp.MustCompile(`Total operations:\s+[0-9]+\s+\(([0-9.]+) per second\)`), "operations_per_second", "ops/s"},
+	{regexp.MustCompile(`transferred \(([0-9.]+) MiB/sec\)`), "throughput", "MiB/s"},
+	{regexp.MustCompile(`min:\s+([0-9.]+)`), "latency.min", "ms"},
+	{regexp.MustCompile(`avg:\s+([0-9.]+)`), "latency.avg", "ms"},
+	{regexp.MustCompile(`max:\s+([0-9.]+)`), "latency.max", "ms"},
+	{regexp.MustCompile(`95th percentile:\s+([0-9.]+)`), "latency.p95", "ms"},
+}
+
+// ParseResults parses the report printed by sysbench (cpu, memory,
+// fileio, threads, mutex and oltp tests)
+func ParseResults(logs string) (*perfv1alpha1.BenchmarkResults, error) {
+	res := &perfv1alpha1.BenchmarkResults{}
+
+	for _, metric := range sysbenchMetrics {
+		match := metric.regexp.FindStringSubmatch(logs)
+		if match == nil {
+			continue
+		}
+		value, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return nil, err
+		}
+		res.AddMetric(metric.name, value, metric.unit)
+	}
+
+	if len(res.Metrics) == 0 {
+		return nil, errors.New("no metrics found in sysbench output")
+	}
+
+	return res, nil
+}
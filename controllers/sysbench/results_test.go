@@ -0,0 +1,104 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sysbench
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const cpuOutput = `sysbench 1.0.17 (using system LuaJIT 2.1.0-beta3)
+
+Running the test with following options:
+Number of threads: 1
+Initializing random number generator from current time
+
+Prime numbers limit: 10000
+
+Initializing worker threads...
+
+Threads started!
+
+CPU speed:
+    events per second:  1215.32
+
+General statistics:
+    total time:                          10.0008s
+    total number of events:              12156
+
+Latency (ms):
+         min:                                    0.79
+         avg:                                    0.82
+         max:                                    2.34
+         95th percentile:                        0.87
+         sum:                                 9988.41
+
+Threads fairness:
+    events (avg/stddev):           12156.0000/0.00
+    execution time (avg/stddev):   9.9884/0.00
+`
+
+const oltpOutput = `SQL statistics:
+    queries performed:
+        read:                            140000
+        write:                           40000
+        other:                           20000
+        total:                           200000
+    transactions:                        10000  (330.61 per sec.)
+    queries:                             200000 (6612.20 per sec.)
+    ignored errors:                      0      (0.00 per sec.)
+    reconnects:                          0      (0.00 per sec.)
+`
+
+var _ = Describe("sysbench results", func() {
+	Context("with the output of the cpu test", func() {
+		results, err := ParseResults(cpuOutput)
+
+		It("should not fail", func() {
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reports the events per second", func() {
+			Expect(results.GetMetric("events_per_second").Value).To(Equal("1215.32"))
+			Expect(results.GetMetric("events").Value).To(Equal("12156"))
+		})
+
+		It("reports the latency", func() {
+			Expect(results.GetMetric("latency.min").Value).To(Equal("0.79"))
+			Expect(results.GetMetric("latency.avg").Value).To(Equal("0.82"))
+			Expect(results.GetMetric("latency.max").Value).To(Equal("2.34"))
+			Expect(results.GetMetric("latency.p95").Value).To(Equal("0.87"))
+		})
+	})
+
+	Context("with the output of the oltp test", func() {
+		results, err := ParseResults(oltpOutput)
+
+		It("reports the transactions and queries per second", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.GetMetric("transactions_per_second").Value).To(Equal("330.61"))
+			Expect(results.GetMetric("queries_per_second").Value).To(Equal("6612.2"))
+		})
+	})
+
+	Context("with unrelated output", func() {
+		It("should fail", func() {
+			_, err := ParseResults("FATAL: unknown test\n")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -17,12 +17,14 @@ limitations under the License.
 package ycsbbench
 
 import (
+	"errors"
 	"fmt"
 	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
 	"github.com/xridge/kubestone/pkg/k8s"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sort"
 	"strconv"
 )
 
@@ -45,9 +47,38 @@ func formatArgs(cr *perfv1alpha1.YcsbBench) []string {
 	for key, val := range cr.Spec.Properties {
 		args = append(args, "-p", fmt.Sprintf("%s=%s", key, val))
 	}
+
+	// Secret values are referenced through their environment variable,
+	// which is expanded by the kubelet when the container starts
+	for i, key := range secretPropertyKeys(cr) {
+		args = append(args, "-p", fmt.Sprintf("%s=$(%s)", key, secretPropertyEnvName(i)))
+	}
 	return args
 }
 
+// secretPropertyKeys returns the names of the secret properties in a stable order
+func secretPropertyKeys(cr *perfv1alpha1.YcsbBench) []string {
+	keys := make([]string, 0, len(cr.Spec.SecretProperties))
+	for key := range cr.Spec.SecretProperties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func secretPropertyEnvName(i int) string {
+	return fmt.Sprintf("YCSB_SECRET_PROPERTY_%d", i)
+}
+
+func secretPropertyEnv(cr *perfv1alpha1.YcsbBench) []corev1.EnvVar {
+	var env []corev1.EnvVar
+	for i, key := range secretPropertyKeys(cr) {
+		ref := cr.Spec.SecretProperties[key]
+		env = append(env, k8s.NewSecretEnvVar(secretPropertyEnvName(i), "", &ref))
+	}
+	return env
+}
+
 func NewJob(cr *perfv1alpha1.YcsbBench) *batchv1.Job {
 	objectMeta := metav1.ObjectMeta{
 		Name:      cr.Name,
@@ -55,12 +86,14 @@ func NewJob(cr *perfv1alpha1.YcsbBench) *batchv1.Job {
 	}
 
 	args := formatArgs(cr)
+	env := secretPropertyEnv(cr)
 	initContainer := corev1.Container{
 		Name:            "ycsbbench-load",
 		Image:           cr.Spec.Image.Name,
 		ImagePullPolicy: corev1.PullPolicy(cr.Spec.Image.PullPolicy),
 		Command:         []string{"./bin/ycsb", "load"},
 		Args:            args,
+		Env:             env,
 	}
 	// append([]string{"./bin/ycsb", "load", args},
 	job := k8s.NewPerfJob(objectMeta, "ycsbbench", cr.Spec.Image, cr.Spec.PodConfig)
@@ -69,6 +102,25 @@ func NewJob(cr *perfv1alpha1.YcsbBench) *batchv1.Job {
 
 	job.Spec.Template.Spec.Containers[0].Command = []string{"./bin/ycsb", "run"}
 	job.Spec.Template.Spec.Containers[0].Args = args
+	job.Spec.Template.Spec.Containers[0].Env = env
 
 	return job
 }
+
+// IsCrValid validates the given CR and raises error if semantic errors detected
+// For ycsbbench, the database and workload are required, and the properties
+// cannot be set both in plain text and from Secrets
+func IsCrValid(cr *perfv1alpha1.YcsbBench) (valid bool, err error) {
+	if cr.Spec.Database == "" || cr.Spec.Workload == "" {
+		return false, errors.New("the database and workload are required")
+	}
+	for key, ref := range cr.Spec.SecretProperties {
+		if _, ok := cr.Spec.Properties[key]; ok {
+			return false, fmt.Errorf("property %q is set both in properties and secretProperties", key)
+		}
+		if ref.Name == "" || ref.Key == "" {
+			return false, fmt.Errorf("the name and key of the secret of property %q are required", key)
+		}
+	}
+	return true, nil
+}
@@ -20,6 +20,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 var _ = Describe("ycsbbench job", func() {
@@ -106,6 +107,51 @@ var _ = Describe("ycsbbench job", func() {
 
 		testNewJob(cr, expected_args)
 	})
+
+	Describe("NewJob with secret properties", func() {
+		cr := perfv1alpha1.YcsbBench{
+			Spec: perfv1alpha1.YcsbBenchSpec{
+				Image: perfv1alpha1.ImageSpec{
+					Name: "diamantisolutions/ycsb:latest",
+				},
+				Database: "redis",
+				Workload: "a",
+				Properties: map[string]string{
+					"redis.host": "10.0.0.1",
+				},
+				SecretProperties: map[string]corev1.SecretKeySelector{
+					"redis.password": {
+						LocalObjectReference: corev1.LocalObjectReference{Name: "redis"},
+						Key:                  "password",
+					},
+				},
+				PodConfig: perfv1alpha1.PodConfigurationSpec{},
+			},
+		}
+		expected_args := []string{
+			"redis",
+			"-P",
+			"workloads/workloada",
+			"-p",
+			"redis.host=10.0.0.1",
+			"-p",
+			"redis.password=$(YCSB_SECRET_PROPERTY_0)",
+		}
+
+		testNewJob(cr, expected_args)
+
+		job := NewJob(&cr)
+		It("should read the secret properties from the secret", func() {
+			containers := append(job.Spec.Template.Spec.InitContainers,
+				job.Spec.Template.Spec.Containers...)
+			for _, container := range containers {
+				Expect(container.Env).To(HaveLen(1))
+				Expect(container.Env[0].Name).To(Equal("YCSB_SECRET_PROPERTY_0"))
+				Expect(container.Env[0].ValueFrom.SecretKeyRef.Name).To(Equal("redis"))
+				Expect(container.Env[0].ValueFrom.SecretKeyRef.Key).To(Equal("password"))
+			}
+		})
+	})
 })
 
 func testNewJob(cr perfv1alpha1.YcsbBench, expected_args []string) {
@@ -147,3 +193,43 @@ func testNewJob(cr perfv1alpha1.YcsbBench, expected_args []string) {
 		})
 	})
 }
+
+var _ = Describe("ycsbbench validation", func() {
+	var cr perfv1alpha1.YcsbBench
+
+	BeforeEach(func() {
+		cr = perfv1alpha1.YcsbBench{
+			Spec: perfv1alpha1.YcsbBenchSpec{
+				Database: "redis",
+				Workload: "a",
+				Properties: map[string]string{
+					"redis.host": "10.0.0.1",
+				},
+				SecretProperties: map[string]corev1.SecretKeySelector{
+					"redis.password": {
+						LocalObjectReference: corev1.LocalObjectReference{Name: "redis"},
+						Key:                  "password",
+					},
+				},
+			},
+		}
+	})
+
+	It("should accept the properties", func() {
+		Expect(IsCrValid(&cr)).To(BeTrue())
+	})
+
+	It("should require the workload", func() {
+		cr.Spec.Workload = ""
+		valid, err := IsCrValid(&cr)
+		Expect(valid).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject properties set both in plain text and from secrets", func() {
+		cr.Spec.Properties["redis.password"] = "secret"
+		valid, err := IsCrValid(&cr)
+		Expect(valid).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("redis.password")))
+	})
+})
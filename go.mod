@@ -10,6 +10,9 @@ require (
 	github.com/golangci/golangci-lint v1.21.0 // indirect
 	github.com/onsi/ginkgo v1.10.1
 	github.com/onsi/gomega v1.7.0
+	github.com/prometheus/client_golang v0.9.3
+	github.com/robfig/cron/v3 v3.0.1
+	github.com/spf13/cobra v0.0.5
 	k8s.io/api v0.0.0-20190409021203-6e4e0e4f393b
 	k8s.io/apimachinery v0.0.0-20190404173353-6a84e37a896d
 	k8s.io/client-go v11.0.1-0.20190409021438-1a26190bd76a+incompatible
@@ -19,6 +19,7 @@ package main
 import (
 	"flag"
 	"os"
+	"strings"
 
 	"github.com/xridge/kubestone/controllers/esrally"
 	"github.com/xridge/kubestone/controllers/nighthawk"
@@ -37,16 +38,23 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/controllers/benchmarkfanout"
+	"github.com/xridge/kubestone/controllers/benchmarkschedule"
+	"github.com/xridge/kubestone/controllers/benchmarksuite"
+	"github.com/xridge/kubestone/controllers/benchmarksweep"
 	"github.com/xridge/kubestone/controllers/drill"
 	"github.com/xridge/kubestone/controllers/fio"
 	"github.com/xridge/kubestone/controllers/ioping"
 	"github.com/xridge/kubestone/controllers/iperf3"
+	"github.com/xridge/kubestone/controllers/iperf3matrix"
 	"github.com/xridge/kubestone/controllers/kafkabench"
 	"github.com/xridge/kubestone/controllers/pgbench"
+	"github.com/xridge/kubestone/controllers/pvclatency"
 	"github.com/xridge/kubestone/controllers/qperf"
 	"github.com/xridge/kubestone/controllers/s3bench"
 	"github.com/xridge/kubestone/controllers/sysbench"
 	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/metrics"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -67,19 +75,36 @@ func init() {
 
 func main() {
 	var metricsAddr string
+	var metricsPodLabels string
 	var enableLeaderElection bool
+	var enableWebhooks bool
+	var webhookPort int
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
+	flag.StringVar(&metricsPodLabels, "metrics-pod-labels", "",
+		"Comma separated list of benchmark pod labels which are added to the published benchmark results.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Enable the defaulting and validating admission webhooks. The serving certificate is read from "+
+			"/tmp/k8s-webhook-server/serving-certs.")
+	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port the admission webhooks are served on.")
 	flag.Parse()
 
 	ctrl.SetLogger(zapr.NewLogger(rootLog))
 
+	if metricsPodLabels != "" {
+		if err := metrics.SetPodLabels(strings.Split(metricsPodLabels, ",")); err != nil {
+			setupLog.Error(err, "Unable to set metrics pod labels")
+			os.Exit(1)
+		}
+	}
+
 	restClientConfig := ctrl.GetConfigOrDie()
 	mgr, err := ctrl.NewManager(restClientConfig, ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: metricsAddr,
 		LeaderElection:     enableLeaderElection,
+		Port:               webhookPort,
 	})
 	if err != nil {
 		setupLog.Error(err, "Unable to start manager")
@@ -200,8 +225,81 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Perfbench")
 		os.Exit(1)
 	}
+	if err = (&benchmarksuite.Reconciler{
+		K8S: k8sAccess,
+		Log: ctrl.Log.WithName("controllers").WithName("BenchmarkSuite"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BenchmarkSuite")
+		os.Exit(1)
+	}
+	if err = (&benchmarkschedule.Reconciler{
+		K8S: k8sAccess,
+		Log: ctrl.Log.WithName("controllers").WithName("BenchmarkSchedule"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BenchmarkSchedule")
+		os.Exit(1)
+	}
+	if err = (&benchmarksweep.Reconciler{
+		K8S: k8sAccess,
+		Log: ctrl.Log.WithName("controllers").WithName("BenchmarkSweep"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BenchmarkSweep")
+		os.Exit(1)
+	}
+	if err = (&benchmarkfanout.Reconciler{
+		K8S: k8sAccess,
+		Log: ctrl.Log.WithName("controllers").WithName("BenchmarkFanout"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BenchmarkFanout")
+		os.Exit(1)
+	}
+	if err = (&iperf3matrix.Reconciler{
+		K8S: k8sAccess,
+		Log: ctrl.Log.WithName("controllers").WithName("Iperf3Matrix"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Iperf3Matrix")
+		os.Exit(1)
+	}
+	if err = (&pvclatency.Reconciler{
+		K8S: k8sAccess,
+		Log: ctrl.Log.WithName("controllers").WithName("PVCLatency"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PVCLatency")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
+	if enableWebhooks {
+		for kind, setupWebhook := range map[string]func(ctrl.Manager) error{
+			"Iperf3":            iperf3.SetupWebhookWithManager,
+			"Fio":               fio.SetupWebhookWithManager,
+			"Sysbench":          sysbench.SetupWebhookWithManager,
+			"Drill":             drill.SetupWebhookWithManager,
+			"Pgbench":           pgbench.SetupWebhookWithManager,
+			"Ioping":            ioping.SetupWebhookWithManager,
+			"Qperf":             qperf.SetupWebhookWithManager,
+			"YcsbBench":         ycsbbench.SetupWebhookWithManager,
+			"OcpLogtest":        ocplogtest.SetupWebhookWithManager,
+			"EsRally":           esrally.SetupWebhookWithManager,
+			"S3Bench":           s3bench.SetupWebhookWithManager,
+			"KafkaBench":        kafkabench.SetupWebhookWithManager,
+			"Osbench":           osbench.SetupWebhookWithManager,
+			"Nighthawk":         nighthawk.SetupWebhookWithManager,
+			"Perfbench":         perfbench.SetupWebhookWithManager,
+			"BenchmarkSuite":    benchmarksuite.SetupWebhookWithManager,
+			"BenchmarkSchedule": benchmarkschedule.SetupWebhookWithManager,
+			"BenchmarkSweep":    benchmarksweep.SetupWebhookWithManager,
+			"BenchmarkFanout":   benchmarkfanout.SetupWebhookWithManager,
+			"Iperf3Matrix":      iperf3matrix.SetupWebhookWithManager,
+			"PVCLatency":        pvclatency.SetupWebhookWithManager,
+		} {
+			if err = setupWebhook(mgr); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", kind)
+				os.Exit(1)
+			}
+		}
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
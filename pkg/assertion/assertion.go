@@ -0,0 +1,135 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package assertion evaluates the expectations declared in the spec of the
+benchmarks on their results.
+*/
+package assertion
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+// valueRegexp splits the expected value into number and optional time unit
+var valueRegexp = regexp.MustCompile(`^\s*([-+]?[0-9.]+(?:[eE][-+]?[0-9]+)?)\s*(ns|us|µs|ms|s)?\s*$`)
+
+// timeUnits contains the length of the time units in nanoseconds
+var timeUnits = map[string]float64{
+	"ns": 1,
+	"us": 1e3,
+	"µs": 1e3,
+	"ms": 1e6,
+	"s":  1e9,
+}
+
+// Evaluate checks the assertions against the results. Assertions on
+// missing metrics do not pass.
+func Evaluate(assertions []perfv1alpha1.Assertion,
+	results *perfv1alpha1.BenchmarkResults) []perfv1alpha1.AssertionResult {
+	outcomes := make([]perfv1alpha1.AssertionResult, 0, len(assertions))
+	for _, assertion := range assertions {
+		outcomes = append(outcomes, evaluate(assertion, results))
+	}
+	return outcomes
+}
+
+func evaluate(assertion perfv1alpha1.Assertion, results *perfv1alpha1.BenchmarkResults) perfv1alpha1.AssertionResult {
+	outcome := perfv1alpha1.AssertionResult{Assertion: assertion}
+
+	var metric *perfv1alpha1.BenchmarkMetric
+	if results != nil {
+		metric = results.GetMetric(assertion.Metric)
+	}
+	if metric == nil {
+		outcome.Message = "metric not found in the results"
+		return outcome
+	}
+	outcome.Observed = metric.Value
+	outcome.Unit = metric.Unit
+
+	observed, err := metric.Float64()
+	if err != nil {
+		outcome.Message = fmt.Sprintf("invalid observed value: %v", err)
+		return outcome
+	}
+	expected, err := ParseValue(assertion.Value, metric.Unit)
+	if err != nil {
+		outcome.Message = err.Error()
+		return outcome
+	}
+
+	switch assertion.Operator {
+	case perfv1alpha1.GreaterThan:
+		outcome.Passed = observed > expected
+	case perfv1alpha1.GreaterThanOrEqual:
+		outcome.Passed = observed >= expected
+	case perfv1alpha1.LessThan:
+		outcome.Passed = observed < expected
+	case perfv1alpha1.LessThanOrEqual:
+		outcome.Passed = observed <= expected
+	case perfv1alpha1.Equal:
+		outcome.Passed = observed == expected
+	default:
+		outcome.Message = fmt.Sprintf("unknown operator %q", assertion.Operator)
+	}
+	return outcome
+}
+
+// ParseValue parses the expected value of an assertion. Durations are
+// converted to the given time unit of the metric.
+func ParseValue(value, unit string) (float64, error) {
+	match := valueRegexp.FindStringSubmatch(value)
+	if match == nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	number, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q: %v", value, err)
+	}
+	if match[2] == "" {
+		return number, nil
+	}
+
+	metricUnit, ok := timeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("duration %q cannot be compared with a metric in %q", value, unit)
+	}
+	return number * timeUnits[match[2]] / metricUnit, nil
+}
+
+// Failed returns the description of the assertions which did not pass
+func Failed(outcomes []perfv1alpha1.AssertionResult) []string {
+	var failed []string
+	for _, outcome := range outcomes {
+		if outcome.Passed {
+			continue
+		}
+		description := fmt.Sprintf("%s %s %s", outcome.Metric, outcome.Operator, outcome.Value)
+		if outcome.Message != "" {
+			description += fmt.Sprintf(" (%s)", outcome.Message)
+		} else {
+			description += fmt.Sprintf(" (observed %s)", strings.TrimSpace(outcome.Observed+" "+outcome.Unit))
+		}
+		failed = append(failed, description)
+	}
+	return failed
+}
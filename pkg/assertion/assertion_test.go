@@ -0,0 +1,89 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assertion
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("assertions", func() {
+	var results *perfv1alpha1.BenchmarkResults
+
+	assert := func(metric string, operator perfv1alpha1.AssertionOperator, value string) perfv1alpha1.AssertionResult {
+		outcomes := Evaluate([]perfv1alpha1.Assertion{{Metric: metric, Operator: operator, Value: value}}, results)
+		Expect(outcomes).To(HaveLen(1))
+		return outcomes[0]
+	}
+
+	BeforeEach(func() {
+		results = &perfv1alpha1.BenchmarkResults{}
+		results.AddMetric("read.iops", 25000, "ops/s")
+		results.AddMetric("receiver.bps", 9.4e9, "bits/s")
+		results.AddMetric("tps", 1400, "tx/s")
+		results.AddMetric("p99", 4200, "us")
+	})
+
+	It("should evaluate the comparisons", func() {
+		Expect(assert("read.iops", perfv1alpha1.GreaterThanOrEqual, "20000").Passed).To(BeTrue())
+		Expect(assert("receiver.bps", perfv1alpha1.GreaterThanOrEqual, "9e9").Passed).To(BeTrue())
+		Expect(assert("tps", perfv1alpha1.GreaterThanOrEqual, "1500").Passed).To(BeFalse())
+		Expect(assert("tps", perfv1alpha1.GreaterThan, "1400").Passed).To(BeFalse())
+		Expect(assert("tps", perfv1alpha1.LessThanOrEqual, "1400").Passed).To(BeTrue())
+		Expect(assert("tps", perfv1alpha1.Equal, "1400").Passed).To(BeTrue())
+	})
+
+	It("should convert durations to the unit of the metric", func() {
+		outcome := assert("p99", perfv1alpha1.LessThan, "5ms")
+		Expect(outcome.Passed).To(BeTrue())
+		Expect(outcome.Observed).To(Equal("4200"))
+		Expect(outcome.Unit).To(Equal("us"))
+		Expect(assert("p99", perfv1alpha1.LessThan, "4000us").Passed).To(BeFalse())
+	})
+
+	It("should fail on missing metrics", func() {
+		outcome := assert("write.iops", perfv1alpha1.GreaterThan, "0")
+		Expect(outcome.Passed).To(BeFalse())
+		Expect(outcome.Message).To(ContainSubstring("not found"))
+		Expect(Evaluate([]perfv1alpha1.Assertion{{Metric: "tps"}}, nil)[0].Passed).To(BeFalse())
+	})
+
+	It("should fail on invalid values", func() {
+		Expect(assert("tps", perfv1alpha1.GreaterThan, "many").Message).To(ContainSubstring("invalid value"))
+		Expect(assert("tps", perfv1alpha1.GreaterThan, "5ms").Message).To(ContainSubstring("cannot be compared"))
+	})
+
+	It("should parse the values", func() {
+		Expect(ParseValue("9e9", "bits/s")).To(Equal(9e9))
+		Expect(ParseValue("1.5 s", "ms")).To(Equal(1500.0))
+		Expect(ParseValue("-3", "")).To(Equal(-3.0))
+	})
+
+	It("should describe the failed assertions", func() {
+		outcomes := Evaluate([]perfv1alpha1.Assertion{
+			{Metric: "tps", Operator: perfv1alpha1.GreaterThanOrEqual, Value: "1500"},
+			{Metric: "read.iops", Operator: perfv1alpha1.GreaterThanOrEqual, Value: "20000"},
+			{Metric: "missing", Operator: perfv1alpha1.LessThan, Value: "1"},
+		}, results)
+		Expect(Failed(outcomes)).To(Equal([]string{
+			"tps >= 1500 (observed 1400 tx/s)",
+			"missing < 1 (metric not found in the results)",
+		}))
+	})
+})
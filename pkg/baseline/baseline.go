@@ -0,0 +1,168 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package baseline compares the results of benchmarks with the results of
+earlier runs marked as baseline, and detects the regressions.
+*/
+package baseline
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+const (
+	// Label marks a BenchmarkResult as the baseline of the key in its value
+	Label = "kubestone.xridge.io/baseline"
+
+	// DefaultTolerancePercent is the largest accepted regression of the
+	// metrics without explicit tolerance
+	DefaultTolerancePercent = 5
+)
+
+// lowerIsBetterUnits are the units of the latency-like metrics and of the
+// percentages (e.g. CPU usage, packet loss), which are better when lower
+var lowerIsBetterUnits = map[string]bool{"ns": true, "us": true, "ms": true, "s": true, "%": true}
+
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=benchmarkresults,verbs=get;list;watch
+
+// Find returns the baseline of the key in the namespace: the most recent
+// BenchmarkResult with the baseline label, or nil if there is none
+func Find(ctx context.Context, c client.Client, namespace, key string) (*perfv1alpha1.BenchmarkResult, error) {
+	var list perfv1alpha1.BenchmarkResultList
+	if err := c.List(ctx, &list, client.InNamespace(namespace),
+		client.MatchingLabels{Label: key}); err != nil {
+		return nil, err
+	}
+
+	var latest *perfv1alpha1.BenchmarkResult
+	for i := range list.Items {
+		if latest == nil || completionTime(&list.Items[i]).After(completionTime(latest)) {
+			latest = &list.Items[i]
+		}
+	}
+	return latest, nil
+}
+
+// completionTime returns the completion time of the recorded run,
+// or the creation time of the record if not available
+func completionTime(result *perfv1alpha1.BenchmarkResult) time.Time {
+	if result.Spec.CompletionTime != nil {
+		return result.Spec.CompletionTime.Time
+	}
+	return result.CreationTimestamp.Time
+}
+
+// Compare compares the metrics of the results with the metrics of the
+// baseline. Only the metrics present in both are compared.
+func Compare(spec *perfv1alpha1.BaselineSpec, baseline *perfv1alpha1.BenchmarkResult,
+	results *perfv1alpha1.BenchmarkResults) *perfv1alpha1.BaselineComparison {
+	comparison := &perfv1alpha1.BaselineComparison{Key: spec.Key}
+	if baseline == nil {
+		return comparison
+	}
+	comparison.BaselineResult = baseline.Name
+	if baseline.Spec.Results == nil || results == nil {
+		return comparison
+	}
+
+	for i := range results.Metrics {
+		metric := &results.Metrics[i]
+		baselineMetric := baseline.Spec.Results.GetMetric(metric.Name)
+		override := metricTolerance(spec, metric.Name)
+		if baselineMetric == nil || (override != nil && override.Ignore) {
+			continue
+		}
+		value, err := metric.Float64()
+		if err != nil {
+			continue
+		}
+		baselineValue, err := baselineMetric.Float64()
+		if err != nil {
+			continue
+		}
+
+		compared := perfv1alpha1.MetricComparison{
+			Name:             metric.Name,
+			Unit:             metric.Unit,
+			Baseline:         baselineMetric.Value,
+			Value:            metric.Value,
+			TolerancePercent: tolerancePercent(spec, override),
+			Direction:        direction(metric, override),
+		}
+		if baselineValue != 0 {
+			delta := (value - baselineValue) / baselineValue * 100
+			compared.DeltaPercent = strconv.FormatFloat(delta, 'f', 2, 64)
+			if compared.Direction == perfv1alpha1.LowerIsBetter {
+				delta = -delta
+			}
+			compared.Regressed = delta < -float64(compared.TolerancePercent)
+		}
+		comparison.Metrics = append(comparison.Metrics, compared)
+	}
+	return comparison
+}
+
+// Regressions returns the names of the regressed metrics
+func Regressions(comparison *perfv1alpha1.BaselineComparison) []string {
+	var names []string
+	for _, metric := range comparison.Metrics {
+		if metric.Regressed {
+			names = append(names, metric.Name)
+		}
+	}
+	return names
+}
+
+// metricTolerance returns the settings of the metric, or nil if the metric
+// has no explicit settings
+func metricTolerance(spec *perfv1alpha1.BaselineSpec, name string) *perfv1alpha1.MetricTolerance {
+	for i := range spec.Metrics {
+		if spec.Metrics[i].Name == name {
+			return &spec.Metrics[i]
+		}
+	}
+	return nil
+}
+
+// tolerancePercent returns the tolerance of the metric
+func tolerancePercent(spec *perfv1alpha1.BaselineSpec, override *perfv1alpha1.MetricTolerance) int32 {
+	switch {
+	case override != nil && override.TolerancePercent != nil:
+		return *override.TolerancePercent
+	case spec.TolerancePercent != nil:
+		return *spec.TolerancePercent
+	default:
+		return DefaultTolerancePercent
+	}
+}
+
+// direction returns whether the higher or lower values of the metric are better
+func direction(metric *perfv1alpha1.BenchmarkMetric, override *perfv1alpha1.MetricTolerance) perfv1alpha1.MetricDirection {
+	if override != nil && override.Direction != "" {
+		return override.Direction
+	}
+	if lowerIsBetterUnits[metric.Unit] {
+		return perfv1alpha1.LowerIsBetter
+	}
+	return perfv1alpha1.HigherIsBetter
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package baseline
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("baseline", func() {
+	var spec *perfv1alpha1.BaselineSpec
+	var baseline *perfv1alpha1.BenchmarkResult
+	var results *perfv1alpha1.BenchmarkResults
+
+	newResult := func(name, key string, completed time.Time) *perfv1alpha1.BenchmarkResult {
+		completionTime := metav1.NewTime(completed)
+		result := &perfv1alpha1.BenchmarkResult{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kubestone"},
+			Spec: perfv1alpha1.BenchmarkResultSpec{
+				CompletionTime: &completionTime,
+				Results:        &perfv1alpha1.BenchmarkResults{},
+			},
+		}
+		if key != "" {
+			result.Labels = map[string]string{Label: key}
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		spec = &perfv1alpha1.BaselineSpec{Key: "fio-randread"}
+		baseline = newResult("fio-baseline", "fio-randread", time.Now())
+		baseline.Spec.Results.AddMetric("read.iops", 20000, "ops/s")
+		baseline.Spec.Results.AddMetric("read.clat.p99", 1000, "us")
+		baseline.Spec.Results.AddMetric("read.bw", 0, "bytes/s")
+
+		results = &perfv1alpha1.BenchmarkResults{}
+		results.AddMetric("read.iops", 19500, "ops/s")
+		results.AddMetric("read.clat.p99", 1200, "us")
+		results.AddMetric("read.bw", 100, "bytes/s")
+		results.AddMetric("write.iops", 100, "ops/s")
+	})
+
+	It("should find the most recent baseline of the key", func() {
+		scheme := runtime.NewScheme()
+		Expect(perfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		older := newResult("fio-older", "fio-randread", time.Now().Add(-time.Hour))
+		other := newResult("fio-other", "fio-seqread", time.Now().Add(time.Hour))
+		unlabelled := newResult("fio-unlabelled", "", time.Now().Add(time.Hour))
+		c := fake.NewFakeClientWithScheme(scheme, older, baseline, other, unlabelled)
+
+		found, err := Find(context.Background(), c, "kubestone", "fio-randread")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.Name).To(Equal("fio-baseline"))
+
+		found, err = Find(context.Background(), c, "kubestone", "missing")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeNil())
+	})
+
+	It("should compare the metrics present in both", func() {
+		comparison := Compare(spec, baseline, results)
+		Expect(comparison.Key).To(Equal("fio-randread"))
+		Expect(comparison.BaselineResult).To(Equal("fio-baseline"))
+		Expect(comparison.Metrics).To(HaveLen(3))
+
+		iops := comparison.Metrics[0]
+		Expect(iops.Name).To(Equal("read.iops"))
+		Expect(iops.Baseline).To(Equal("20000"))
+		Expect(iops.Value).To(Equal("19500"))
+		Expect(iops.DeltaPercent).To(Equal("-2.50"))
+		Expect(iops.TolerancePercent).To(Equal(int32(DefaultTolerancePercent)))
+		Expect(iops.Direction).To(Equal(perfv1alpha1.HigherIsBetter))
+		Expect(iops.Regressed).To(BeFalse())
+	})
+
+	It("should consider higher latencies as regression", func() {
+		comparison := Compare(spec, baseline, results)
+		latency := comparison.Metrics[1]
+		Expect(latency.Direction).To(Equal(perfv1alpha1.LowerIsBetter))
+		Expect(latency.DeltaPercent).To(Equal("20.00"))
+		Expect(latency.Regressed).To(BeTrue())
+		Expect(comparison.IsRegressed()).To(BeTrue())
+		Expect(Regressions(comparison)).To(Equal([]string{"read.clat.p99"}))
+	})
+
+	It("should not compute the delta from zero baseline values", func() {
+		comparison := Compare(spec, baseline, results)
+		Expect(comparison.Metrics[2].DeltaPercent).To(BeEmpty())
+		Expect(comparison.Metrics[2].Regressed).To(BeFalse())
+	})
+
+	It("should apply the tolerance and the overrides of the metrics", func() {
+		tolerance, latencyTolerance := int32(1), int32(25)
+		spec.TolerancePercent = &tolerance
+		spec.Metrics = []perfv1alpha1.MetricTolerance{
+			{Name: "read.clat.p99", TolerancePercent: &latencyTolerance},
+			{Name: "read.bw", Ignore: true},
+		}
+		comparison := Compare(spec, baseline, results)
+		Expect(comparison.Metrics).To(HaveLen(2))
+		Expect(comparison.Metrics[0].Regressed).To(BeTrue())
+		Expect(comparison.Metrics[1].TolerancePercent).To(Equal(int32(25)))
+		Expect(comparison.Metrics[1].Regressed).To(BeFalse())
+	})
+
+	It("should honour the direction of the metrics", func() {
+		spec.Metrics = []perfv1alpha1.MetricTolerance{
+			{Name: "read.iops", Direction: perfv1alpha1.LowerIsBetter},
+		}
+		comparison := Compare(spec, baseline, results)
+		Expect(comparison.Metrics[0].Regressed).To(BeFalse())
+	})
+
+	It("should compare nothing without baseline", func() {
+		comparison := Compare(spec, nil, results)
+		Expect(comparison.BaselineResult).To(BeEmpty())
+		Expect(comparison.Metrics).To(BeEmpty())
+		Expect(comparison.IsRegressed()).To(BeFalse())
+	})
+})
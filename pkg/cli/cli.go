@@ -0,0 +1,217 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package cli implements the commands of the kubectl-kubestone plugin.
+The plugin runs benchmarks from templates, follows their progress and
+logs, and reports and compares their results.
+*/
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/template"
+)
+
+// pollInterval is the time between the checks of the benchmarks
+// followed by the commands
+var pollInterval = 2 * time.Second
+
+// Options contains the settings shared by the commands
+type Options struct {
+	// Kubeconfig is the path of the kubeconfig file. The default
+	// loading rules of kubectl are used when it is empty.
+	Kubeconfig string
+	// Context is the kubeconfig context to use
+	Context string
+	// Namespace of the benchmarks. Defaults to the namespace of the context.
+	Namespace string
+
+	// Out receives the output of the commands
+	Out io.Writer
+	// ErrOut receives the progress and warning messages
+	ErrOut io.Writer
+
+	access    *k8s.Access
+	mapper    meta.RESTMapper
+	namespace string
+}
+
+// NewRootCommand creates the kubectl-kubestone command with its subcommands
+func NewRootCommand(out, errOut io.Writer) *cobra.Command {
+	o := &Options{Out: out, ErrOut: errOut}
+	cmd := &cobra.Command{
+		Use:          "kubectl-kubestone",
+		Short:        "Run Kubestone benchmarks and report their results",
+		SilenceUsage: true,
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&o.Kubeconfig, "kubeconfig", "", "Path to the kubeconfig file")
+	flags.StringVar(&o.Context, "context", "", "The kubeconfig context to use")
+	flags.StringVarP(&o.Namespace, "namespace", "n", "", "The namespace of the benchmarks")
+
+	cmd.AddCommand(
+		newRunCommand(o),
+		newStatusCommand(o),
+		newLogsCommand(o),
+		newResultsCommand(o),
+		newCompareCommand(o),
+	)
+	return cmd
+}
+
+// NewScheme returns the scheme of the objects used by the plugin
+func NewScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = k8sscheme.AddToScheme(scheme)
+	_ = perfv1alpha1.AddToScheme(scheme)
+	return scheme
+}
+
+// Access returns the clients of the cluster and the namespace of the
+// benchmarks. The clients are created on the first call.
+func (o *Options) Access() (*k8s.Access, string, error) {
+	if o.access != nil {
+		return o.access, o.namespace, nil
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.Kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: o.Context}
+	overrides.Context.Namespace = o.Namespace
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, "", err
+	}
+
+	scheme := NewScheme()
+	c, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, "", err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, "", err
+	}
+	mapper, err := apiutil.NewDiscoveryRESTMapper(config)
+	if err != nil {
+		return nil, "", err
+	}
+
+	o.access = &k8s.Access{Client: c, Clientset: clientset, Scheme: scheme}
+	o.mapper = mapper
+	o.namespace = namespace
+	return o.access, o.namespace, nil
+}
+
+// resolveKind returns the kind matching the name given on the command
+// line. The kind or its plural resource name is accepted in any case
+// (e.g. Fio, fio, fios). The names are resolved by the RESTMapper, as
+// the plurals of the kinds are not always guessable (e.g. qperves).
+func resolveKind(mapper meta.RESTMapper, name string) (string, error) {
+	gvk, err := mapper.KindFor(perfv1alpha1.GroupVersion.WithResource(strings.ToLower(name)))
+	if err != nil {
+		return "", fmt.Errorf("unknown benchmark kind %q", name)
+	}
+	return gvk.Kind, nil
+}
+
+// parseReference splits a KIND/NAME reference given on the command line
+func parseReference(mapper meta.RESTMapper, reference string) (kind, name string, err error) {
+	parts := strings.Split(reference, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid reference %q, expected KIND/NAME (e.g. fio/fio-sample)", reference)
+	}
+	kind, err = resolveKind(mapper, parts[0])
+	if err != nil {
+		return "", "", err
+	}
+	return kind, parts[1], nil
+}
+
+// getBenchmark reads the benchmark with the given kind and name
+func getBenchmark(ctx context.Context, access *k8s.Access, kind string,
+	key types.NamespacedName) (perfv1alpha1.Benchmark, error) {
+	prototype, ok := template.Kinds(access.Scheme)[kind]
+	if !ok {
+		return nil, fmt.Errorf("%s is not a benchmark kind", kind)
+	}
+	benchmark := prototype.DeepCopyObject().(perfv1alpha1.Benchmark)
+	if err := access.Client.Get(ctx, key, benchmark); err != nil {
+		return nil, err
+	}
+	return benchmark, nil
+}
+
+// run is the outcome of a benchmark run, read either from the custom
+// resource of the benchmark or from its BenchmarkResult
+type run struct {
+	Name    string
+	Phase   perfv1alpha1.BenchmarkPhase
+	Results *perfv1alpha1.BenchmarkResults
+}
+
+// getRun reads the outcome of the benchmark run given by the KIND/NAME reference
+func (o *Options) getRun(ctx context.Context, reference string) (*run, error) {
+	access, namespace, err := o.Access()
+	if err != nil {
+		return nil, err
+	}
+	kind, name, err := parseReference(o.mapper, reference)
+	if err != nil {
+		return nil, err
+	}
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+
+	if kind == "BenchmarkResult" {
+		var result perfv1alpha1.BenchmarkResult
+		if err := access.Client.Get(ctx, key, &result); err != nil {
+			return nil, err
+		}
+		return &run{Name: reference, Phase: result.Spec.Phase, Results: result.Spec.Results}, nil
+	}
+
+	benchmark, err := getBenchmark(ctx, access, kind, key)
+	if err != nil {
+		return nil, err
+	}
+	status := benchmark.GetBenchmarkStatus()
+	return &run{Name: reference, Phase: status.Phase, Results: status.Results}, nil
+}
@@ -0,0 +1,385 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+)
+
+const iperf3Template = `
+apiVersion: perf.kubestone.xridge.io/v1alpha1
+kind: Iperf3
+metadata:
+  name: iperf3-sample
+  labels:
+    team: network
+spec:
+  image:
+    name: xridge/iperf3:3.7.0
+  clientConfiguration:
+    cmdLineArgs: --time 10
+  udp: false
+`
+
+// crdRESTMapper returns a RESTMapper of the kinds and plurals
+// declared by the CustomResourceDefinitions of kubestone
+func crdRESTMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{perfv1alpha1.GroupVersion})
+	files, err := filepath.Glob("../../config/crd/bases/*.yaml")
+	Expect(err).NotTo(HaveOccurred())
+	Expect(files).NotTo(BeEmpty())
+	for _, file := range files {
+		manifest, err := os.Open(file)
+		Expect(err).NotTo(HaveOccurred())
+		// The manifests start with an empty document
+		var crd unstructured.Unstructured
+		decoder := yaml.NewYAMLOrJSONDecoder(manifest, 4096)
+		for crd.Object == nil {
+			Expect(decoder.Decode(&crd.Object)).To(Succeed())
+		}
+		manifest.Close()
+
+		kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+		plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+		mapper.AddSpecific(perfv1alpha1.GroupVersion.WithKind(kind),
+			perfv1alpha1.GroupVersion.WithResource(plural),
+			perfv1alpha1.GroupVersion.WithResource(strings.ToLower(kind)), meta.RESTScopeNamespace)
+	}
+	return mapper
+}
+
+var _ = Describe("kubectl-kubestone", func() {
+	var o *Options
+	var out, errOut bytes.Buffer
+	var scheme *runtime.Scheme
+	var mapper meta.RESTMapper
+	var ctx = context.Background()
+
+	newOptions := func(objects ...runtime.Object) {
+		out.Reset()
+		errOut.Reset()
+		o = &Options{
+			Out:    &out,
+			ErrOut: &errOut,
+			access: &k8s.Access{
+				Client: fake.NewFakeClientWithScheme(scheme, objects...),
+				Scheme: scheme,
+			},
+			mapper:    mapper,
+			namespace: "bench",
+		}
+	}
+
+	newFio := func(name string, phase perfv1alpha1.BenchmarkPhase, iops string) *perfv1alpha1.Fio {
+		fio := &perfv1alpha1.Fio{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "bench",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+		}}
+		fio.Status.Phase = phase
+		if iops != "" {
+			fio.Status.Results = &perfv1alpha1.BenchmarkResults{
+				Metrics: []perfv1alpha1.BenchmarkMetric{{Name: "read.iops", Value: iops, Unit: "ops/s"}},
+			}
+		}
+		return fio
+	}
+
+	BeforeEach(func() {
+		scheme = NewScheme()
+		if mapper == nil {
+			mapper = crdRESTMapper()
+		}
+		pollInterval = 10 * time.Millisecond
+		newOptions()
+	})
+
+	Context("resolving kinds", func() {
+		It("should accept kinds and plurals in any case", func() {
+			for _, name := range []string{"Fio", "fio", "fios", "IPERF3", "benchmarksuites", "benchmarkresult"} {
+				_, err := resolveKind(mapper, name)
+				Expect(err).NotTo(HaveOccurred(), name)
+			}
+			kind, _ := resolveKind(mapper, "esrallies")
+			Expect(kind).To(Equal("EsRally"))
+		})
+
+		It("should accept the plurals which cannot be guessed", func() {
+			kind, err := resolveKind(mapper, "qperves")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kind).To(Equal("Qperf"))
+			kind, err = resolveKind(mapper, "iperf3matrixes")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kind).To(Equal("Iperf3Matrix"))
+		})
+
+		It("should reject unknown kinds", func() {
+			_, err := resolveKind(mapper, "pod")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should parse KIND/NAME references", func() {
+			kind, name, err := parseReference(mapper, "fio/fio-sample")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kind).To(Equal("Fio"))
+			Expect(name).To(Equal("fio-sample"))
+
+			for _, reference := range []string{"fio", "fio/", "/fio-sample", "fio/a/b"} {
+				_, _, err := parseReference(mapper, reference)
+				Expect(err).To(HaveOccurred(), reference)
+			}
+		})
+	})
+
+	Context("running a benchmark", func() {
+		It("should create the benchmark with the settings applied", func() {
+			runOptions := &RunOptions{
+				Filename: "-",
+				Output:   OutputTable,
+				Values:   []string{"udp=true", "clientConfiguration.cmdLineArgs=--time 30"},
+			}
+			Expect(o.Run(runOptions, strings.NewReader(iperf3Template))).To(Succeed())
+			Expect(errOut.String()).To(Equal("iperf3/iperf3-sample created\n"))
+
+			var cr perfv1alpha1.Iperf3
+			key := types.NamespacedName{Namespace: "bench", Name: "iperf3-sample"}
+			Expect(o.access.Client.Get(ctx, key, &cr)).To(Succeed())
+			Expect(cr.Spec.UDP).To(BeTrue())
+			Expect(cr.Spec.ClientConfiguration.CmdLineArgs).To(Equal("--time 30"))
+			Expect(cr.Spec.Image.Name).To(Equal("xridge/iperf3:3.7.0"))
+			Expect(cr.Labels).To(HaveKeyWithValue("team", "network"))
+		})
+
+		It("should use the given name", func() {
+			runOptions := &RunOptions{Filename: "-", Name: "iperf3-udp", Output: OutputTable}
+			Expect(o.Run(runOptions, strings.NewReader(iperf3Template))).To(Succeed())
+
+			var cr perfv1alpha1.Iperf3
+			key := types.NamespacedName{Namespace: "bench", Name: "iperf3-udp"}
+			Expect(o.access.Client.Get(ctx, key, &cr)).To(Succeed())
+		})
+
+		It("should reject invalid settings and templates", func() {
+			runOptions := &RunOptions{Filename: "-", Values: []string{"udp"}, Output: OutputTable}
+			Expect(o.Run(runOptions, strings.NewReader(iperf3Template))).NotTo(Succeed())
+
+			runOptions = &RunOptions{Filename: "-", Values: []string{"udp=maybe"}, Output: OutputTable}
+			Expect(o.Run(runOptions, strings.NewReader(iperf3Template))).NotTo(Succeed())
+
+			runOptions = &RunOptions{Filename: "-", Output: OutputTable}
+			pod := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: pod\n"
+			Expect(o.Run(runOptions, strings.NewReader(pod))).NotTo(Succeed())
+		})
+
+		It("should wait for the completion and print the results", func() {
+			var err error
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				runOptions := &RunOptions{Filename: "-", Wait: true, Output: OutputCSV}
+				err = o.Run(runOptions, strings.NewReader(iperf3Template))
+			}()
+
+			var cr perfv1alpha1.Iperf3
+			key := types.NamespacedName{Namespace: "bench", Name: "iperf3-sample"}
+			Eventually(func() error {
+				return o.access.Client.Get(ctx, key, &cr)
+			}).Should(Succeed())
+			cr.Status.SetPhase(perfv1alpha1.BenchmarkSucceeded, "JobSucceeded", "")
+			cr.Status.Results = &perfv1alpha1.BenchmarkResults{
+				Metrics: []perfv1alpha1.BenchmarkMetric{{Name: "bandwidth", Value: "940", Unit: "Mbit/s"}},
+			}
+			Expect(o.access.Client.Status().Update(ctx, &cr)).To(Succeed())
+
+			wg.Wait()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out.String()).To(Equal("metric,value,unit\nbandwidth,940,Mbit/s\n"))
+			Expect(errOut.String()).To(ContainSubstring(" Succeeded\n"))
+		})
+
+		It("should fail if the benchmark fails", func() {
+			var err error
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				runOptions := &RunOptions{Filename: "-", Wait: true, Output: OutputTable}
+				err = o.Run(runOptions, strings.NewReader(iperf3Template))
+			}()
+
+			var cr perfv1alpha1.Iperf3
+			key := types.NamespacedName{Namespace: "bench", Name: "iperf3-sample"}
+			Eventually(func() error {
+				return o.access.Client.Get(ctx, key, &cr)
+			}).Should(Succeed())
+			cr.Status.SetPhase(perfv1alpha1.BenchmarkFailed, "BackoffLimitExceeded", "exit code 1")
+			Expect(o.access.Client.Status().Update(ctx, &cr)).To(Succeed())
+
+			wg.Wait()
+			Expect(err).To(MatchError("iperf3/iperf3-sample failed: exit code 1"))
+			Expect(errOut.String()).To(ContainSubstring(" Failed: exit code 1\n"))
+		})
+
+		It("should time out", func() {
+			runOptions := &RunOptions{Filename: "-", Wait: true, Timeout: 50 * time.Millisecond, Output: OutputTable}
+			err := o.Run(runOptions, strings.NewReader(iperf3Template))
+			Expect(err).To(MatchError(ContainSubstring("timed out")))
+		})
+	})
+
+	Context("listing the benchmarks", func() {
+		It("should print the phase of the benchmarks of all kinds", func() {
+			iperf3 := &perfv1alpha1.Iperf3{ObjectMeta: metav1.ObjectMeta{Name: "iperf3", Namespace: "bench"}}
+			other := newFio("other", perfv1alpha1.BenchmarkRunning, "")
+			other.Namespace = "other"
+			newOptions(newFio("fio", perfv1alpha1.BenchmarkSucceeded, "1000"), iperf3, other)
+
+			Expect(o.Status(false, "")).To(Succeed())
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			Expect(lines).To(HaveLen(3))
+			Expect(lines[0]).To(HavePrefix("KIND"))
+			Expect(strings.Fields(lines[1])[:3]).To(Equal([]string{"fio", "fio", "Succeeded"}))
+			Expect(strings.Fields(lines[2])[:3]).To(Equal([]string{"iperf3", "iperf3", "New"}))
+
+			out.Reset()
+			Expect(o.Status(true, "")).To(Succeed())
+			Expect(out.String()).To(HavePrefix("NAMESPACE"))
+			Expect(out.String()).To(ContainSubstring("other"))
+		})
+
+		It("should report the lack of benchmarks", func() {
+			Expect(o.Status(false, "")).To(Succeed())
+			Expect(out.String()).To(BeEmpty())
+			Expect(errOut.String()).To(Equal("No benchmarks found\n"))
+		})
+
+		It("should reject invalid selectors", func() {
+			Expect(o.Status(false, "a=b=c")).NotTo(Succeed())
+		})
+	})
+
+	Context("printing the results", func() {
+		It("should print the results of benchmarks and BenchmarkResults", func() {
+			result := &perfv1alpha1.BenchmarkResult{
+				ObjectMeta: metav1.ObjectMeta{Name: "fio-baseline", Namespace: "bench"},
+				Spec: perfv1alpha1.BenchmarkResultSpec{
+					Results: newFio("", "", "900").Status.Results,
+				},
+			}
+			newOptions(newFio("fio", perfv1alpha1.BenchmarkSucceeded, "1000"), result)
+
+			Expect(o.Results("fio/fio", OutputCSV)).To(Succeed())
+			Expect(out.String()).To(Equal("metric,value,unit\nread.iops,1000,ops/s\n"))
+
+			out.Reset()
+			Expect(o.Results("benchmarkresult/fio-baseline", OutputCSV)).To(Succeed())
+			Expect(out.String()).To(Equal("metric,value,unit\nread.iops,900,ops/s\n"))
+		})
+
+		It("should fail without results", func() {
+			newOptions(newFio("fio", perfv1alpha1.BenchmarkRunning, ""))
+			Expect(o.Results("fio/fio", OutputTable)).To(MatchError("fio/fio has no results (phase: Running)"))
+			Expect(o.Results("fio/missing", OutputTable)).NotTo(Succeed())
+		})
+	})
+
+	Context("comparing two runs", func() {
+		BeforeEach(func() {
+			newOptions(
+				newFio("baseline", perfv1alpha1.BenchmarkSucceeded, "1000"),
+				newFio("slower", perfv1alpha1.BenchmarkSucceeded, "900"),
+				newFio("faster", perfv1alpha1.BenchmarkSucceeded, "1100"),
+			)
+		})
+
+		It("should print the comparison", func() {
+			compareOptions := &CompareOptions{TolerancePercent: 5, FailOnRegression: true, Output: OutputCSV}
+			Expect(o.Compare("fio/baseline", "fio/faster", compareOptions)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("read.iops,ops/s,1000,1100,10.00,false\n"))
+		})
+
+		It("should fail on regression only if asked", func() {
+			compareOptions := &CompareOptions{TolerancePercent: 5, Output: OutputTable}
+			Expect(o.Compare("fio/baseline", "fio/slower", compareOptions)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("-10.00%"))
+
+			compareOptions.FailOnRegression = true
+			Expect(o.Compare("fio/baseline", "fio/slower", compareOptions)).
+				To(MatchError("fio/slower has regressed compared to fio/baseline"))
+
+			compareOptions.TolerancePercent = 15
+			Expect(o.Compare("fio/baseline", "fio/slower", compareOptions)).To(Succeed())
+		})
+	})
+
+	Context("printing the logs", func() {
+		It("should skip the pending pods", func() {
+			fio := newFio("fio", perfv1alpha1.BenchmarkRunning, "")
+			fio.Status.AddChild("batch/v1", "Job", "fio")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "fio-x1",
+					Namespace: "bench",
+					Labels:    map[string]string{"job-name": "fio"},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodPending},
+			}
+			newOptions(fio, pod)
+
+			Expect(o.Logs("fio/fio", &LogsOptions{Tail: -1})).To(Succeed())
+			Expect(errOut.String()).To(Equal("No running pods found for fio/fio\n"))
+		})
+
+		It("should prefix the lines with the name of the pod", func() {
+			var mutex sync.Mutex
+			writer := newLinePrefixer(&out, "[pod] ", &mutex)
+			_, err := writer.Write([]byte("first\nsec"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out.String()).To(Equal("[pod] first\n"))
+
+			_, err = writer.Write([]byte("ond\nthird"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(writer.Flush()).To(Succeed())
+			Expect(out.String()).To(Equal("[pod] first\n[pod] second\n[pod] third\n"))
+		})
+	})
+})
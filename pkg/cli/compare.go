@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/baseline"
+)
+
+// CompareOptions are the settings of the compare command
+type CompareOptions struct {
+	// TolerancePercent is the allowed regression of the metrics in percent
+	TolerancePercent int32
+	// FailOnRegression makes the command fail if any metric has regressed
+	FailOnRegression bool
+	// Output format of the comparison
+	Output string
+}
+
+func newCompareCommand(o *Options) *cobra.Command {
+	compareOptions := CompareOptions{}
+	cmd := &cobra.Command{
+		Use:   "compare BASELINE CURRENT",
+		Short: "Compare the results of two benchmark runs",
+		Long: `Compare the results of two benchmark runs.
+
+Both runs are given as KIND/NAME references of benchmarks or
+BenchmarkResults. The metrics measured by both runs are compared the
+same way as the baseline of a benchmark.`,
+		Example: "  kubectl kubestone compare benchmarkresult/fio-baseline fio/fio-sample --fail-on-regression",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Compare(args[0], args[1], &compareOptions)
+		},
+	}
+	cmd.Flags().Int32Var(&compareOptions.TolerancePercent, "tolerance", baseline.DefaultTolerancePercent,
+		"Allowed regression of the metrics in percent")
+	cmd.Flags().BoolVar(&compareOptions.FailOnRegression, "fail-on-regression", false,
+		"Fail if any metric has regressed beyond the tolerance")
+	cmd.Flags().StringVarP(&compareOptions.Output, "output", "o", OutputTable,
+		"Output format: table, json or csv")
+	return cmd
+}
+
+// Compare prints the comparison of the results of the two benchmark runs
+func (o *Options) Compare(baselineReference, currentReference string, compareOptions *CompareOptions) error {
+	ctx := context.Background()
+	if err := validateOutput(compareOptions.Output); err != nil {
+		return err
+	}
+	base, err := o.getRun(ctx, baselineReference)
+	if err != nil {
+		return err
+	}
+	current, err := o.getRun(ctx, currentReference)
+	if err != nil {
+		return err
+	}
+	for _, r := range []*run{base, current} {
+		if r.Results == nil {
+			return fmt.Errorf("%s has no results (phase: %s)", r.Name, phaseName(r.Phase))
+		}
+	}
+
+	spec := &perfv1alpha1.BaselineSpec{TolerancePercent: &compareOptions.TolerancePercent}
+	result := &perfv1alpha1.BenchmarkResult{
+		ObjectMeta: metav1.ObjectMeta{Name: base.Name},
+		Spec:       perfv1alpha1.BenchmarkResultSpec{Results: base.Results},
+	}
+	comparison := baseline.Compare(spec, result, current.Results)
+	if len(comparison.Metrics) == 0 {
+		fmt.Fprintf(o.ErrOut, "No common metrics in %s and %s\n", base.Name, current.Name)
+		return nil
+	}
+	if err := PrintComparison(o.Out, compareOptions.Output, comparison); err != nil {
+		return err
+	}
+
+	if compareOptions.FailOnRegression && comparison.IsRegressed() {
+		return fmt.Errorf("%s has regressed compared to %s", current.Name, base.Name)
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/xridge/kubestone/pkg/k8s"
+)
+
+// LogsOptions are the settings of the logs command
+type LogsOptions struct {
+	// Follow streams the logs until the benchmark finishes
+	Follow bool
+	// Container of the pods, defaults to the only container of the pods
+	Container string
+	// Tail is the number of lines to show from the end of the logs,
+	// negative values show all lines
+	Tail int64
+}
+
+func newLogsCommand(o *Options) *cobra.Command {
+	logsOptions := LogsOptions{}
+	cmd := &cobra.Command{
+		Use:   "logs KIND/NAME",
+		Short: "Print the logs of the pods of a benchmark",
+		Long: `Print the logs of the pods of a benchmark.
+
+The logs of all pods created for the benchmark are printed, including the
+server and client pods of client-server benchmarks and the pods of the
+benchmarks of suites and sweeps. Each line is prefixed with the name of
+its pod.`,
+		Example: "  kubectl kubestone logs -f iperf3/iperf3-sample",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Logs(args[0], &logsOptions)
+		},
+	}
+	cmd.Flags().BoolVarP(&logsOptions.Follow, "follow", "f", false,
+		"Stream the logs until the benchmark finishes")
+	cmd.Flags().StringVarP(&logsOptions.Container, "container", "c", "",
+		"Container of the pods")
+	cmd.Flags().Int64Var(&logsOptions.Tail, "tail", -1,
+		"Lines of the end of the logs to show, -1 shows all lines")
+	return cmd
+}
+
+// Logs prints the logs of the pods of the benchmark given by the KIND/NAME reference
+func (o *Options) Logs(reference string, logsOptions *LogsOptions) error {
+	ctx := context.Background()
+	access, namespace, err := o.Access()
+	if err != nil {
+		return err
+	}
+	kind, name, err := parseReference(o.mapper, reference)
+	if err != nil {
+		return err
+	}
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+	benchmark, err := getBenchmark(ctx, access, kind, key)
+	if err != nil {
+		return err
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	errs := make(chan error, 1)
+	streamed := map[string]bool{}
+	streamPods := func() error {
+		pods, err := access.GetBenchmarkPods(benchmark)
+		if err != nil {
+			return err
+		}
+		for _, pod := range pods {
+			if streamed[pod.Name] || pod.Status.Phase == corev1.PodPending {
+				continue
+			}
+			streamed[pod.Name] = true
+			if !logsOptions.Follow {
+				if err := streamLogs(access, pod, logsOptions, o.Out, &mutex); err != nil {
+					return err
+				}
+				continue
+			}
+			wg.Add(1)
+			go func(pod corev1.Pod) {
+				defer wg.Done()
+				if err := streamLogs(access, pod, logsOptions, o.Out, &mutex); err != nil {
+					select {
+					case errs <- err:
+					default:
+					}
+				}
+			}(pod)
+		}
+		return nil
+	}
+
+	if err := streamPods(); err != nil {
+		return err
+	}
+	// Server and client pods are started one after the other, so the pods
+	// are looked up until the benchmark finishes
+	for logsOptions.Follow && !benchmark.GetBenchmarkStatus().IsFinished() {
+		time.Sleep(pollInterval)
+		if err := access.Client.Get(ctx, key, benchmark); err != nil {
+			return err
+		}
+		if err := streamPods(); err != nil {
+			return err
+		}
+	}
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		return err
+	default:
+	}
+	if len(streamed) == 0 {
+		fmt.Fprintf(o.ErrOut, "No running pods found for %s\n", reference)
+	}
+	return nil
+}
+
+// streamLogs copies the logs of the pod to the output with the name of
+// the pod prefixed to each line
+func streamLogs(access *k8s.Access, pod corev1.Pod, logsOptions *LogsOptions,
+	out io.Writer, mutex *sync.Mutex) error {
+	podLogOptions := &corev1.PodLogOptions{
+		Container: logsOptions.Container,
+		Follow:    logsOptions.Follow,
+	}
+	if logsOptions.Tail >= 0 {
+		podLogOptions.TailLines = &logsOptions.Tail
+	}
+	stream, err := access.Clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, podLogOptions).Stream()
+	if err != nil {
+		return fmt.Errorf("reading logs of pod %s: %v", pod.Name, err)
+	}
+	defer stream.Close()
+
+	writer := newLinePrefixer(out, fmt.Sprintf("[%s] ", pod.Name), mutex)
+	if _, err := io.Copy(writer, stream); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+// linePrefixer writes the prefix before every line written to it. The
+// lines of several linePrefixers sharing the mutex are not interleaved.
+type linePrefixer struct {
+	out     io.Writer
+	prefix  string
+	mutex   *sync.Mutex
+	partial bytes.Buffer
+}
+
+func newLinePrefixer(out io.Writer, prefix string, mutex *sync.Mutex) *linePrefixer {
+	return &linePrefixer{out: out, prefix: prefix, mutex: mutex}
+}
+
+// Write writes the complete lines of the data, and buffers the last
+// incomplete one until its end is written
+func (w *linePrefixer) Write(data []byte) (int, error) {
+	w.partial.Write(data)
+	var lines bytes.Buffer
+	reader := bufio.NewReader(&w.partial)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			w.partial.Reset()
+			w.partial.Write(line)
+			break
+		}
+		lines.WriteString(w.prefix)
+		lines.Write(line)
+	}
+	if lines.Len() == 0 {
+		return len(data), nil
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if _, err := w.out.Write(lines.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// Flush writes the buffered incomplete line
+func (w *linePrefixer) Flush() error {
+	if w.partial.Len() == 0 {
+		return nil
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	_, err := fmt.Fprintf(w.out, "%s%s\n", w.prefix, w.partial.Bytes())
+	w.partial.Reset()
+	return err
+}
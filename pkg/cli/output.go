@@ -0,0 +1,172 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+const (
+	// OutputTable prints aligned columns for humans
+	OutputTable = "table"
+	// OutputJSON prints the objects as indented JSON
+	OutputJSON = "json"
+	// OutputCSV prints comma separated values with a header row
+	OutputCSV = "csv"
+)
+
+// validateOutput returns an error if the output format is not supported
+func validateOutput(format string) error {
+	switch format {
+	case OutputTable, OutputJSON, OutputCSV:
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %q, use one of %s, %s, %s",
+			format, OutputTable, OutputJSON, OutputCSV)
+	}
+}
+
+func newTableWriter(out io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+}
+
+func printJSON(out io.Writer, value interface{}) error {
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, string(content))
+	return err
+}
+
+func printCSV(out io.Writer, rows [][]string) error {
+	w := csv.NewWriter(out)
+	if err := w.WriteAll(rows); err != nil {
+		return err
+	}
+	return w.Error()
+}
+
+func printTable(out io.Writer, rows [][]string) error {
+	w := newTableWriter(out)
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// PrintResults prints the metrics of the results in the given format.
+// The statistics of the repeated benchmarks are printed in a second
+// table, or as additional columns in CSV.
+func PrintResults(out io.Writer, format string, results *perfv1alpha1.BenchmarkResults) error {
+	if format == OutputJSON {
+		return printJSON(out, results)
+	}
+
+	statistics := map[string]perfv1alpha1.MetricStatistics{}
+	for _, s := range results.Statistics {
+		statistics[s.Name] = s
+	}
+
+	if format == OutputCSV {
+		rows := [][]string{{"metric", "value", "unit"}}
+		if len(statistics) > 0 {
+			rows[0] = append(rows[0], "samples", "mean", "median", "stddev", "min", "max",
+				"confidence_low", "confidence_high")
+		}
+		for _, metric := range results.Metrics {
+			row := []string{metric.Name, metric.Value, metric.Unit}
+			if len(statistics) > 0 {
+				s := statistics[metric.Name]
+				row = append(row, samples(s), s.Mean, s.Median, s.StdDev, s.Min, s.Max,
+					s.ConfidenceLow, s.ConfidenceHigh)
+			}
+			rows = append(rows, row)
+		}
+		return printCSV(out, rows)
+	}
+
+	rows := [][]string{{"METRIC", "VALUE", "UNIT"}}
+	for _, metric := range results.Metrics {
+		rows = append(rows, []string{metric.Name, metric.Value, metric.Unit})
+	}
+	if err := printTable(out, rows); err != nil {
+		return err
+	}
+	if len(results.Statistics) == 0 {
+		return nil
+	}
+
+	rows = [][]string{{"METRIC", "SAMPLES", "MEAN", "MEDIAN", "STDDEV", "MIN", "MAX", "95% CONFIDENCE"}}
+	for _, s := range results.Statistics {
+		confidence := "-"
+		if s.ConfidenceLow != "" {
+			confidence = fmt.Sprintf("%s - %s", s.ConfidenceLow, s.ConfidenceHigh)
+		}
+		rows = append(rows, []string{s.Name, samples(s), s.Mean, s.Median, s.StdDev, s.Min, s.Max, confidence})
+	}
+	fmt.Fprintln(out)
+	return printTable(out, rows)
+}
+
+func samples(s perfv1alpha1.MetricStatistics) string {
+	if s.Name == "" {
+		return ""
+	}
+	return strconv.Itoa(int(s.Samples))
+}
+
+// PrintComparison prints the compared metrics of two runs in the given format
+func PrintComparison(out io.Writer, format string, comparison *perfv1alpha1.BaselineComparison) error {
+	if format == OutputJSON {
+		return printJSON(out, comparison)
+	}
+
+	if format == OutputCSV {
+		rows := [][]string{{"metric", "unit", "baseline", "value", "delta_percent", "regressed"}}
+		for _, m := range comparison.Metrics {
+			rows = append(rows, []string{m.Name, m.Unit, m.Baseline, m.Value, m.DeltaPercent,
+				strconv.FormatBool(m.Regressed)})
+		}
+		return printCSV(out, rows)
+	}
+
+	rows := [][]string{{"METRIC", "UNIT", "BASELINE", "VALUE", "DELTA", "REGRESSED"}}
+	for _, m := range comparison.Metrics {
+		delta := "n/a"
+		if m.DeltaPercent != "" {
+			delta = m.DeltaPercent + "%"
+			if !strings.HasPrefix(delta, "-") {
+				delta = "+" + delta
+			}
+		}
+		regressed := "no"
+		if m.Regressed {
+			regressed = "yes"
+		}
+		rows = append(rows, []string{m.Name, m.Unit, m.Baseline, m.Value, delta, regressed})
+	}
+	return printTable(out, rows)
+}
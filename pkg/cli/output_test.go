@@ -0,0 +1,121 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("output", func() {
+	var out bytes.Buffer
+	var results *perfv1alpha1.BenchmarkResults
+
+	BeforeEach(func() {
+		out.Reset()
+		results = &perfv1alpha1.BenchmarkResults{
+			Metrics: []perfv1alpha1.BenchmarkMetric{
+				{Name: "read.iops", Value: "1200", Unit: "ops/s"},
+				{Name: "read.latency.p99", Value: "850", Unit: "us"},
+			},
+		}
+	})
+
+	Context("validating the format", func() {
+		It("should reject unknown formats", func() {
+			Expect(validateOutput(OutputCSV)).To(Succeed())
+			Expect(validateOutput("yaml")).NotTo(Succeed())
+		})
+	})
+
+	Context("printing results", func() {
+		It("should print a table", func() {
+			Expect(PrintResults(&out, OutputTable, results)).To(Succeed())
+			Expect(out.String()).To(Equal(
+				"METRIC            VALUE  UNIT\n" +
+					"read.iops         1200   ops/s\n" +
+					"read.latency.p99  850    us\n"))
+		})
+
+		It("should print the statistics in a second table", func() {
+			results.Statistics = []perfv1alpha1.MetricStatistics{{
+				Name: "read.iops", Samples: 3, Mean: "1200", Median: "1190", StdDev: "20",
+				Min: "1180", Max: "1230", ConfidenceLow: "1150", ConfidenceHigh: "1250",
+			}}
+			Expect(PrintResults(&out, OutputTable, results)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("\n\nMETRIC     SAMPLES  MEAN"))
+			Expect(out.String()).To(ContainSubstring("1150 - 1250"))
+		})
+
+		It("should print CSV", func() {
+			Expect(PrintResults(&out, OutputCSV, results)).To(Succeed())
+			Expect(out.String()).To(Equal(
+				"metric,value,unit\n" +
+					"read.iops,1200,ops/s\n" +
+					"read.latency.p99,850,us\n"))
+		})
+
+		It("should add the statistics columns to CSV", func() {
+			results.Statistics = []perfv1alpha1.MetricStatistics{{
+				Name: "read.iops", Samples: 3, Mean: "1200",
+			}}
+			Expect(PrintResults(&out, OutputCSV, results)).To(Succeed())
+			Expect(out.String()).To(HavePrefix("metric,value,unit,samples,mean,"))
+			Expect(out.String()).To(ContainSubstring("read.iops,1200,ops/s,3,1200,"))
+			Expect(out.String()).To(ContainSubstring("read.latency.p99,850,us,,,"))
+		})
+
+		It("should print JSON", func() {
+			Expect(PrintResults(&out, OutputJSON, results)).To(Succeed())
+			var printed perfv1alpha1.BenchmarkResults
+			Expect(json.Unmarshal(out.Bytes(), &printed)).To(Succeed())
+			Expect(printed).To(Equal(*results))
+		})
+	})
+
+	Context("printing comparisons", func() {
+		var comparison *perfv1alpha1.BaselineComparison
+
+		BeforeEach(func() {
+			comparison = &perfv1alpha1.BaselineComparison{
+				Metrics: []perfv1alpha1.MetricComparison{
+					{Name: "read.iops", Unit: "ops/s", Baseline: "1000", Value: "1200", DeltaPercent: "20.00"},
+					{Name: "read.latency.p99", Unit: "us", Baseline: "500", Value: "850",
+						DeltaPercent: "70.00", Regressed: true},
+				},
+			}
+		})
+
+		It("should print a table with signed deltas", func() {
+			Expect(PrintComparison(&out, OutputTable, comparison)).To(Succeed())
+			Expect(out.String()).To(Equal(
+				"METRIC            UNIT   BASELINE  VALUE  DELTA    REGRESSED\n" +
+					"read.iops         ops/s  1000      1200   +20.00%  no\n" +
+					"read.latency.p99  us     500       850    +70.00%  yes\n"))
+		})
+
+		It("should print CSV", func() {
+			Expect(PrintComparison(&out, OutputCSV, comparison)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("read.latency.p99,us,500,850,70.00,true\n"))
+		})
+	})
+})
@@ -0,0 +1,58 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newResultsCommand(o *Options) *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "results KIND/NAME",
+		Short: "Print the parsed results of a benchmark",
+		Long: `Print the parsed results of a benchmark.
+
+The results are read from the status of the benchmark, or from a
+BenchmarkResult (e.g. benchmarkresult/fio-sample-baseline).`,
+		Example: "  kubectl kubestone results fio/fio-sample -o csv",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Results(args[0], output)
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", OutputTable, "Output format: table, json or csv")
+	return cmd
+}
+
+// Results prints the results of the benchmark given by the KIND/NAME reference
+func (o *Options) Results(reference, output string) error {
+	if err := validateOutput(output); err != nil {
+		return err
+	}
+	r, err := o.getRun(context.Background(), reference)
+	if err != nil {
+		return err
+	}
+	if r.Results == nil {
+		return fmt.Errorf("%s has no results (phase: %s)", reference, phaseName(r.Phase))
+	}
+	return PrintResults(o.Out, output, r.Results)
+}
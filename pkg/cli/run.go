@@ -0,0 +1,211 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/template"
+)
+
+// RunOptions are the settings of the run command
+type RunOptions struct {
+	// Filename of the template, - reads it from the standard input
+	Filename string
+	// Name of the created benchmark. The name of the template is used if
+	// empty, or a generated name if the template has no name either.
+	Name string
+	// Values are PATH=VALUE settings of the spec of the template
+	Values []string
+	// Wait for the completion of the benchmark
+	Wait bool
+	// Timeout of the wait, zero waits without limit
+	Timeout time.Duration
+	// Output format of the results
+	Output string
+}
+
+func newRunCommand(o *Options) *cobra.Command {
+	runOptions := RunOptions{}
+	cmd := &cobra.Command{
+		Use:   "run -f FILENAME",
+		Short: "Create a benchmark from a template and wait for its completion",
+		Long: `Create a benchmark from a template and wait for its completion.
+
+The template is a benchmark custom resource of any kind. The fields of
+its spec can be overridden with --set, using the same paths as the
+parameters of benchmark sweeps. The results are printed once the
+benchmark succeeds. The command fails if the benchmark does not succeed.`,
+		Example: `  kubectl kubestone run -f config/samples/perf_v1alpha1_iperf3.yaml \
+    --set udp=true --set clientConfiguration.cmdLineArgs="--time 30"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run(&runOptions, os.Stdin)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&runOptions.Filename, "filename", "f", "", "Template of the benchmark, - for standard input")
+	flags.StringVar(&runOptions.Name, "name", "", "Name of the created benchmark")
+	flags.StringArrayVar(&runOptions.Values, "set", nil, "Set a field of the spec (PATH=VALUE, can be repeated)")
+	flags.BoolVar(&runOptions.Wait, "wait", true, "Wait for the completion of the benchmark")
+	flags.DurationVar(&runOptions.Timeout, "timeout", 0, "Timeout of the wait (e.g. 30m), 0 waits without limit")
+	flags.StringVarP(&runOptions.Output, "output", "o", OutputTable, "Output format of the results: table, json or csv")
+	_ = cmd.MarkFlagRequired("filename")
+	return cmd
+}
+
+// Run creates the benchmark from the template and waits for its completion
+func (o *Options) Run(runOptions *RunOptions, stdin io.Reader) error {
+	ctx := context.Background()
+	if err := validateOutput(runOptions.Output); err != nil {
+		return err
+	}
+	access, namespace, err := o.Access()
+	if err != nil {
+		return err
+	}
+
+	var content []byte
+	if runOptions.Filename == "-" {
+		content, err = ioutil.ReadAll(stdin)
+	} else {
+		content, err = ioutil.ReadFile(runOptions.Filename)
+	}
+	if err != nil {
+		return err
+	}
+	benchmark, kind, err := NewBenchmark(access.Scheme, content, runOptions.Values)
+	if err != nil {
+		return err
+	}
+
+	if benchmark.GetNamespace() == "" || o.Namespace != "" {
+		benchmark.SetNamespace(namespace)
+	}
+	if runOptions.Name != "" {
+		benchmark.SetName(runOptions.Name)
+	}
+	if benchmark.GetName() == "" && benchmark.GetGenerateName() == "" {
+		benchmark.SetGenerateName(strings.ToLower(kind) + "-")
+	}
+
+	if err := access.Client.Create(ctx, benchmark); err != nil {
+		return err
+	}
+	reference := fmt.Sprintf("%s/%s", strings.ToLower(kind), benchmark.GetName())
+	fmt.Fprintf(o.ErrOut, "%s created\n", reference)
+	if !runOptions.Wait {
+		return nil
+	}
+
+	if err := o.waitForCompletion(ctx, access, benchmark, runOptions.Timeout); err != nil {
+		return err
+	}
+	status := benchmark.GetBenchmarkStatus()
+	if status.Results != nil {
+		if err := PrintResults(o.Out, runOptions.Output, status.Results); err != nil {
+			return err
+		}
+	}
+	if status.Phase != perfv1alpha1.BenchmarkSucceeded {
+		return fmt.Errorf("%s %s: %s", reference, strings.ToLower(string(status.Phase)), status.Message)
+	}
+	return nil
+}
+
+// NewBenchmark decodes the benchmark template and applies the PATH=VALUE
+// settings to its spec. The kind of the benchmark is returned as well.
+func NewBenchmark(scheme *runtime.Scheme, content []byte, values []string) (perfv1alpha1.Benchmark, string, error) {
+	object, gvk, err := serializer.NewCodecFactory(scheme).UniversalDeserializer().Decode(content, nil, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid template: %v", err)
+	}
+	decoded, ok := object.(perfv1alpha1.Benchmark)
+	prototype, known := template.Kinds(scheme)[gvk.Kind]
+	if !ok || !known {
+		return nil, "", fmt.Errorf("%s is not a benchmark kind", gvk.Kind)
+	}
+
+	spec, err := template.SpecOf(decoded)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 {
+			return nil, "", fmt.Errorf("invalid setting %q, expected PATH=VALUE", value)
+		}
+		if err := template.SetField(spec, parts[0], parts[1]); err != nil {
+			return nil, "", fmt.Errorf("setting %s: %v", parts[0], err)
+		}
+	}
+
+	benchmark, err := template.NewBenchmark(prototype, spec,
+		decoded.GetName(), decoded.GetNamespace(), decoded.GetLabels())
+	if err != nil {
+		return nil, "", err
+	}
+	benchmark.SetGenerateName(decoded.GetGenerateName())
+	benchmark.SetAnnotations(decoded.GetAnnotations())
+	return benchmark, gvk.Kind, nil
+}
+
+// waitForCompletion polls the benchmark until it finishes, and reports
+// the changes of its phase
+func (o *Options) waitForCompletion(ctx context.Context, access *k8s.Access,
+	benchmark perfv1alpha1.Benchmark, timeout time.Duration) error {
+	key := types.NamespacedName{Namespace: benchmark.GetNamespace(), Name: benchmark.GetName()}
+	deadline := time.Now().Add(timeout)
+
+	var reported string
+	for {
+		if err := access.Client.Get(ctx, key, benchmark); err != nil {
+			return err
+		}
+		status := benchmark.GetBenchmarkStatus()
+		progress := phaseName(status.Phase)
+		if status.Message != "" {
+			progress += ": " + status.Message
+		}
+		if progress != reported {
+			fmt.Fprintf(o.ErrOut, "%s %s\n", time.Now().Format("15:04:05"), progress)
+			reported = progress
+		}
+
+		if status.IsFinished() {
+			return nil
+		}
+		if timeout > 0 && time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %v waiting for %s", timeout, key.Name)
+		}
+		time.Sleep(pollInterval)
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/template"
+)
+
+func newStatusCommand(o *Options) *cobra.Command {
+	var allNamespaces bool
+	var selector string
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the phase of the benchmarks of all kinds",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Status(allNamespaces, selector)
+		},
+	}
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false,
+		"List the benchmarks of all namespaces")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "",
+		"Label selector of the benchmarks (e.g. kubestone.xridge.io/suite=nightly)")
+	return cmd
+}
+
+// Status prints the phase of the benchmarks of all kinds in the namespace
+func (o *Options) Status(allNamespaces bool, selector string) error {
+	ctx := context.Background()
+	access, namespace, err := o.Access()
+	if err != nil {
+		return err
+	}
+	if allNamespaces {
+		namespace = ""
+	}
+	matchLabels, err := labels.ConvertSelectorToLabelsMap(selector)
+	if err != nil {
+		return err
+	}
+
+	header := []string{"KIND", "NAME", "PHASE", "REASON", "DURATION", "AGE"}
+	if allNamespaces {
+		header = append([]string{"NAMESPACE"}, header...)
+	}
+	rows := [][]string{header}
+
+	now := time.Now()
+	for _, kind := range template.SortedKinds(template.Kinds(access.Scheme)) {
+		benchmarks, err := template.List(ctx, access.Client, access.Scheme, kind, namespace, matchLabels)
+		if err != nil {
+			return err
+		}
+		for _, benchmark := range benchmarks {
+			status := benchmark.GetBenchmarkStatus()
+			row := []string{strings.ToLower(kind), benchmark.GetName(), phaseName(status.Phase),
+				dash(status.Reason), benchmarkDuration(status, now),
+				duration.HumanDuration(now.Sub(benchmark.GetCreationTimestamp().Time))}
+			if allNamespaces {
+				row = append([]string{benchmark.GetNamespace()}, row...)
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	if len(rows) == 1 {
+		fmt.Fprintln(o.ErrOut, "No benchmarks found")
+		return nil
+	}
+	return printTable(o.Out, rows)
+}
+
+// phaseName returns the name of the phase shown to the user. Benchmarks
+// not yet picked up by the controller have no phase.
+func phaseName(phase perfv1alpha1.BenchmarkPhase) string {
+	if phase == "" {
+		return "New"
+	}
+	return string(phase)
+}
+
+func dash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// benchmarkDuration returns the duration of the finished benchmarks, and
+// the time elapsed since the start of the benchmarks in progress
+func benchmarkDuration(status *perfv1alpha1.BenchmarkStatus, now time.Time) string {
+	switch {
+	case status.Duration != nil:
+		return status.Duration.Duration.Round(time.Second).String()
+	case status.StartTime != nil && !status.IsFinished():
+		return now.Sub(status.StartTime.Time).Round(time.Second).String()
+	default:
+		return "-"
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/assertion"
+)
+
+// evaluateAssertions checks the assertions of the succeeded benchmark
+// against its results and stores their outcome in the status. A Warning
+// event is raised if any of them does not hold. It returns the description
+// of the failed assertions, or empty string if all of them hold.
+func (a *Access) evaluateAssertions(cr perfv1alpha1.Benchmark) string {
+	assertions := cr.GetCommonSpec().Assertions
+	if len(assertions) == 0 {
+		return ""
+	}
+
+	status := cr.GetBenchmarkStatus()
+	status.Assertions = assertion.Evaluate(assertions, status.Results)
+	failed := assertion.Failed(status.Assertions)
+	if len(failed) == 0 {
+		return ""
+	}
+
+	message := fmt.Sprintf("Failed assertions: %s", strings.Join(failed, ", "))
+	_ = a.RecordEventf(cr, corev1.EventTypeWarning, AssertionFailed, "%s", message)
+	return message
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("assertions", func() {
+	var ctx context.Context
+	var access Access
+	var recorder *record.FakeRecorder
+	var cr *perfv1alpha1.Pgbench
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		_ = k8sscheme.AddToScheme(scheme)
+		_ = perfv1alpha1.AddToScheme(scheme)
+
+		cr = &perfv1alpha1.Pgbench{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pgbench",
+				Namespace: "assertion",
+				SelfLink:  "/apis/perf.kubestone.xridge.io/v1alpha1/namespaces/assertion/pgbenches/pgbench",
+			},
+		}
+		recorder = record.NewFakeRecorder(10)
+		access = Access{
+			Client:        fake.NewFakeClientWithScheme(scheme, cr.DeepCopy()),
+			Scheme:        scheme,
+			EventRecorder: recorder,
+		}
+		Expect(access.Client.Get(ctx, types.NamespacedName{
+			Namespace: cr.Namespace,
+			Name:      cr.Name,
+		}, cr)).To(Succeed())
+		cr.Status.Results = &perfv1alpha1.BenchmarkResults{}
+		cr.Status.Results.AddMetric("tps", 1400, "tx/s")
+	})
+
+	It("fails the benchmark if any of them does not hold", func() {
+		cr.Spec.Assertions = []perfv1alpha1.Assertion{
+			{Metric: "tps", Operator: perfv1alpha1.GreaterThanOrEqual, Value: "1000"},
+			{Metric: "tps", Operator: perfv1alpha1.GreaterThanOrEqual, Value: "1500"},
+		}
+		Expect(access.FinishBenchmark(ctx, cr, nil)).To(Succeed())
+		Expect(cr.Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+		Expect(cr.Status.Reason).To(Equal(AssertionFailed))
+		Expect(cr.Status.Message).To(Equal("Failed assertions: tps >= 1500 (observed 1400 tx/s)"))
+		Expect(cr.Status.Assertions).To(HaveLen(2))
+		Expect(cr.Status.Assertions[0].Passed).To(BeTrue())
+		Expect(cr.Status.Assertions[1].Passed).To(BeFalse())
+		Expect(recorder.Events).To(Receive(ContainSubstring(AssertionFailed)))
+	})
+
+	It("lets the benchmark succeed if all of them hold", func() {
+		cr.Spec.Assertions = []perfv1alpha1.Assertion{
+			{Metric: "tps", Operator: perfv1alpha1.GreaterThan, Value: "1000"},
+		}
+		Expect(access.FinishBenchmark(ctx, cr, nil)).To(Succeed())
+		Expect(cr.Status.Phase).To(Equal(perfv1alpha1.BenchmarkSucceeded))
+		Expect(cr.Status.Assertions[0].Observed).To(Equal("1400"))
+		Expect(recorder.Events).To(BeEmpty())
+	})
+})
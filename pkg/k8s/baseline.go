@@ -0,0 +1,70 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/baseline"
+)
+
+// compareWithBaseline compares the results of the succeeded benchmark with
+// its baseline, if configured. The comparison is stored in the status and
+// the Regressed condition is set accordingly, a Warning event is raised on
+// regression. It returns the description of the regressions, or empty
+// string if there are none.
+func (a *Access) compareWithBaseline(ctx context.Context, cr perfv1alpha1.Benchmark) (string, error) {
+	spec := cr.GetCommonSpec().Baseline
+	if spec == nil {
+		return "", nil
+	}
+
+	found, err := baseline.Find(ctx, a.Client, cr.GetNamespace(), spec.Key)
+	if err != nil {
+		return "", err
+	}
+
+	status := cr.GetBenchmarkStatus()
+	status.Comparison = baseline.Compare(spec, found, status.Results)
+	if found == nil {
+		status.SetCondition(perfv1alpha1.ConditionRegressed, corev1.ConditionUnknown, NoBaseline,
+			fmt.Sprintf("No baseline found for key %q", spec.Key))
+		return "", nil
+	}
+
+	var regressions []string
+	for _, metric := range status.Comparison.Metrics {
+		if metric.Regressed {
+			regressions = append(regressions, fmt.Sprintf("%s (%s%%)", metric.Name, metric.DeltaPercent))
+		}
+	}
+	if len(regressions) == 0 {
+		status.SetCondition(perfv1alpha1.ConditionRegressed, corev1.ConditionFalse, WithinTolerance,
+			fmt.Sprintf("Compared with %s", found.Name))
+		return "", nil
+	}
+
+	message := fmt.Sprintf("Regressed compared with %s: %s", found.Name, strings.Join(regressions, ", "))
+	status.SetCondition(perfv1alpha1.ConditionRegressed, corev1.ConditionTrue, Regressed, message)
+	_ = a.RecordEventf(cr, corev1.EventTypeWarning, Regressed, "%s", message)
+	return message, nil
+}
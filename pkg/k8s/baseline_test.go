@@ -0,0 +1,118 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/baseline"
+)
+
+var _ = Describe("baseline comparison", func() {
+	var ctx context.Context
+	var access Access
+	var recorder *record.FakeRecorder
+	var cr *perfv1alpha1.Iperf3
+
+	setup := func(objects ...runtime.Object) {
+		scheme := runtime.NewScheme()
+		_ = k8sscheme.AddToScheme(scheme)
+		_ = perfv1alpha1.AddToScheme(scheme)
+		recorder = record.NewFakeRecorder(10)
+		access = Access{
+			Client:        fake.NewFakeClientWithScheme(scheme, append(objects, cr.DeepCopy())...),
+			Scheme:        scheme,
+			EventRecorder: recorder,
+		}
+		Expect(access.Client.Get(ctx, types.NamespacedName{
+			Namespace: cr.Namespace,
+			Name:      cr.Name,
+		}, cr)).To(Succeed())
+		cr.Status.Results = &perfv1alpha1.BenchmarkResults{}
+		cr.Status.Results.AddMetric("receiver.bps", 8e9, "bits/s")
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cr = &perfv1alpha1.Iperf3{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "iperf3",
+				Namespace: "baseline",
+				SelfLink:  "/apis/perf.kubestone.xridge.io/v1alpha1/namespaces/baseline/iperf3s/iperf3",
+			},
+		}
+		cr.Spec.Baseline = &perfv1alpha1.BaselineSpec{Key: "pod-network"}
+	})
+
+	newBaseline := func(bps float64) *perfv1alpha1.BenchmarkResult {
+		result := &perfv1alpha1.BenchmarkResult{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "iperf3-baseline",
+				Namespace: "baseline",
+				Labels:    map[string]string{baseline.Label: "pod-network"},
+			},
+		}
+		result.Spec.Results = &perfv1alpha1.BenchmarkResults{}
+		result.Spec.Results.AddMetric("receiver.bps", bps, "bits/s")
+		return result
+	}
+
+	It("reports the missing baseline", func() {
+		setup()
+		Expect(access.FinishBenchmark(ctx, cr, nil)).To(Succeed())
+		Expect(cr.Status.Phase).To(Equal(perfv1alpha1.BenchmarkSucceeded))
+		Expect(cr.Status.Comparison.BaselineResult).To(BeEmpty())
+		Expect(cr.Status.GetCondition(perfv1alpha1.ConditionRegressed).Status).To(Equal(corev1.ConditionUnknown))
+	})
+
+	It("succeeds within the tolerance", func() {
+		setup(newBaseline(8.2e9))
+		Expect(access.FinishBenchmark(ctx, cr, nil)).To(Succeed())
+		Expect(cr.Status.Phase).To(Equal(perfv1alpha1.BenchmarkSucceeded))
+		Expect(cr.Status.Comparison.Metrics).To(HaveLen(1))
+		Expect(cr.Status.GetCondition(perfv1alpha1.ConditionRegressed).Status).To(Equal(corev1.ConditionFalse))
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("reports the regressions", func() {
+		setup(newBaseline(10e9))
+		Expect(access.FinishBenchmark(ctx, cr, nil)).To(Succeed())
+		Expect(cr.Status.Phase).To(Equal(perfv1alpha1.BenchmarkSucceeded))
+		Expect(cr.Status.IsConditionTrue(perfv1alpha1.ConditionRegressed)).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring("receiver.bps (-20.00%)")))
+	})
+
+	It("fails on regression if requested", func() {
+		cr.Spec.Baseline.FailOnRegression = true
+		setup(newBaseline(10e9))
+		Expect(access.FinishBenchmark(ctx, cr, nil)).To(Succeed())
+		Expect(cr.Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+		Expect(cr.Status.Reason).To(Equal(Regressed))
+	})
+})
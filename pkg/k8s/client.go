@@ -19,11 +19,15 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
+
 	v1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	k8sclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
@@ -32,13 +36,19 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
 )
 
 // Access provides client related structs to access kubernetes
 type Access struct {
-	Client        client.Client
-	Clientset     *k8sclient.Clientset
+	// Client reads the objects through the cache of the manager
+	Client client.Client
+	// Clientset is used for the requests not supported by Client (pod logs)
+	// and for the uncached reads of the objects not watched by the manager
+	Clientset     k8sclient.Interface
 	Scheme        *runtime.Scheme
 	EventRecorder record.EventRecorder
 }
@@ -65,7 +75,8 @@ func (a *Access) RecordEventf(object metav1.Object, eventtype, reason, messageFm
 // sets the owner reference to a given object. It provides basic
 // idempotency (by ignoring Already Exists errors).
 // Successful creation of the event is logged via EventRecorder
-// to the owner.
+// to the owner. Jobs of benchmarks with timeout receive the
+// remaining time of the benchmark as active deadline.
 func (a *Access) CreateWithReference(ctx context.Context, object, owner metav1.Object) error {
 	runtimeObject, ok := object.(runtime.Object)
 	if !ok {
@@ -76,6 +87,12 @@ func (a *Access) CreateWithReference(ctx context.Context, object, owner metav1.O
 		return err
 	}
 
+	if job, ok := object.(*batchv1.Job); ok {
+		if benchmark, ok := owner.(perfv1alpha1.Benchmark); ok {
+			setJobDeadline(job, benchmark, time.Now())
+		}
+	}
+
 	err := a.Client.Create(ctx, runtimeObject)
 	if IgnoreAlreadyExists(err) != nil {
 		return err
@@ -86,6 +103,14 @@ func (a *Access) CreateWithReference(ctx context.Context, object, owner metav1.O
 			"Created %v", object.GetSelfLink())
 	}
 
+	// Keep track of the created objects in the status of the benchmark.
+	// The status is persisted by the next status update of the owner.
+	if benchmark, ok := owner.(perfv1alpha1.Benchmark); ok {
+		if gvk, err := apiutil.GVKForObject(runtimeObject, a.Scheme); err == nil {
+			benchmark.GetBenchmarkStatus().AddChild(gvk.GroupVersion().String(), gvk.Kind, object.GetName())
+		}
+	}
+
 	return nil
 }
 
@@ -99,69 +124,266 @@ func (a *Access) DeleteObject(ctx context.Context, object, owner metav1.Object)
 		return fmt.Errorf("object (%T) is not a runtime.Object", object)
 	}
 
-	// Need to get the object first so that the object.GetSelfLink()
-	// works during Event Recording
-	namespacedName := types.NamespacedName{
-		Namespace: object.GetNamespace(),
-		Name:      object.GetName(),
+	// The object is not read first, as reading the kinds not watched by
+	// the controllers would start a cluster-wide informer in the cache
+	kind := fmt.Sprintf("%T", runtimeObject)
+	if gvk, err := apiutil.GVKForObject(runtimeObject, a.Scheme); err == nil {
+		kind = gvk.Kind
 	}
-	err := a.Client.Get(ctx, namespacedName, runtimeObject)
-	if IgnoreNotFound(err) != nil {
-		return err
-	} else if errors.IsNotFound(err) {
+
+	// The dependents (e.g. the pods of a job) are deleted as well
+	err := a.Client.Delete(ctx, runtimeObject,
+		client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if errors.IsNotFound(err) {
 		return nil
+	} else if err != nil {
+		return err
 	}
 
-	err = a.Client.Delete(ctx, runtimeObject)
-	if IgnoreNotFound(err) != nil {
-		return err
+	_ = a.RecordEventf(owner, corev1.EventTypeNormal, Deleted,
+		"Deleted %s %s", kind, object.GetName())
+
+	return nil
+}
+
+// getUncached reads the object through the clientset if the controllers
+// do not watch its kind, as reading it through the client would start a
+// cluster-wide informer in the cache. The watched kinds are read through
+// the cache.
+func (a *Access) getUncached(ctx context.Context, key types.NamespacedName, object runtime.Object) error {
+	var err error
+	options := metav1.GetOptions{}
+	switch typed := object.(type) {
+	case *corev1.ConfigMap:
+		var got *corev1.ConfigMap
+		if got, err = a.Clientset.CoreV1().ConfigMaps(key.Namespace).Get(key.Name, options); err == nil {
+			*typed = *got
+		}
+	case *corev1.PersistentVolumeClaim:
+		var got *corev1.PersistentVolumeClaim
+		if got, err = a.Clientset.CoreV1().PersistentVolumeClaims(key.Namespace).Get(key.Name, options); err == nil {
+			*typed = *got
+		}
+	case *corev1.Pod:
+		var got *corev1.Pod
+		if got, err = a.Clientset.CoreV1().Pods(key.Namespace).Get(key.Name, options); err == nil {
+			*typed = *got
+		}
+	case *corev1.Endpoints:
+		var got *corev1.Endpoints
+		if got, err = a.Clientset.CoreV1().Endpoints(key.Namespace).Get(key.Name, options); err == nil {
+			*typed = *got
+		}
+	case *v1.StatefulSet:
+		var got *v1.StatefulSet
+		if got, err = a.Clientset.AppsV1().StatefulSets(key.Namespace).Get(key.Name, options); err == nil {
+			*typed = *got
+		}
+	default:
+		err = a.Client.Get(ctx, key, object)
 	}
+	return err
+}
 
-	if !errors.IsNotFound(err) {
-		_ = a.RecordEventf(owner, corev1.EventTypeNormal, Deleted,
-			"Deleted %v", object.GetSelfLink())
+// +kubebuilder:rbac:groups="",resources=configmaps;persistentvolumeclaims;services,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;delete
+
+// DeleteChildren deletes the objects created for the benchmark,
+// as registered in the Children list of its status. The volumes
+// created for the StatefulSets are deleted as well.
+func (a *Access) DeleteChildren(ctx context.Context, cr perfv1alpha1.Benchmark) error {
+	for _, child := range cr.GetBenchmarkStatus().Children {
+		if child.APIVersion == "" {
+			// Registered without version, the object is left to
+			// the garbage collector
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(child.APIVersion)
+		if err != nil {
+			return err
+		}
+		runtimeObject, err := a.Scheme.New(gv.WithKind(child.Kind))
+		if err != nil {
+			return err
+		}
+		object, ok := runtimeObject.(metav1.Object)
+		if !ok {
+			return fmt.Errorf("object (%T) is not a metav1.Object", runtimeObject)
+		}
+		object.SetNamespace(cr.GetNamespace())
+		object.SetName(child.Name)
+
+		if statefulSet, ok := object.(*v1.StatefulSet); ok {
+			if err := a.deleteStatefulSetVolumes(ctx, statefulSet, cr); err != nil {
+				return err
+			}
+		}
+
+		if err := a.DeleteObject(ctx, object, cr); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
+// deleteStatefulSetVolumes deletes the PersistentVolumeClaims created from
+// the volume claim templates of the StatefulSet, which are not removed
+// together with the StatefulSet
+func (a *Access) deleteStatefulSetVolumes(ctx context.Context, statefulSet *v1.StatefulSet, owner metav1.Object) error {
+	statefulSet = a.GetStatefulSet(types.NamespacedName{
+		Namespace: statefulSet.Namespace,
+		Name:      statefulSet.Name,
+	})
+	if statefulSet == nil || statefulSet.Spec.Replicas == nil {
+		return nil
+	}
+
+	for _, template := range statefulSet.Spec.VolumeClaimTemplates {
+		for ordinal := int32(0); ordinal < *statefulSet.Spec.Replicas; ordinal++ {
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("%s-%s-%d", template.Name, statefulSet.Name, ordinal),
+					Namespace: statefulSet.Namespace,
+				},
+			}
+			if err := a.DeleteObject(ctx, pvc, owner); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
-// IsJobFinished returns true if the given job has already succeeded or failed
+// IsJobFinished returns true if the given job has already succeeded or failed.
+// A job is considered to be failed when its Failed condition is set, which
+// covers both the exceeded backoff limit and the exceeded active deadline.
+// A job which is not (yet) present in the cache is considered unfinished.
 func (a *Access) IsJobFinished(namespacedName types.NamespacedName) (finished bool, err error) {
-	job, err := a.Clientset.BatchV1().Jobs(namespacedName.Namespace).Get(
-		namespacedName.Name, metav1.GetOptions{})
-	if err != nil {
+	job, err := a.getJob(namespacedName)
+	if err != nil || job == nil {
 		return false, err
 	}
 
-	finished = job.Status.CompletionTime != nil
+	finished = job.Status.CompletionTime != nil || JobFailedCondition(job) != nil
 	return finished, nil
 }
 
+// IsJobStarted returns true if any pod of the given job has left the
+// Pending phase, i.e. the pod is scheduled and its containers are started
+func (a *Access) IsJobStarted(namespacedName types.NamespacedName) (started bool, err error) {
+	pods, err := a.GetJobPods(namespacedName)
+	if err != nil || pods == nil {
+		return false, err
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodPending && pod.Status.Phase != corev1.PodUnknown {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+// GetJobFailure returns the details of the failure if the given job has failed,
+// or nil if the job is still running or has succeeded
+func (a *Access) GetJobFailure(namespacedName types.NamespacedName) (*JobFailure, error) {
+	job, err := a.getJob(namespacedName)
+	if err != nil || job == nil {
+		return nil, err
+	}
+	if JobFailedCondition(job) == nil {
+		return nil, nil
+	}
+
+	pods, err := a.GetJobPods(namespacedName)
+	if err != nil {
+		return nil, err
+	}
+	return NewJobFailure(job, pods), nil
+}
+
+// GetJob returns the given job, or nil if it is not found
 func (a *Access) GetJob(namespacedName types.NamespacedName) *batchv1.Job {
-	job, err := a.Clientset.BatchV1().Jobs(namespacedName.Namespace).Get(
-		namespacedName.Name, metav1.GetOptions{})
+	job, err := a.getJob(namespacedName)
 	if err != nil {
 		return nil
 	}
 	return job
 }
 
-func (a *Access) GetJobPods(namespacedName types.NamespacedName) (*corev1.PodList, error) {
-	job, err := a.Clientset.BatchV1().Jobs(namespacedName.Namespace).Get(
-		namespacedName.Name, metav1.GetOptions{})
-	if err != nil {
-		return nil, nil
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch
+
+// getJob returns the given job, or nil if it is not found
+func (a *Access) getJob(namespacedName types.NamespacedName) (*batchv1.Job, error) {
+	var job batchv1.Job
+	if err := a.Client.Get(context.Background(), namespacedName, &job); err != nil {
+		return nil, IgnoreNotFound(err)
 	}
+	return &job, nil
+}
 
-	uid := job.Labels["controller-uid"]
+// GetJobPods returns the pods created for the given job,
+// or nil if the job is not found. The pods are listed via the Clientset,
+// as listing them through Client would start an informer caching all
+// the pods of the cluster.
+func (a *Access) GetJobPods(namespacedName types.NamespacedName) (*corev1.PodList, error) {
+	job, err := a.getJob(namespacedName)
+	if err != nil || job == nil {
+		return nil, err
+	}
 
 	return a.Clientset.CoreV1().Pods(namespacedName.Namespace).List(
 		metav1.ListOptions{
-			LabelSelector: fmt.Sprintf("%s=%s", "controller-uid", uid),
+			LabelSelector: fmt.Sprintf("%s=%s", "controller-uid", job.Labels["controller-uid"]),
 		})
 }
 
-// +kubebuilder:rbac:groups="",resources=endpoints,verbs=get;list
+// +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
+
+// GetJobLogs returns the concatenated logs of the main container
+// of the successfully completed pods of the given job
+func (a *Access) GetJobLogs(namespacedName types.NamespacedName) (string, error) {
+	pods, err := a.GetJobPods(namespacedName)
+	if err != nil || pods == nil {
+		return "", err
+	}
+
+	var logs strings.Builder
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+		podLogs, err := a.GetPodLogs(&pods.Items[i], nil)
+		if err != nil {
+			return "", err
+		}
+		logs.WriteString(podLogs)
+	}
+
+	return logs.String(), nil
+}
+
+// GetPodLogs returns the logs of the main (first) container of the pod.
+// If tailLines is provided, only the given number of lines are returned
+// from the end of the logs.
+func (a *Access) GetPodLogs(pod *corev1.Pod, tailLines *int64) (string, error) {
+	if len(pod.Spec.Containers) == 0 {
+		return "", nil
+	}
+	logs, err := a.Clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name,
+		&corev1.PodLogOptions{
+			Container: pod.Spec.Containers[0].Name,
+			TailLines: tailLines,
+		}).DoRaw()
+	if err != nil {
+		return "", err
+	}
+	return string(logs), nil
+}
+
+// +kubebuilder:rbac:groups="",resources=endpoints,verbs=get
 
 // IsEndpointReady returns true if the given endpoint is fully connected to at least one pod
 func (a *Access) IsEndpointReady(namespacedName types.NamespacedName) (finished bool, err error) {
@@ -172,10 +394,10 @@ func (a *Access) IsEndpointReady(namespacedName types.NamespacedName) (finished
 	//
 	// Even though it is not enough to wait for the endpoints in certain cloud providers,
 	// it is still the closest we can get between service creation and connectibility.
-	endpoint, err := a.Clientset.CoreV1().Endpoints(namespacedName.Namespace).Get(
-		namespacedName.Name, metav1.GetOptions{})
-	if err != nil {
-		return false, err
+	var endpoint corev1.Endpoints
+	if err := a.getUncached(context.Background(), namespacedName, &endpoint); err != nil {
+		// The endpoint is created asynchronously for the service
+		return false, IgnoreNotFound(err)
 	}
 
 	readyAddresses := 0
@@ -193,38 +415,35 @@ func (a *Access) IsEndpointReady(namespacedName types.NamespacedName) (finished
 
 // IsDeploymentReady returns true if the given deployment's ready replicas matching with the desired replicas
 func (a *Access) IsDeploymentReady(namespacedName types.NamespacedName) (ready bool, err error) {
-	ready, err = false, nil
-	deployment, err := a.Clientset.AppsV1().Deployments(namespacedName.Namespace).Get(
-		namespacedName.Name, metav1.GetOptions{})
-	if err != nil {
-		return ready, err
+	var deployment v1.Deployment
+	if err := a.Client.Get(context.Background(), namespacedName, &deployment); err != nil {
+		return false, IgnoreNotFound(err)
 	}
 
-	ready = deployment.Status.ReadyReplicas == *deployment.Spec.Replicas
+	ready = deployment.Spec.Replicas != nil &&
+		deployment.Status.ReadyReplicas == *deployment.Spec.Replicas
 
-	return ready, err
+	return ready, nil
 }
 
 // IsStatefulSetReady returns true if the given deployment's ready replicas matching with the desired replicas
 func (a *Access) IsStatefulSetReady(namespacedName types.NamespacedName) (set *v1.StatefulSet, ready bool, err error) {
-	ready, err = false, nil
-	statefulSet, err := a.Clientset.AppsV1().StatefulSets(namespacedName.Namespace).Get(
-		namespacedName.Name, metav1.GetOptions{})
-	if err != nil {
-		return set, ready, err
+	statefulSet := a.GetStatefulSet(namespacedName)
+	if statefulSet == nil {
+		return nil, false, nil
 	}
 
-	ready = statefulSet.Status.ReadyReplicas == *statefulSet.Spec.Replicas
+	ready = statefulSet.Spec.Replicas != nil &&
+		statefulSet.Status.ReadyReplicas == *statefulSet.Spec.Replicas
 
-	return statefulSet, ready, err
+	return statefulSet, ready, nil
 }
 
+// GetStatefulSet returns the given stateful set, or nil if it is not found
 func (a *Access) GetStatefulSet(namespacedName types.NamespacedName) *v1.StatefulSet {
-	statefulSet, err := a.Clientset.AppsV1().StatefulSets(namespacedName.Namespace).Get(
-		namespacedName.Name, metav1.GetOptions{})
-
-	if err != nil {
-		return statefulSet
+	var statefulSet v1.StatefulSet
+	if err := a.getUncached(context.Background(), namespacedName, &statefulSet); err != nil {
+		return nil
 	}
-	return nil
+	return &statefulSet
 }
@@ -0,0 +1,67 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"math"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+// Deadline returns the time until the benchmark has to finish, or nil if
+// the benchmark has no timeout. The timeout is measured from the start of
+// the benchmark (or from its creation if it is not started yet).
+func Deadline(cr perfv1alpha1.Benchmark) *time.Time {
+	timeout := cr.GetCommonSpec().Timeout
+	if timeout == nil {
+		return nil
+	}
+
+	start := cr.GetCreationTimestamp().Time
+	if startTime := cr.GetBenchmarkStatus().StartTime; startTime != nil {
+		start = startTime.Time
+	}
+	deadline := start.Add(timeout.Duration)
+	return &deadline
+}
+
+// IsDeadlineExceeded returns true if the benchmark has a timeout,
+// which has elapsed by the given time
+func IsDeadlineExceeded(cr perfv1alpha1.Benchmark, now time.Time) bool {
+	deadline := Deadline(cr)
+	return deadline != nil && !now.Before(*deadline)
+}
+
+// setJobDeadline limits the active deadline of the job to the time
+// remaining until the deadline of the benchmark
+func setJobDeadline(job *batchv1.Job, cr perfv1alpha1.Benchmark, now time.Time) {
+	deadline := Deadline(cr)
+	if deadline == nil {
+		return
+	}
+
+	remaining := int64(math.Ceil(deadline.Sub(now).Seconds()))
+	if remaining < 1 {
+		remaining = 1
+	}
+	if job.Spec.ActiveDeadlineSeconds == nil || *job.Spec.ActiveDeadlineSeconds > remaining {
+		job.Spec.ActiveDeadlineSeconds = &remaining
+	}
+}
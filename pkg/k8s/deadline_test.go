@@ -0,0 +1,89 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("benchmark deadline", func() {
+	var cr *perfv1alpha1.Fio
+	var now time.Time
+
+	BeforeEach(func() {
+		now = time.Now()
+		cr = &perfv1alpha1.Fio{
+			ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: metav1.Time{Time: now.Add(-time.Minute)},
+			},
+		}
+	})
+
+	Context("without timeout", func() {
+		It("should have no deadline", func() {
+			Expect(Deadline(cr)).To(BeNil())
+			Expect(IsDeadlineExceeded(cr, now)).To(BeFalse())
+		})
+
+		It("should not limit the jobs", func() {
+			job := &batchv1.Job{}
+			setJobDeadline(job, cr, now)
+			Expect(job.Spec.ActiveDeadlineSeconds).To(BeNil())
+		})
+	})
+
+	Context("with timeout", func() {
+		BeforeEach(func() {
+			cr.Spec.Timeout = &metav1.Duration{Duration: 10 * time.Minute}
+		})
+
+		It("should be measured from the creation before the start", func() {
+			Expect(*Deadline(cr)).To(Equal(now.Add(9 * time.Minute)))
+		})
+
+		It("should be measured from the start", func() {
+			cr.Status.StartTime = &metav1.Time{Time: now}
+			Expect(*Deadline(cr)).To(Equal(now.Add(10 * time.Minute)))
+			Expect(IsDeadlineExceeded(cr, now.Add(11*time.Minute))).To(BeTrue())
+		})
+
+		It("should limit the jobs to the remaining time", func() {
+			job := &batchv1.Job{}
+			setJobDeadline(job, cr, now)
+			Expect(job.Spec.ActiveDeadlineSeconds).To(Equal(int64Ptr(540)))
+		})
+
+		It("should keep the shorter deadline of the job", func() {
+			job := &batchv1.Job{}
+			job.Spec.ActiveDeadlineSeconds = int64Ptr(60)
+			setJobDeadline(job, cr, now)
+			Expect(job.Spec.ActiveDeadlineSeconds).To(Equal(int64Ptr(60)))
+		})
+	})
+})
+
+func int64Ptr(value int64) *int64 {
+	return &value
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NewSecretEnvVar creates an environment variable which is populated from
+// the Secret key selected by ref, so the credential itself never appears in
+// the pod spec. When ref is nil the plain value is used instead.
+func NewSecretEnvVar(name, value string, ref *corev1.SecretKeySelector) corev1.EnvVar {
+	if ref == nil {
+		return corev1.EnvVar{Name: name, Value: value}
+	}
+
+	return corev1.EnvVar{
+		Name:      name,
+		ValueFrom: &corev1.EnvVarSource{SecretKeyRef: ref.DeepCopy()},
+	}
+}
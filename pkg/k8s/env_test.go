@@ -0,0 +1,43 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("secret env var", func() {
+	It("should use the plain value without a secret reference", func() {
+		Expect(NewSecretEnvVar("PASSWORD", "hunter2", nil)).To(Equal(
+			corev1.EnvVar{Name: "PASSWORD", Value: "hunter2"}))
+	})
+
+	It("should read the value from the secret when referenced", func() {
+		ref := &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "db"},
+			Key:                  "password",
+		}
+		envVar := NewSecretEnvVar("PASSWORD", "hunter2", ref)
+
+		Expect(envVar.Value).To(BeEmpty())
+		Expect(envVar.ValueFrom).NotTo(BeNil())
+		Expect(envVar.ValueFrom.SecretKeyRef).To(Equal(ref))
+	})
+})
@@ -32,6 +32,73 @@ const (
 	Created = "Created"
 	// Deleted is an event provided via EventRecorder
 	Deleted = "Deleted"
+	// Failed is an event provided via EventRecorder
+	Failed = "Failed"
+	// Completed is the reason of the successful benchmark completion
+	Completed = "Completed"
+	// ValidationFailed is the reason of the benchmark failure when
+	// the CR did not pass the validation
+	ValidationFailed = "ValidationFailed"
+	// ResultsParseFailed is an event provided via EventRecorder when
+	// the output of the benchmark could not be parsed
+	ResultsParseFailed = "ResultsParseFailed"
+	// Recorded is an event provided via EventRecorder when the
+	// BenchmarkResult of the finished benchmark is created
+	Recorded = "Recorded"
+	// RecordFailed is an event provided via EventRecorder when the
+	// BenchmarkResult of the finished benchmark could not be created
+	RecordFailed = "RecordFailed"
+	// Cancelled is the reason of the benchmark cancellation
+	Cancelled = "Cancelled"
+	// DeadlineExceeded is the reason of the benchmark failure when
+	// the benchmark has not finished within its timeout
+	DeadlineExceeded = "DeadlineExceeded"
+	// StepFailed is the reason of the benchmark suite failure when
+	// the benchmark of any of its steps has failed
+	StepFailed = "StepFailed"
+	// Skipped is the reason of the benchmark suite steps which were
+	// not started, because the suite was stopped by a failed step
+	Skipped = "Skipped"
+	// AlreadyActive is an event provided via EventRecorder when a
+	// scheduled run is skipped, as the previous run is still in progress
+	AlreadyActive = "AlreadyActive"
+	// TooManyMissedRuns is an event provided via EventRecorder when the
+	// runs of a schedule are skipped, as too many of them were missed
+	TooManyMissedRuns = "TooManyMissedRuns"
+	// RunFailed is the reason of the benchmark sweep failure when
+	// the benchmark of any of its runs has failed
+	RunFailed = "RunFailed"
+	// Repeating is the reason of the repeated benchmarks waiting for
+	// their next run
+	Repeating = "Repeating"
+	// Regressed is an event provided via EventRecorder when any of the
+	// metrics of the benchmark has regressed compared to its baseline
+	Regressed = "Regressed"
+	// WithinTolerance is the reason of the Regressed condition when
+	// the metrics of the benchmark are within the tolerance of the baseline
+	WithinTolerance = "WithinTolerance"
+	// NoBaseline is the reason of the Regressed condition when the
+	// baseline of the benchmark was not found
+	NoBaseline = "NoBaseline"
+	// AssertionFailed is the reason of the benchmark failure when any of
+	// the assertions on the results of the benchmark does not hold
+	AssertionFailed = "AssertionFailed"
+	// NoMatchingNodes is the reason of the benchmark fan-out and iperf3
+	// matrix failure when not enough schedulable nodes match the node selector
+	NoMatchingNodes = "NoMatchingNodes"
+	// NodeFailed is the reason of the benchmark fan-out failure when
+	// the benchmark of any of its nodes has failed
+	NodeFailed = "NodeFailed"
+	// PairFailed is the reason of the iperf3 matrix failure when the
+	// benchmark of any of its node pairs has failed
+	PairFailed = "PairFailed"
+	// PodFailed is the reason of the PVC latency benchmark failure when
+	// any of the pods consuming its PVCs has failed
+	PodFailed = "PodFailed"
+	// Outliers is an event provided via EventRecorder when the metrics
+	// of any of the nodes of a benchmark fan-out, or the throughput of
+	// any of the node pairs of an iperf3 matrix deviate from the others
+	Outliers = "Outliers"
 )
 
 // NewEventRecorder creates a new event recorder
@@ -17,6 +17,8 @@ limitations under the License.
 package k8s
 
 import (
+	"fmt"
+
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -99,3 +101,87 @@ func NewPerfJob(objectMeta metav1.ObjectMeta, app string, imageSpec perfv1alpha1
 
 	return &job
 }
+
+// JobFailure contains the details of a failed benchmark job
+type JobFailure struct {
+	// Reason is the reason of the job's Failed condition
+	// (e.g. BackoffLimitExceeded or DeadlineExceeded)
+	Reason string
+	// Message is the human readable message of the job's Failed condition
+	Message string
+	// ContainerName is the name of the container which terminated with error
+	ContainerName string
+	// ExitCode is the exit code of the failed container
+	ExitCode int32
+	// TerminationMessage is the termination message (or the termination
+	// reason if the message is not available) of the failed container
+	TerminationMessage string
+}
+
+// String returns the failure in a single line format suitable for
+// events and status messages
+func (f *JobFailure) String() string {
+	msg := f.Reason
+	if f.Message != "" {
+		msg += ": " + f.Message
+	}
+	if f.ContainerName != "" {
+		msg += fmt.Sprintf(" (container %v exited with code %v", f.ContainerName, f.ExitCode)
+		if f.TerminationMessage != "" {
+			msg += ": " + f.TerminationMessage
+		}
+		msg += ")"
+	}
+	return msg
+}
+
+// JobFailedCondition returns the Failed condition of the job if the
+// job has failed, nil otherwise
+func JobFailedCondition(job *batchv1.Job) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		condition := &job.Status.Conditions[i]
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return condition
+		}
+	}
+	return nil
+}
+
+// NewJobFailure creates a JobFailure from the given failed job and its pods.
+// The container details are taken from the first container that terminated
+// with non-zero exit code. It returns nil if the job has not failed.
+func NewJobFailure(job *batchv1.Job, pods *corev1.PodList) *JobFailure {
+	condition := JobFailedCondition(job)
+	if condition == nil {
+		return nil
+	}
+
+	failure := JobFailure{
+		Reason:  condition.Reason,
+		Message: condition.Message,
+	}
+	if pods == nil {
+		return &failure
+	}
+
+	for _, pod := range pods.Items {
+		statuses := []corev1.ContainerStatus{}
+		statuses = append(statuses, pod.Status.InitContainerStatuses...)
+		statuses = append(statuses, pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			terminated := status.State.Terminated
+			if terminated == nil || terminated.ExitCode == 0 {
+				continue
+			}
+			failure.ContainerName = status.Name
+			failure.ExitCode = terminated.ExitCode
+			failure.TerminationMessage = terminated.Message
+			if failure.TerminationMessage == "" {
+				failure.TerminationMessage = terminated.Reason
+			}
+			return &failure
+		}
+	}
+
+	return &failure
+}
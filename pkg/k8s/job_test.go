@@ -167,3 +167,82 @@ var _ = Describe("perf job", func() {
 		})
 	})
 })
+
+var _ = Describe("job failure", func() {
+	var job *batchv1.Job
+	var pods *corev1.PodList
+
+	BeforeEach(func() {
+		job = &batchv1.Job{
+			Status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{
+					{
+						Type:    batchv1.JobFailed,
+						Status:  corev1.ConditionTrue,
+						Reason:  "BackoffLimitExceeded",
+						Message: "Job has reached the specified backoff limit",
+					},
+				},
+			},
+		}
+		pods = &corev1.PodList{
+			Items: []corev1.Pod{
+				{
+					Status: corev1.PodStatus{
+						ContainerStatuses: []corev1.ContainerStatus{
+							{
+								Name: "fio",
+								State: corev1.ContainerState{
+									Terminated: &corev1.ContainerStateTerminated{
+										ExitCode: 1,
+										Reason:   "Error",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	Describe("JobFailedCondition", func() {
+		It("should return the Failed condition", func() {
+			Expect(JobFailedCondition(job)).To(Equal(&job.Status.Conditions[0]))
+		})
+		It("should return nil for a completed job", func() {
+			job.Status.Conditions[0].Type = batchv1.JobComplete
+			Expect(JobFailedCondition(job)).To(BeNil())
+		})
+	})
+
+	Describe("NewJobFailure", func() {
+		It("should contain the reason of the job condition", func() {
+			failure := NewJobFailure(job, pods)
+			Expect(failure).NotTo(BeNil())
+			Expect(failure.Reason).To(Equal("BackoffLimitExceeded"))
+		})
+		It("should contain the details of the failed container", func() {
+			failure := NewJobFailure(job, pods)
+			Expect(failure.ContainerName).To(Equal("fio"))
+			Expect(failure.ExitCode).To(Equal(int32(1)))
+			Expect(failure.TerminationMessage).To(Equal("Error"))
+			Expect(failure.String()).To(ContainSubstring("exited with code 1"))
+		})
+		It("should prefer the termination message over the reason", func() {
+			pods.Items[0].Status.ContainerStatuses[0].State.Terminated.Message = "no space left on device"
+			failure := NewJobFailure(job, pods)
+			Expect(failure.TerminationMessage).To(Equal("no space left on device"))
+		})
+		It("should report deadline exceeded jobs without container details", func() {
+			job.Status.Conditions[0].Reason = "DeadlineExceeded"
+			failure := NewJobFailure(job, nil)
+			Expect(failure.Reason).To(Equal("DeadlineExceeded"))
+			Expect(failure.ContainerName).To(BeEmpty())
+		})
+		It("should return nil for a running job", func() {
+			job.Status.Conditions = nil
+			Expect(NewJobFailure(job, pods)).To(BeNil())
+		})
+	})
+})
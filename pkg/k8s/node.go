@@ -0,0 +1,77 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+
+// GetSchedulableNodes returns the names of the nodes matching the labels,
+// which can run benchmark pods, ordered by name
+func (a *Access) GetSchedulableNodes(ctx context.Context, matchLabels map[string]string) ([]string, error) {
+	var nodes corev1.NodeList
+	if err := a.Client.List(ctx, &nodes, client.MatchingLabels(matchLabels)); err != nil {
+		return nil, err
+	}
+	return SchedulableNodes(nodes.Items), nil
+}
+
+// SchedulableNodes returns the names of the nodes which can run benchmark
+// pods: the schedulable and ready ones, ordered by name
+func SchedulableNodes(nodes []corev1.Node) []string {
+	var names []string
+	for i := range nodes {
+		if !nodes[i].Spec.Unschedulable && isNodeReady(&nodes[i]) {
+			names = append(names, nodes[i].Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isNodeReady returns true if the Ready condition of the node is true
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// ValidateNodeSelector checks that the node selector consists of valid
+// label keys and values
+func ValidateNodeSelector(nodeSelector map[string]string) error {
+	for key, value := range nodeSelector {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid node selector key %q: %s", key, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("invalid node selector value %q: %s", value, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("nodes", func() {
+	newNode := func(name string, ready corev1.ConditionStatus, unschedulable bool) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"pool": "storage"}},
+			Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: ready},
+			}},
+		}
+	}
+
+	It("should skip the unschedulable and not ready nodes", func() {
+		Expect(SchedulableNodes([]corev1.Node{
+			*newNode("node-c", corev1.ConditionTrue, false),
+			*newNode("node-b", corev1.ConditionFalse, false),
+			*newNode("node-a", corev1.ConditionTrue, false),
+			*newNode("node-d", corev1.ConditionTrue, true),
+			{ObjectMeta: metav1.ObjectMeta{Name: "node-e"}},
+		})).To(Equal([]string{"node-a", "node-c"}))
+	})
+
+	It("should list the nodes matching the labels", func() {
+		scheme := runtime.NewScheme()
+		_ = k8sscheme.AddToScheme(scheme)
+		other := newNode("node-x", corev1.ConditionTrue, false)
+		other.Labels["pool"] = "compute"
+		access := Access{
+			Client: fake.NewFakeClientWithScheme(scheme,
+				newNode("node-b", corev1.ConditionTrue, false),
+				newNode("node-a", corev1.ConditionTrue, false),
+				other),
+			Scheme: scheme,
+		}
+
+		nodes, err := access.GetSchedulableNodes(context.Background(), map[string]string{"pool": "storage"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nodes).To(Equal([]string{"node-a", "node-b"}))
+	})
+
+	It("should validate node selectors", func() {
+		Expect(ValidateNodeSelector(map[string]string{"node-role.kubernetes.io/worker": ""})).To(Succeed())
+		Expect(ValidateNodeSelector(map[string]string{"pool": "not valid"})).NotTo(Succeed())
+		Expect(ValidateNodeSelector(map[string]string{"-pool": "storage"})).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,105 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+// GetBenchmarkPods returns the pods of the jobs, deployments and
+// statefulsets created for the benchmark, including the ones of the
+// benchmarks run by the benchmark (e.g. the steps of a suite). The pods
+// are ordered by their creation time.
+func (a *Access) GetBenchmarkPods(cr perfv1alpha1.Benchmark) ([]corev1.Pod, error) {
+	var pods []corev1.Pod
+	for _, child := range cr.GetBenchmarkStatus().Children {
+		childPods, err := a.getChildPods(cr.GetNamespace(), child)
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, childPods...)
+	}
+
+	sort.SliceStable(pods, func(i, j int) bool {
+		return pods[i].CreationTimestamp.Before(&pods[j].CreationTimestamp)
+	})
+	return pods, nil
+}
+
+func (a *Access) getChildPods(namespace string, child perfv1alpha1.ChildReference) ([]corev1.Pod, error) {
+	ctx := context.Background()
+	key := types.NamespacedName{Namespace: namespace, Name: child.Name}
+
+	var selector *metav1.LabelSelector
+	switch child.Kind {
+	case "Job":
+		pods, err := a.GetJobPods(key)
+		if err != nil || pods == nil {
+			return nil, err
+		}
+		return pods.Items, nil
+	case "Deployment":
+		var deployment appsv1.Deployment
+		if err := a.Client.Get(ctx, key, &deployment); err != nil {
+			return nil, IgnoreNotFound(err)
+		}
+		selector = deployment.Spec.Selector
+	case "StatefulSet":
+		var statefulSet appsv1.StatefulSet
+		if err := a.Client.Get(ctx, key, &statefulSet); err != nil {
+			return nil, IgnoreNotFound(err)
+		}
+		selector = statefulSet.Spec.Selector
+	default:
+		if child.APIVersion != perfv1alpha1.GroupVersion.String() {
+			return nil, nil
+		}
+		object, err := a.Scheme.New(perfv1alpha1.GroupVersion.WithKind(child.Kind))
+		if err != nil {
+			return nil, nil
+		}
+		benchmark, ok := object.(perfv1alpha1.Benchmark)
+		if !ok {
+			return nil, nil
+		}
+		if err := a.Client.Get(ctx, key, benchmark); err != nil {
+			return nil, IgnoreNotFound(err)
+		}
+		return a.GetBenchmarkPods(benchmark)
+	}
+
+	if selector == nil {
+		return nil, nil
+	}
+	// The pods are not cached, see GetJobPods
+	pods, err := a.Clientset.CoreV1().Pods(namespace).List(metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(selector.MatchLabels).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("benchmark pods", func() {
+	var access Access
+	var cr *perfv1alpha1.Iperf3
+	var suite *perfv1alpha1.BenchmarkSuite
+
+	newPod := func(name string, labels map[string]string, created time.Time) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "pods",
+			Labels:            labels,
+			CreationTimestamp: metav1.NewTime(created),
+		}}
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		_ = k8sscheme.AddToScheme(scheme)
+		_ = perfv1alpha1.AddToScheme(scheme)
+
+		cr = &perfv1alpha1.Iperf3{ObjectMeta: metav1.ObjectMeta{Name: "iperf3", Namespace: "pods"}}
+		cr.Status.AddChild("apps/v1", "Deployment", "iperf3")
+		cr.Status.AddChild("batch/v1", "Job", "iperf3-client")
+		cr.Status.AddChild("v1", "Service", "iperf3")
+
+		suite = &perfv1alpha1.BenchmarkSuite{ObjectMeta: metav1.ObjectMeta{Name: "suite", Namespace: "pods"}}
+		suite.Status.AddChild(perfv1alpha1.GroupVersion.String(), "Iperf3", "iperf3")
+
+		serverLabels := map[string]string{"kubestone.xridge.io/app": "iperf3-server"}
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "iperf3", Namespace: "pods"},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: serverLabels},
+			},
+		}
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name:      "iperf3-client",
+			Namespace: "pods",
+			Labels:    map[string]string{"controller-uid": "client-uid"},
+		}}
+
+		now := time.Now()
+		access = Access{
+			Client: fake.NewFakeClientWithScheme(scheme, cr.DeepCopy(), deployment, job),
+			Clientset: k8sfake.NewSimpleClientset(
+				newPod("iperf3-client-x1", map[string]string{"controller-uid": "client-uid"}, now),
+				newPod("iperf3-server-a1", serverLabels, now.Add(-time.Minute)),
+				newPod("unrelated", map[string]string{"app": "other"}, now.Add(-time.Hour))),
+			Scheme: scheme,
+		}
+	})
+
+	It("should return the pods of the server and the client", func() {
+		pods, err := access.GetBenchmarkPods(cr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pods).To(HaveLen(2))
+		Expect(pods[0].Name).To(Equal("iperf3-server-a1"))
+		Expect(pods[1].Name).To(Equal("iperf3-client-x1"))
+	})
+
+	It("should return the pods of the benchmarks run by the benchmark", func() {
+		pods, err := access.GetBenchmarkPods(suite)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pods).To(HaveLen(2))
+	})
+
+	It("should skip the deleted children", func() {
+		cr.Status.AddChild("batch/v1", "Job", "iperf3-deleted")
+		pods, err := access.GetBenchmarkPods(cr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pods).To(HaveLen(2))
+	})
+})
@@ -0,0 +1,175 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/stats"
+)
+
+// IsRepeating returns true if the benchmark is waiting for the objects of
+// its previous run to be deleted before starting its next run
+func IsRepeating(cr perfv1alpha1.Benchmark) bool {
+	status := cr.GetBenchmarkStatus()
+	return status.Phase == perfv1alpha1.BenchmarkPending && status.Reason == Repeating
+}
+
+// finishIteration stores the results of the successful run of a repeated
+// benchmark among its iterations. It returns true if further runs are
+// needed, otherwise the statistics of the measured runs become the
+// results of the benchmark.
+func finishIteration(cr perfv1alpha1.Benchmark, now metav1.Time) (repeat bool) {
+	runs := cr.GetCommonSpec().Runs()
+	if runs <= 1 {
+		return false
+	}
+
+	status := cr.GetBenchmarkStatus()
+	iteration := perfv1alpha1.BenchmarkIteration{
+		Iteration:      int32(len(status.Iterations) + 1),
+		Warmup:         len(status.Iterations) < int(cr.GetCommonSpec().WarmupRuns),
+		CompletionTime: &now,
+	}
+	if status.Results != nil {
+		iteration.Metrics = status.Results.Metrics
+	}
+	status.Iterations = append(status.Iterations, iteration)
+
+	if len(status.Iterations) < runs {
+		status.Results = nil
+		return true
+	}
+
+	// The detailed results of the last run are kept
+	if status.Results == nil {
+		status.Results = &perfv1alpha1.BenchmarkResults{}
+	}
+	status.Results.Metrics, status.Results.Statistics = Aggregate(status.Iterations)
+	if len(status.Results.Metrics) == 0 && status.Results.Fio == nil &&
+		status.Results.Iperf3 == nil && status.Results.StorageClasses == nil {
+		status.Results = nil
+	}
+	return false
+}
+
+// Aggregate computes the statistics of the metrics of the measured (not
+// warmup) iterations. The returned metrics are the mean values, in the
+// order in which the metrics first appear in the iterations.
+func Aggregate(iterations []perfv1alpha1.BenchmarkIteration) ([]perfv1alpha1.BenchmarkMetric,
+	[]perfv1alpha1.MetricStatistics) {
+	var names []string
+	units := map[string]string{}
+	values := map[string][]float64{}
+	for _, iteration := range iterations {
+		if iteration.Warmup {
+			continue
+		}
+		for i := range iteration.Metrics {
+			metric := &iteration.Metrics[i]
+			value, err := metric.Float64()
+			if err != nil {
+				continue
+			}
+			if _, ok := values[metric.Name]; !ok {
+				names = append(names, metric.Name)
+				units[metric.Name] = metric.Unit
+			}
+			values[metric.Name] = append(values[metric.Name], value)
+		}
+	}
+
+	var means []perfv1alpha1.BenchmarkMetric
+	var statistics []perfv1alpha1.MetricStatistics
+	for _, name := range names {
+		summary := stats.Summarize(values[name])
+		means = append(means, perfv1alpha1.BenchmarkMetric{
+			Name:  name,
+			Value: formatFloat(summary.Mean),
+			Unit:  units[name],
+		})
+		statistics = append(statistics, perfv1alpha1.MetricStatistics{
+			Name:           name,
+			Unit:           units[name],
+			Samples:        int32(summary.Samples),
+			Mean:           formatFloat(summary.Mean),
+			Median:         formatFloat(summary.Median),
+			StdDev:         formatFloat(summary.StdDev),
+			Min:            formatFloat(summary.Min),
+			Max:            formatFloat(summary.Max),
+			ConfidenceLow:  formatFloat(summary.ConfidenceLow),
+			ConfidenceHigh: formatFloat(summary.ConfidenceHigh),
+		})
+	}
+	return means, statistics
+}
+
+// formatFloat formats the value the same way as the metrics, NaN is
+// formatted as empty string
+func formatFloat(value float64) string {
+	if math.IsNaN(value) {
+		return ""
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// repetitionMessage describes the next run of the repeated benchmark
+func repetitionMessage(cr perfv1alpha1.Benchmark) string {
+	next := len(cr.GetBenchmarkStatus().Iterations) + 1
+	message := fmt.Sprintf("Starting run %d of %d", next, cr.GetCommonSpec().Runs())
+	if next <= int(cr.GetCommonSpec().WarmupRuns) {
+		message += " (warmup)"
+	}
+	return message
+}
+
+// ChildrenExist returns true if any of the objects registered in the
+// Children list of the benchmark status still exists
+func (a *Access) ChildrenExist(ctx context.Context, cr perfv1alpha1.Benchmark) (bool, error) {
+	for _, child := range cr.GetBenchmarkStatus().Children {
+		if child.APIVersion == "" {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(child.APIVersion)
+		if err != nil {
+			return false, err
+		}
+		object, err := a.Scheme.New(gv.WithKind(child.Kind))
+		if err != nil {
+			return false, err
+		}
+		err = a.getUncached(ctx, types.NamespacedName{
+			Namespace: cr.GetNamespace(),
+			Name:      child.Name,
+		}, object)
+		if err == nil {
+			return true, nil
+		} else if !errors.IsNotFound(err) {
+			return false, err
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("repetition", func() {
+	var ctx context.Context
+	var access Access
+	var cr *perfv1alpha1.Pgbench
+	var name types.NamespacedName
+
+	// runOnce simulates a successful run reporting the given tps
+	runOnce := func(tps float64) {
+		Expect(access.UpdatePhase(ctx, cr, perfv1alpha1.BenchmarkRunning, "", "")).To(Succeed())
+		cr.Status.Results = &perfv1alpha1.BenchmarkResults{}
+		cr.Status.Results.AddMetric("tps", tps, "tx/s")
+		Expect(access.FinishBenchmark(ctx, cr, nil)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		_ = k8sscheme.AddToScheme(scheme)
+		_ = perfv1alpha1.AddToScheme(scheme)
+
+		repetitions := int32(3)
+		cr = &perfv1alpha1.Pgbench{
+			ObjectMeta: metav1.ObjectMeta{Name: "pgbench", Namespace: "repetition"},
+		}
+		cr.Spec.Repetitions = &repetitions
+		cr.Spec.WarmupRuns = 1
+		name = types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}
+
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "pgbench", Namespace: "repetition"}}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "pgbench", Namespace: "repetition"}}
+		access = Access{
+			Client:        fake.NewFakeClientWithScheme(scheme, cr.DeepCopy(), job),
+			Clientset:     k8sfake.NewSimpleClientset(configMap),
+			Scheme:        scheme,
+			EventRecorder: record.NewFakeRecorder(10),
+		}
+		Expect(access.Client.Get(ctx, name, cr)).To(Succeed())
+	})
+
+	It("counts the warmup runs", func() {
+		Expect(cr.Spec.Runs()).To(Equal(4))
+		Expect((&perfv1alpha1.CommonSpec{}).Runs()).To(Equal(1))
+	})
+
+	It("repeats the benchmark until all the runs have finished", func() {
+		runOnce(1000)
+		Expect(cr.Status.Phase).To(Equal(perfv1alpha1.BenchmarkPending))
+		Expect(cr.Status.Message).To(Equal("Starting run 2 of 4"))
+		Expect(IsRepeating(cr)).To(BeTrue())
+		Expect(cr.Status.Results).To(BeNil())
+		Expect(cr.Status.Iterations).To(HaveLen(1))
+		Expect(cr.Status.Iterations[0].Warmup).To(BeTrue())
+
+		runOnce(100)
+		runOnce(110)
+		Expect(cr.Status.IsFinished()).To(BeFalse())
+		runOnce(120)
+
+		var stored perfv1alpha1.Pgbench
+		Expect(access.Client.Get(ctx, name, &stored)).To(Succeed())
+		Expect(stored.Status.Phase).To(Equal(perfv1alpha1.BenchmarkSucceeded))
+		Expect(IsRepeating(&stored)).To(BeFalse())
+		Expect(stored.Status.Iterations).To(HaveLen(4))
+		Expect(stored.Status.Iterations[3].Iteration).To(Equal(int32(4)))
+		Expect(stored.Status.Iterations[3].Warmup).To(BeFalse())
+
+		// The warmup run is excluded from the statistics
+		Expect(stored.Status.Results.GetMetric("tps").Value).To(Equal("110"))
+		Expect(stored.Status.Results.Statistics).To(HaveLen(1))
+		statistics := stored.Status.Results.Statistics[0]
+		Expect(statistics.Name).To(Equal("tps"))
+		Expect(statistics.Unit).To(Equal("tx/s"))
+		Expect(statistics.Samples).To(Equal(int32(3)))
+		Expect(statistics.Median).To(Equal("110"))
+		Expect(statistics.StdDev).To(Equal("10"))
+		Expect(statistics.Min).To(Equal("100"))
+		Expect(statistics.Max).To(Equal("120"))
+		Expect(statistics.ConfidenceLow).NotTo(BeEmpty())
+		Expect(statistics.ConfidenceHigh).NotTo(BeEmpty())
+	})
+
+	It("does not repeat failed runs", func() {
+		runOnce(100)
+		Expect(access.FinishBenchmark(ctx, cr, &JobFailure{Reason: "BackoffLimitExceeded"})).To(Succeed())
+		Expect(cr.Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+		Expect(cr.Status.Iterations).To(HaveLen(1))
+	})
+
+	It("leaves the confidence interval of single samples empty", func() {
+		_, statistics := Aggregate([]perfv1alpha1.BenchmarkIteration{{
+			Iteration: 1,
+			Metrics:   []perfv1alpha1.BenchmarkMetric{{Name: "tps", Value: "100"}},
+		}})
+		Expect(statistics).To(HaveLen(1))
+		Expect(statistics[0].ConfidenceLow).To(BeEmpty())
+	})
+
+	It("checks the existence of the children", func() {
+		Expect(access.ChildrenExist(ctx, cr)).To(BeFalse())
+		cr.Status.AddChild("batch/v1", "Job", "missing")
+		Expect(access.ChildrenExist(ctx, cr)).To(BeFalse())
+		cr.Status.AddChild("batch/v1", "Job", "pgbench")
+		Expect(access.ChildrenExist(ctx, cr)).To(BeTrue())
+	})
+
+	It("checks the existence of the children not watched through the clientset", func() {
+		cr.Status.AddChild("v1", "ConfigMap", "missing")
+		Expect(access.ChildrenExist(ctx, cr)).To(BeFalse())
+		cr.Status.AddChild("v1", "ConfigMap", "pgbench")
+		Expect(access.ChildrenExist(ctx, cr)).To(BeTrue())
+	})
+})
@@ -0,0 +1,62 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+const (
+	// MinRequeueDelay is the delay of the first check of a condition
+	// which is not signalled by any of the watched objects
+	MinRequeueDelay = time.Second
+
+	// MaxRequeueDelay is the upper limit of the delay between the checks
+	MaxRequeueDelay = 30 * time.Second
+)
+
+// RequeueWithBackoff returns the result which schedules the next check of
+// a benchmark waiting in its current phase. The delay is half of the time
+// spent in the phase, so the checks back off exponentially between
+// MinRequeueDelay and MaxRequeueDelay.
+func RequeueWithBackoff(cr perfv1alpha1.Benchmark) ctrl.Result {
+	delay := MinRequeueDelay
+	if since := cr.GetBenchmarkStatus().PhaseTransitionTime; since != nil {
+		if waited := time.Since(since.Time) / 2; waited > delay {
+			delay = waited
+		}
+	}
+	if delay > MaxRequeueDelay {
+		delay = MaxRequeueDelay
+	}
+	return ctrl.Result{RequeueAfter: delay}
+}
+
+// WaitForJobs returns the result of the reconciliation while the jobs of
+// the benchmark are running. The completion of the jobs is signalled by the
+// owned Job objects, while the start of their pods (which moves the benchmark
+// from Pending to Running phase) is checked with backoff.
+func WaitForJobs(cr perfv1alpha1.Benchmark) ctrl.Result {
+	if cr.GetBenchmarkStatus().Phase == perfv1alpha1.BenchmarkPending {
+		return RequeueWithBackoff(cr)
+	}
+	return ctrl.Result{}
+}
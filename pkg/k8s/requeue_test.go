@@ -0,0 +1,191 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("requeue", func() {
+	var cr *perfv1alpha1.Fio
+
+	BeforeEach(func() {
+		cr = &perfv1alpha1.Fio{}
+	})
+
+	Describe("RequeueWithBackoff", func() {
+		It("should start with the minimal delay", func() {
+			Expect(RequeueWithBackoff(cr).RequeueAfter).To(Equal(MinRequeueDelay))
+			cr.Status.SetPhase(perfv1alpha1.BenchmarkDeployingServer, "", "")
+			Expect(RequeueWithBackoff(cr).RequeueAfter).To(Equal(MinRequeueDelay))
+		})
+
+		It("should back off with the time spent in the phase", func() {
+			cr.Status.SetPhase(perfv1alpha1.BenchmarkDeployingServer, "", "")
+			cr.Status.PhaseTransitionTime = &metav1.Time{Time: time.Now().Add(-20 * time.Second)}
+			Expect(RequeueWithBackoff(cr).RequeueAfter).To(BeNumerically("~", 10*time.Second, time.Second))
+		})
+
+		It("should not exceed the maximal delay", func() {
+			cr.Status.SetPhase(perfv1alpha1.BenchmarkDeployingServer, "", "")
+			cr.Status.PhaseTransitionTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+			Expect(RequeueWithBackoff(cr).RequeueAfter).To(Equal(MaxRequeueDelay))
+		})
+	})
+
+	Describe("WaitForJobs", func() {
+		It("should poll while the job pods are pending", func() {
+			cr.Status.SetPhase(perfv1alpha1.BenchmarkPending, "", "")
+			Expect(WaitForJobs(cr).RequeueAfter).NotTo(BeZero())
+		})
+
+		It("should rely on the job events once running", func() {
+			cr.Status.SetPhase(perfv1alpha1.BenchmarkRunning, "", "")
+			Expect(WaitForJobs(cr).Requeue).To(BeFalse())
+			Expect(WaitForJobs(cr).RequeueAfter).To(BeZero())
+		})
+	})
+})
+
+var _ = Describe("children state", func() {
+	var access Access
+	var jobName types.NamespacedName
+
+	BeforeEach(func() {
+		childScheme := runtime.NewScheme()
+		_ = k8sscheme.AddToScheme(childScheme)
+
+		jobName = types.NamespacedName{Namespace: "children", Name: "job"}
+		completionTime := metav1.Now()
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      jobName.Name,
+				Namespace: jobName.Namespace,
+				Labels:    map[string]string{"controller-uid": "1234"},
+			},
+			Status: batchv1.JobStatus{CompletionTime: &completionTime},
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "job-abcde",
+				Namespace: jobName.Namespace,
+				Labels:    map[string]string{"controller-uid": "1234"},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+		}
+		otherPod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "other",
+				Namespace: jobName.Namespace,
+				Labels:    map[string]string{"controller-uid": "5678"},
+			},
+		}
+		endpoints := &corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Name: "server", Namespace: jobName.Namespace},
+			Subsets: []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}},
+			}},
+		}
+		access = Access{
+			Client:    fake.NewFakeClientWithScheme(childScheme, job),
+			Clientset: k8sfake.NewSimpleClientset(pod, otherPod, endpoints),
+			Scheme:    childScheme,
+		}
+	})
+
+	It("should report the finished job", func() {
+		Expect(access.IsJobFinished(jobName)).To(BeTrue())
+		Expect(access.IsJobStarted(jobName)).To(BeTrue())
+	})
+
+	It("should list the pods of the job only", func() {
+		pods, err := access.GetJobPods(jobName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pods.Items).To(HaveLen(1))
+		Expect(pods.Items[0].Name).To(Equal("job-abcde"))
+	})
+
+	It("should consider the missing job unfinished", func() {
+		missing := types.NamespacedName{Namespace: "children", Name: "missing"}
+		Expect(access.IsJobFinished(missing)).To(BeFalse())
+		Expect(access.GetJobFailure(missing)).To(BeNil())
+	})
+
+	It("should delete the children with the volumes of the stateful sets", func() {
+		ctx := context.Background()
+		replicas := int32(2)
+		statefulSet := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "children"},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: &replicas,
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+					ObjectMeta: metav1.ObjectMeta{Name: "data"},
+				}},
+			},
+		}
+		// The stateful set is read through the clientset and deleted through the client
+		Expect(access.Client.Create(ctx, statefulSet.DeepCopy())).To(Succeed())
+		_, err := access.Clientset.AppsV1().StatefulSets("children").Create(statefulSet)
+		Expect(err).NotTo(HaveOccurred())
+		for _, name := range []string{"data-es-0", "data-es-1", "data-other-0"} {
+			Expect(access.Client.Create(ctx, &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "children"},
+			})).To(Succeed())
+		}
+
+		cr := &perfv1alpha1.EsRally{
+			ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "children"},
+		}
+		cr.Status.AddChild("apps/v1", "StatefulSet", "es")
+		cr.Status.AddChild("batch/v1", "Job", "job")
+		access.EventRecorder = record.NewFakeRecorder(10)
+		Expect(access.DeleteChildren(ctx, cr)).To(Succeed())
+
+		Expect(access.IsJobFinished(jobName)).To(BeFalse())
+		err = access.Client.Get(ctx, types.NamespacedName{Namespace: "children", Name: "es"}, statefulSet)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		var pvcs corev1.PersistentVolumeClaimList
+		Expect(access.Client.List(ctx, &pvcs)).To(Succeed())
+		Expect(pvcs.Items).To(HaveLen(1))
+		Expect(pvcs.Items[0].Name).To(Equal("data-other-0"))
+	})
+
+	It("should report the ready endpoint", func() {
+		Expect(access.IsEndpointReady(types.NamespacedName{
+			Namespace: "children", Name: "server"})).To(BeTrue())
+		Expect(access.IsEndpointReady(types.NamespacedName{
+			Namespace: "children", Name: "missing"})).To(BeFalse())
+	})
+})
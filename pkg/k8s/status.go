@@ -0,0 +1,125 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/metrics"
+)
+
+// UpdatePhase moves the benchmark to the given phase and persists the
+// status of the benchmark. The generation of the benchmark is recorded
+// as observed generation when the benchmark is picked up the first time.
+// Phase changes are reported to the operator metrics once persisted, and
+// the results of the succeeded benchmarks are published as metrics.
+func (a *Access) UpdatePhase(ctx context.Context, cr perfv1alpha1.Benchmark,
+	phase perfv1alpha1.BenchmarkPhase, reason, message string) error {
+	status := cr.GetBenchmarkStatus()
+	if status.Phase == "" {
+		status.ObservedGeneration = cr.GetGeneration()
+	}
+
+	previousPhase := status.Phase
+	var inPhase time.Duration
+	if status.PhaseTransitionTime != nil {
+		inPhase = time.Since(status.PhaseTransitionTime.Time)
+	}
+
+	status.SetPhase(phase, reason, message)
+	if err := a.Client.Status().Update(ctx, cr); err != nil {
+		return err
+	}
+
+	if gvk, err := apiutil.GVKForObject(cr, a.Scheme); err == nil {
+		metrics.RecordPhaseTransition(gvk.Kind, cr.GetNamespace(),
+			previousPhase, phase, inPhase)
+		if phase == perfv1alpha1.BenchmarkSucceeded {
+			metrics.PublishResults(gvk.Kind, cr.GetNamespace(), cr.GetName(), status.Results)
+		}
+	}
+	return nil
+}
+
+// SyncRunningPhase keeps the benchmark in Pending phase until a pod of
+// any of the given jobs is started, and moves it to Running afterwards.
+func (a *Access) SyncRunningPhase(ctx context.Context, cr perfv1alpha1.Benchmark,
+	jobs ...types.NamespacedName) error {
+	status := cr.GetBenchmarkStatus()
+	if status.Phase == perfv1alpha1.BenchmarkRunning {
+		return nil
+	}
+
+	phase := perfv1alpha1.BenchmarkPending
+	for _, job := range jobs {
+		started, err := a.IsJobStarted(job)
+		if err != nil {
+			return err
+		}
+		if started {
+			phase = perfv1alpha1.BenchmarkRunning
+			break
+		}
+	}
+
+	if status.Phase == phase {
+		return nil
+	}
+	return a.UpdatePhase(ctx, cr, phase, "", "")
+}
+
+// FinishBenchmark moves the benchmark to its terminal phase: Failed if
+// jobFailure is provided (with a Warning event), Succeeded otherwise.
+// Repeated benchmarks are moved back to Pending phase (with Repeating
+// reason) until all of their runs have succeeded. The results of the
+// succeeded benchmark are compared with its baseline and checked against
+// its assertions. Failed assertions fail the benchmark, and so do the
+// regressions if requested in its baseline spec.
+func (a *Access) FinishBenchmark(ctx context.Context, cr perfv1alpha1.Benchmark, jobFailure *JobFailure) error {
+	if jobFailure == nil {
+		if finishIteration(cr, metav1.Now()) {
+			return a.UpdatePhase(ctx, cr, perfv1alpha1.BenchmarkPending, Repeating, repetitionMessage(cr))
+		}
+
+		regression, err := a.compareWithBaseline(ctx, cr)
+		if err != nil {
+			return err
+		}
+		if failed := a.evaluateAssertions(cr); failed != "" {
+			return a.UpdatePhase(ctx, cr, perfv1alpha1.BenchmarkFailed, AssertionFailed, failed)
+		}
+		if regression != "" && cr.GetCommonSpec().Baseline.FailOnRegression {
+			return a.UpdatePhase(ctx, cr, perfv1alpha1.BenchmarkFailed, Regressed, regression)
+		}
+		return a.UpdatePhase(ctx, cr, perfv1alpha1.BenchmarkSucceeded, Completed, "")
+	}
+
+	if err := a.UpdatePhase(ctx, cr, perfv1alpha1.BenchmarkFailed,
+		jobFailure.Reason, jobFailure.String()); err != nil {
+		return err
+	}
+	_ = a.RecordEventf(cr, corev1.EventTypeWarning, Failed,
+		"Benchmark job failed: %v", jobFailure)
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("benchmark status", func() {
+	var ctx context.Context
+	var access Access
+	var recorder *record.FakeRecorder
+	var cr *perfv1alpha1.Fio
+	var name types.NamespacedName
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		statusScheme := runtime.NewScheme()
+		_ = k8sscheme.AddToScheme(statusScheme)
+		_ = perfv1alpha1.AddToScheme(statusScheme)
+
+		cr = &perfv1alpha1.Fio{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "fio",
+				Namespace:  "status",
+				Generation: 3,
+				// Needed by the event recorder to reference the object
+				SelfLink: "/apis/perf.kubestone.xridge.io/v1alpha1/namespaces/status/fios/fio",
+			},
+		}
+		name = types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}
+		recorder = record.NewFakeRecorder(10)
+		access = Access{
+			Client:        fake.NewFakeClientWithScheme(statusScheme, cr.DeepCopy()),
+			Scheme:        statusScheme,
+			EventRecorder: recorder,
+		}
+		Expect(access.Client.Get(ctx, name, cr)).To(Succeed())
+	})
+
+	Context("when the benchmark is picked up", func() {
+		It("records the observed generation and start time", func() {
+			Expect(access.UpdatePhase(ctx, cr, perfv1alpha1.BenchmarkValidating, "", "")).To(Succeed())
+
+			var stored perfv1alpha1.Fio
+			Expect(access.Client.Get(ctx, name, &stored)).To(Succeed())
+			Expect(stored.Status.Phase).To(Equal(perfv1alpha1.BenchmarkValidating))
+			Expect(stored.Status.ObservedGeneration).To(Equal(int64(3)))
+			Expect(stored.Status.StartTime).NotTo(BeNil())
+			Expect(stored.Status.PhaseTransitionTime).NotTo(BeNil())
+			Expect(stored.Status.CompletionTime).To(BeNil())
+		})
+	})
+
+	Context("when the benchmark succeeds", func() {
+		It("moves to Succeeded phase", func() {
+			Expect(access.UpdatePhase(ctx, cr, perfv1alpha1.BenchmarkRunning, "", "")).To(Succeed())
+			Expect(access.FinishBenchmark(ctx, cr, nil)).To(Succeed())
+
+			var stored perfv1alpha1.Fio
+			Expect(access.Client.Get(ctx, name, &stored)).To(Succeed())
+			Expect(stored.Status.Phase).To(Equal(perfv1alpha1.BenchmarkSucceeded))
+			Expect(stored.Status.IsFinished()).To(BeTrue())
+			Expect(stored.Status.CompletionTime).NotTo(BeNil())
+			Expect(stored.Status.Duration).NotTo(BeNil())
+			Expect(stored.Status.IsConditionTrue(perfv1alpha1.ConditionSucceeded)).To(BeTrue())
+			Expect(stored.Status.IsConditionTrue(perfv1alpha1.ConditionRunning)).To(BeFalse())
+			Expect(stored.Status.GetCondition(perfv1alpha1.ConditionFailed)).To(BeNil())
+			Expect(recorder.Events).To(BeEmpty())
+		})
+	})
+
+	Context("when the benchmark job fails", func() {
+		It("moves to Failed phase with the failure details", func() {
+			jobFailure := &JobFailure{
+				Reason:   "BackoffLimitExceeded",
+				Message:  "Job has reached the specified backoff limit",
+				ExitCode: 1,
+			}
+			Expect(access.FinishBenchmark(ctx, cr, jobFailure)).To(Succeed())
+
+			var stored perfv1alpha1.Fio
+			Expect(access.Client.Get(ctx, name, &stored)).To(Succeed())
+			Expect(stored.Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+			Expect(stored.Status.Reason).To(Equal("BackoffLimitExceeded"))
+			Expect(stored.Status.Message).To(Equal(jobFailure.String()))
+			Expect(stored.Status.IsConditionTrue(perfv1alpha1.ConditionFailed)).To(BeTrue())
+			Expect(stored.Status.IsConditionTrue(perfv1alpha1.ConditionSucceeded)).To(BeFalse())
+			Expect(recorder.Events).To(Receive(ContainSubstring(Failed)))
+		})
+	})
+
+	Context("when children are registered", func() {
+		It("keeps the references unique", func() {
+			cr.Status.AddChild("batch/v1", "Job", "fio")
+			cr.Status.AddChild("batch/v1", "Job", "fio")
+			cr.Status.AddChild("v1", "ConfigMap", "fio")
+			Expect(cr.Status.Children).To(HaveLen(2))
+		})
+	})
+})
@@ -0,0 +1,73 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+// StorageClassObjectName returns the name of the PVC and the job created
+// for the given StorageClass in the StorageClass comparison of the benchmark
+func StorageClassObjectName(crName, storageClass string) string {
+	return crName + "-" + storageClass
+}
+
+// CreateStorageClassJobs creates the PVCs of the StorageClass comparison
+// described by the volume, and the jobs running the benchmark against them.
+// The PVCs are created at once, so they are provisioned in parallel, but the
+// jobs are created one after the other: the job of the next StorageClass is
+// only created when the previous one has succeeded. This way the jobs do
+// not compete for the resources of the nodes. The names of the created jobs
+// are returned in the order of the StorageClassNames.
+func (a *Access) CreateStorageClassJobs(ctx context.Context, cr perfv1alpha1.Benchmark,
+	volume *perfv1alpha1.VolumeSpec, newJob func(storageClass string) *batchv1.Job) ([]types.NamespacedName, error) {
+	for _, storageClass := range volume.StorageClassNames {
+		pvc := NewPersistentVolumeClaim(volume.StorageClassClaimSpec(storageClass),
+			StorageClassObjectName(cr.GetName(), storageClass), cr.GetNamespace())
+		if err := a.CreateWithReference(ctx, pvc, cr); err != nil {
+			return nil, err
+		}
+	}
+
+	var jobNames []types.NamespacedName
+	for _, storageClass := range volume.StorageClassNames {
+		job := newJob(storageClass)
+		if err := a.CreateWithReference(ctx, job, cr); err != nil {
+			return nil, err
+		}
+		jobName := types.NamespacedName{
+			Namespace: job.Namespace,
+			Name:      job.Name,
+		}
+		jobNames = append(jobNames, jobName)
+
+		finished, err := a.IsJobFinished(jobName)
+		if err != nil || !finished {
+			return jobNames, err
+		}
+		failure, err := a.GetJobFailure(jobName)
+		if err != nil || failure != nil {
+			return jobNames, err
+		}
+	}
+	return jobNames, nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("StorageClass comparison", func() {
+	var ctx context.Context
+	var access Access
+	var cr *perfv1alpha1.Ioping
+
+	newJob := func(storageClass string) *batchv1.Job {
+		return &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name:      StorageClassObjectName(cr.Name, storageClass),
+			Namespace: cr.Namespace,
+		}}
+	}
+
+	completeJob := func(name string, failed bool) {
+		var job batchv1.Job
+		Expect(access.Client.Get(ctx, types.NamespacedName{
+			Namespace: cr.Namespace, Name: name}, &job)).To(Succeed())
+		if failed {
+			job.Status.Conditions = []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: corev1.ConditionTrue},
+			}
+		} else {
+			now := metav1.Now()
+			job.Status.CompletionTime = &now
+		}
+		Expect(access.Client.Update(ctx, &job)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		_ = k8sscheme.AddToScheme(scheme)
+		_ = perfv1alpha1.AddToScheme(scheme)
+
+		cr = &perfv1alpha1.Ioping{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ioping",
+				Namespace: "storage",
+				UID:       "ioping-uid",
+				// Needed by the event recorder to reference the object
+				SelfLink: "/apis/perf.kubestone.xridge.io/v1alpha1/namespaces/storage/iopings/ioping",
+			},
+			Spec: perfv1alpha1.IopingSpec{
+				Volume: perfv1alpha1.VolumeSpec{
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: perfv1alpha1.GeneratedPVC,
+						},
+					},
+					StorageClassNames: []string{"standard", "fast"},
+				},
+			},
+		}
+		access = Access{
+			Client:        fake.NewFakeClientWithScheme(scheme, cr.DeepCopy()),
+			Clientset:     k8sfake.NewSimpleClientset(),
+			Scheme:        scheme,
+			EventRecorder: record.NewFakeRecorder(20),
+		}
+	})
+
+	It("should create a PVC for each StorageClass", func() {
+		_, err := access.CreateStorageClassJobs(ctx, cr, &cr.Spec.Volume, newJob)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, storageClass := range []string{"standard", "fast"} {
+			var pvc corev1.PersistentVolumeClaim
+			Expect(access.Client.Get(ctx, types.NamespacedName{
+				Namespace: cr.Namespace,
+				Name:      "ioping-" + storageClass,
+			}, &pvc)).To(Succeed())
+			Expect(*pvc.Spec.StorageClassName).To(Equal(storageClass))
+			Expect(pvc.Spec.AccessModes).To(Equal(
+				[]corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}))
+			Expect(pvc.Spec.Resources.Requests[corev1.ResourceStorage]).To(
+				Equal(resource.MustParse(perfv1alpha1.DefaultComparisonVolumeSize)))
+		}
+	})
+
+	It("should run the jobs one after the other", func() {
+		jobNames, err := access.CreateStorageClassJobs(ctx, cr, &cr.Spec.Volume, newJob)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jobNames).To(HaveLen(1))
+		Expect(jobNames[0].Name).To(Equal("ioping-standard"))
+
+		completeJob("ioping-standard", false)
+		jobNames, err = access.CreateStorageClassJobs(ctx, cr, &cr.Spec.Volume, newJob)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jobNames).To(HaveLen(2))
+		Expect(jobNames[1].Name).To(Equal("ioping-fast"))
+	})
+
+	It("should stop at the first failed job", func() {
+		_, err := access.CreateStorageClassJobs(ctx, cr, &cr.Spec.Volume, newJob)
+		Expect(err).NotTo(HaveOccurred())
+
+		completeJob("ioping-standard", true)
+		jobNames, err := access.CreateStorageClassJobs(ctx, cr, &cr.Spec.Volume, newJob)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jobNames).To(HaveLen(1))
+	})
+
+	It("should keep the storage request of the PVC template", func() {
+		cr.Spec.Volume.PersistentVolumeClaimSpec = &corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("50Gi")},
+			},
+		}
+		spec := cr.Spec.Volume.StorageClassClaimSpec("fast")
+		Expect(*spec.StorageClassName).To(Equal("fast"))
+		Expect(spec.AccessModes).To(Equal(
+			[]corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}))
+		Expect(cr.Spec.Volume.PersistentVolumeClaimSpec.StorageClassName).To(BeNil())
+	})
+})
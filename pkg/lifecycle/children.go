@@ -0,0 +1,280 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/results"
+)
+
+// Children runs the benchmarks created by a composite benchmark (a suite,
+// a sweep, a fan-out or an iperf3 matrix): its children. The controller of
+// the composite benchmark plans the children and summarizes their results,
+// Children creates their benchmarks and tracks their state.
+type Children struct {
+	// Statuses are the states of the children, in the order they are started
+	Statuses []*perfv1alpha1.ChildBenchmarkStatus
+
+	// Parallelism is the maximum number of children running at the same
+	// time. Defaults to 1, which runs the children one after the other.
+	Parallelism *int32
+
+	// Noun is the plural name of the children in the messages (e.g. runs)
+	Noun string
+
+	// FailedReason is the reason of the failure of the composite
+	// benchmark when any of its children has failed
+	FailedReason string
+
+	// Describe names the child in the events (e.g. run 2)
+	Describe func(i int) string
+
+	// Prototype returns an empty benchmark of the kind of the child
+	Prototype func(i int) perfv1alpha1.Benchmark
+
+	// New creates the benchmark of the child
+	New func(ctx context.Context, i int) (perfv1alpha1.Benchmark, error)
+
+	// Sync updates the kind specific status of the child (e.g. its
+	// metrics) from its benchmark. Optional.
+	Sync func(i int, benchmark perfv1alpha1.Benchmark)
+
+	// Stopped returns true if no more children are started, as the
+	// composite benchmark is stopped by a failed child. The children
+	// which were not started are skipped. Optional.
+	Stopped func() bool
+
+	// Summarize updates the results of the composite benchmark from its
+	// children. It is called on every reconcile, finished is true once
+	// all the children are finished. Optional.
+	Summarize func(finished bool)
+}
+
+// Validate validates the composite benchmark on its first reconcile with
+// the given validation of its kind. Invalid benchmarks are moved to
+// Failed phase. It returns false if the benchmark is invalid.
+func Validate(ctx context.Context, access *k8s.Access, cr perfv1alpha1.Benchmark,
+	isValid func() (bool, error)) (bool, error) {
+	if cr.GetBenchmarkStatus().Phase != "" {
+		return true, nil
+	}
+
+	if err := access.UpdatePhase(ctx, cr, perfv1alpha1.BenchmarkValidating, "", ""); err != nil {
+		return false, err
+	}
+	if valid, err := isValid(); !valid {
+		_ = access.RecordEventf(cr, corev1.EventTypeWarning, k8s.CreateFailed,
+			"CR validation failed: %v", err)
+
+		// Do not requeue invalid CRs
+		return false, access.UpdatePhase(ctx, cr, perfv1alpha1.BenchmarkFailed,
+			k8s.ValidationFailed, err.Error())
+	}
+	cr.GetBenchmarkStatus().SetCondition(perfv1alpha1.ConditionValidated, corev1.ConditionTrue, "", "")
+	return true, nil
+}
+
+// FailBenchmark moves the benchmark to Failed phase, and reports the failure
+// with a Warning event
+func FailBenchmark(ctx context.Context, access *k8s.Access, cr perfv1alpha1.Benchmark, reason, message string) error {
+	if err := access.UpdatePhase(ctx, cr, perfv1alpha1.BenchmarkFailed, reason, message); err != nil {
+		return err
+	}
+	_ = access.RecordEventf(cr, corev1.EventTypeWarning, reason, "%s", message)
+	return nil
+}
+
+// Reconcile updates the children from their benchmarks, and starts the
+// next children in order while the number of running children is below
+// the parallelism. Failed children do not stop the composite benchmark,
+// unless Stopped says so. Once all of the children are finished, the
+// composite benchmark is moved to its terminal phase and recorded:
+// Succeeded if all of its children succeeded (and its results have not
+// regressed), Failed otherwise. The status is only updated if it differs
+// from the original copy of the composite benchmark.
+func (c *Children) Reconcile(ctx context.Context, access *k8s.Access,
+	cr, original perfv1alpha1.Benchmark) error {
+	if err := c.sync(ctx, access, cr); err != nil {
+		return err
+	}
+	running, err := c.start(ctx, access, cr)
+	if err != nil {
+		return err
+	}
+
+	if running > 0 || (!c.stopped() && c.hasUnstarted()) {
+		c.summarize(false)
+		if cr.GetBenchmarkStatus().Phase != perfv1alpha1.BenchmarkRunning {
+			return access.UpdatePhase(ctx, cr, perfv1alpha1.BenchmarkRunning, "", "")
+		}
+		if apiequality.Semantic.DeepEqual(original, cr) {
+			return nil
+		}
+		return access.Client.Status().Update(ctx, cr)
+	}
+
+	c.summarize(true)
+	if err := c.finish(ctx, access, cr); err != nil {
+		return err
+	}
+
+	// Keep a record of the composite benchmark, which outlives the custom resource
+	return results.Record(ctx, access, cr)
+}
+
+// sync updates the status of the started, unfinished children from
+// their benchmarks
+func (c *Children) sync(ctx context.Context, access *k8s.Access, cr perfv1alpha1.Benchmark) error {
+	for i, child := range c.Statuses {
+		if child.BenchmarkName == "" || child.IsFinished() {
+			continue
+		}
+
+		benchmark := c.Prototype(i).DeepCopyObject().(perfv1alpha1.Benchmark)
+		err := access.Client.Get(ctx, types.NamespacedName{
+			Namespace: cr.GetNamespace(),
+			Name:      child.BenchmarkName,
+		}, benchmark)
+		if errors.IsNotFound(err) {
+			// The created benchmark is not in the cache yet
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		status := benchmark.GetBenchmarkStatus()
+		child.Phase = status.Phase
+		if child.Phase == "" {
+			child.Phase = perfv1alpha1.BenchmarkPending
+		}
+		child.Reason = status.Reason
+		child.Message = status.Message
+		if c.Sync != nil {
+			c.Sync(i, benchmark)
+		}
+	}
+	return nil
+}
+
+// start creates the benchmarks of the next children in order, while the
+// number of running children is below the parallelism. It returns the
+// number of running children.
+func (c *Children) start(ctx context.Context, access *k8s.Access, cr perfv1alpha1.Benchmark) (running int, err error) {
+	parallelism := 1
+	if c.Parallelism != nil {
+		parallelism = int(*c.Parallelism)
+	}
+
+	for _, child := range c.Statuses {
+		if child.BenchmarkName != "" && !child.IsFinished() {
+			running++
+		}
+	}
+
+	for i, child := range c.Statuses {
+		if running >= parallelism || c.stopped() {
+			break
+		}
+		if child.Phase != "" {
+			continue
+		}
+
+		benchmark, err := c.New(ctx, i)
+		if err == nil {
+			err = access.CreateWithReference(ctx, benchmark, cr)
+		}
+		if err != nil {
+			if !isPermanent(err) {
+				return running, err
+			}
+			_ = access.RecordEventf(cr, corev1.EventTypeWarning, k8s.CreateFailed,
+				"Unable to create the benchmark of %s: %v", c.Describe(i), err)
+			child.Phase = perfv1alpha1.BenchmarkFailed
+			child.Reason = k8s.CreateFailed
+			child.Message = err.Error()
+			continue
+		}
+
+		child.BenchmarkName = benchmark.GetName()
+		child.Phase = perfv1alpha1.BenchmarkPending
+		running++
+	}
+	return running, nil
+}
+
+// finish moves the composite benchmark to its terminal phase. The
+// children which were not started are skipped.
+func (c *Children) finish(ctx context.Context, access *k8s.Access, cr perfv1alpha1.Benchmark) error {
+	failed := 0
+	for _, child := range c.Statuses {
+		switch {
+		case child.Phase == "":
+			child.Phase = perfv1alpha1.BenchmarkCancelled
+			child.Reason = k8s.Skipped
+			child.Message = "Stopped by a failed benchmark"
+		case child.Phase != perfv1alpha1.BenchmarkSucceeded:
+			failed++
+		}
+	}
+
+	if failed == 0 {
+		// The summarized results are compared with the baseline
+		return access.FinishBenchmark(ctx, cr, nil)
+	}
+	return FailBenchmark(ctx, access, cr, c.FailedReason,
+		fmt.Sprintf("%d of %d %s failed", failed, len(c.Statuses), c.Noun))
+}
+
+// stopped returns true if no more children are started
+func (c *Children) stopped() bool {
+	return c.Stopped != nil && c.Stopped()
+}
+
+// hasUnstarted returns true if any of the children is yet to be started
+func (c *Children) hasUnstarted() bool {
+	for _, child := range c.Statuses {
+		if child.Phase == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// summarize calls Summarize if it is set
+func (c *Children) summarize(finished bool) {
+	if c.Summarize != nil {
+		c.Summarize(finished)
+	}
+}
+
+// isPermanent returns true if the benchmark of a child cannot be created
+// by retrying: its spec is malformed or rejected by the API server
+func isPermanent(err error) bool {
+	if _, ok := err.(errors.APIStatus); !ok {
+		return true
+	}
+	return errors.IsInvalid(err) || errors.IsBadRequest(err)
+}
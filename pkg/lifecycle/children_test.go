@@ -0,0 +1,256 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+)
+
+var _ = Describe("children", func() {
+	var ctx context.Context
+	var access *k8s.Access
+	var name types.NamespacedName
+	var statuses []perfv1alpha1.ChildBenchmarkStatus
+	var parallelism *int32
+	var stopOnFailure bool
+	var newErr error
+
+	childName := func(index int) types.NamespacedName {
+		return types.NamespacedName{Namespace: name.Namespace, Name: fmt.Sprintf("%s-%d", name.Name, index)}
+	}
+
+	children := func() *Children {
+		pointers := make([]*perfv1alpha1.ChildBenchmarkStatus, len(statuses))
+		for i := range statuses {
+			pointers[i] = &statuses[i]
+		}
+		return &Children{
+			Statuses:     pointers,
+			Parallelism:  parallelism,
+			Noun:         "runs",
+			FailedReason: k8s.RunFailed,
+			Describe: func(i int) string {
+				return fmt.Sprintf("run %d", i)
+			},
+			Prototype: func(i int) perfv1alpha1.Benchmark {
+				return &perfv1alpha1.Sysbench{}
+			},
+			New: func(ctx context.Context, i int) (perfv1alpha1.Benchmark, error) {
+				if newErr != nil {
+					return nil, newErr
+				}
+				return &perfv1alpha1.Sysbench{ObjectMeta: metav1.ObjectMeta{
+					Name:      childName(i).Name,
+					Namespace: childName(i).Namespace,
+				}}, nil
+			},
+			Stopped: func() bool {
+				for _, status := range statuses {
+					if stopOnFailure && status.Phase == perfv1alpha1.BenchmarkFailed {
+						return true
+					}
+				}
+				return false
+			},
+		}
+	}
+
+	stored := func() *perfv1alpha1.BenchmarkSweep {
+		var sweep perfv1alpha1.BenchmarkSweep
+		Expect(access.Client.Get(ctx, name, &sweep)).To(Succeed())
+		return &sweep
+	}
+
+	reconcile := func() error {
+		cr := stored()
+		return children().Reconcile(ctx, access, cr, cr.DeepCopy())
+	}
+
+	childExists := func(index int) bool {
+		var sysbench perfv1alpha1.Sysbench
+		err := access.Client.Get(ctx, childName(index), &sysbench)
+		Expect(errors.IsNotFound(err) || err == nil).To(BeTrue())
+		return err == nil
+	}
+
+	finishChild := func(index int, phase perfv1alpha1.BenchmarkPhase) {
+		var sysbench perfv1alpha1.Sysbench
+		Expect(access.Client.Get(ctx, childName(index), &sysbench)).To(Succeed())
+		sysbench.Status.SetPhase(phase, "", "")
+		Expect(access.Client.Status().Update(ctx, &sysbench)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		name = types.NamespacedName{Namespace: "children", Name: "parent"}
+		statuses = make([]perfv1alpha1.ChildBenchmarkStatus, 3)
+		parallelism = nil
+		stopOnFailure = false
+		newErr = nil
+
+		scheme := runtime.NewScheme()
+		_ = k8sscheme.AddToScheme(scheme)
+		_ = perfv1alpha1.AddToScheme(scheme)
+		access = &k8s.Access{
+			Client: fake.NewFakeClientWithScheme(scheme, &perfv1alpha1.BenchmarkSweep{
+				ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+			}),
+			Scheme:        scheme,
+			EventRecorder: record.NewFakeRecorder(100),
+		}
+	})
+
+	Describe("reconcile", func() {
+		It("should run the children one after the other", func() {
+			Expect(reconcile()).To(Succeed())
+			Expect(stored().Status.Phase).To(Equal(perfv1alpha1.BenchmarkRunning))
+			Expect(statuses[0].BenchmarkName).To(Equal("parent-0"))
+			Expect(statuses[0].Phase).To(Equal(perfv1alpha1.BenchmarkPending))
+			Expect(childExists(1)).To(BeFalse())
+
+			for i := 0; i < 3; i++ {
+				finishChild(i, perfv1alpha1.BenchmarkSucceeded)
+				Expect(reconcile()).To(Succeed())
+			}
+
+			cr := stored()
+			Expect(cr.Status.Phase).To(Equal(perfv1alpha1.BenchmarkSucceeded))
+			Expect(cr.Status.Children).To(HaveLen(3))
+			Expect(statuses[2].Phase).To(Equal(perfv1alpha1.BenchmarkSucceeded))
+		})
+
+		It("should run the given number of children at the same time", func() {
+			two := int32(2)
+			parallelism = &two
+
+			Expect(reconcile()).To(Succeed())
+			Expect(childExists(0)).To(BeTrue())
+			Expect(childExists(1)).To(BeTrue())
+			Expect(childExists(2)).To(BeFalse())
+
+			finishChild(1, perfv1alpha1.BenchmarkSucceeded)
+			Expect(reconcile()).To(Succeed())
+			Expect(childExists(2)).To(BeTrue())
+		})
+
+		It("should continue after failed children, but fail the parent", func() {
+			Expect(reconcile()).To(Succeed())
+			finishChild(0, perfv1alpha1.BenchmarkFailed)
+			Expect(reconcile()).To(Succeed())
+			Expect(childExists(1)).To(BeTrue())
+
+			for i := 1; i < 3; i++ {
+				finishChild(i, perfv1alpha1.BenchmarkSucceeded)
+				Expect(reconcile()).To(Succeed())
+			}
+
+			cr := stored()
+			Expect(cr.Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+			Expect(cr.Status.Reason).To(Equal(k8s.RunFailed))
+			Expect(cr.Status.Message).To(Equal("1 of 3 runs failed"))
+		})
+
+		It("should skip the children once stopped", func() {
+			stopOnFailure = true
+
+			Expect(reconcile()).To(Succeed())
+			finishChild(0, perfv1alpha1.BenchmarkFailed)
+			Expect(reconcile()).To(Succeed())
+
+			Expect(childExists(1)).To(BeFalse())
+			Expect(statuses[1].Phase).To(Equal(perfv1alpha1.BenchmarkCancelled))
+			Expect(statuses[1].Reason).To(Equal(k8s.Skipped))
+			Expect(stored().Status.Message).To(Equal("1 of 3 runs failed"))
+		})
+
+		It("should fail the children which cannot be created", func() {
+			newErr = fmt.Errorf("malformed spec")
+
+			Expect(reconcile()).To(Succeed())
+			for _, status := range statuses {
+				Expect(status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+				Expect(status.Reason).To(Equal(k8s.CreateFailed))
+			}
+			Expect(stored().Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+		})
+
+		It("should retry the transient errors", func() {
+			newErr = errors.NewServerTimeout(perfv1alpha1.GroupVersion.WithResource("sysbenches").GroupResource(),
+				"create", 1)
+
+			Expect(reconcile()).NotTo(Succeed())
+			Expect(statuses[0].Phase).To(BeEmpty())
+		})
+
+		It("should wait for the benchmarks which are not in the cache yet", func() {
+			statuses[0] = perfv1alpha1.ChildBenchmarkStatus{
+				BenchmarkName: "parent-0",
+				Phase:         perfv1alpha1.BenchmarkPending,
+			}
+
+			Expect(reconcile()).To(Succeed())
+			Expect(statuses[0].Phase).To(Equal(perfv1alpha1.BenchmarkPending))
+			Expect(childExists(1)).To(BeFalse())
+		})
+	})
+
+	Describe("validate", func() {
+		It("should fail the invalid benchmarks", func() {
+			cr := stored()
+			valid, err := Validate(ctx, access, cr, func() (bool, error) {
+				return false, fmt.Errorf("no parameters")
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(valid).To(BeFalse())
+
+			cr = stored()
+			Expect(cr.Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+			Expect(cr.Status.Reason).To(Equal(k8s.ValidationFailed))
+			Expect(cr.Status.Message).To(Equal("no parameters"))
+		})
+
+		It("should only validate on the first reconcile", func() {
+			cr := stored()
+			valid, err := Validate(ctx, access, cr, func() (bool, error) { return true, nil })
+			Expect(err).NotTo(HaveOccurred())
+			Expect(valid).To(BeTrue())
+			Expect(cr.Status.GetCondition(perfv1alpha1.ConditionValidated)).NotTo(BeNil())
+
+			valid, err = Validate(ctx, access, stored(), func() (bool, error) {
+				Fail("validated again")
+				return false, nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(valid).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,242 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package lifecycle enforces the settings shared by all the benchmark kinds
+(see CommonSpec in the API), so the controllers of the benchmark kinds
+only have to deal with running their benchmark.
+*/
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/metrics"
+	"github.com/xridge/kubestone/pkg/results"
+)
+
+// Reconciler wraps the Reconciler of a benchmark kind. It stops the
+// cancelled and timed out benchmarks, cleans up the finished ones and
+// passes the other requests to the wrapped Reconciler.
+type Reconciler struct {
+	reconcile.Reconciler
+
+	K8S *k8s.Access
+
+	// Benchmark is an empty custom resource of the benchmark kind
+	Benchmark perfv1alpha1.Benchmark
+}
+
+// NewReconciler wraps the reconciler of the kind of the given (empty) benchmark
+func NewReconciler(reconciler reconcile.Reconciler, access *k8s.Access,
+	benchmark perfv1alpha1.Benchmark) *Reconciler {
+	return &Reconciler{
+		Reconciler: reconciler,
+		K8S:        access,
+		Benchmark:  benchmark,
+	}
+}
+
+// Reconcile records and cleans up the finished benchmark, stops the benchmark if it
+// is cancelled or has timed out, otherwise reconciles it with the wrapped
+// Reconciler. Benchmarks with timeout are requeued at their deadline at
+// the latest.
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+
+	cr := r.Benchmark.DeepCopyObject().(perfv1alpha1.Benchmark)
+	if err := r.K8S.Client.Get(ctx, req.NamespacedName, cr); err != nil {
+		if errors.IsNotFound(err) {
+			// The results of the deleted benchmark are not published anymore
+			if gvk, err := apiutil.GVKForObject(r.Benchmark, r.K8S.Scheme); err == nil {
+				metrics.DeleteResults(gvk.Kind, req.Namespace, req.Name)
+			}
+		}
+		return ctrl.Result{}, k8s.IgnoreNotFound(err)
+	}
+
+	if cr.GetBenchmarkStatus().IsFinished() {
+		// The record is retried if it could not be created when the
+		// benchmark finished, before its children are cleaned up
+		if err := results.Record(ctx, r.K8S, cr, jobNames(cr)...); err != nil {
+			return ctrl.Result{}, err
+		}
+		return Cleanup(ctx, r.K8S, cr, time.Now())
+	}
+
+	if stopped, err := Stop(ctx, r.K8S, cr); stopped || err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if k8s.IsRepeating(cr) {
+		if ready, err := PrepareNextRun(ctx, r.K8S, cr); !ready || err != nil {
+			return requeueAtDeadline(cr, k8s.RequeueWithBackoff(cr), time.Now()), err
+		}
+	}
+
+	result, err := r.Reconciler.Reconcile(req)
+	if err != nil {
+		return result, err
+	}
+	return requeueAtDeadline(cr, result, time.Now()), nil
+}
+
+// requeueAtDeadline makes sure that the benchmark is reconciled
+// (and stopped if still running) when its deadline is reached
+func requeueAtDeadline(cr perfv1alpha1.Benchmark, result ctrl.Result, now time.Time) ctrl.Result {
+	deadline := k8s.Deadline(cr)
+	if deadline == nil {
+		return result
+	}
+
+	remaining := deadline.Sub(now)
+	if remaining < time.Second {
+		remaining = time.Second
+	}
+	if result.RequeueAfter == 0 || result.RequeueAfter > remaining {
+		result.RequeueAfter = remaining
+	}
+	return result
+}
+
+// Stop terminates the benchmark if it has been cancelled or has exceeded
+// its timeout. The run is recorded while the pods of the benchmark are
+// still available, then the objects created for the benchmark are deleted
+// and the benchmark is moved to Cancelled or Failed phase. It returns true
+// if the benchmark has been stopped.
+func Stop(ctx context.Context, access *k8s.Access, cr perfv1alpha1.Benchmark) (bool, error) {
+	var phase perfv1alpha1.BenchmarkPhase
+	var eventType, reason, message string
+	switch {
+	case cr.GetCommonSpec().Cancel:
+		phase, eventType, reason = perfv1alpha1.BenchmarkCancelled, corev1.EventTypeNormal, k8s.Cancelled
+		message = "Benchmark was cancelled"
+	case k8s.IsDeadlineExceeded(cr, time.Now()):
+		phase, eventType, reason = perfv1alpha1.BenchmarkFailed, corev1.EventTypeWarning, k8s.DeadlineExceeded
+		message = fmt.Sprintf("Benchmark did not finish within %v", cr.GetCommonSpec().Timeout.Duration)
+	default:
+		return false, nil
+	}
+
+	// The record is created from a copy, as the phase is persisted
+	// only after the children are deleted
+	stopped := cr.DeepCopyObject().(perfv1alpha1.Benchmark)
+	stopped.GetBenchmarkStatus().SetPhase(phase, reason, message)
+	if err := results.CreateRecord(ctx, access, stopped, jobNames(cr)...); err != nil {
+		return false, err
+	}
+	if recorded := stopped.GetBenchmarkStatus().GetCondition(perfv1alpha1.ConditionRecorded); recorded != nil {
+		cr.GetBenchmarkStatus().SetCondition(recorded.Type, recorded.Status, recorded.Reason, recorded.Message)
+	}
+
+	if err := access.DeleteChildren(ctx, cr); err != nil {
+		return false, err
+	}
+	cr.GetBenchmarkStatus().SetCondition(perfv1alpha1.ConditionCleanedUp,
+		corev1.ConditionTrue, reason, "")
+
+	if err := access.UpdatePhase(ctx, cr, phase, reason, message); err != nil {
+		return false, err
+	}
+	_ = access.RecordEventf(cr, eventType, reason, message)
+
+	return true, nil
+}
+
+// PrepareNextRun deletes the objects created for the previous run of the
+// repeated benchmark. Once they are gone, the benchmark is moved out of
+// the Repeating state, so the benchmark reconciler creates them afresh
+// for the next run. It returns true if the next run can be started.
+func PrepareNextRun(ctx context.Context, access *k8s.Access, cr perfv1alpha1.Benchmark) (bool, error) {
+	exist, err := access.ChildrenExist(ctx, cr)
+	if err != nil {
+		return false, err
+	}
+	if exist {
+		return false, access.DeleteChildren(ctx, cr)
+	}
+
+	status := cr.GetBenchmarkStatus()
+	status.Children = nil
+	// The servers of the previous run are deleted with the other children
+	if status.GetCondition(perfv1alpha1.ConditionServerReady) != nil {
+		status.SetCondition(perfv1alpha1.ConditionServerReady, corev1.ConditionFalse, k8s.Repeating, "")
+	}
+	if err := access.UpdatePhase(ctx, cr, perfv1alpha1.BenchmarkPending, "", status.Message); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Cleanup applies the cleanup policy and the TTL of the finished benchmark.
+// The objects created for the benchmark are deleted only once, after its
+// results have been collected. The benchmark itself is deleted when its
+// TTL expires, and is requeued for the expiry until then.
+func Cleanup(ctx context.Context, access *k8s.Access, cr perfv1alpha1.Benchmark, now time.Time) (ctrl.Result, error) {
+	status := cr.GetBenchmarkStatus()
+	common := cr.GetCommonSpec()
+
+	if common.ShouldCleanup(status.Phase) && !status.IsConditionTrue(perfv1alpha1.ConditionCleanedUp) {
+		if err := access.DeleteChildren(ctx, cr); err != nil {
+			return ctrl.Result{}, err
+		}
+		status.SetCondition(perfv1alpha1.ConditionCleanedUp, corev1.ConditionTrue,
+			string(common.CleanupPolicy), "")
+		if err := access.Client.Status().Update(ctx, cr); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if common.TTLSecondsAfterFinished == nil || status.CompletionTime == nil {
+		return ctrl.Result{}, nil
+	}
+
+	expiry := status.CompletionTime.Add(time.Duration(*common.TTLSecondsAfterFinished) * time.Second)
+	if now.Before(expiry) {
+		return ctrl.Result{RequeueAfter: expiry.Sub(now)}, nil
+	}
+
+	// The objects created for the benchmark are deleted by the garbage collector
+	err := access.Client.Delete(ctx, cr, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	return ctrl.Result{}, k8s.IgnoreNotFound(err)
+}
+
+// jobNames returns the jobs created for the benchmark
+func jobNames(cr perfv1alpha1.Benchmark) []types.NamespacedName {
+	var jobs []types.NamespacedName
+	for _, child := range cr.GetBenchmarkStatus().Children {
+		if child.Kind == "Job" {
+			jobs = append(jobs, types.NamespacedName{
+				Namespace: cr.GetNamespace(),
+				Name:      child.Name,
+			})
+		}
+	}
+	return jobs
+}
@@ -0,0 +1,354 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+)
+
+// countingReconciler counts the requests passed to it
+type countingReconciler struct {
+	requests int
+	result   ctrl.Result
+}
+
+func (r *countingReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	r.requests++
+	return r.result, nil
+}
+
+var _ = Describe("lifecycle", func() {
+	var ctx context.Context
+	var access *k8s.Access
+	var cr *perfv1alpha1.Sysbench
+	var name, jobName types.NamespacedName
+	var inner *countingReconciler
+	var reconciler *Reconciler
+
+	setup := func(modify func(cr *perfv1alpha1.Sysbench)) {
+		modify(cr)
+		scheme := runtime.NewScheme()
+		_ = k8sscheme.AddToScheme(scheme)
+		_ = perfv1alpha1.AddToScheme(scheme)
+
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: jobName.Name, Namespace: jobName.Namespace},
+		}
+		access = &k8s.Access{
+			Client:        fake.NewFakeClientWithScheme(scheme, cr.DeepCopy(), job),
+			Clientset:     k8sfake.NewSimpleClientset(),
+			Scheme:        scheme,
+			EventRecorder: record.NewFakeRecorder(10),
+		}
+		reconciler = NewReconciler(inner, access, &perfv1alpha1.Sysbench{})
+	}
+
+	stored := func() *perfv1alpha1.Sysbench {
+		var sysbench perfv1alpha1.Sysbench
+		Expect(access.Client.Get(ctx, name, &sysbench)).To(Succeed())
+		return &sysbench
+	}
+
+	jobExists := func() bool {
+		var job batchv1.Job
+		err := access.Client.Get(ctx, jobName, &job)
+		Expect(errors.IsNotFound(err) || err == nil).To(BeTrue())
+		return err == nil
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		name = types.NamespacedName{Namespace: "lifecycle", Name: "sysbench"}
+		jobName = name
+		inner = &countingReconciler{}
+		cr = &perfv1alpha1.Sysbench{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name.Name,
+				Namespace:         name.Namespace,
+				CreationTimestamp: metav1.Now(),
+			},
+		}
+		cr.Status.SetPhase(perfv1alpha1.BenchmarkRunning, "", "")
+		cr.Status.AddChild("batch/v1", "Job", jobName.Name)
+	})
+
+	Context("with a running benchmark", func() {
+		BeforeEach(func() {
+			setup(func(cr *perfv1alpha1.Sysbench) {})
+		})
+
+		It("should pass the request to the benchmark reconciler", func() {
+			result, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(inner.requests).To(Equal(1))
+			Expect(jobExists()).To(BeTrue())
+		})
+	})
+
+	Context("with a cancelled benchmark", func() {
+		BeforeEach(func() {
+			setup(func(cr *perfv1alpha1.Sysbench) {
+				cr.Spec.Cancel = true
+			})
+		})
+
+		It("should delete the children and move to Cancelled phase", func() {
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inner.requests).To(BeZero())
+			Expect(jobExists()).To(BeFalse())
+			Expect(stored().Status.Phase).To(Equal(perfv1alpha1.BenchmarkCancelled))
+			Expect(stored().Status.IsConditionTrue(perfv1alpha1.ConditionCancelled)).To(BeTrue())
+		})
+
+		It("should record the run", func() {
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+
+			var records perfv1alpha1.BenchmarkResultList
+			Expect(access.Client.List(ctx, &records)).To(Succeed())
+			Expect(records.Items).To(HaveLen(1))
+			Expect(records.Items[0].Spec.Phase).To(Equal(perfv1alpha1.BenchmarkCancelled))
+			Expect(stored().Status.IsConditionTrue(perfv1alpha1.ConditionRecorded)).To(BeTrue())
+		})
+	})
+
+	Context("with a timed out benchmark", func() {
+		BeforeEach(func() {
+			setup(func(cr *perfv1alpha1.Sysbench) {
+				cr.Spec.Timeout = &metav1.Duration{Duration: time.Minute}
+				cr.Status.StartTime = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+			})
+		})
+
+		It("should delete the children and move to Failed phase", func() {
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inner.requests).To(BeZero())
+			Expect(jobExists()).To(BeFalse())
+			Expect(stored().Status.Phase).To(Equal(perfv1alpha1.BenchmarkFailed))
+			Expect(stored().Status.Reason).To(Equal(k8s.DeadlineExceeded))
+		})
+	})
+
+	Context("with a benchmark within its timeout", func() {
+		BeforeEach(func() {
+			setup(func(cr *perfv1alpha1.Sysbench) {
+				cr.Spec.Timeout = &metav1.Duration{Duration: time.Hour}
+				cr.Status.StartTime = &metav1.Time{Time: time.Now()}
+			})
+		})
+
+		It("should be requeued at its deadline", func() {
+			result, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inner.requests).To(Equal(1))
+			Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+		})
+
+		It("should keep the earlier requeue of the benchmark reconciler", func() {
+			inner.result = ctrl.Result{RequeueAfter: time.Second}
+			result, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Second))
+		})
+	})
+
+	Context("with a benchmark waiting for its next run", func() {
+		BeforeEach(func() {
+			setup(func(cr *perfv1alpha1.Sysbench) {
+				repetitions := int32(2)
+				cr.Spec.Repetitions = &repetitions
+				cr.Status.SetPhase(perfv1alpha1.BenchmarkPending, k8s.Repeating, "Starting run 2 of 2")
+			})
+		})
+
+		It("should delete the children of the previous run first", func() {
+			result, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).NotTo(BeZero())
+			Expect(jobExists()).To(BeFalse())
+			Expect(inner.requests).To(BeZero())
+			Expect(k8s.IsRepeating(stored())).To(BeTrue())
+		})
+
+		It("should start the next run once the children are gone", func() {
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inner.requests).To(Equal(1))
+
+			sysbench := stored()
+			Expect(k8s.IsRepeating(sysbench)).To(BeFalse())
+			Expect(sysbench.Status.Phase).To(Equal(perfv1alpha1.BenchmarkPending))
+			Expect(sysbench.Status.Children).To(BeEmpty())
+		})
+
+		It("should deploy the servers again for the next run", func() {
+			cr := stored()
+			cr.Status.SetCondition(perfv1alpha1.ConditionServerReady, corev1.ConditionTrue, "", "")
+			Expect(access.Client.Status().Update(ctx, cr)).To(Succeed())
+
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored().Status.IsConditionTrue(perfv1alpha1.ConditionServerReady)).To(BeFalse())
+		})
+	})
+
+	Context("with a succeeded benchmark to be deleted on success", func() {
+		BeforeEach(func() {
+			setup(func(cr *perfv1alpha1.Sysbench) {
+				cr.Spec.CleanupPolicy = perfv1alpha1.CleanupDeleteOnSuccess
+				cr.Status.SetPhase(perfv1alpha1.BenchmarkSucceeded, "", "")
+			})
+		})
+
+		It("should delete the children once", func() {
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobExists()).To(BeFalse())
+			Expect(stored().Status.IsConditionTrue(perfv1alpha1.ConditionCleanedUp)).To(BeTrue())
+			Expect(inner.requests).To(BeZero())
+		})
+	})
+
+	Context("with a failed benchmark to be deleted on success", func() {
+		BeforeEach(func() {
+			setup(func(cr *perfv1alpha1.Sysbench) {
+				cr.Spec.CleanupPolicy = perfv1alpha1.CleanupDeleteOnSuccess
+				cr.Status.SetPhase(perfv1alpha1.BenchmarkFailed, "", "")
+			})
+		})
+
+		It("should keep the children for investigation", func() {
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobExists()).To(BeTrue())
+			Expect(stored().Status.GetCondition(perfv1alpha1.ConditionCleanedUp)).To(BeNil())
+		})
+	})
+
+	Context("with a failed benchmark to be deleted always", func() {
+		BeforeEach(func() {
+			setup(func(cr *perfv1alpha1.Sysbench) {
+				cr.Spec.CleanupPolicy = perfv1alpha1.CleanupDeleteAlways
+				cr.Status.SetPhase(perfv1alpha1.BenchmarkFailed, "", "")
+			})
+		})
+
+		It("should delete the children", func() {
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobExists()).To(BeFalse())
+		})
+	})
+
+	Context("with a finished benchmark with TTL", func() {
+		It("should be requeued until the TTL expires", func() {
+			setup(func(cr *perfv1alpha1.Sysbench) {
+				ttl := int32(3600)
+				cr.Spec.TTLSecondsAfterFinished = &ttl
+				cr.Status.SetPhase(perfv1alpha1.BenchmarkSucceeded, "", "")
+			})
+			result, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+			stored()
+		})
+
+		It("should be deleted when the TTL expires", func() {
+			setup(func(cr *perfv1alpha1.Sysbench) {
+				ttl := int32(60)
+				cr.Spec.TTLSecondsAfterFinished = &ttl
+				cr.Status.SetPhase(perfv1alpha1.BenchmarkSucceeded, "", "")
+				cr.Status.CompletionTime = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+			})
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+
+			var sysbench perfv1alpha1.Sysbench
+			Expect(errors.IsNotFound(access.Client.Get(ctx, name, &sysbench))).To(BeTrue())
+		})
+	})
+
+	Context("with a finished benchmark", func() {
+		BeforeEach(func() {
+			setup(func(cr *perfv1alpha1.Sysbench) {
+				cr.Spec.Cancel = true
+				cr.Status.SetPhase(perfv1alpha1.BenchmarkSucceeded, "", "")
+			})
+		})
+
+		It("should retain the children by default", func() {
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobExists()).To(BeTrue())
+			Expect(stored().Status.Phase).To(Equal(perfv1alpha1.BenchmarkSucceeded))
+		})
+
+		It("should be recorded if the record is missing", func() {
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+
+			var records perfv1alpha1.BenchmarkResultList
+			Expect(access.Client.List(ctx, &records)).To(Succeed())
+			Expect(records.Items).To(HaveLen(1))
+			Expect(stored().Status.IsConditionTrue(perfv1alpha1.ConditionRecorded)).To(BeTrue())
+		})
+	})
+
+	Context("with a recorded benchmark", func() {
+		BeforeEach(func() {
+			setup(func(cr *perfv1alpha1.Sysbench) {
+				cr.Status.SetPhase(perfv1alpha1.BenchmarkSucceeded, "", "")
+				cr.Status.SetCondition(perfv1alpha1.ConditionRecorded, corev1.ConditionTrue, k8s.Recorded, "")
+			})
+		})
+
+		It("should not be recorded again", func() {
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+
+			var records perfv1alpha1.BenchmarkResultList
+			Expect(access.Client.List(ctx, &records)).To(Succeed())
+			Expect(records.Items).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,242 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package metrics publishes kubestone specific metrics on the metrics
+endpoint of the controller manager: the results of the completed
+benchmarks and operator level statistics about the benchmarks processed.
+*/
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+const namespace = "kubestone"
+
+var (
+	benchmarksStarted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "benchmarks_started_total",
+		Help:      "Number of benchmarks picked up by the operator.",
+	}, []string{"kind", "namespace"})
+
+	benchmarksSucceeded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "benchmarks_succeeded_total",
+		Help:      "Number of benchmarks completed successfully.",
+	}, []string{"kind", "namespace"})
+
+	benchmarksFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "benchmarks_failed_total",
+		Help:      "Number of failed benchmarks.",
+	}, []string{"kind", "namespace"})
+
+	benchmarksCancelled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "benchmarks_cancelled_total",
+		Help:      "Number of cancelled benchmarks.",
+	}, []string{"kind", "namespace"})
+
+	phaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "benchmark_phase_duration_seconds",
+		Help:      "Time spent by the benchmarks in each phase.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 15),
+	}, []string{"kind", "phase"})
+)
+
+// resultLabels are the labels of the result gauges, which are always present
+var resultLabels = []string{"kind", "namespace", "name", "metric", "unit"}
+
+// results are registered on first use, as the label names of a metric
+// cannot be changed after its registration. The label values published
+// for each benchmark are kept, so its series can be deleted together with
+// the benchmark. The pod labels of the benchmarks are staged when their
+// results are collected, until the results are published.
+var (
+	resultsMu sync.Mutex
+	podLabels []string
+	results   *prometheus.GaugeVec
+	published = map[benchmarkKey][][]string{}
+	staged    = map[benchmarkKey]map[string]string{}
+)
+
+// benchmarkKey identifies the benchmark whose results are published
+type benchmarkKey struct {
+	kind, namespace, name string
+}
+
+func init() {
+	metrics.Registry.MustRegister(benchmarksStarted, benchmarksSucceeded,
+		benchmarksFailed, benchmarksCancelled, phaseDuration)
+}
+
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// LabelName converts a pod label key (e.g. app.kubernetes.io/name) to the
+// name of the corresponding label of the result gauges (label_app_kubernetes_io_name)
+func LabelName(podLabel string) string {
+	return "label_" + invalidLabelChars.ReplaceAllString(podLabel, "_")
+}
+
+func newResultsGauge(podLabels []string) *prometheus.GaugeVec {
+	labels := append([]string{}, resultLabels...)
+	for _, podLabel := range podLabels {
+		labels = append(labels, LabelName(podLabel))
+	}
+	return prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "benchmark_result",
+		Help:      "Results of the successfully completed benchmarks.",
+	}, labels)
+}
+
+// resultsGauge returns the result gauges, registering them if needed.
+// resultsMu must be held by the caller.
+func resultsGauge() *prometheus.GaugeVec {
+	if results == nil {
+		results = newResultsGauge(podLabels)
+		metrics.Registry.MustRegister(results)
+	}
+	return results
+}
+
+// SetPodLabels selects the labels of the benchmark pods which are added
+// to the result gauges. It must be called on startup, before the first
+// results are published.
+func SetPodLabels(labels []string) error {
+	resultsMu.Lock()
+	defer resultsMu.Unlock()
+
+	if results != nil {
+		return errors.New("benchmark results are already published")
+	}
+	names := map[string]string{}
+	for _, podLabel := range labels {
+		name := LabelName(podLabel)
+		if other, ok := names[name]; ok {
+			return fmt.Errorf("pod labels %q and %q are both published as %s", other, podLabel, name)
+		}
+		names[name] = podLabel
+	}
+	podLabels = labels
+	resultsGauge()
+	return nil
+}
+
+// PodLabels returns the pod labels selected by SetPodLabels
+func PodLabels() []string {
+	resultsMu.Lock()
+	defer resultsMu.Unlock()
+	return podLabels
+}
+
+// RecordPhaseTransition updates the operator level statistics when a
+// benchmark moves from one phase to another. inPhase is the time spent
+// by the benchmark in the previous phase.
+func RecordPhaseTransition(kind, ns string, from, to perfv1alpha1.BenchmarkPhase, inPhase time.Duration) {
+	if from == to {
+		return
+	}
+
+	if from == "" {
+		benchmarksStarted.WithLabelValues(kind, ns).Inc()
+	} else {
+		phaseDuration.WithLabelValues(kind, string(from)).Observe(inPhase.Seconds())
+	}
+
+	switch to {
+	case perfv1alpha1.BenchmarkSucceeded:
+		benchmarksSucceeded.WithLabelValues(kind, ns).Inc()
+	case perfv1alpha1.BenchmarkFailed:
+		benchmarksFailed.WithLabelValues(kind, ns).Inc()
+	case perfv1alpha1.BenchmarkCancelled:
+		benchmarksCancelled.WithLabelValues(kind, ns).Inc()
+	}
+}
+
+// SetResultLabels stages the labels of the benchmark pods, which are used
+// to label the results of the benchmark once they are published
+func SetResultLabels(kind, ns, name string, labels map[string]string) {
+	resultsMu.Lock()
+	defer resultsMu.Unlock()
+	staged[benchmarkKey{kind, ns, name}] = labels
+}
+
+// PublishResults exposes the metrics of the benchmark results as gauges.
+// It is called once the benchmark has succeeded, as the results of the
+// benchmark are checked against its assertions and baseline first. The
+// values of the selected pod labels are taken from the labels staged by
+// SetResultLabels, missing labels are published with empty value. The
+// earlier results of the benchmark (e.g. of its previous run) are replaced.
+func PublishResults(kind, ns, name string, benchmarkResults *perfv1alpha1.BenchmarkResults) {
+	if benchmarkResults == nil {
+		return
+	}
+
+	resultsMu.Lock()
+	defer resultsMu.Unlock()
+
+	key := benchmarkKey{kind, ns, name}
+	deleteResults(key)
+	labels := staged[key]
+
+	gauge := resultsGauge()
+	var series [][]string
+	for _, metric := range benchmarkResults.Metrics {
+		value, err := metric.Float64()
+		if err != nil {
+			continue
+		}
+		labelValues := []string{kind, ns, name, metric.Name, metric.Unit}
+		for _, podLabel := range podLabels {
+			labelValues = append(labelValues, labels[podLabel])
+		}
+		gauge.WithLabelValues(labelValues...).Set(value)
+		series = append(series, labelValues)
+	}
+	published[key] = series
+}
+
+// DeleteResults removes the published results of the benchmark, which
+// is called once the benchmark is deleted
+func DeleteResults(kind, ns, name string) {
+	resultsMu.Lock()
+	defer resultsMu.Unlock()
+	key := benchmarkKey{kind, ns, name}
+	deleteResults(key)
+	delete(staged, key)
+}
+
+// deleteResults removes the series published for the benchmark.
+// resultsMu must be held by the caller.
+func deleteResults(key benchmarkKey) {
+	for _, labelValues := range published[key] {
+		results.DeleteLabelValues(labelValues...)
+	}
+	delete(published, key)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("operator metrics", func() {
+	Context("when benchmarks move between phases", func() {
+		It("should count the started, succeeded and failed benchmarks", func() {
+			RecordPhaseTransition("Fio", "phases", "", perfv1alpha1.BenchmarkPending, 0)
+			RecordPhaseTransition("Fio", "phases", perfv1alpha1.BenchmarkPending,
+				perfv1alpha1.BenchmarkRunning, time.Second)
+			RecordPhaseTransition("Fio", "phases", perfv1alpha1.BenchmarkRunning,
+				perfv1alpha1.BenchmarkSucceeded, time.Minute)
+			RecordPhaseTransition("Fio", "phases", "", perfv1alpha1.BenchmarkValidating, 0)
+			RecordPhaseTransition("Fio", "phases", perfv1alpha1.BenchmarkValidating,
+				perfv1alpha1.BenchmarkFailed, time.Second)
+
+			Expect(testutil.ToFloat64(benchmarksStarted.WithLabelValues("Fio", "phases"))).To(Equal(2.0))
+			Expect(testutil.ToFloat64(benchmarksSucceeded.WithLabelValues("Fio", "phases"))).To(Equal(1.0))
+			Expect(testutil.ToFloat64(benchmarksFailed.WithLabelValues("Fio", "phases"))).To(Equal(1.0))
+		})
+
+		It("should ignore updates without phase change", func() {
+			RecordPhaseTransition("Fio", "same", perfv1alpha1.BenchmarkRunning,
+				perfv1alpha1.BenchmarkRunning, time.Second)
+			Expect(testutil.ToFloat64(benchmarksStarted.WithLabelValues("Fio", "same"))).To(BeZero())
+		})
+	})
+})
+
+var _ = Describe("result metrics", func() {
+	benchmarkResults := &perfv1alpha1.BenchmarkResults{}
+	benchmarkResults.AddMetric("read.iops", 1234.5, "")
+	benchmarkResults.AddMetric("read.bw", 1048576, "bytes/s")
+
+	countResults := func() int {
+		families, err := crmetrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+		count := 0
+		for _, family := range families {
+			if family.GetName() == "kubestone_benchmark_result" {
+				count += len(family.GetMetric())
+			}
+		}
+		return count
+	}
+
+	BeforeEach(func() {
+		crmetrics.Registry = prometheus.NewRegistry()
+		results = nil
+		podLabels = nil
+		published = map[benchmarkKey][][]string{}
+		staged = map[benchmarkKey]map[string]string{}
+	})
+
+	It("should publish the metrics of the results", func() {
+		PublishResults("Fio", "results", "fio-sample", benchmarkResults)
+
+		Expect(testutil.ToFloat64(results.WithLabelValues(
+			"Fio", "results", "fio-sample", "read.iops", ""))).To(Equal(1234.5))
+		Expect(testutil.ToFloat64(results.WithLabelValues(
+			"Fio", "results", "fio-sample", "read.bw", "bytes/s"))).To(Equal(1048576.0))
+	})
+
+	It("should label the results with the selected pod labels", func() {
+		Expect(SetPodLabels([]string{"app.kubernetes.io/instance", "zone"})).To(Succeed())
+		SetResultLabels("Fio", "results", "fio-sample",
+			map[string]string{"app.kubernetes.io/instance": "fio-sample", "other": "x"})
+		PublishResults("Fio", "results", "fio-sample", benchmarkResults)
+
+		Expect(PodLabels()).To(ConsistOf("app.kubernetes.io/instance", "zone"))
+		Expect(testutil.ToFloat64(results.WithLabelValues(
+			"Fio", "results", "fio-sample", "read.iops", "", "fio-sample", ""))).To(Equal(1234.5))
+	})
+
+	It("should not allow to change the pod labels after publishing", func() {
+		PublishResults("Fio", "results", "fio-sample", benchmarkResults)
+		Expect(SetPodLabels([]string{"zone"})).NotTo(Succeed())
+	})
+
+	It("should replace the earlier results of the benchmark", func() {
+		PublishResults("Fio", "results", "fio-sample", benchmarkResults)
+		rerun := &perfv1alpha1.BenchmarkResults{}
+		rerun.AddMetric("read.iops", 1000, "")
+		PublishResults("Fio", "results", "fio-sample", rerun)
+
+		Expect(countResults()).To(Equal(1))
+		Expect(testutil.ToFloat64(results.WithLabelValues(
+			"Fio", "results", "fio-sample", "read.iops", ""))).To(Equal(1000.0))
+	})
+
+	It("should delete the results of the deleted benchmark only", func() {
+		PublishResults("Fio", "results", "fio-sample", benchmarkResults)
+		PublishResults("Fio", "results", "fio-other", benchmarkResults)
+		DeleteResults("Fio", "results", "fio-sample")
+
+		Expect(countResults()).To(Equal(2))
+		Expect(testutil.ToFloat64(results.WithLabelValues(
+			"Fio", "results", "fio-other", "read.bw", "bytes/s"))).To(Equal(1048576.0))
+	})
+
+	It("should reject pod labels published under the same name", func() {
+		err := SetPodLabels([]string{"app.kubernetes.io/name", "app.kubernetes.io_name"})
+		Expect(err).To(MatchError(ContainSubstring("label_app_kubernetes_io_name")))
+	})
+
+	It("should convert the pod label keys to valid label names", func() {
+		Expect(LabelName("app.kubernetes.io/name")).To(Equal("label_app_kubernetes_io_name"))
+		Expect(LabelName("zone")).To(Equal("label_zone"))
+	})
+})
@@ -0,0 +1,276 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package results
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/baseline"
+	"github.com/xridge/kubestone/pkg/k8s"
+)
+
+const (
+	// LogExcerptLines is the number of lines kept from the end of the
+	// logs of each benchmark pod in the BenchmarkResult
+	LogExcerptLines = 50
+
+	// maxLogExcerptLength limits the size of the log excerpt, so the
+	// BenchmarkResult stays well below the object size limit of etcd
+	maxLogExcerptLength = 16 * 1024
+
+	// CRNameLabel is the label of the BenchmarkResult selecting the records
+	// of a benchmark by its name. Long names are shortened in the label,
+	// and are kept intact in the annotation of the same key.
+	CRNameLabel = "kubestone.xridge.io/cr-name"
+)
+
+// +kubebuilder:rbac:groups=perf.kubestone.xridge.io,resources=benchmarkresults,verbs=get;list;watch;create
+
+// Record creates a BenchmarkResult from the finished benchmark and the
+// pods of its jobs, and persists the Recorded condition of the benchmark.
+// The BenchmarkResult is not owned by the benchmark, therefore it outlives
+// the custom resource. Benchmarks are recorded only once: those with the
+// Recorded condition are skipped, so the record can be retried until it
+// is created. Repeated benchmarks are recorded once, when their last run
+// has finished.
+func Record(ctx context.Context, access *k8s.Access, cr perfv1alpha1.Benchmark, jobs ...types.NamespacedName) error {
+	status := cr.GetBenchmarkStatus()
+	if !status.IsFinished() || status.GetCondition(perfv1alpha1.ConditionRecorded) != nil {
+		return nil
+	}
+
+	if err := CreateRecord(ctx, access, cr, jobs...); err != nil {
+		return err
+	}
+	return access.Client.Status().Update(ctx, cr)
+}
+
+// CreateRecord creates the BenchmarkResult of the finished benchmark and
+// sets its Recorded condition, which is persisted by the next status
+// update. Errors are returned, so the record can be retried, except when
+// the BenchmarkResult is rejected as invalid: that is reported as an event
+// and the Recorded condition is set to false.
+func CreateRecord(ctx context.Context, access *k8s.Access, cr perfv1alpha1.Benchmark, jobs ...types.NamespacedName) error {
+	var pods []corev1.Pod
+	for _, job := range jobs {
+		jobPods, err := access.GetJobPods(job)
+		if err != nil {
+			return err
+		}
+		if jobPods != nil {
+			pods = append(pods, jobPods.Items...)
+		}
+	}
+
+	tailLines := int64(LogExcerptLines)
+	var logs strings.Builder
+	for i := range pods {
+		podLogs, err := access.GetPodLogs(&pods[i], &tailLines)
+		if err != nil {
+			// Logs are not available if the pod could not be started
+			continue
+		}
+		logs.WriteString(podLogs)
+	}
+
+	result, err := NewBenchmarkResult(cr, access.Scheme, pods, logs.String())
+	if err != nil {
+		return err
+	}
+
+	status := cr.GetBenchmarkStatus()
+	switch err := access.Client.Create(ctx, result); {
+	case err == nil:
+		_ = access.RecordEventf(cr, corev1.EventTypeNormal, k8s.Recorded,
+			"Recorded benchmark result: %s", result.Name)
+	case errors.IsAlreadyExists(err):
+		// Created by an earlier attempt
+	case errors.IsInvalid(err):
+		_ = access.RecordEventf(cr, corev1.EventTypeWarning, k8s.RecordFailed,
+			"Unable to record benchmark result: %v", err)
+		status.SetCondition(perfv1alpha1.ConditionRecorded, corev1.ConditionFalse,
+			k8s.RecordFailed, err.Error())
+		return nil
+	default:
+		return err
+	}
+
+	status.SetCondition(perfv1alpha1.ConditionRecorded, corev1.ConditionTrue,
+		k8s.Recorded, result.Name)
+	return nil
+}
+
+// NewBenchmarkResult assembles the BenchmarkResult of a finished benchmark
+// from its custom resource, the pods of its jobs and the excerpt of their logs.
+// The name of the BenchmarkResult is derived from the kind, name and UID of
+// the benchmark, so that every run is recorded exactly once.
+func NewBenchmarkResult(cr perfv1alpha1.Benchmark, scheme *runtime.Scheme,
+	pods []corev1.Pod, logs string) (*perfv1alpha1.BenchmarkResult, error) {
+	gvk, err := apiutil.GVKForObject(cr, scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	benchmarkSpec, err := specSnapshot(cr)
+	if err != nil {
+		return nil, err
+	}
+
+	status := cr.GetBenchmarkStatus()
+	uid := string(cr.GetUID())
+	if len(uid) > 8 {
+		uid = uid[:8]
+	}
+
+	// The kubestone labels of the benchmark (e.g. the schedule or suite
+	// which created it) are kept, so the records can be selected by them
+	labels := map[string]string{}
+	for key, value := range cr.GetLabels() {
+		if strings.HasPrefix(key, "kubestone.xridge.io/") {
+			labels[key] = value
+		}
+	}
+	labels["kubestone.xridge.io/app"] = strings.ToLower(gvk.Kind)
+
+	// The record of a succeeded run becomes the new baseline when requested,
+	// unless the run has regressed compared to the previous baseline
+	if spec := cr.GetCommonSpec().Baseline; spec != nil && spec.Promote &&
+		status.Phase == perfv1alpha1.BenchmarkSucceeded &&
+		(status.Comparison == nil || !status.Comparison.IsRegressed()) {
+		labels[baseline.Label] = spec.Key
+	}
+	labels[CRNameLabel] = crNameLabelValue(cr.GetName())
+
+	result := &perfv1alpha1.BenchmarkResult{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resultName(gvk.Kind, cr.GetName(), uid),
+			Namespace: cr.GetNamespace(),
+			Labels:    labels,
+			// The label may be shortened, the annotation keeps the full name
+			Annotations: map[string]string{CRNameLabel: cr.GetName()},
+		},
+		Spec: perfv1alpha1.BenchmarkResultSpec{
+			Benchmark: perfv1alpha1.BenchmarkReference{
+				APIVersion: gvk.GroupVersion().String(),
+				Kind:       gvk.Kind,
+				Name:       cr.GetName(),
+				UID:        cr.GetUID(),
+			},
+			BenchmarkSpec:  benchmarkSpec,
+			Phase:          status.Phase,
+			Reason:         status.Reason,
+			Message:        status.Message,
+			StartTime:      status.StartTime,
+			CompletionTime: status.CompletionTime,
+			Duration:       status.Duration,
+			Results:        status.Results,
+			Iterations:     status.Iterations,
+			Comparison:     status.Comparison,
+			Assertions:     status.Assertions,
+			LogExcerpt:     truncateLogs(logs),
+		},
+	}
+
+	nodeNames := map[string]bool{}
+	images := map[perfv1alpha1.ContainerImage]bool{}
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" && !nodeNames[pod.Spec.NodeName] {
+			nodeNames[pod.Spec.NodeName] = true
+			result.Spec.NodeNames = append(result.Spec.NodeNames, pod.Spec.NodeName)
+		}
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			image := perfv1alpha1.ContainerImage{
+				Container: containerStatus.Name,
+				Image:     containerStatus.Image,
+				ImageID:   containerStatus.ImageID,
+			}
+			if !images[image] {
+				images[image] = true
+				result.Spec.Images = append(result.Spec.Images, image)
+			}
+		}
+	}
+	sort.Strings(result.Spec.NodeNames)
+
+	return result, nil
+}
+
+// crNameLabelValue returns the name of the benchmark as label value. Names
+// longer than the 63 characters allowed for label values are shortened.
+func crNameLabelValue(name string) string {
+	return shorten(name, validation.LabelValueMaxLength)
+}
+
+// resultName returns the name of the record of the benchmark. Names longer
+// than the 253 characters allowed for object names are shortened.
+func resultName(kind, name, uid string) string {
+	return shorten(fmt.Sprintf("%s-%s-%s", strings.ToLower(kind), name, uid),
+		validation.DNS1123SubdomainMaxLength)
+}
+
+// shorten cuts the name to the given length, suffixed with the hash of the
+// full name, so the shortened name stays unique
+func shorten(name string, length int) string {
+	if len(name) <= length {
+		return name
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(name)))[:8]
+	return strings.TrimRight(name[:length-len(hash)-1], "-.") + "-" + hash
+}
+
+// specSnapshot returns the spec of the benchmark custom resource as raw json
+func specSnapshot(cr perfv1alpha1.Benchmark) (*runtime.RawExtension, error) {
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cr)
+	if err != nil {
+		return nil, err
+	}
+	spec, ok := object["spec"]
+	if !ok {
+		return nil, nil
+	}
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &runtime.RawExtension{Raw: raw}, nil
+}
+
+// truncateLogs keeps the end of the logs if they exceed the size limit
+func truncateLogs(logs string) string {
+	if len(logs) <= maxLogExcerptLength {
+		return logs
+	}
+	logs = logs[len(logs)-maxLogExcerptLength:]
+	if newline := strings.IndexByte(logs, '\n'); newline >= 0 {
+		logs = logs[newline+1:]
+	}
+	return logs
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package results
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/baseline"
+	"github.com/xridge/kubestone/pkg/k8s"
+)
+
+var _ = Describe("benchmark result record", func() {
+	var scheme *runtime.Scheme
+	var cr *perfv1alpha1.Ioping
+	var pods []corev1.Pod
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		_ = k8sscheme.AddToScheme(scheme)
+		_ = perfv1alpha1.AddToScheme(scheme)
+
+		cr = &perfv1alpha1.Ioping{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ioping-sample",
+				Namespace: "kubestone",
+				UID:       "0c5dd3a4-7f8a-4b35-9a4c-0b1c2d3e4f50",
+			},
+			Spec: perfv1alpha1.IopingSpec{
+				Image: perfv1alpha1.ImageSpec{Name: "xridge/ioping:0.1"},
+				Args:  "-c 10",
+			},
+		}
+		cr.Status.SetPhase(perfv1alpha1.BenchmarkSucceeded, "Completed", "")
+		cr.Status.Results = &perfv1alpha1.BenchmarkResults{}
+		cr.Status.Results.AddMetric("iops", 1234, "")
+
+		pods = []corev1.Pod{
+			{
+				Spec: corev1.PodSpec{NodeName: "node-b"},
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:    "main",
+						Image:   "xridge/ioping:0.1",
+						ImageID: "docker-pullable://xridge/ioping@sha256:0123",
+					}},
+				},
+			},
+			{
+				Spec: corev1.PodSpec{NodeName: "node-a"},
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:    "main",
+						Image:   "xridge/ioping:0.1",
+						ImageID: "docker-pullable://xridge/ioping@sha256:0123",
+					}},
+				},
+			},
+		}
+	})
+
+	Context("when created from a finished benchmark", func() {
+		var result *perfv1alpha1.BenchmarkResult
+
+		BeforeEach(func() {
+			var err error
+			result, err = NewBenchmarkResult(cr, scheme, pods, "10 requests completed\n")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should be named after the benchmark run", func() {
+			Expect(result.Name).To(Equal("ioping-ioping-sample-0c5dd3a4"))
+			Expect(result.Namespace).To(Equal("kubestone"))
+			Expect(result.Labels).To(HaveKeyWithValue("kubestone.xridge.io/cr-name", "ioping-sample"))
+		})
+
+		It("should keep the kubestone labels of the benchmark", func() {
+			cr.Labels = map[string]string{
+				"kubestone.xridge.io/schedule": "nightly",
+				"team":                         "storage",
+			}
+			result, err := NewBenchmarkResult(cr, scheme, pods, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Labels).To(HaveKeyWithValue("kubestone.xridge.io/schedule", "nightly"))
+			Expect(result.Labels).NotTo(HaveKey("team"))
+		})
+
+		It("should become the baseline if promoted", func() {
+			cr.Spec.Baseline = &perfv1alpha1.BaselineSpec{Key: "local-disk", Promote: true}
+			result, err := NewBenchmarkResult(cr, scheme, pods, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Labels).To(HaveKeyWithValue(baseline.Label, "local-disk"))
+
+			cr.Status.Comparison = &perfv1alpha1.BaselineComparison{
+				Metrics: []perfv1alpha1.MetricComparison{{Name: "iops", Regressed: true}},
+			}
+			result, err = NewBenchmarkResult(cr, scheme, pods, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Labels).NotTo(HaveKey(baseline.Label))
+		})
+
+		It("should not be owned by the benchmark", func() {
+			Expect(result.OwnerReferences).To(BeEmpty())
+		})
+
+		It("should refer to the benchmark", func() {
+			Expect(result.Spec.Benchmark).To(Equal(perfv1alpha1.BenchmarkReference{
+				APIVersion: "perf.kubestone.xridge.io/v1alpha1",
+				Kind:       "Ioping",
+				Name:       "ioping-sample",
+				UID:        cr.UID,
+			}))
+		})
+
+		It("should contain the snapshot of the spec", func() {
+			var spec perfv1alpha1.IopingSpec
+			Expect(json.Unmarshal(result.Spec.BenchmarkSpec.Raw, &spec)).To(Succeed())
+			Expect(spec.Args).To(Equal("-c 10"))
+		})
+
+		It("should contain the status of the run", func() {
+			Expect(result.Spec.Phase).To(Equal(perfv1alpha1.BenchmarkSucceeded))
+			Expect(result.Spec.StartTime).NotTo(BeNil())
+			Expect(result.Spec.CompletionTime).NotTo(BeNil())
+			Expect(result.Spec.Results.GetMetric("iops")).NotTo(BeNil())
+			Expect(result.Spec.LogExcerpt).To(Equal("10 requests completed\n"))
+		})
+
+		It("should contain the iterations of repeated runs", func() {
+			cr.Status.Iterations = []perfv1alpha1.BenchmarkIteration{{Iteration: 1, Warmup: true}, {Iteration: 2}}
+			result, err := NewBenchmarkResult(cr, scheme, pods, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Spec.Iterations).To(HaveLen(2))
+		})
+
+		It("should contain the nodes and images of the pods", func() {
+			Expect(result.Spec.NodeNames).To(Equal([]string{"node-a", "node-b"}))
+			Expect(result.Spec.Images).To(HaveLen(1))
+			Expect(result.Spec.Images[0].ImageID).To(ContainSubstring("sha256:0123"))
+		})
+	})
+
+	Context("when the benchmark is waiting for its next run", func() {
+		It("should not be recorded", func() {
+			cr.Status.SetPhase(perfv1alpha1.BenchmarkPending, k8s.Repeating, "")
+			Expect(Record(context.Background(), &k8s.Access{}, cr)).To(Succeed())
+		})
+	})
+
+	Context("with a long name", func() {
+		It("should shorten the name in the label and keep it in the annotation", func() {
+			cr.Name = strings.Repeat("long-benchmark-name-", 5)
+			result, err := NewBenchmarkResult(cr, scheme, pods, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			label := result.Labels[CRNameLabel]
+			Expect(validation.IsValidLabelValue(label)).To(BeEmpty())
+			Expect(label).To(HavePrefix("long-benchmark-name-"))
+			Expect(result.Annotations).To(HaveKeyWithValue(CRNameLabel, cr.Name))
+
+			other := cr.DeepCopy()
+			other.Name = cr.Name + "x"
+			otherResult, err := NewBenchmarkResult(other, scheme, pods, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(otherResult.Labels[CRNameLabel]).NotTo(Equal(label))
+		})
+
+		It("should shorten the name of the result", func() {
+			cr.Name = strings.Repeat("long.benchmark-name-", 13)
+			result, err := NewBenchmarkResult(cr, scheme, pods, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(validation.IsDNS1123Subdomain(result.Name)).To(BeEmpty())
+			Expect(result.Name).To(HavePrefix("ioping-long.benchmark-name-"))
+
+			other := cr.DeepCopy()
+			other.UID = "other-uid"
+			otherResult, err := NewBenchmarkResult(other, scheme, pods, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(otherResult.Name).NotTo(Equal(result.Name))
+		})
+	})
+
+	Context("with long logs", func() {
+		It("should keep the end of the logs", func() {
+			logs := strings.Repeat("0123456789abcdef\n", 2*maxLogExcerptLength/17) + "last line\n"
+			excerpt := truncateLogs(logs)
+			Expect(len(excerpt)).To(BeNumerically("<=", maxLogExcerptLength))
+			Expect(excerpt).To(HavePrefix("0123456789abcdef\n"))
+			Expect(excerpt).To(HaveSuffix("last line\n"))
+		})
+	})
+})
@@ -0,0 +1,143 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package results parses the output of the finished benchmarks into
+machine-readable results, which are stored in the status of the benchmark.
+
+Each controller registers the parser of its benchmark kind, which is looked
+up by the kind of the custom resource when the benchmark job(s) complete.
+*/
+package results
+
+import (
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+	"github.com/xridge/kubestone/pkg/metrics"
+)
+
+// Parser extracts the results of a benchmark from the logs of its job(s)
+type Parser interface {
+	Parse(logs string) (*perfv1alpha1.BenchmarkResults, error)
+}
+
+// ParserFunc is an adapter to use ordinary functions as Parser
+type ParserFunc func(logs string) (*perfv1alpha1.BenchmarkResults, error)
+
+// Parse calls f(logs)
+func (f ParserFunc) Parse(logs string) (*perfv1alpha1.BenchmarkResults, error) {
+	return f(logs)
+}
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = map[string]Parser{}
+)
+
+// Register makes the parser available for the given benchmark kind
+// (e.g. Fio, Pgbench). It is intended to be called from the init
+// function of the controller packages.
+func Register(kind string, parser Parser) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+	parsers[kind] = parser
+}
+
+// Lookup returns the parser registered for the given benchmark kind
+func Lookup(kind string) (Parser, bool) {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+	parser, ok := parsers[kind]
+	return parser, ok
+}
+
+// Collect fetches the logs of the given jobs and parses them with the
+// parser registered for the kind of the benchmark. The results are
+// stored in the status of the benchmark, persisted by the next status
+// update and published as metrics. Benchmark kinds without a registered
+// parser are ignored. Unparsable output is reported as an event, but is not
+// considered an error, as the benchmark itself has completed.
+func Collect(access *k8s.Access, cr perfv1alpha1.Benchmark, jobs ...types.NamespacedName) error {
+	gvk, err := apiutil.GVKForObject(cr, access.Scheme)
+	if err != nil {
+		return err
+	}
+
+	parser, ok := Lookup(gvk.Kind)
+	if !ok {
+		return nil
+	}
+
+	var logs strings.Builder
+	for _, job := range jobs {
+		jobLogs, err := access.GetJobLogs(job)
+		if err != nil {
+			return err
+		}
+		logs.WriteString(jobLogs)
+	}
+
+	results, err := parser.Parse(logs.String())
+	if err != nil {
+		_ = access.RecordEventf(cr, corev1.EventTypeWarning, k8s.ResultsParseFailed,
+			"Unable to parse benchmark results: %v", err)
+		return nil
+	}
+
+	return Publish(access, cr, results, podLabels(access, jobs))
+}
+
+// Publish stores the results in the status of the benchmark, persisted
+// by the next status update. The results are published as metrics once
+// the benchmark has succeeded (see k8s.Access.UpdatePhase), labelled with
+// the selected labels of the benchmark pods (see metrics.SetPodLabels),
+// given by labels.
+func Publish(access *k8s.Access, cr perfv1alpha1.Benchmark,
+	results *perfv1alpha1.BenchmarkResults, labels map[string]string) error {
+	gvk, err := apiutil.GVKForObject(cr, access.Scheme)
+	if err != nil {
+		return err
+	}
+
+	cr.GetBenchmarkStatus().Results = results
+	metrics.SetResultLabels(gvk.Kind, cr.GetNamespace(), cr.GetName(), labels)
+	return nil
+}
+
+// podLabels returns the labels of the first pod of the jobs, which are
+// used to label the published results
+func podLabels(access *k8s.Access, jobs []types.NamespacedName) map[string]string {
+	if len(metrics.PodLabels()) == 0 {
+		return nil
+	}
+	for _, job := range jobs {
+		pods, err := access.GetJobPods(job)
+		if err != nil {
+			continue
+		}
+		for _, pod := range pods.Items {
+			return pod.Labels
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package results
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+)
+
+var _ = Describe("parser registry", func() {
+	Context("with a registered parser", func() {
+		BeforeEach(func() {
+			Register("Test", ParserFunc(func(logs string) (*perfv1alpha1.BenchmarkResults, error) {
+				if logs == "" {
+					return nil, errors.New("empty output")
+				}
+				results := &perfv1alpha1.BenchmarkResults{}
+				results.AddMetric("length", float64(len(logs)), "bytes")
+				return results, nil
+			}))
+		})
+
+		It("is found by kind", func() {
+			parser, ok := Lookup("Test")
+			Expect(ok).To(BeTrue())
+
+			results, err := parser.Parse("12345")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.GetMetric("length").Value).To(Equal("5"))
+		})
+
+		It("returns the errors of the parser", func() {
+			parser, _ := Lookup("Test")
+			_, err := parser.Parse("")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("without a registered parser", func() {
+		It("is not found", func() {
+			_, ok := Lookup("Unknown")
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("publishing results", func() {
+	var access *k8s.Access
+
+	// isPublished returns true if the results of the benchmark are published as metrics
+	isPublished := func(name string) bool {
+		families, err := crmetrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+		for _, family := range families {
+			if family.GetName() != "kubestone_benchmark_result" {
+				continue
+			}
+			for _, metric := range family.Metric {
+				for _, label := range metric.Label {
+					if label.GetName() == "name" && label.GetValue() == name {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+
+	publish := func(name string) *perfv1alpha1.PVCLatency {
+		cr := &perfv1alpha1.PVCLatency{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "publish"}}
+		Expect(access.Client.Create(context.Background(), cr)).To(Succeed())
+		results := &perfv1alpha1.BenchmarkResults{}
+		results.AddMetric("bound.p50", 1.5, "s")
+
+		Expect(Publish(access, cr, results, nil)).To(Succeed())
+		Expect(cr.Status.Results).To(Equal(results))
+		return cr
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		_ = perfv1alpha1.AddToScheme(scheme)
+		access = &k8s.Access{Client: fake.NewFakeClientWithScheme(scheme), Scheme: scheme}
+	})
+
+	It("should publish the results as metrics once the benchmark has succeeded", func() {
+		cr := publish("succeeded")
+		Expect(isPublished("succeeded")).To(BeFalse())
+
+		Expect(access.UpdatePhase(context.Background(), cr, perfv1alpha1.BenchmarkSucceeded,
+			k8s.Completed, "")).To(Succeed())
+		Expect(isPublished("succeeded")).To(BeTrue())
+	})
+
+	It("should not publish the results of failed benchmarks", func() {
+		cr := publish("failed")
+		Expect(access.UpdatePhase(context.Background(), cr, perfv1alpha1.BenchmarkFailed,
+			k8s.AssertionFailed, "")).To(Succeed())
+		Expect(isPublished("failed")).To(BeFalse())
+	})
+})
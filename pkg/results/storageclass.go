@@ -0,0 +1,93 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package results
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/k8s"
+)
+
+// CollectStorageClasses is the counterpart of Collect for the StorageClass
+// comparison: the logs of each job are parsed on their own, and the results
+// are stored side by side, labelled with the StorageClass of the job. The
+// jobs must be given in the order of the storageClasses.
+func CollectStorageClasses(access *k8s.Access, cr perfv1alpha1.Benchmark,
+	storageClasses []string, jobs ...types.NamespacedName) error {
+	if len(storageClasses) != len(jobs) {
+		return fmt.Errorf("got %d jobs for %d storage classes", len(jobs), len(storageClasses))
+	}
+
+	gvk, err := apiutil.GVKForObject(cr, access.Scheme)
+	if err != nil {
+		return err
+	}
+
+	parser, ok := Lookup(gvk.Kind)
+	if !ok {
+		return nil
+	}
+
+	var classResults []*perfv1alpha1.BenchmarkResults
+	for i, job := range jobs {
+		jobLogs, err := access.GetJobLogs(job)
+		if err != nil {
+			return err
+		}
+		res, err := parser.Parse(jobLogs)
+		if err != nil {
+			_ = access.RecordEventf(cr, corev1.EventTypeWarning, k8s.ResultsParseFailed,
+				"Unable to parse benchmark results of StorageClass %s: %v", storageClasses[i], err)
+			return nil
+		}
+		classResults = append(classResults, res)
+	}
+
+	return Publish(access, cr, CompareStorageClasses(storageClasses, classResults),
+		podLabels(access, jobs))
+}
+
+// CompareStorageClasses combines the results of the benchmark runs
+// against the PVCs of the given StorageClasses. The metrics of each
+// StorageClass are also added to the combined metrics, prefixed with
+// the name of the StorageClass, so they can be used in assertions and
+// baseline comparisons.
+func CompareStorageClasses(storageClasses []string,
+	classResults []*perfv1alpha1.BenchmarkResults) *perfv1alpha1.BenchmarkResults {
+	results := &perfv1alpha1.BenchmarkResults{}
+	for i, storageClass := range storageClasses {
+		entry := perfv1alpha1.StorageClassResults{StorageClass: storageClass}
+		if i < len(classResults) && classResults[i] != nil {
+			entry.Metrics = classResults[i].Metrics
+			entry.Fio = classResults[i].Fio
+		}
+		for _, metric := range entry.Metrics {
+			results.Metrics = append(results.Metrics, perfv1alpha1.BenchmarkMetric{
+				Name:  storageClass + "." + metric.Name,
+				Value: metric.Value,
+				Unit:  metric.Unit,
+			})
+		}
+		results.StorageClasses = append(results.StorageClasses, entry)
+	}
+	return results
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package results
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("StorageClass comparison", func() {
+	It("should report the results side by side", func() {
+		standard := &perfv1alpha1.BenchmarkResults{}
+		standard.AddMetric("iops", 1200, "ops/s")
+		standard.AddMetric("latency.avg", 830, "us")
+		fast := &perfv1alpha1.BenchmarkResults{Fio: &perfv1alpha1.FioResults{}}
+		fast.AddMetric("iops", 24000, "ops/s")
+		fast.AddMetric("latency.avg", 41.5, "us")
+
+		results := CompareStorageClasses([]string{"standard", "fast"},
+			[]*perfv1alpha1.BenchmarkResults{standard, fast})
+
+		Expect(results.StorageClasses).To(HaveLen(2))
+		Expect(results.StorageClasses[0].StorageClass).To(Equal("standard"))
+		Expect(results.StorageClasses[0].Metrics).To(Equal(standard.Metrics))
+		Expect(results.StorageClasses[0].Fio).To(BeNil())
+		Expect(results.StorageClasses[1].StorageClass).To(Equal("fast"))
+		Expect(results.StorageClasses[1].Fio).To(Equal(fast.Fio))
+
+		Expect(results.Metrics).To(Equal([]perfv1alpha1.BenchmarkMetric{
+			{Name: "standard.iops", Value: "1200", Unit: "ops/s"},
+			{Name: "standard.latency.avg", Value: "830", Unit: "us"},
+			{Name: "fast.iops", Value: "24000", Unit: "ops/s"},
+			{Name: "fast.latency.avg", Value: "41.5", Unit: "us"},
+		}))
+	})
+})
@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package results
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var byteUnits = map[string]float64{
+	"":    1,
+	"B":   1,
+	"K":   1 << 10,
+	"KB":  1000,
+	"KiB": 1 << 10,
+	"M":   1 << 20,
+	"MB":  1000 * 1000,
+	"MiB": 1 << 20,
+	"G":   1 << 30,
+	"GB":  1000 * 1000 * 1000,
+	"GiB": 1 << 30,
+	"T":   1 << 40,
+	"TB":  1000 * 1000 * 1000 * 1000,
+	"TiB": 1 << 40,
+}
+
+// Bytes converts the value given in the provided size unit
+// (e.g. KiB, MB) to bytes
+func Bytes(value float64, unit string) (float64, error) {
+	multiplier, ok := byteUnits[strings.TrimSpace(unit)]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit: %q", unit)
+	}
+	return value * multiplier, nil
+}
+
+var timeUnits = map[string]float64{
+	"ns":  0.001,
+	"us":  1,
+	"µs":  1,
+	"ms":  1000,
+	"s":   1000 * 1000,
+	"sec": 1000 * 1000,
+	"m":   60 * 1000 * 1000,
+	"min": 60 * 1000 * 1000,
+}
+
+// Microseconds converts the value given in the provided time unit
+// (e.g. ns, ms, s) to microseconds
+func Microseconds(value float64, unit string) (float64, error) {
+	multiplier, ok := timeUnits[strings.TrimSpace(unit)]
+	if !ok {
+		return 0, fmt.Errorf("unknown time unit: %q", unit)
+	}
+	return value * multiplier, nil
+}
+
+var durationRegexp = regexp.MustCompile(`^([0-9.]+)\s*([a-zµ]+)$`)
+
+// ParseMicroseconds parses a duration with unit (e.g. 1.2ms, 300 us)
+// and returns it in microseconds
+func ParseMicroseconds(duration string) (float64, error) {
+	match := durationRegexp.FindStringSubmatch(strings.TrimSpace(duration))
+	if match == nil {
+		return 0, fmt.Errorf("invalid duration: %q", duration)
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	return Microseconds(value, match[2])
+}
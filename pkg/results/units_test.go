@@ -0,0 +1,55 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package results
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("units", func() {
+	Context("size units", func() {
+		It("converts binary and decimal units to bytes", func() {
+			Expect(Bytes(2, "KiB")).To(Equal(2048.0))
+			Expect(Bytes(1.5, "MB")).To(Equal(1500000.0))
+			Expect(Bytes(3, "B")).To(Equal(3.0))
+		})
+
+		It("rejects unknown units", func() {
+			_, err := Bytes(1, "parsec")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("time units", func() {
+		It("converts to microseconds", func() {
+			Expect(Microseconds(1.5, "ms")).To(Equal(1500.0))
+			Expect(Microseconds(2, "s")).To(Equal(2000000.0))
+			Expect(Microseconds(500, "ns")).To(Equal(0.5))
+		})
+
+		It("parses durations with units", func() {
+			Expect(ParseMicroseconds("12ms")).To(Equal(12000.0))
+			Expect(ParseMicroseconds("162.3 us")).To(Equal(162.3))
+		})
+
+		It("rejects invalid durations", func() {
+			_, err := ParseMicroseconds("fast")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,128 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package stats computes the descriptive statistics of the metrics of
+repeated benchmark runs, and the percentiles of measured samples.
+*/
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// tCritical contains the two-sided 95% critical values of Student's
+// t-distribution, indexed by the degrees of freedom
+var tCritical = []float64{
+	math.NaN(),
+	12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+// zCritical is the two-sided 95% critical value of the normal
+// distribution, used above 30 degrees of freedom
+const zCritical = 1.960
+
+// Summary contains the descriptive statistics of a sample
+type Summary struct {
+	Samples int
+	Mean    float64
+	Median  float64
+	// StdDev is the sample (Bessel-corrected) standard deviation
+	StdDev float64
+	Min    float64
+	Max    float64
+	// ConfidenceLow and ConfidenceHigh bound the 95% confidence interval
+	// of the mean. They are NaN for a single sample.
+	ConfidenceLow  float64
+	ConfidenceHigh float64
+}
+
+// Summarize computes the statistics of the values. The values must not be empty.
+func Summarize(values []float64) Summary {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+
+	var sum float64
+	for _, value := range sorted {
+		sum += value
+	}
+	mean := sum / float64(n)
+
+	summary := Summary{
+		Samples:        n,
+		Mean:           mean,
+		Median:         median(sorted),
+		Min:            sorted[0],
+		Max:            sorted[n-1],
+		ConfidenceLow:  math.NaN(),
+		ConfidenceHigh: math.NaN(),
+	}
+	if n < 2 {
+		return summary
+	}
+
+	var squares float64
+	for _, value := range sorted {
+		squares += (value - mean) * (value - mean)
+	}
+	summary.StdDev = math.Sqrt(squares / float64(n-1))
+
+	margin := critical(n-1) * summary.StdDev / math.Sqrt(float64(n))
+	summary.ConfidenceLow = mean - margin
+	summary.ConfidenceHigh = mean + margin
+	return summary
+}
+
+// Percentile returns the p-th percentile (0 <= p <= 100) of the values,
+// interpolating linearly between the closest ranks. The values must not
+// be empty.
+func Percentile(values []float64, p float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower < 0 {
+		return sorted[0]
+	}
+	if upper >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (rank-float64(lower))*(sorted[upper]-sorted[lower])
+}
+
+// median returns the median of the sorted values
+func median(sorted []float64) float64 {
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// critical returns the critical value of the 95% confidence interval
+// for the given degrees of freedom
+func critical(degrees int) float64 {
+	if degrees < len(tCritical) {
+		return tCritical[degrees]
+	}
+	return zCritical
+}
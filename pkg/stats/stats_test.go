@@ -0,0 +1,78 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stats
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("stats", func() {
+	It("should summarize the values", func() {
+		summary := Summarize([]float64{4, 2, 5, 1, 3})
+		Expect(summary.Samples).To(Equal(5))
+		Expect(summary.Mean).To(Equal(3.0))
+		Expect(summary.Median).To(Equal(3.0))
+		Expect(summary.Min).To(Equal(1.0))
+		Expect(summary.Max).To(Equal(5.0))
+		Expect(summary.StdDev).To(BeNumerically("~", math.Sqrt(2.5), 1e-9))
+
+		margin := 2.776 * math.Sqrt(2.5) / math.Sqrt(5)
+		Expect(summary.ConfidenceLow).To(BeNumerically("~", 3-margin, 1e-9))
+		Expect(summary.ConfidenceHigh).To(BeNumerically("~", 3+margin, 1e-9))
+	})
+
+	It("should take the middle of the even samples as median", func() {
+		Expect(Summarize([]float64{10, 1, 2, 20}).Median).To(Equal(6.0))
+	})
+
+	It("should not compute confidence intervals from a single sample", func() {
+		summary := Summarize([]float64{7})
+		Expect(summary.Mean).To(Equal(7.0))
+		Expect(summary.StdDev).To(Equal(0.0))
+		Expect(math.IsNaN(summary.ConfidenceLow)).To(BeTrue())
+		Expect(math.IsNaN(summary.ConfidenceHigh)).To(BeTrue())
+	})
+
+	It("should use the normal distribution for large samples", func() {
+		values := make([]float64, 100)
+		for i := range values {
+			values[i] = float64(i % 2)
+		}
+		summary := Summarize(values)
+		margin := 1.96 * summary.StdDev / 10
+		Expect(summary.ConfidenceHigh - summary.Mean).To(BeNumerically("~", margin, 1e-9))
+	})
+
+	It("should interpolate the percentiles", func() {
+		values := []float64{50, 10, 40, 20, 30}
+		Expect(Percentile(values, 0)).To(Equal(10.0))
+		Expect(Percentile(values, 50)).To(Equal(30.0))
+		Expect(Percentile(values, 90)).To(BeNumerically("~", 46, 1e-9))
+		Expect(Percentile(values, 100)).To(Equal(50.0))
+		Expect(Percentile([]float64{7}, 99)).To(Equal(7.0))
+		Expect(values).To(Equal([]float64{50, 10, 40, 20, 30}))
+	})
+
+	It("should not modify the values", func() {
+		values := []float64{3, 1, 2}
+		Summarize(values)
+		Expect(values).To(Equal([]float64{3, 1, 2}))
+	})
+})
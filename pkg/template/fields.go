@@ -0,0 +1,208 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pathSegment matches a field of a JSON path with optional list indices,
+// e.g. tests[0]
+var pathSegment = regexp.MustCompile(`^([^\[\]]+)((?:\[\d+\])*)$`)
+
+// ParsePath splits the JSON path of a spec field (e.g. .tests[0].threads)
+// into field names and list indices. The leading dot and the spec prefix
+// are optional.
+func ParsePath(path string) ([]interface{}, error) {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(path, "."), "spec.")
+	if trimmed == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+
+	var segments []interface{}
+	for _, part := range strings.Split(trimmed, ".") {
+		match := pathSegment.FindStringSubmatch(part)
+		if match == nil {
+			return nil, fmt.Errorf("invalid path %q", path)
+		}
+		segments = append(segments, match[1])
+		for _, index := range strings.Split(strings.Trim(match[2], "[]"), "][") {
+			if index == "" {
+				continue
+			}
+			i, _ := strconv.Atoi(index)
+			segments = append(segments, i)
+		}
+	}
+	return segments, nil
+}
+
+// SetField sets the field of the spec at the given JSON path. The
+// missing objects on the path are created, while the indexed lists must
+// already contain the element. The value is converted to the type of the
+// current value of the field. Values of missing fields are converted to
+// numbers or booleans if they look like one, otherwise kept as strings.
+func SetField(spec map[string]interface{}, path string, value string) error {
+	segments, err := ParsePath(path)
+	if err != nil {
+		return err
+	}
+
+	var parent interface{} = spec
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		switch container := parent.(type) {
+		case map[string]interface{}:
+			name, ok := segment.(string)
+			if !ok {
+				return fmt.Errorf("%s: not a list", path)
+			}
+			if last {
+				converted, err := convert(value, container[name])
+				if err != nil {
+					return fmt.Errorf("%s: %v", path, err)
+				}
+				container[name] = converted
+				return nil
+			}
+			if _, ok := container[name]; !ok {
+				container[name] = newContainer(segments[i+1])
+			}
+			parent = container[name]
+		case []interface{}:
+			index, ok := segment.(int)
+			if !ok {
+				return fmt.Errorf("%s: not an object", path)
+			}
+			if index >= len(container) {
+				return fmt.Errorf("%s: index %d out of range", path, index)
+			}
+			if last {
+				converted, err := convert(value, container[index])
+				if err != nil {
+					return fmt.Errorf("%s: %v", path, err)
+				}
+				container[index] = converted
+				return nil
+			}
+			parent = container[index]
+		default:
+			return fmt.Errorf("%s: not an object", path)
+		}
+	}
+	return nil
+}
+
+// GetField returns the value of the field of the spec at the given JSON
+// path, or nil if the spec has no such field
+func GetField(spec map[string]interface{}, path string) (interface{}, error) {
+	segments, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{} = spec
+	for _, segment := range segments {
+		switch container := value.(type) {
+		case map[string]interface{}:
+			name, ok := segment.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: not a list", path)
+			}
+			value = container[name]
+		case []interface{}:
+			index, ok := segment.(int)
+			if !ok {
+				return nil, fmt.Errorf("%s: not an object", path)
+			}
+			if index >= len(container) {
+				return nil, nil
+			}
+			value = container[index]
+		default:
+			return nil, nil
+		}
+	}
+	return value, nil
+}
+
+// newContainer creates the missing object on the path
+func newContainer(next interface{}) interface{} {
+	if _, ok := next.(int); ok {
+		return []interface{}{}
+	}
+	return map[string]interface{}{}
+}
+
+// convert converts the value to the type of the current value
+func convert(value string, current interface{}) (interface{}, error) {
+	switch current.(type) {
+	case string:
+		return value, nil
+	case int64, int:
+		return strconv.ParseInt(value, 10, 64)
+	case float64:
+		return strconv.ParseFloat(value, 64)
+	case bool:
+		return strconv.ParseBool(value)
+	case nil:
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i, nil
+		}
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f, nil
+		}
+		if value == "true" || value == "false" {
+			return value == "true", nil
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("cannot set %T field", current)
+	}
+}
+
+// Substitute replaces the $(name) references in the string fields of
+// the spec with the given values
+func Substitute(spec map[string]interface{}, values map[string]string) {
+	if len(values) == 0 {
+		return
+	}
+	oldnew := make([]string, 0, 2*len(values))
+	for name, value := range values {
+		oldnew = append(oldnew, fmt.Sprintf("$(%s)", name), value)
+	}
+	substitute(spec, strings.NewReplacer(oldnew...))
+}
+
+func substitute(value interface{}, replacer *strings.Replacer) interface{} {
+	switch typed := value.(type) {
+	case string:
+		return replacer.Replace(typed)
+	case map[string]interface{}:
+		for key, item := range typed {
+			typed[key] = substitute(item, replacer)
+		}
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = substitute(item, replacer)
+		}
+	}
+	return value
+}
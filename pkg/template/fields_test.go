@@ -0,0 +1,97 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("fields", func() {
+	var spec map[string]interface{}
+
+	BeforeEach(func() {
+		var err error
+		spec, err = SpecFromRaw(&runtime.RawExtension{Raw: []byte(`{
+			"testName": "cpu",
+			"options": "--threads=$(threads) --time=$(time)",
+			"numConnections": 1,
+			"tests": [{"threads": 2, "args": ["--bs=$(bs)"]}]
+		}`)})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should parse paths", func() {
+		Expect(ParsePath(".tests[0].threads")).To(Equal([]interface{}{"tests", 0, "threads"}))
+		Expect(ParsePath("spec.testName")).To(Equal([]interface{}{"testName"}))
+		Expect(ParsePath("matrix[1][2]")).To(Equal([]interface{}{"matrix", 1, 2}))
+	})
+
+	It("should reject invalid paths", func() {
+		for _, path := range []string{"", ".", "tests[a]", "tests..threads", "[0]"} {
+			_, err := ParsePath(path)
+			Expect(err).To(HaveOccurred(), path)
+		}
+	})
+
+	It("should keep the type of existing fields", func() {
+		Expect(SetField(spec, "testName", "memory")).To(Succeed())
+		Expect(SetField(spec, "numConnections", "8")).To(Succeed())
+		Expect(SetField(spec, ".tests[0].threads", "16")).To(Succeed())
+		Expect(spec["testName"]).To(Equal("memory"))
+		Expect(spec["numConnections"]).To(Equal(8.0))
+		Expect(spec["tests"].([]interface{})[0]).To(HaveKeyWithValue("threads", 16.0))
+	})
+
+	It("should reject values not matching the type of the field", func() {
+		Expect(SetField(spec, "numConnections", "many")).NotTo(Succeed())
+		Expect(SetField(spec, "tests", "1")).NotTo(Succeed())
+	})
+
+	It("should create missing fields", func() {
+		Expect(SetField(spec, "podConfig.resources.limits.cpu", "2")).To(Succeed())
+		Expect(SetField(spec, "podConfig.resources.limits.memory", "1Gi")).To(Succeed())
+		Expect(SetField(spec, "podConfig.hostNetwork", "true")).To(Succeed())
+		podConfig := spec["podConfig"].(map[string]interface{})
+		limits := podConfig["resources"].(map[string]interface{})["limits"]
+		Expect(limits).To(HaveKeyWithValue("cpu", int64(2)))
+		Expect(limits).To(HaveKeyWithValue("memory", "1Gi"))
+		Expect(podConfig).To(HaveKeyWithValue("hostNetwork", true))
+	})
+
+	It("should not index past the end of lists", func() {
+		Expect(SetField(spec, "tests[1].threads", "4")).NotTo(Succeed())
+		Expect(SetField(spec, "testName.value", "4")).NotTo(Succeed())
+	})
+
+	It("should get the fields", func() {
+		Expect(GetField(spec, ".tests[0].threads")).To(Equal(2.0))
+		Expect(GetField(spec, "testName")).To(Equal("cpu"))
+		Expect(GetField(spec, "tests[1].threads")).To(BeNil())
+		Expect(GetField(spec, "podConfig.hostNetwork")).To(BeNil())
+		Expect(GetField(spec, "testName.value")).To(BeNil())
+	})
+
+	It("should substitute references in strings", func() {
+		Substitute(spec, map[string]string{"threads": "4", "time": "10", "bs": "4k"})
+		Expect(spec["options"]).To(Equal("--threads=4 --time=10"))
+		Expect(spec["tests"].([]interface{})[0]).To(HaveKeyWithValue("args", []interface{}{"--bs=4k"}))
+		Expect(spec["testName"]).To(Equal("cpu"))
+	})
+})
@@ -0,0 +1,160 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package template creates benchmark custom resources of any kind from
+templates. It is used by the kinds which run other benchmarks, such as
+suites, schedules and sweeps.
+*/
+package template
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+// Kinds returns an empty custom resource for each benchmark kind
+// registered in the scheme, keyed by kind
+func Kinds(scheme *runtime.Scheme) map[string]perfv1alpha1.Benchmark {
+	kinds := map[string]perfv1alpha1.Benchmark{}
+	for kind := range scheme.KnownTypes(perfv1alpha1.GroupVersion) {
+		object, err := scheme.New(perfv1alpha1.GroupVersion.WithKind(kind))
+		if err != nil {
+			continue
+		}
+		if benchmark, ok := object.(perfv1alpha1.Benchmark); ok {
+			kinds[kind] = benchmark
+		}
+	}
+	return kinds
+}
+
+// SortedKinds returns the names of the benchmark kinds in alphabetical order
+func SortedKinds(kinds map[string]perfv1alpha1.Benchmark) []string {
+	names := make([]string, 0, len(kinds))
+	for kind := range kinds {
+		names = append(names, kind)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SpecFromRaw returns the embedded spec of a template
+func SpecFromRaw(raw *runtime.RawExtension) (map[string]interface{}, error) {
+	spec := map[string]interface{}{}
+	if raw == nil || len(raw.Raw) == 0 {
+		return spec, nil
+	}
+	if err := json.Unmarshal(raw.Raw, &spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// SpecOf returns the spec of an existing benchmark to be used as template.
+// The benchmark might have been cancelled, which does not apply to the
+// benchmarks created from the template.
+func SpecOf(benchmark perfv1alpha1.Benchmark) (map[string]interface{}, error) {
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(benchmark)
+	if err != nil {
+		return nil, err
+	}
+	spec, _ := object["spec"].(map[string]interface{})
+	if spec == nil {
+		spec = map[string]interface{}{}
+	}
+	delete(spec, "cancel")
+	return spec, nil
+}
+
+// NewBenchmark creates a benchmark with the given name, namespace and labels
+// from the spec. The prototype is an empty custom resource of the kind of
+// the benchmark.
+func NewBenchmark(prototype perfv1alpha1.Benchmark, spec map[string]interface{},
+	name, namespace string, labels map[string]string) (perfv1alpha1.Benchmark, error) {
+	benchmark := prototype.DeepCopyObject().(perfv1alpha1.Benchmark)
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(map[string]interface{}{
+		"spec": spec,
+	}, benchmark)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spec: %v", err)
+	}
+
+	benchmark.SetName(name)
+	benchmark.SetNamespace(namespace)
+	benchmark.SetLabels(labels)
+	return benchmark, nil
+}
+
+// CheckField checks that the kind of the prototype has the field at the
+// given JSON path of the spec. The value is set in a copy of the spec,
+// which is converted to the kind and back. The conversion silently drops
+// the fields unknown to the kind, therefore the value does not survive
+// it if the kind has no such field. Zero values might be dropped as well,
+// so the value to check should not be empty.
+func CheckField(prototype perfv1alpha1.Benchmark, spec map[string]interface{}, path, value string) error {
+	probe := runtime.DeepCopyJSON(spec)
+	if err := SetField(probe, path, value); err != nil {
+		return err
+	}
+	benchmark, err := NewBenchmark(prototype, probe, "", "", nil)
+	if err != nil {
+		return err
+	}
+	converted, err := SpecOf(benchmark)
+	if err != nil {
+		return err
+	}
+	if field, err := GetField(converted, path); err != nil || field == nil {
+		return fmt.Errorf("%s: no such field", path)
+	}
+	return nil
+}
+
+// List returns the benchmarks of the given kind in the namespace
+// which match the labels
+func List(ctx context.Context, c client.Client, scheme *runtime.Scheme, kind, namespace string,
+	labels map[string]string) ([]perfv1alpha1.Benchmark, error) {
+	list, err := scheme.New(perfv1alpha1.GroupVersion.WithKind(kind + "List"))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.List(ctx, list, client.InNamespace(namespace), client.MatchingLabels(labels)); err != nil {
+		return nil, err
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	benchmarks := make([]perfv1alpha1.Benchmark, 0, len(items))
+	for _, item := range items {
+		benchmark, ok := item.(perfv1alpha1.Benchmark)
+		if !ok {
+			return nil, fmt.Errorf("%T is not a benchmark", item)
+		}
+		benchmarks = append(benchmarks, benchmark)
+	}
+	return benchmarks, nil
+}
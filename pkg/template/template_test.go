@@ -0,0 +1,114 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("template", func() {
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(perfv1alpha1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	It("should know the benchmark kinds of the scheme", func() {
+		kinds := Kinds(scheme)
+		Expect(kinds).To(HaveKey("Fio"))
+		Expect(kinds).To(HaveKey("BenchmarkSuite"))
+		Expect(kinds).NotTo(HaveKey("BenchmarkResult"))
+		Expect(kinds).NotTo(HaveKey("FioList"))
+		Expect(SortedKinds(kinds)).To(HaveLen(len(kinds)))
+	})
+
+	It("should accept empty specs", func() {
+		spec, err := SpecFromRaw(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec).To(BeEmpty())
+	})
+
+	It("should reject malformed specs", func() {
+		_, err := SpecFromRaw(&runtime.RawExtension{Raw: []byte(`{"testName":`)})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should create benchmarks from the spec", func() {
+		spec, err := SpecFromRaw(&runtime.RawExtension{Raw: []byte(`{"testName": "cpu", "timeout": "1h"}`)})
+		Expect(err).NotTo(HaveOccurred())
+
+		benchmark, err := NewBenchmark(&perfv1alpha1.Sysbench{}, spec, "sysbench", "kubestone",
+			map[string]string{"pool": "blue"})
+		Expect(err).NotTo(HaveOccurred())
+
+		sysbench := benchmark.(*perfv1alpha1.Sysbench)
+		Expect(sysbench.Name).To(Equal("sysbench"))
+		Expect(sysbench.Namespace).To(Equal("kubestone"))
+		Expect(sysbench.Labels).To(HaveKeyWithValue("pool", "blue"))
+		Expect(sysbench.Spec.TestName).To(Equal("cpu"))
+		Expect(sysbench.Spec.Timeout.Hours()).To(Equal(1.0))
+	})
+
+	It("should take the spec of existing benchmarks without cancellation", func() {
+		spec, err := SpecOf(&perfv1alpha1.Sysbench{Spec: perfv1alpha1.SysbenchSpec{
+			CommonSpec: perfv1alpha1.CommonSpec{Cancel: true},
+			TestName:   "memory",
+		}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec).To(HaveKeyWithValue("testName", "memory"))
+		Expect(spec).NotTo(HaveKey("cancel"))
+	})
+
+	It("should check the fields of the kind", func() {
+		spec, err := SpecFromRaw(&runtime.RawExtension{Raw: []byte(`{"testName": "cpu"}`)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(CheckField(&perfv1alpha1.Sysbench{}, spec, ".podConfig.podScheduling.nodeName", "node")).To(Succeed())
+		Expect(CheckField(&perfv1alpha1.Sysbench{}, spec, ".podConfig.resources.limits.cpu", "2")).To(Succeed())
+		Expect(CheckField(&perfv1alpha1.Sysbench{}, spec, "testName", "memory")).To(Succeed())
+		Expect(CheckField(&perfv1alpha1.Sysbench{}, spec, ".podConfig.bogusField", "1")).NotTo(Succeed())
+		Expect(CheckField(&perfv1alpha1.Sysbench{}, spec, ".clientConfiguration.podScheduling.nodeName",
+			"node")).NotTo(Succeed())
+		Expect(spec).NotTo(HaveKey("podConfig"))
+	})
+
+	It("should list the benchmarks of a kind by labels", func() {
+		labelled := &perfv1alpha1.Sysbench{ObjectMeta: metav1.ObjectMeta{
+			Name: "labelled", Namespace: "kubestone", Labels: map[string]string{"pool": "blue"},
+		}}
+		other := &perfv1alpha1.Sysbench{ObjectMeta: metav1.ObjectMeta{
+			Name: "other", Namespace: "kubestone",
+		}}
+		c := fake.NewFakeClientWithScheme(scheme, labelled, other)
+
+		benchmarks, err := List(context.Background(), c, scheme, "Sysbench", "kubestone",
+			map[string]string{"pool": "blue"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(benchmarks).To(HaveLen(1))
+		Expect(benchmarks[0].GetName()).To(Equal("labelled"))
+	})
+})
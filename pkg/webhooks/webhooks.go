@@ -0,0 +1,266 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package webhooks implements the defaulting and validating admission
+webhooks of the custom resources. The kind specific defaults and checks
+are provided by the controllers of the kinds, while the checks shared by
+all the benchmarks (common settings, spec changes of running benchmarks)
+are done here.
+*/
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+	"github.com/xridge/kubestone/pkg/assertion"
+)
+
+// MutableFields are the spec fields of the benchmarks which can be
+// changed while the benchmark is in progress, as they only control
+// its lifecycle
+var MutableFields = []string{"cancel", "cleanupPolicy", "ttlSecondsAfterFinished"}
+
+// Hooks are the kind specific parts of the admission of a custom resource.
+// Both of them are optional.
+type Hooks struct {
+	// Default sets the default values of the unset fields of the custom resource
+	Default func(obj runtime.Object)
+
+	// Validate returns an error if the custom resource is invalid
+	Validate func(obj runtime.Object) error
+}
+
+// SetupWithManager registers the admission webhooks of the kind of the
+// prototype in the webhook server of the manager. The defaulting webhook
+// is only registered if the hooks have defaults.
+func SetupWithManager(mgr ctrl.Manager, prototype runtime.Object, hooks Hooks) error {
+	gvk, err := apiutil.GVKForObject(prototype, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+
+	server := mgr.GetWebhookServer()
+	if hooks.Default != nil {
+		server.Register(MutatePath(gvk.Kind), &webhook.Admission{
+			Handler: NewDefaulter(prototype, hooks),
+		})
+	}
+	server.Register(ValidatePath(gvk.Kind), &webhook.Admission{
+		Handler: NewValidator(prototype, hooks),
+	})
+	return nil
+}
+
+// MutatePath returns the path of the defaulting webhook of the kind.
+// It has to match the path in the webhook marker of the kind.
+func MutatePath(kind string) string {
+	return "/mutate-" + pathSuffix(kind)
+}
+
+// ValidatePath returns the path of the validating webhook of the kind.
+// It has to match the path in the webhook marker of the kind.
+func ValidatePath(kind string) string {
+	return "/validate-" + pathSuffix(kind)
+}
+
+func pathSuffix(kind string) string {
+	return strings.Replace(perfv1alpha1.GroupVersion.Group, ".", "-", -1) + "-" +
+		perfv1alpha1.GroupVersion.Version + "-" + strings.ToLower(kind)
+}
+
+// Defaulter is the admission handler which sets the defaults of the
+// custom resources
+type Defaulter struct {
+	prototype runtime.Object
+	hooks     Hooks
+	decoder   *admission.Decoder
+}
+
+// NewDefaulter creates the defaulting admission handler of the kind of the prototype
+func NewDefaulter(prototype runtime.Object, hooks Hooks) *Defaulter {
+	return &Defaulter{prototype: prototype, hooks: hooks}
+}
+
+// InjectDecoder injects the decoder of the admission requests
+func (d *Defaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+// Handle sets the defaults of the custom resource in the request
+func (d *Defaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	obj := d.prototype.DeepCopyObject()
+	if err := d.decoder.Decode(req, obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	d.hooks.Default(obj)
+	marshalled, err := json.Marshal(obj)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshalled)
+}
+
+// Validator is the admission handler which rejects the invalid custom
+// resources and the spec changes of the benchmarks in progress
+type Validator struct {
+	prototype runtime.Object
+	hooks     Hooks
+	decoder   *admission.Decoder
+}
+
+// NewValidator creates the validating admission handler of the kind of the prototype
+func NewValidator(prototype runtime.Object, hooks Hooks) *Validator {
+	return &Validator{prototype: prototype, hooks: hooks}
+}
+
+// InjectDecoder injects the decoder of the admission requests
+func (v *Validator) InjectDecoder(decoder *admission.Decoder) error {
+	v.decoder = decoder
+	return nil
+}
+
+// Handle validates the custom resource in the request. Both the new and
+// the old custom resources are defaulted first, so that the defaults do not
+// count as spec changes. Updates which leave the spec unchanged are always
+// allowed, so that the custom resources accepted by earlier versions can
+// still be labelled or deleted.
+func (v *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	obj := v.prototype.DeepCopyObject()
+	if err := v.decoder.Decode(req, obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if v.hooks.Default != nil {
+		v.hooks.Default(obj)
+	}
+
+	if req.Operation == admissionv1beta1.Update {
+		old := v.prototype.DeepCopyObject()
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if v.hooks.Default != nil {
+			v.hooks.Default(old)
+		}
+
+		unchanged, err := specEqual(old, obj)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if unchanged {
+			return admission.Allowed("")
+		}
+		if err := ValidateUpdate(old, obj); err != nil {
+			return admission.Denied(err.Error())
+		}
+	}
+
+	if err := Validate(obj, v.hooks); err != nil {
+		return admission.Denied(err.Error())
+	}
+	return admission.Allowed("")
+}
+
+// Validate checks the common settings of the benchmarks, followed by the
+// kind specific validation of the custom resource
+func Validate(obj runtime.Object, hooks Hooks) error {
+	if benchmark, ok := obj.(perfv1alpha1.Benchmark); ok {
+		if err := validateCommonSpec(benchmark.GetCommonSpec()); err != nil {
+			return err
+		}
+	}
+	if hooks.Validate != nil {
+		return hooks.Validate(obj)
+	}
+	return nil
+}
+
+// ValidateUpdate returns an error if the spec of a benchmark in progress
+// is changed, except for its MutableFields
+func ValidateUpdate(old, obj runtime.Object) error {
+	benchmark, ok := old.(perfv1alpha1.Benchmark)
+	if !ok || !benchmark.GetBenchmarkStatus().IsInProgress() {
+		return nil
+	}
+
+	unchanged, err := specEqual(old, obj, MutableFields...)
+	if err != nil {
+		return err
+	}
+	if !unchanged {
+		return fmt.Errorf("the spec cannot be changed while the benchmark is %s, only %s can be updated",
+			benchmark.GetBenchmarkStatus().Phase, strings.Join(MutableFields, ", "))
+	}
+	return nil
+}
+
+func validateCommonSpec(spec *perfv1alpha1.CommonSpec) error {
+	if spec.Timeout != nil && spec.Timeout.Duration <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	for _, a := range spec.Assertions {
+		// Any time unit is accepted here, the durations are converted
+		// to the unit of the metric when the assertion is evaluated
+		if _, err := assertion.ParseValue(a.Value, "s"); err != nil {
+			return fmt.Errorf("assertion on %q: %v", a.Metric, err)
+		}
+	}
+	return nil
+}
+
+// specEqual compares the specs of the custom resources, ignoring the given fields
+func specEqual(old, obj runtime.Object, ignoredFields ...string) (bool, error) {
+	oldSpec, err := specOf(old)
+	if err != nil {
+		return false, err
+	}
+	spec, err := specOf(obj)
+	if err != nil {
+		return false, err
+	}
+	for _, field := range ignoredFields {
+		delete(oldSpec, field)
+		delete(spec, field)
+	}
+	return equality.Semantic.DeepEqual(oldSpec, spec), nil
+}
+
+func specOf(obj runtime.Object) (map[string]interface{}, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	spec, _ := content["spec"].(map[string]interface{})
+	if spec == nil {
+		spec = map[string]interface{}{}
+	}
+	return spec, nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	perfv1alpha1 "github.com/xridge/kubestone/api/v1alpha1"
+)
+
+var _ = Describe("webhooks", func() {
+	var decoder *admission.Decoder
+	var hooks Hooks
+
+	newFio := func() *perfv1alpha1.Fio {
+		return &perfv1alpha1.Fio{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: perfv1alpha1.GroupVersion.String(),
+				Kind:       "Fio",
+			},
+			ObjectMeta: metav1.ObjectMeta{Name: "fio", Namespace: "kubestone"},
+			Spec: perfv1alpha1.FioSpec{
+				Image:       perfv1alpha1.ImageSpec{Name: "xridge/fio:3.13"},
+				CmdLineArgs: "--name=randwrite --rw=randwrite",
+			},
+		}
+	}
+
+	raw := func(obj runtime.Object) runtime.RawExtension {
+		content, err := json.Marshal(obj)
+		Expect(err).NotTo(HaveOccurred())
+		return runtime.RawExtension{Raw: content}
+	}
+
+	create := func(obj runtime.Object) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: admissionv1beta1.Create,
+			Object:    raw(obj),
+		}}
+	}
+
+	update := func(old, obj runtime.Object) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: admissionv1beta1.Update,
+			Object:    raw(obj),
+			OldObject: raw(old),
+		}}
+	}
+
+	validate := func(req admission.Request) admission.Response {
+		validator := NewValidator(&perfv1alpha1.Fio{}, hooks)
+		Expect(validator.InjectDecoder(decoder)).To(Succeed())
+		return validator.Handle(context.Background(), req)
+	}
+
+	running := func(cr *perfv1alpha1.Fio) *perfv1alpha1.Fio {
+		cr.Status.SetPhase(perfv1alpha1.BenchmarkRunning, "", "")
+		return cr
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(perfv1alpha1.AddToScheme(scheme)).To(Succeed())
+		var err error
+		decoder, err = admission.NewDecoder(scheme)
+		Expect(err).NotTo(HaveOccurred())
+
+		hooks = Hooks{
+			Default: func(obj runtime.Object) {
+				cr := obj.(*perfv1alpha1.Fio)
+				if cr.Spec.OutputFormat == "" {
+					cr.Spec.OutputFormat = "json+"
+				}
+			},
+			Validate: func(obj runtime.Object) error {
+				if len(obj.(*perfv1alpha1.Fio).Spec.CustomJobFiles) > 3 {
+					return errors.New("too many job files")
+				}
+				return nil
+			},
+		}
+	})
+
+	It("should build the paths of the webhook markers", func() {
+		Expect(MutatePath("EsRally")).To(Equal("/mutate-perf-kubestone-xridge-io-v1alpha1-esrally"))
+		Expect(ValidatePath("Fio")).To(Equal("/validate-perf-kubestone-xridge-io-v1alpha1-fio"))
+	})
+
+	Context("defaulter", func() {
+		It("should patch the defaults into the custom resource", func() {
+			defaulter := NewDefaulter(&perfv1alpha1.Fio{}, hooks)
+			Expect(defaulter.InjectDecoder(decoder)).To(Succeed())
+
+			response := defaulter.Handle(context.Background(), create(newFio()))
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Patches).To(HaveLen(1))
+			Expect(response.Patches[0].Path).To(Equal("/spec/outputFormat"))
+			Expect(response.Patches[0].Value).To(Equal("json+"))
+		})
+	})
+
+	Context("validator", func() {
+		It("should allow valid custom resources", func() {
+			Expect(validate(create(newFio())).Allowed).To(BeTrue())
+		})
+
+		It("should run the kind specific validation", func() {
+			cr := newFio()
+			cr.Spec.CustomJobFiles = []string{"a", "b", "c", "d"}
+			response := validate(create(cr))
+			Expect(response.Allowed).To(BeFalse())
+			Expect(string(response.Result.Reason)).To(ContainSubstring("too many job files"))
+		})
+
+		It("should validate the common settings", func() {
+			cr := newFio()
+			cr.Spec.Timeout = &metav1.Duration{Duration: -time.Minute}
+			Expect(validate(create(cr)).Allowed).To(BeFalse())
+
+			cr = newFio()
+			cr.Spec.Assertions = []perfv1alpha1.Assertion{
+				{Metric: "read.iops", Operator: perfv1alpha1.GreaterThan, Value: "fast"},
+			}
+			response := validate(create(cr))
+			Expect(response.Allowed).To(BeFalse())
+			Expect(string(response.Result.Reason)).To(ContainSubstring(`assertion on "read.iops"`))
+
+			cr.Spec.Assertions[0].Value = "5ms"
+			Expect(validate(create(cr)).Allowed).To(BeTrue())
+		})
+
+		It("should reject spec changes while the benchmark is in progress", func() {
+			old := running(newFio())
+			cr := running(newFio())
+			cr.Spec.CmdLineArgs = "--name=randread --rw=randread"
+
+			response := validate(update(old, cr))
+			Expect(response.Allowed).To(BeFalse())
+			Expect(string(response.Result.Reason)).To(ContainSubstring("while the benchmark is Running"))
+		})
+
+		It("should allow the lifecycle fields to be changed while the benchmark is in progress", func() {
+			old := running(newFio())
+			cr := running(newFio())
+			cr.Spec.Cancel = true
+			cr.Spec.CleanupPolicy = perfv1alpha1.CleanupDeleteAlways
+
+			Expect(validate(update(old, cr)).Allowed).To(BeTrue())
+		})
+
+		It("should allow spec changes before and after the run", func() {
+			cr := newFio()
+			cr.Spec.CmdLineArgs = "--name=randread --rw=randread"
+			Expect(validate(update(newFio(), cr)).Allowed).To(BeTrue())
+
+			old := newFio()
+			old.Status.SetPhase(perfv1alpha1.BenchmarkSucceeded, "", "")
+			Expect(validate(update(old, cr)).Allowed).To(BeTrue())
+		})
+
+		It("should allow updates which do not change the spec", func() {
+			old := running(newFio())
+			old.Spec.CustomJobFiles = []string{"a", "b", "c", "d"}
+			cr := old.DeepCopy()
+			cr.Labels = map[string]string{"team": "storage"}
+
+			Expect(validate(update(old, cr)).Allowed).To(BeTrue())
+		})
+
+		It("should apply the defaults to the old custom resource before the comparison", func() {
+			old := running(newFio())
+			cr := running(newFio())
+			cr.Spec.OutputFormat = "json+"
+
+			Expect(validate(update(old, cr)).Allowed).To(BeTrue())
+		})
+	})
+})
@@ -18,6 +18,17 @@ set -eEuo pipefail
 
 DOCKER_IMAGE="xridge/kubestone:e2e"
 KUBESTONE_ROOT=$(dirname $0)/../../../
+CERT_MANAGER_VERSION="v0.10.1"
+
+deploy_cert_manager() {
+    # cert-manager issues the serving certificate of the admission webhooks
+    kubectl create namespace cert-manager
+    kubectl label namespace cert-manager certmanager.k8s.io/disable-validation=true
+    kubectl apply -f https://github.com/jetstack/cert-manager/releases/download/${CERT_MANAGER_VERSION}/cert-manager.yaml
+    kubectl -n cert-manager \
+        wait --for=condition=Available --timeout=2m \
+        deployments --all
+}
 
 build_kubestone() {
     pushd ${KUBESTONE_ROOT}
@@ -48,6 +59,7 @@ show_all_objects() {
 main() {
     build_kubestone
     upload_kubestone_to_kind
+    deploy_cert_manager
     deploy_kubestone
     validate_kubestone_deployment
     show_all_objects
@@ -59,7 +59,7 @@ var _ = Describe("pgbench end to end test", func() {
 				if err := client.Get(ctx, namespacedName, cr); err != nil {
 					Fail("Unable to get pgbench CR")
 				}
-				return (cr.Status.Running == false) && (cr.Status.Completed)
+				return cr.Status.Phase == v1alpha1.BenchmarkSucceeded
 			}, timeout).Should(BeTrue())
 		})
 
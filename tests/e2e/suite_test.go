@@ -46,6 +46,7 @@ const (
 	e2eNamespacePgbench  = "kubestone-e2e-pgbench"
 	e2eNamespaceQperf    = "kubestone-e2e-qperf"
 	e2eNamespaceSysbench = "kubestone-e2e-sysbench"
+	e2eNamespaceWebhook  = "kubestone-e2e-webhook"
 )
 
 var e2eNamespaces = []string{
@@ -56,6 +57,7 @@ var e2eNamespaces = []string{
 	e2eNamespacePgbench,
 	e2eNamespaceQperf,
 	e2eNamespaceSysbench,
+	e2eNamespaceWebhook,
 }
 
 var restClientConfig = ctrl.GetConfigOrDie()
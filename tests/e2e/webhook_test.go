@@ -0,0 +1,86 @@
+/*
+Copyright 2019 The xridge kubestone contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/xridge/kubestone/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("admission webhook end to end test", func() {
+	Context("creation of invalid CRs", func() {
+		It("should reject the drill CR with a missing benchmark file", func() {
+			cr := &v1alpha1.Drill{
+				ObjectMeta: metav1.ObjectMeta{Name: "drill-invalid", Namespace: e2eNamespaceWebhook},
+				Spec: v1alpha1.DrillSpec{
+					Image:            v1alpha1.ImageSpec{Name: "xridge/drill:0.5.0"},
+					BenchmarksVolume: map[string]string{"benchmark.yml": "---"},
+					BenchmarkFile:    "missing.yml",
+				},
+			}
+			err := client.Create(ctx, cr)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("BenchmarkFile does not exists"))
+		})
+	})
+
+	Context("spec changes of a running benchmark", func() {
+		namespacedName := types.NamespacedName{
+			Namespace: e2eNamespaceWebhook,
+			Name:      "sysbench-running",
+		}
+
+		It("should create the sysbench CR", func() {
+			cr := &v1alpha1.Sysbench{
+				ObjectMeta: metav1.ObjectMeta{Name: namespacedName.Name, Namespace: namespacedName.Namespace},
+				Spec: v1alpha1.SysbenchSpec{
+					Image:    v1alpha1.ImageSpec{Name: "xridge/sysbench:1.0.17-1"},
+					Options:  "--threads=1 --time=300",
+					TestName: "cpu",
+					Command:  "run",
+				},
+			}
+			Expect(client.Create(ctx, cr)).To(Succeed())
+		})
+
+		It("should reject the spec change while running", func() {
+			timeout := 60
+			cr := &v1alpha1.Sysbench{}
+			Eventually(func() bool {
+				if err := client.Get(ctx, namespacedName, cr); err != nil {
+					Fail("Unable to get sysbench CR")
+				}
+				return cr.Status.Phase == v1alpha1.BenchmarkRunning
+			}, timeout).Should(BeTrue())
+
+			cr.Spec.Options = "--threads=2 --time=300"
+			err := client.Update(ctx, cr)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the spec cannot be changed"))
+		})
+
+		It("should allow the cancellation while running", func() {
+			cr := &v1alpha1.Sysbench{}
+			Expect(client.Get(ctx, namespacedName, cr)).To(Succeed())
+			cr.Spec.Cancel = true
+			Expect(client.Update(ctx, cr)).To(Succeed())
+		})
+	})
+})